@@ -0,0 +1,188 @@
+// Package armclient contains the generic Azure Resource Manager client of the provider, so the tools which work alongside the provider,
+// e.g. the custom operators and the scripts, can send the requests with exactly the same semantics: the long-running operations are
+// polled until they complete, the throttled requests are retried, the pages of the list requests are merged, and the errors are the
+// typed errors of the azerrors package.
+package armclient
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/terraform-provider-azapi/internal/clients"
+	"github.com/Azure/terraform-provider-azapi/version"
+	"github.com/cenkalti/backoff/v4"
+)
+
+// Options contains the options of the client, the zero values mean the defaults of the provider.
+type Options struct {
+	// Cloud is the cloud configuration, the Azure public cloud is used if it's empty
+	Cloud cloud.Configuration
+	// UserAgent is appended to the user agent of the provider
+	UserAgent string
+	// SkipProviderRegistration disables the automatic registration of the resource providers
+	SkipProviderRegistration bool
+	// AuxiliaryTenantIDs are the tenants whose tokens are sent in the `x-ms-authorization-auxiliary` header, the credential must be able to
+	// issue the tokens for them
+	AuxiliaryTenantIDs []string
+	// CorrelationRequestID is sent in the `x-ms-correlation-request-id` header of the requests, a random ID is used if it's empty
+	CorrelationRequestID string
+	// Retry is the retry policy of the requests, e.g. the throttled requests
+	Retry policy.RetryOptions
+	// Transport sends the HTTP requests, the default HTTP client of the SDK is used if it's nil
+	Transport policy.Transporter
+}
+
+// RequestOptions contains the options of a request.
+type RequestOptions struct {
+	// Headers are the additional headers of the request
+	Headers map[string]string
+	// QueryParameters are the additional query parameters of the request, the api-version is set by the client
+	QueryParameters map[string]string
+	// PollingInterval is the interval between the polling requests of the long-running operations when the responses don't contain the Retry-After header,
+	// it defaults to 10 seconds
+	PollingInterval time.Duration
+	// Endpoint is the resource manager endpoint which the request is sent to instead of the endpoint of the cloud,
+	// e.g. the regional endpoint `https://westus.management.azure.com`
+	Endpoint string
+}
+
+// RetryableErrors configures the retries of the errors which match the regular expressions, in addition to the retries of the transient errors,
+// it's the same as the `retry` block of the resources.
+type RetryableErrors struct {
+	// ErrorMessageRegex are the regular expressions of the error messages to retry on
+	ErrorMessageRegex []string
+	// Interval is the initial interval between the retries, it defaults to 10 seconds
+	Interval time.Duration
+	// MaxInterval is the maximum interval between the retries, it defaults to 180 seconds
+	MaxInterval time.Duration
+	// Multiplier is the multiplier of the interval after each retry, it defaults to 1.5
+	Multiplier float64
+	// RandomizationFactor is the randomization factor of the intervals, it defaults to 0.5, set it to zero for no randomization
+	RandomizationFactor *float64
+}
+
+// Client sends the requests to the Azure Resource Manager.
+type Client struct {
+	requester clients.Requester
+}
+
+// New returns a client which authenticates the requests with the credential.
+func New(credential azcore.TokenCredential, options *Options) (*Client, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	cloudCfg := options.Cloud
+	if _, ok := cloudCfg.Services[cloud.ResourceManager]; !ok {
+		cloudCfg = cloud.AzurePublic
+	}
+	userAgent := fmt.Sprintf("terraform-provider-azapi/%s", version.ProviderVersion)
+	if options.UserAgent != "" {
+		userAgent = fmt.Sprintf("%s %s", userAgent, options.UserAgent)
+	}
+
+	client := &clients.Client{}
+	err := client.Build(context.Background(), &clients.Option{
+		Cred:                       credential,
+		ApplicationUserAgent:       userAgent,
+		SkipProviderRegistration:   options.SkipProviderRegistration,
+		CloudCfg:                   cloudCfg,
+		CustomCorrelationRequestID: options.CorrelationRequestID,
+		AuxiliaryTenantIds:         options.AuxiliaryTenantIDs,
+		Retry:                      options.Retry,
+		Transport:                  options.Transport,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		requester: client.ResourceClient,
+	}, nil
+}
+
+// WithRetryableErrors returns a copy of the client which retries the requests that fail with the errors matching the regular expressions.
+func (c *Client) WithRetryableErrors(retryableErrors RetryableErrors) (*Client, error) {
+	resourceClient, ok := c.requester.(*clients.ResourceClient)
+	if !ok {
+		return nil, fmt.Errorf("the retryable errors are already configured")
+	}
+	errRegExps := make([]regexp.Regexp, 0, len(retryableErrors.ErrorMessageRegex))
+	for _, v := range retryableErrors.ErrorMessageRegex {
+		r, err := regexp.Compile(v)
+		if err != nil {
+			return nil, fmt.Errorf("the regular expression %q is invalid: %+v", v, err)
+		}
+		errRegExps = append(errRegExps, *r)
+	}
+	bkof := backoff.NewExponentialBackOff(
+		backoff.WithRandomizationFactor(0.5),
+		backoff.WithInitialInterval(durationOrDefault(retryableErrors.Interval, 10*time.Second)),
+		backoff.WithMaxInterval(durationOrDefault(retryableErrors.MaxInterval, 180*time.Second)),
+		backoff.WithMultiplier(floatOrDefault(retryableErrors.Multiplier, 1.5)),
+	)
+	if retryableErrors.RandomizationFactor != nil {
+		bkof.RandomizationFactor = *retryableErrors.RandomizationFactor
+	}
+	return &Client{
+		requester: resourceClient.WithRetry(bkof, errRegExps),
+	}, nil
+}
+
+// Get returns the resource.
+func (c *Client) Get(ctx context.Context, resourceID string, apiVersion string, options *RequestOptions) (interface{}, error) {
+	return c.requester.Get(ctx, resourceID, apiVersion, requestOptions(options))
+}
+
+// CreateOrUpdate creates or updates the resource with the body, and waits for the long-running operation to complete.
+func (c *Client) CreateOrUpdate(ctx context.Context, resourceID string, apiVersion string, body interface{}, options *RequestOptions) (interface{}, error) {
+	return c.requester.CreateOrUpdate(ctx, resourceID, apiVersion, body, requestOptions(options))
+}
+
+// Delete deletes the resource, and waits for the long-running operation to complete.
+func (c *Client) Delete(ctx context.Context, resourceID string, apiVersion string, options *RequestOptions) (interface{}, error) {
+	return c.requester.Delete(ctx, resourceID, apiVersion, requestOptions(options))
+}
+
+// Action sends the request of the action to the resource, e.g. `listKeys`, the action is empty if the request is sent to the resource itself.
+func (c *Client) Action(ctx context.Context, resourceID string, action string, apiVersion string, method string, body interface{}, options *RequestOptions) (interface{}, error) {
+	return c.requester.Action(ctx, resourceID, action, apiVersion, method, body, requestOptions(options))
+}
+
+// List returns the resources of the collection, the items of all the pages are merged into the `value` array.
+func (c *Client) List(ctx context.Context, url string, apiVersion string, options *RequestOptions) (interface{}, error) {
+	return c.requester.List(ctx, url, apiVersion, requestOptions(options))
+}
+
+func requestOptions(options *RequestOptions) clients.RequestOptions {
+	out := clients.DefaultRequestOptions()
+	if options == nil {
+		return out
+	}
+	out.PollingInterval = options.PollingInterval
+	out.Endpoint = options.Endpoint
+	for k, v := range options.Headers {
+		out.Headers[k] = v
+	}
+	for k, v := range options.QueryParameters {
+		out.QueryParameters[k] = v
+	}
+	return out
+}
+
+func durationOrDefault(value time.Duration, defaultValue time.Duration) time.Duration {
+	if value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+func floatOrDefault(value float64, defaultValue float64) float64 {
+	if value <= 0 {
+		return defaultValue
+	}
+	return value
+}
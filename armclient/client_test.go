@@ -0,0 +1,119 @@
+package armclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/terraform-provider-azapi/azerrors"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTokenCredential struct{}
+
+func (fakeTokenCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func TestClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.Equal(t, "2023-01-01", r.URL.Query().Get("api-version"))
+		assert.True(t, strings.Contains(r.Header.Get("User-Agent"), "my-operator/1.0"), r.Header.Get("User-Agent"))
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Query().Get("$skipToken") == "" && strings.HasSuffix(r.URL.Path, "/resourceGroups"):
+			_, _ = w.Write([]byte(`{"value":[{"name":"rg1"}],"nextLink":"https://` + r.Host + r.URL.Path + `?api-version=2023-01-01&$skipToken=1"}`))
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/resourceGroups"):
+			_, _ = w.Write([]byte(`{"value":[{"name":"rg2"}]}`))
+		case r.Method == http.MethodPut:
+			assert.Equal(t, "value", r.Header.Get("x-custom-header"))
+			_, _ = w.Write([]byte(`{"name":"rg1","location":"westus"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":"ResourceGroupNotFound","message":"not found"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(fakeTokenCredential{}, &Options{
+		Cloud: cloud.Configuration{
+			ActiveDirectoryAuthorityHost: "https://login.microsoftonline.com/",
+			Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+				cloud.ResourceManager: {
+					Audience: "https://management.core.windows.net/",
+					Endpoint: server.URL,
+				},
+			},
+		},
+		UserAgent:                "my-operator/1.0",
+		SkipProviderRegistration: true,
+		Retry:                    policy.RetryOptions{MaxRetries: -1},
+		Transport:                server.Client(),
+	})
+	assert.NoError(t, err)
+
+	const resourceGroupId = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1"
+	out, err := client.CreateOrUpdate(context.Background(), resourceGroupId, "2023-01-01", map[string]interface{}{"location": "westus"}, &RequestOptions{
+		Headers: map[string]string{"x-custom-header": "value"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "rg1", "location": "westus"}, out)
+
+	out, err = client.List(context.Background(), "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups", "2023-01-01", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"value": []interface{}{map[string]interface{}{"name": "rg1"}, map[string]interface{}{"name": "rg2"}}}, out)
+
+	_, err = client.Get(context.Background(), resourceGroupId, "2023-01-01", nil)
+	var notFoundErr *azerrors.NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr), err)
+}
+
+func TestClientWithRetryableErrors(t *testing.T) {
+	client, err := New(fakeTokenCredential{}, nil)
+	assert.NoError(t, err)
+
+	_, err = client.WithRetryableErrors(RetryableErrors{ErrorMessageRegex: []string{"("}})
+	assert.Error(t, err)
+
+	retryClient, err := client.WithRetryableErrors(RetryableErrors{ErrorMessageRegex: []string{"ResourceGroupNotFound"}})
+	assert.NoError(t, err)
+	_, err = retryClient.WithRetryableErrors(RetryableErrors{})
+	assert.Error(t, err)
+}
+
+func TestClientWithAuxiliaryTenants(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("x-ms-authorization-auxiliary"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"peering1"}`))
+	}))
+	defer server.Close()
+
+	client, err := New(fakeTokenCredential{}, &Options{
+		Cloud: cloud.Configuration{
+			ActiveDirectoryAuthorityHost: "https://login.microsoftonline.com/",
+			Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+				cloud.ResourceManager: {
+					Audience: "https://management.core.windows.net/",
+					Endpoint: server.URL,
+				},
+			},
+		},
+		AuxiliaryTenantIDs:       []string{"00000000-0000-0000-0000-000000000001"},
+		SkipProviderRegistration: true,
+		Retry:                    policy.RetryOptions{MaxRetries: -1},
+		Transport:                server.Client(),
+	})
+	assert.NoError(t, err)
+
+	_, err = client.Get(context.Background(), "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/virtualNetworkPeerings/peering1", "2023-04-01", nil)
+	assert.NoError(t, err)
+}
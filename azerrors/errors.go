@@ -0,0 +1,112 @@
+// Package azerrors contains the typed errors of the Azure requests, so the tools which embed the provider and the tests can assert on
+// the error categories with errors.As instead of matching the error messages. The typed errors wrap the original errors, e.g. the
+// *azcore.ResponseError, and their messages are the same as the original errors.
+package azerrors
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+)
+
+// errorCodePolicyDenied is the error code of the requests which are denied by the Azure Policy.
+const errorCodePolicyDenied = "RequestDisallowedByPolicy"
+
+// ThrottledError is returned when the request is throttled by Azure, i.e. the response status code is 429.
+type ThrottledError struct {
+	// RetryAfter is the duration in the Retry-After header of the response, it's zero if the header is missing.
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *ThrottledError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ThrottledError) Unwrap() error {
+	return e.Err
+}
+
+// PolicyDeniedError is returned when the request is denied by the Azure Policy, i.e. the error code is `RequestDisallowedByPolicy`.
+type PolicyDeniedError struct {
+	Err error
+}
+
+func (e *PolicyDeniedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PolicyDeniedError) Unwrap() error {
+	return e.Err
+}
+
+// NotFoundError is returned when the resource doesn't exist, i.e. the response status code is 404.
+type NotFoundError struct {
+	Err error
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *NotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// LROFailedError is returned when the long-running operation is accepted by Azure but fails while it's polled.
+// The error it wraps may be categorized as well, e.g. a PolicyDeniedError.
+type LROFailedError struct {
+	// OperationURL is the URL which is used to track the long-running operation.
+	OperationURL string
+	Err          error
+}
+
+func (e *LROFailedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LROFailedError) Unwrap() error {
+	return e.Err
+}
+
+// FromResponseError returns the typed error of the response error based on its status code and error code,
+// it returns the error as is if it's not a response error or it doesn't belong to any category.
+func FromResponseError(err error) error {
+	var responseErr *azcore.ResponseError
+	if !errors.As(err, &responseErr) {
+		return err
+	}
+	switch {
+	case strings.EqualFold(responseErr.ErrorCode, errorCodePolicyDenied):
+		return &PolicyDeniedError{Err: err}
+	case responseErr.StatusCode == http.StatusTooManyRequests:
+		return &ThrottledError{RetryAfter: retryAfter(responseErr.RawResponse), Err: err}
+	case responseErr.StatusCode == http.StatusNotFound:
+		return &NotFoundError{Err: err}
+	}
+	return err
+}
+
+// FromPollingError returns a LROFailedError which wraps the typed error of the polling error, it returns the error as is if it's not a response error,
+// e.g. the context is canceled.
+func FromPollingError(err error, operationUrl string) error {
+	var responseErr *azcore.ResponseError
+	if !errors.As(err, &responseErr) {
+		return err
+	}
+	return &LROFailedError{OperationURL: operationUrl, Err: FromResponseError(err)}
+}
+
+func retryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	if v, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && v > 0 {
+		return time.Duration(v) * time.Second
+	}
+	return 0
+}
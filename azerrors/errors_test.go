@@ -0,0 +1,67 @@
+package azerrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func newResponseError(statusCode int, header http.Header, body string) error {
+	req, _ := http.NewRequest(http.MethodPut, "https://management.azure.com/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1?api-version=2021-04-01", nil)
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Content-Type", "application/json")
+	return runtime.NewResponseError(&http.Response{
+		StatusCode: statusCode,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	})
+}
+
+func TestFromResponseError(t *testing.T) {
+	err := FromResponseError(newResponseError(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"30"}}, `{"error":{"code":"TooManyRequests","message":"throttled"}}`))
+	var throttledErr *ThrottledError
+	assert.True(t, errors.As(err, &throttledErr))
+	assert.Equal(t, 30*time.Second, throttledErr.RetryAfter)
+
+	err = FromResponseError(newResponseError(http.StatusForbidden, nil, `{"error":{"code":"RequestDisallowedByPolicy","message":"denied"}}`))
+	var policyDeniedErr *PolicyDeniedError
+	assert.True(t, errors.As(err, &policyDeniedErr))
+
+	err = FromResponseError(newResponseError(http.StatusNotFound, nil, `{"error":{"code":"ResourceGroupNotFound","message":"not found"}}`))
+	var notFoundErr *NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+
+	// the typed errors keep the messages and the wrapped response errors
+	var responseErr *azcore.ResponseError
+	assert.True(t, errors.As(err, &responseErr))
+	assert.Equal(t, responseErr.Error(), err.Error())
+
+	err = newResponseError(http.StatusBadRequest, nil, `{"error":{"code":"InvalidTemplate","message":"bad request"}}`)
+	assert.Equal(t, err, FromResponseError(err))
+
+	err = fmt.Errorf("dial tcp: connection refused")
+	assert.Equal(t, err, FromResponseError(err))
+}
+
+func TestFromPollingError(t *testing.T) {
+	err := FromPollingError(newResponseError(http.StatusOK, nil, `{"status":"Failed","error":{"code":"RequestDisallowedByPolicy","message":"denied"}}`), "https://management.azure.com/operations/1")
+	var lroFailedErr *LROFailedError
+	assert.True(t, errors.As(err, &lroFailedErr))
+	assert.Equal(t, "https://management.azure.com/operations/1", lroFailedErr.OperationURL)
+	var policyDeniedErr *PolicyDeniedError
+	assert.True(t, errors.As(err, &policyDeniedErr))
+
+	assert.Equal(t, context.DeadlineExceeded, FromPollingError(context.DeadlineExceeded, ""))
+}
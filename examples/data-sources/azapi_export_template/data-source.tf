@@ -0,0 +1,26 @@
+terraform {
+  required_providers {
+    azapi = {
+      source = "Azure/azapi"
+    }
+    local = {
+      source = "hashicorp/local"
+    }
+  }
+}
+
+provider "azapi" {
+}
+
+data "azapi_client_config" "current" {}
+
+data "azapi_export_template" "example" {
+  resource_group_id = "/subscriptions/${data.azapi_client_config.current.subscription_id}/resourceGroups/example-rg"
+  options           = ["SkipAllParameterization"]
+}
+
+// the template is written to a file, e.g. to keep the snapshots of the infrastructure for the audits
+resource "local_file" "template" {
+  filename = "${path.module}/template.json"
+  content  = jsonencode(data.azapi_export_template.example.template)
+}
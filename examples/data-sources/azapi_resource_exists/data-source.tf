@@ -0,0 +1,27 @@
+terraform {
+  required_providers {
+    azapi = {
+      source = "Azure/azapi"
+    }
+  }
+}
+
+provider "azapi" {
+}
+
+data "azapi_client_config" "current" {}
+
+data "azapi_resource_exists" "resourceGroup" {
+  type      = "Microsoft.Resources/resourceGroups@2024-03-01"
+  parent_id = "/subscriptions/${data.azapi_client_config.current.subscription_id}"
+  name      = "example-rg"
+}
+
+// the resource group is created only if it doesn't exist
+resource "azapi_resource" "resourceGroup" {
+  count     = data.azapi_resource_exists.resourceGroup.exists ? 0 : 1
+  type      = "Microsoft.Resources/resourceGroups@2024-03-01"
+  parent_id = "/subscriptions/${data.azapi_client_config.current.subscription_id}"
+  name      = "example-rg"
+  location  = "westeurope"
+}
@@ -0,0 +1,35 @@
+terraform {
+  required_providers {
+    azapi = {
+      source = "Azure/azapi"
+    }
+  }
+}
+
+provider "azapi" {
+}
+
+data "azapi_client_config" "current" {}
+
+data "azapi_resource_import_list" "example" {
+  parent_id = "/subscriptions/${data.azapi_client_config.current.subscription_id}/resourceGroups/example-rg"
+  types     = ["Microsoft.Network/virtualNetworks"]
+}
+
+locals {
+  imported_resources = { for r in data.azapi_resource_import_list.example.resources : r.suggested_address => r if r.api_version != null }
+}
+
+// the resources are imported with the api-versions which are used in the configuration
+import {
+  for_each = local.imported_resources
+  to       = azapi_resource.imported[each.key]
+  id       = "${each.value.id}?api-version=${each.value.api_version}"
+}
+
+resource "azapi_resource" "imported" {
+  for_each  = local.imported_resources
+  type      = "${each.value.type}@${each.value.api_version}"
+  parent_id = "/subscriptions/${data.azapi_client_config.current.subscription_id}/resourceGroups/example-rg"
+  name      = reverse(split("/", each.value.id))[0]
+}
@@ -27,3 +27,23 @@ data "azapi_resource_list" "listSubnetsByVnet" {
   parent_id              = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1"
   response_export_values = ["*"]
 }
+
+// all the pages of the list are returned, so the network security groups in the subscription can be used in the for_each
+data "azapi_resource_list" "networkSecurityGroups" {
+  type      = "Microsoft.Network/networkSecurityGroups@2023-09-01"
+  parent_id = "/subscriptions/00000000-0000-0000-0000-000000000000"
+  response_export_values = {
+    ids = "value[].id"
+  }
+}
+
+resource "azapi_update_resource" "networkSecurityGroupTags" {
+  for_each    = toset(data.azapi_resource_list.networkSecurityGroups.output.ids)
+  type        = "Microsoft.Network/networkSecurityGroups@2023-09-01"
+  resource_id = each.value
+  body = {
+    tags = {
+      audited = "true"
+    }
+  }
+}
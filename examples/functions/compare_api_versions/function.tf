@@ -0,0 +1,16 @@
+// it returns the breaking changes of the api-version upgrade, e.g.
+# [
+#   {
+#     detail = "the property is removed"
+#     kind   = "removed"
+#     path   = "properties.networkProfile.dockerBridgeCidr"
+#   },
+# ]
+output "breaking_changes" {
+  value = provider::azapi::compare_api_versions("Microsoft.ContainerService/managedClusters", "2021-05-01", "2024-02-01")
+}
+
+// the properties which are used by the configuration can be checked before the api-version is changed
+output "removed_paths" {
+  value = [for change in provider::azapi::compare_api_versions("Microsoft.ContainerService/managedClusters", "2021-05-01", "2024-02-01") : change.path if change.kind == "removed"]
+}
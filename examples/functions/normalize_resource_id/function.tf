@@ -0,0 +1,12 @@
+locals {
+  // the IDs of the same virtual networks from different sources
+  virtual_network_ids = [
+    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/myRG/providers/Microsoft.Network/virtualNetworks/vnet1",
+    "/SUBSCRIPTIONS/00000000-0000-0000-0000-000000000000/resourcegroups/myRG/providers/microsoft.network/virtualnetworks/vnet1/",
+  ]
+}
+
+// it will output ["/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/myRG/providers/Microsoft.Network/virtualNetworks/vnet1"]
+output "unique_virtual_network_ids" {
+  value = toset([for id in local.virtual_network_ids : provider::azapi::normalize_resource_id(id)])
+}
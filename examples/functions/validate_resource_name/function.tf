@@ -0,0 +1,11 @@
+variable "storage_account_name" {
+  type    = string
+  default = "mystorageaccount01"
+}
+
+// it returns the name if it's valid, otherwise the plan fails with the reason, e.g.
+# the name "MyStorage01" of Microsoft.Storage/storageAccounts is invalid, it can contain only lowercase letters and numbers.
+# Note: the name of Microsoft.Storage/storageAccounts must be globally unique across Azure.
+output "storage_account_name" {
+  value = provider::azapi::validate_resource_name("Microsoft.Storage/storageAccounts", var.storage_account_name)
+}
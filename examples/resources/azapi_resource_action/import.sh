@@ -0,0 +1,7 @@
+# An action which was performed outside of Terraform can be imported using the resource id with the action as a query parameter, e.g.
+terraform import azapi_resource_action.example "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/resGroup1/providers/Microsoft.Automation/automationAccounts/account1?action=agentRegistrationInformation/regenerateKey"
+
+# It also supports specifying API version by using the resource id with api-version as a query parameter, e.g.
+terraform import azapi_resource_action.example "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/resGroup1/providers/Microsoft.Automation/automationAccounts/account1?api-version=2021-06-22&action=agentRegistrationInformation/regenerateKey"
+
+# The imported action is a placeholder, the next apply adopts it without performing the action, and the `output` is empty until the action is performed again.
@@ -48,3 +48,35 @@ resource "azapi_resource_action" "stop" {
 
   count = var.enabled ? 0 : 1
 }
+
+data "azapi_client_config" "current" {}
+
+// register the resource provider in the subscription, the action is invoked when the resource is created
+resource "azapi_resource_action" "register_provider" {
+  type        = "Microsoft.Resources/subscriptions/providers@2021-04-01"
+  resource_id = "/subscriptions/${data.azapi_client_config.current.subscription_id}/providers/Microsoft.AppPlatform"
+  action      = "register"
+}
+
+// stop the spring service before it's deleted, the action is invoked when the resource is destroyed
+resource "azapi_resource_action" "stop_on_destroy" {
+  type        = "Microsoft.AppPlatform/Spring@2022-05-01-preview"
+  resource_id = azurerm_spring_cloud_service.test.id
+  action      = "stop"
+  when        = "destroy"
+}
+
+// regenerate the primary test key of the spring service, the action is only performed again when the triggers are changed or the last run is older than the rerun interval
+resource "azapi_resource_action" "regenerate_test_key" {
+  type        = "Microsoft.AppPlatform/Spring@2022-05-01-preview"
+  resource_id = azurerm_spring_cloud_service.test.id
+  action      = "regenerateTestKey"
+  body = {
+    keyType = "Primary"
+  }
+  triggers = {
+    rotation = "2024-01"
+  }
+  // regenerate the key again every 30 days
+  rerun_interval = "720h"
+}
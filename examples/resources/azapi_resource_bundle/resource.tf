@@ -0,0 +1,100 @@
+terraform {
+  required_providers {
+    azapi = {
+      source = "Azure/azapi"
+    }
+  }
+}
+
+provider "azapi" {
+}
+
+variable "resource_name" {
+  type    = string
+  default = "acctest0001"
+}
+
+variable "location" {
+  type    = string
+  default = "westeurope"
+}
+
+variable "subnet_id" {
+  type = string
+}
+
+resource "azapi_resource" "resourceGroup" {
+  type     = "Microsoft.Resources/resourceGroups@2021-04-01"
+  name     = var.resource_name
+  location = var.location
+}
+
+resource "azapi_resource" "storageAccount" {
+  type      = "Microsoft.Storage/storageAccounts@2023-01-01"
+  parent_id = azapi_resource.resourceGroup.id
+  name      = var.resource_name
+  location  = var.location
+  body = {
+    kind = "StorageV2"
+    sku = {
+      name = "Standard_LRS"
+    }
+  }
+}
+
+locals {
+  private_endpoint_id = "${azapi_resource.resourceGroup.id}/providers/Microsoft.Network/privateEndpoints/${var.resource_name}"
+}
+
+// the private DNS zone, the private endpoint and the DNS zone group are created as a unit, if any of them fails, the others are deleted
+resource "azapi_resource_bundle" "privateEndpoint" {
+  resources = [
+    {
+      type      = "Microsoft.Network/privateDnsZones@2020-06-01"
+      name      = "privatelink.blob.core.windows.net"
+      parent_id = azapi_resource.resourceGroup.id
+      body = {
+        location = "global"
+      }
+    },
+    {
+      type      = "Microsoft.Network/privateEndpoints@2023-04-01"
+      name      = var.resource_name
+      parent_id = azapi_resource.resourceGroup.id
+      body = {
+        location = var.location
+        properties = {
+          subnet = {
+            id = var.subnet_id
+          }
+          privateLinkServiceConnections = [
+            {
+              name = var.resource_name
+              properties = {
+                privateLinkServiceId = azapi_resource.storageAccount.id
+                groupIds             = ["blob"]
+              }
+            }
+          ]
+        }
+      }
+    },
+    {
+      type      = "Microsoft.Network/privateEndpoints/privateDnsZoneGroups@2023-04-01"
+      name      = "default"
+      parent_id = local.private_endpoint_id
+      body = {
+        properties = {
+          privateDnsZoneConfigs = [
+            {
+              name = "blob"
+              properties = {
+                privateDnsZoneId = "${azapi_resource.resourceGroup.id}/providers/Microsoft.Network/privateDnsZones/privatelink.blob.core.windows.net"
+              }
+            }
+          ]
+        }
+      }
+    },
+  ]
+}
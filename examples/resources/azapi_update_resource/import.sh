@@ -0,0 +1,7 @@
+# The resource managed by azapi_update_resource can be imported using the resource id, e.g.
+terraform import azapi_update_resource.example /subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/resGroup1/providers/Microsoft.Network/loadBalancers/lb1
+
+# It also supports specifying API version by using the resource id with api-version as a query parameter, e.g.
+terraform import azapi_update_resource.example /subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/resGroup1/providers/Microsoft.Network/loadBalancers/lb1?api-version=2023-09-01
+
+# The body isn't imported, because it's unknown which properties are managed, the next apply sends the configured properties, which doesn't change the resource if they already have the configured values.
@@ -34,6 +34,7 @@ func BuildTestClient() (*clients.Client, error) {
 		case "china":
 			cloudConfig = cloud.AzureChina
 		default:
+			env = "public"
 			cloudConfig = cloud.AzurePublic
 		}
 
@@ -63,6 +64,7 @@ func BuildTestClient() (*clients.Client, error) {
 		copt := &clients.Option{
 			Cred:                     cred,
 			CloudCfg:                 cloudConfig,
+			Environment:              strings.ToLower(env),
 			Features:                 features.Default(),
 			SkipProviderRegistration: true,
 			TenantId:                 os.Getenv("ARM_TENANT_ID"),
@@ -0,0 +1,248 @@
+package azure
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/terraform-provider-azapi/internal/azure/types"
+)
+
+const (
+	BreakingChangeRemoved          = "removed"
+	BreakingChangeRenamed          = "renamed"
+	BreakingChangeTypeChanged      = "type_changed"
+	BreakingChangeRequired         = "required"
+	BreakingChangeEnumValueRemoved = "enum_value_removed"
+)
+
+// BreakingChange is a change of the resource definition which may break the configurations written for the earlier api-version.
+type BreakingChange struct {
+	// Path is the path of the property in the body, e.g. `properties.sku.name`, the items of the arrays are `[]`, the values of the maps are `*`,
+	// and the kinds of the discriminated objects are `[kind=value]`
+	Path string
+	// Kind is one of `removed`, `renamed`, `type_changed`, `required` and `enum_value_removed`
+	Kind   string
+	Detail string
+}
+
+// GetBreakingChanges compares the definitions of the resource type in the two api-versions, and returns the breaking changes sorted by the paths.
+// The properties whose names only differ in casing are reported as renamed, the other renames can't be told from the removals.
+func GetBreakingChanges(resourceType, fromApiVersion, toApiVersion string) ([]BreakingChange, error) {
+	from, err := GetResourceDefinition(resourceType, fromApiVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := GetResourceDefinition(resourceType, toApiVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	c := breakingChangeComparer{
+		visited: make(map[[2]*types.TypeBase]bool),
+	}
+	if from.Body != nil && to.Body != nil {
+		c.compare("", from.Body.Type, to.Body.Type)
+	}
+	sort.SliceStable(c.changes, func(i, j int) bool {
+		return c.changes[i].Path < c.changes[j].Path
+	})
+	return c.changes, nil
+}
+
+type breakingChangeComparer struct {
+	// visited records the compared pairs of types, the definitions are recursive, e.g. the error details
+	visited map[[2]*types.TypeBase]bool
+	changes []BreakingChange
+}
+
+func (c *breakingChangeComparer) add(path, kind, format string, a ...interface{}) {
+	c.changes = append(c.changes, BreakingChange{
+		Path:   path,
+		Kind:   kind,
+		Detail: fmt.Sprintf(format, a...),
+	})
+}
+
+func (c *breakingChangeComparer) compare(path string, from, to *types.TypeBase) {
+	if from == nil || to == nil || *from == nil || *to == nil {
+		return
+	}
+	key := [2]*types.TypeBase{from, to}
+	if c.visited[key] {
+		return
+	}
+	c.visited[key] = true
+
+	fromKind, toKind := typeKind(*from), typeKind(*to)
+	if fromKind != toKind {
+		// any value is accepted by the new api-version
+		if toKind != "any" {
+			c.add(path, BreakingChangeTypeChanged, "the type is changed from %s to %s", fromKind, toKind)
+		}
+		return
+	}
+
+	switch f := (*from).(type) {
+	case *types.ObjectType:
+		t := (*to).(*types.ObjectType)
+		c.compareProperties(path, f.Properties, t.Properties)
+		if f.AdditionalProperties != nil && t.AdditionalProperties != nil {
+			c.compare(joinPath(path, "*"), f.AdditionalProperties.Type, t.AdditionalProperties.Type)
+		}
+	case *types.DiscriminatedObjectType:
+		t := (*to).(*types.DiscriminatedObjectType)
+		c.compareProperties(path, f.BaseProperties, t.BaseProperties)
+		for _, value := range sortedKeys(f.Elements) {
+			elementPath := fmt.Sprintf("%s[%s=%s]", path, f.Discriminator, value)
+			toElement, ok := t.Elements[value]
+			if !ok {
+				c.add(elementPath, BreakingChangeRemoved, "the %s %q is removed", f.Discriminator, value)
+				continue
+			}
+			if fromElement := f.Elements[value]; fromElement != nil {
+				c.compare(elementPath, fromElement.Type, toElement.Type)
+			}
+		}
+	case *types.ArrayType:
+		t := (*to).(*types.ArrayType)
+		if f.ItemType != nil && t.ItemType != nil {
+			c.compare(path+"[]", f.ItemType.Type, t.ItemType.Type)
+		}
+	default:
+		fromValues, toValues := enumValues(*from), enumValues(*to)
+		if len(fromValues) == 0 || len(toValues) == 0 {
+			return
+		}
+		for _, value := range fromValues {
+			if !containsFold(toValues, value) {
+				c.add(path, BreakingChangeEnumValueRemoved, "the value %q is not allowed", value)
+			}
+		}
+	}
+}
+
+func (c *breakingChangeComparer) compareProperties(path string, from, to map[string]types.ObjectProperty) {
+	for _, name := range sortedKeys(from) {
+		// the api-version of the body is always changed
+		if path == "" && name == "apiVersion" {
+			continue
+		}
+		fromProperty := from[name]
+		// the read-only properties aren't specified in the configurations, so their changes don't break them
+		if fromProperty.IsReadOnly() {
+			continue
+		}
+		propertyPath := joinPath(path, name)
+		toProperty, ok := to[name]
+		if !ok {
+			renamed := false
+			for toName, v := range to {
+				if _, exists := from[toName]; !exists && strings.EqualFold(toName, name) {
+					c.add(propertyPath, BreakingChangeRenamed, "the property is renamed to %s", toName)
+					toProperty, renamed = v, true
+					break
+				}
+			}
+			if !renamed {
+				c.add(propertyPath, BreakingChangeRemoved, "the property is removed")
+				continue
+			}
+		}
+		if toProperty.IsRequired() && !fromProperty.IsRequired() {
+			c.add(propertyPath, BreakingChangeRequired, "the property is required")
+		}
+		if fromProperty.Type != nil && toProperty.Type != nil {
+			c.compare(propertyPath, fromProperty.Type.Type, toProperty.Type.Type)
+		}
+	}
+	for _, name := range sortedKeys(to) {
+		if _, ok := from[name]; ok || !to[name].IsRequired() {
+			continue
+		}
+		renamed := false
+		for fromName := range from {
+			if strings.EqualFold(fromName, name) {
+				renamed = true
+				break
+			}
+		}
+		if !renamed {
+			c.add(joinPath(path, name), BreakingChangeRequired, "the property is added and required")
+		}
+	}
+}
+
+// typeKind returns the kind of the values of the type, the enums are strings.
+func typeKind(t types.TypeBase) string {
+	switch v := t.(type) {
+	case *types.ObjectType, *types.DiscriminatedObjectType, *types.ResourceType:
+		return "object"
+	case *types.ArrayType:
+		return "array"
+	case *types.StringType, *types.StringLiteralType:
+		return "string"
+	case *types.IntegerType:
+		return "integer"
+	case *types.BooleanType:
+		return "boolean"
+	case *types.AnyType:
+		return "any"
+	case *types.UnionType:
+		// the unions of the strings and the string literals are the extensible enums
+		for _, element := range v.Elements {
+			if element == nil || element.Type == nil || typeKind(*element.Type) != "string" {
+				return "union"
+			}
+		}
+		return "string"
+	}
+	return fmt.Sprintf("%T", t)
+}
+
+// enumValues returns the allowed values of the string literal or the union of the string literals, it returns nil for the other types.
+func enumValues(t types.TypeBase) []string {
+	switch v := t.(type) {
+	case *types.StringLiteralType:
+		return []string{v.Value}
+	case *types.UnionType:
+		values := make([]string, 0, len(v.Elements))
+		for _, element := range v.Elements {
+			if element == nil || element.Type == nil {
+				return nil
+			}
+			literal, ok := (*element.Type).(*types.StringLiteralType)
+			if !ok {
+				return nil
+			}
+			values = append(values, literal.Value)
+		}
+		return values
+	}
+	return nil
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,51 @@
+package azure_test
+
+import (
+	"testing"
+
+	"github.com/Azure/terraform-provider-azapi/internal/azure"
+)
+
+func Test_GetBreakingChanges(t *testing.T) {
+	changes, err := azure.GetBreakingChanges("Microsoft.ContainerService/managedClusters", "2021-05-01", "2024-02-01")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	found := false
+	for _, change := range changes {
+		if change.Path == "apiVersion" {
+			t.Errorf("expect the api-version not to be reported, got %+v", change)
+		}
+		if change.Path == "properties.networkProfile.dockerBridgeCidr" && change.Kind == azure.BreakingChangeRemoved {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expect properties.networkProfile.dockerBridgeCidr to be removed, got %+v", changes)
+	}
+
+	changes, err = azure.GetBreakingChanges("Microsoft.Storage/storageAccounts", "2023-01-01", "2016-01-01")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	kinds := make(map[string]string)
+	for _, change := range changes {
+		kinds[change.Path] = change.Kind
+	}
+	if kinds["properties.accessTier"] != azure.BreakingChangeEnumValueRemoved {
+		t.Errorf("expect the value of properties.accessTier to be removed, got %+v", changes)
+	}
+	if kinds["properties.encryption.keySource"] != azure.BreakingChangeRequired {
+		t.Errorf("expect properties.encryption.keySource to be required, got %+v", changes)
+	}
+	// the read-only properties are removed in the earlier api-version, but they aren't specified in the configurations
+	for _, path := range []string{"properties.blobRestoreStatus", "properties.provisioningState", "properties.geoReplicationStats"} {
+		if kind, ok := kinds[path]; ok {
+			t.Errorf("expect the read-only property %s not to be reported, got %s", path, kind)
+		}
+	}
+
+	if _, err := azure.GetBreakingChanges("Microsoft.Storage/storageAccounts", "2023-01-01", "2099-01-01"); err == nil {
+		t.Errorf("expect an error for the unknown api-version")
+	}
+}
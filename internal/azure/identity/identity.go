@@ -3,8 +3,11 @@ package identity
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/terraform-provider-azapi/internal/services/parse"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -21,6 +24,8 @@ const (
 	SystemAssignedUserAssigned IdentityType = "SystemAssigned, UserAssigned"
 )
 
+const userAssignedIdentityResourceType = "Microsoft.ManagedIdentity/userAssignedIdentities"
+
 type Model struct {
 	Type        types.String `tfsdk:"type"`
 	IdentityIDs types.List   `tfsdk:"identity_ids"`
@@ -61,6 +66,8 @@ func (m Model) ObjectType() attr.Type {
 	return types.ObjectType{AttrTypes: m.AttrType()}
 }
 
+// ExpandIdentity returns the identity in the request body. The identity IDs are validated and deduplicated case-insensitively,
+// because ARM treats the IDs which only differ in casing as the same identity. The unknown IDs are skipped by the validation, e.g. in the config validation.
 func ExpandIdentity(input Model) (interface{}, error) {
 	config := map[string]interface{}{}
 	identityType := IdentityType(input.Type.ValueString())
@@ -71,31 +78,63 @@ func ExpandIdentity(input Model) (interface{}, error) {
 		if identityType != UserAssigned && identityType != SystemAssignedUserAssigned {
 			return nil, fmt.Errorf("`identity_ids` can only be specified when `type` includes `UserAssigned`")
 		}
+		seen := make(map[string]bool, len(identityIds))
 		for _, id := range identityIds {
-			userAssignedIdentities[id.(basetypes.StringValue).ValueString()] = make(map[string]interface{})
+			value, ok := id.(basetypes.StringValue)
+			if !ok || value.IsUnknown() || value.IsNull() {
+				continue
+			}
+			if err := validateUserAssignedIdentityID(value.ValueString()); err != nil {
+				return nil, fmt.Errorf("`identity_ids` contains an invalid user assigned identity ID %q: %+v", value.ValueString(), err)
+			}
+			if seen[strings.ToLower(value.ValueString())] {
+				continue
+			}
+			seen[strings.ToLower(value.ValueString())] = true
+			userAssignedIdentities[value.ValueString()] = make(map[string]interface{})
 		}
 		config["userAssignedIdentities"] = userAssignedIdentities
 	}
 	return config, nil
 }
 
+// validateUserAssignedIdentityID validates the user assigned identity ID case-insensitively, because ARM accepts the IDs in any casing,
+// e.g. the IDs with a lower-cased `resourcegroups` segment are returned by some resource providers.
+func validateUserAssignedIdentityID(input string) error {
+	id, err := arm.ParseResourceID(input)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(id.ResourceType.String(), userAssignedIdentityResourceType) {
+		return fmt.Errorf("expect the resource type %s but got %s", userAssignedIdentityResourceType, id.ResourceType.String())
+	}
+	return nil
+}
+
+// FlattenIdentity returns the identity in the response body, the identity IDs are sorted so the order is stable.
+// The identity types which aren't known by the provider, e.g. the ones introduced by the newer API versions, are preserved instead of being reported as `None`.
 func FlattenIdentity(identity interface{}) *Model {
 	if identity == nil {
 		return nil
 	}
 	if identityMap, ok := identity.(map[string]interface{}); ok {
-		identityIds := make([]attr.Value, 0)
-		if identityMap["userAssignedIdentities"] != nil {
-			userAssignedIdentities := identityMap["userAssignedIdentities"].(map[string]interface{})
+		ids := make([]string, 0)
+		if userAssignedIdentities, ok := identityMap["userAssignedIdentities"].(map[string]interface{}); ok {
 			for key := range userAssignedIdentities {
-				identityId, err := parse.UserAssignedIdentitiesID(key)
-				if err == nil {
-					identityIds = append(identityIds, basetypes.NewStringValue(identityId.ID()))
+				if identityId, err := parse.UserAssignedIdentitiesID(key); err == nil {
+					ids = append(ids, identityId.ID())
+				} else {
+					ids = append(ids, key)
 				}
 			}
 		}
+		sort.Strings(ids)
+		identityIds := make([]attr.Value, 0, len(ids))
+		for _, id := range ids {
+			identityIds = append(identityIds, basetypes.NewStringValue(id))
+		}
 
-		identityType := identityMap["type"].(string)
+		identityType, _ := identityMap["type"].(string)
 		switch {
 		case strings.Contains(identityType, ","):
 			identityType = string(SystemAssignedUserAssigned)
@@ -103,7 +142,7 @@ func FlattenIdentity(identity interface{}) *Model {
 			identityType = string(UserAssigned)
 		case strings.EqualFold(identityType, string(SystemAssigned)):
 			identityType = string(SystemAssigned)
-		default:
+		case identityType == "" || strings.EqualFold(identityType, string(None)):
 			identityType = string(None)
 		}
 
@@ -126,6 +165,20 @@ func FlattenIdentity(identity interface{}) *Model {
 	return nil
 }
 
+// SameIdentityIds returns whether the two lists contain the same identity IDs, the IDs are compared case-insensitively, and the order and the duplicates are ignored.
+func SameIdentityIds(a types.List, b types.List) bool {
+	idSet := func(input types.List) map[string]bool {
+		out := make(map[string]bool)
+		for _, element := range input.Elements() {
+			if v, ok := element.(basetypes.StringValue); ok {
+				out[strings.ToLower(v.ValueString())] = true
+			}
+		}
+		return out
+	}
+	return reflect.DeepEqual(idSet(a), idSet(b))
+}
+
 func FromList(input types.List) Model {
 	identityModel := Model{
 		Type: types.StringValue(string(None)),
@@ -1,7 +1,9 @@
 package azure
 
 import (
+	"crypto/sha256"
 	"embed"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -43,30 +45,150 @@ func GetApiVersions(resourceType string) []string {
 		return []string{}
 	}
 	res := make([]string, 0)
-	for key, value := range azureSchema.Resources {
-		if strings.EqualFold(key, resourceType) {
-			for _, v := range value.Definitions {
-				res = append(res, v.ApiVersion)
-			}
+	for _, value := range lookupResources(azureSchema, resourceType) {
+		for _, v := range value.Definitions {
+			res = append(res, v.ApiVersion)
 		}
 	}
 	sort.Strings(res)
 	return res
 }
 
-func GetResourceDefinition(resourceType, apiVersion string) (*types.ResourceType, error) {
+var resourcesByLowerType map[string][]*Resource
+var resourcesByLowerTypeOnce sync.Once
+
+// lookupResources returns the resources whose types match the resource type case-insensitively.
+// The index is built once, because the lookups are made for every resource in a plan and scanning all the resource types is expensive.
+func lookupResources(azureSchema *Schema, resourceType string) []*Resource {
+	resourcesByLowerTypeOnce.Do(func() {
+		resourcesByLowerType = make(map[string][]*Resource, len(azureSchema.Resources))
+		for key, value := range azureSchema.Resources {
+			lowerKey := strings.ToLower(key)
+			resourcesByLowerType[lowerKey] = append(resourcesByLowerType[lowerKey], value)
+		}
+	})
+	return resourcesByLowerType[strings.ToLower(resourceType)]
+}
+
+// GetChildResourceTypes returns the resource types which are the direct children of the resource type, mapped to their latest api-versions.
+// Only the child resource types which can be created by users are returned, the read-only types, which are created by the resource providers,
+// and the singleton types, whose names are fixed by the schema, e.g. blobServices/default, are skipped.
+func GetChildResourceTypes(resourceType string) map[string]string {
 	azureSchema := GetAzureSchema()
 	if azureSchema == nil {
-		return nil, fmt.Errorf("failed to load azure schema index")
+		return map[string]string{}
 	}
+	prefix := strings.ToLower(resourceType) + "/"
+	res := make(map[string]string)
 	for key, value := range azureSchema.Resources {
-		if strings.EqualFold(key, resourceType) {
+		childName, ok := strings.CutPrefix(strings.ToLower(key), prefix)
+		if !ok || childName == "" || strings.Contains(childName, "/") || len(value.Definitions) == 0 {
+			continue
+		}
+		latest := value.Definitions[0]
+		for _, v := range value.Definitions {
+			if v.ApiVersion > latest.ApiVersion {
+				latest = v
+			}
+		}
+		definition, err := latest.GetDefinition()
+		if err != nil || definition == nil || definition.IsReadOnly() || IsSingletonResourceType(definition) {
+			continue
+		}
+		res[key] = latest.ApiVersion
+	}
+	return res
+}
+
+// IsSingletonResourceType returns true if the name of the resource type is fixed by the schema, e.g. the name of blobServices is always default.
+func IsSingletonResourceType(definition *types.ResourceType) bool {
+	if definition == nil || definition.Body == nil || definition.Body.Type == nil {
+		return false
+	}
+	body, ok := (*definition.Body.Type).(*types.ObjectType)
+	if !ok {
+		return false
+	}
+	name, ok := body.Properties["name"]
+	if !ok || name.Type == nil || name.Type.Type == nil {
+		return false
+	}
+	return len(enumValues(*name.Type.Type)) != 0
+}
+
+var canonicalResourceTypes map[string]string
+var canonicalResourceTypesOnce sync.Once
+
+// GetCanonicalResourceType returns the resource type, or the resource provider namespace, in the casing of the schema index.
+// The casing of some resource types differs between api-versions, the casing used by the latest api-version is returned.
+// It returns false if neither the resource type nor the namespace is found.
+func GetCanonicalResourceType(resourceType string) (string, bool) {
+	canonicalResourceTypesOnce.Do(func() {
+		canonicalResourceTypes = make(map[string]string)
+		azureSchema := GetAzureSchema()
+		if azureSchema == nil {
+			return
+		}
+		latestApiVersions := make(map[string]string)
+		add := func(value string, apiVersion string) {
+			key := strings.ToLower(value)
+			if _, ok := canonicalResourceTypes[key]; !ok || apiVersion > latestApiVersions[key] {
+				canonicalResourceTypes[key] = value
+				latestApiVersions[key] = apiVersion
+			}
+		}
+		for key, value := range azureSchema.Resources {
+			latestApiVersion := ""
 			for _, v := range value.Definitions {
-				if v.ApiVersion == apiVersion {
-					return v.GetDefinition()
+				if v.ApiVersion > latestApiVersion {
+					latestApiVersion = v.ApiVersion
 				}
 			}
+			add(key, latestApiVersion)
+			if namespace, _, ok := strings.Cut(key, "/"); ok {
+				add(namespace, latestApiVersion)
+			}
+		}
+	})
+	v, ok := canonicalResourceTypes[strings.ToLower(resourceType)]
+	return v, ok
+}
+
+func GetResourceDefinition(resourceType, apiVersion string) (*types.ResourceType, error) {
+	azureSchema := GetAzureSchema()
+	if azureSchema == nil {
+		return nil, fmt.Errorf("failed to load azure schema index")
+	}
+	for _, value := range lookupResources(azureSchema, resourceType) {
+		for _, v := range value.Definitions {
+			if v.ApiVersion == apiVersion {
+				return v.GetDefinition()
+			}
 		}
 	}
 	return nil, fmt.Errorf("failed to find resource type %s api-version %s in azure schema index", resourceType, apiVersion)
 }
+
+// GetResourceDefinitionHash returns the SHA-256 hash of the type definitions of the resource type and api-version,
+// it changes when the embedded definitions are updated, e.g. the bicep types are upgraded.
+func GetResourceDefinitionHash(resourceType, apiVersion string) (string, error) {
+	azureSchema := GetAzureSchema()
+	if azureSchema == nil {
+		return "", fmt.Errorf("failed to load azure schema index")
+	}
+	for _, value := range lookupResources(azureSchema, resourceType) {
+		for _, v := range value.Definitions {
+			if v.ApiVersion == apiVersion {
+				data, err := StaticFiles.ReadFile("generated/" + v.Location.Location)
+				if err != nil {
+					return "", err
+				}
+				hash := sha256.New()
+				hash.Write(data)
+				hash.Write([]byte(fmt.Sprintf("#/%d", v.Location.Index)))
+				return hex.EncodeToString(hash.Sum(nil)), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("failed to find resource type %s api-version %s in azure schema index", resourceType, apiVersion)
+}
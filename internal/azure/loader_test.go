@@ -1,6 +1,7 @@
 package azure_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/Azure/terraform-provider-azapi/internal/azure"
@@ -24,6 +25,46 @@ func Test_GetApiVersions(t *testing.T) {
 	}
 }
 
+func Test_GetChildResourceTypes(t *testing.T) {
+	childTypes := azure.GetChildResourceTypes("Microsoft.MachineLearningServices/workspaces")
+	if _, ok := childTypes["Microsoft.MachineLearningServices/workspaces/computes"]; !ok {
+		t.Errorf("expect Microsoft.MachineLearningServices/workspaces/computes to be a child type of Microsoft.MachineLearningServices/workspaces")
+	}
+	for childType, apiVersion := range childTypes {
+		if strings.Count(childType, "/") != 2 {
+			t.Errorf("expect only the direct child types but got %s", childType)
+		}
+		if apiVersion == "" {
+			t.Errorf("expect the latest api-version of %s but got empty", childType)
+		}
+	}
+
+	childTypes = azure.GetChildResourceTypes("Microsoft.Storage/storageAccounts")
+	if _, ok := childTypes["Microsoft.Storage/storageAccounts/encryptionScopes"]; !ok {
+		t.Errorf("expect Microsoft.Storage/storageAccounts/encryptionScopes to be a child type of Microsoft.Storage/storageAccounts")
+	}
+	for _, childType := range []string{"Microsoft.Storage/storageAccounts/blobServices", "Microsoft.Storage/storageAccounts/networkSecurityPerimeterConfigurations"} {
+		if _, ok := childTypes[childType]; ok {
+			t.Errorf("expect %s not to be a child type which can be created by users", childType)
+		}
+	}
+}
+
+func Test_GetCanonicalResourceType(t *testing.T) {
+	testcases := map[string]string{
+		"microsoft.sql/SERVERS/databases": "Microsoft.Sql/servers/databases",
+		"MICROSOFT.SQL":                   "Microsoft.Sql",
+	}
+	for input, expect := range testcases {
+		if actual, ok := azure.GetCanonicalResourceType(input); !ok || actual != expect {
+			t.Errorf("expect %s but got %s for %s", expect, actual, input)
+		}
+	}
+	if _, ok := azure.GetCanonicalResourceType("Microsoft.Foo/bars"); ok {
+		t.Errorf("expect Microsoft.Foo/bars not to be found")
+	}
+}
+
 func Test_GetResourceDefinition(t *testing.T) {
 	case1 := "Microsoft.MachineLearningServices/workspaces/computes"
 	versions := azure.GetApiVersions(case1)
@@ -64,3 +105,23 @@ func Test_AllBicepTypes(t *testing.T) {
 		}
 	}
 }
+
+func Test_GetResourceDefinitionHash(t *testing.T) {
+	hash, err := azure.GetResourceDefinitionHash("Microsoft.Resources/resourceGroups", "2021-04-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hash) != 64 {
+		t.Errorf("expect a SHA-256 hash, got %q", hash)
+	}
+	other, err := azure.GetResourceDefinitionHash("Microsoft.Resources/resourceGroups", "2022-09-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash == other {
+		t.Errorf("expect different hashes for different api-versions, got %q", hash)
+	}
+	if _, err := azure.GetResourceDefinitionHash("Microsoft.Resources/resourceGroups", "1900-01-01"); err == nil {
+		t.Error("expect an error for the unknown api-version")
+	}
+}
@@ -0,0 +1,76 @@
+package azure
+
+import (
+	"github.com/Azure/terraform-provider-azapi/internal/azure/types"
+)
+
+// StripReadOnlyProperties returns a copy of the body without the properties which are read-only in the resource definition, e.g. the body
+// copied from the response of a GET request. Unlike GetWriteOnly, the properties which are not in the definition are kept, and so are the
+// read-only properties which are also required.
+func StripReadOnlyProperties(resourceDef *types.ResourceType, body interface{}) interface{} {
+	if resourceDef == nil || resourceDef.Body == nil {
+		return body
+	}
+	return stripReadOnly(resourceDef.Body.Type, body)
+}
+
+func stripReadOnly(t *types.TypeBase, body interface{}) interface{} {
+	if t == nil || *t == nil || body == nil {
+		return body
+	}
+	switch v := (*t).(type) {
+	case *types.ObjectType:
+		bodyMap, ok := body.(map[string]interface{})
+		if !ok {
+			return body
+		}
+		var additionalProperties *types.TypeBase
+		if v.AdditionalProperties != nil {
+			additionalProperties = v.AdditionalProperties.Type
+		}
+		return stripReadOnlyProperties(v.Properties, additionalProperties, bodyMap)
+	case *types.DiscriminatedObjectType:
+		bodyMap, ok := body.(map[string]interface{})
+		if !ok {
+			return body
+		}
+		res := stripReadOnlyProperties(v.BaseProperties, nil, bodyMap)
+		if discriminator, ok := bodyMap[v.Discriminator].(string); ok {
+			if element := v.Elements[discriminator]; element != nil {
+				res = stripReadOnly(element.Type, res).(map[string]interface{})
+			}
+		}
+		return res
+	case *types.ArrayType:
+		bodyArray, ok := body.([]interface{})
+		if !ok || v.ItemType == nil {
+			return body
+		}
+		res := make([]interface{}, 0, len(bodyArray))
+		for _, item := range bodyArray {
+			res = append(res, stripReadOnly(v.ItemType.Type, item))
+		}
+		return res
+	}
+	return body
+}
+
+func stripReadOnlyProperties(properties map[string]types.ObjectProperty, additionalProperties *types.TypeBase, bodyMap map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(bodyMap))
+	for key, value := range bodyMap {
+		def, ok := properties[key]
+		switch {
+		case !ok && additionalProperties != nil:
+			res[key] = stripReadOnly(additionalProperties, value)
+		case !ok:
+			res[key] = value
+		case def.IsReadOnly() && !def.IsRequired():
+			continue
+		case def.Type != nil:
+			res[key] = stripReadOnly(def.Type.Type, value)
+		default:
+			res[key] = value
+		}
+	}
+	return res
+}
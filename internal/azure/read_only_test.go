@@ -0,0 +1,55 @@
+package azure_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/Azure/terraform-provider-azapi/internal/azure"
+)
+
+func Test_StripReadOnlyProperties(t *testing.T) {
+	resourceDef, err := azure.GetResourceDefinition("Microsoft.Storage/storageAccounts", "2023-01-01")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var body interface{}
+	_ = json.Unmarshal([]byte(`{
+  "id": "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Storage/storageAccounts/sa1",
+  "name": "sa1",
+  "type": "Microsoft.Storage/storageAccounts",
+  "kind": "StorageV2",
+  "sku": {"name": "Standard_LRS", "tier": "Standard"},
+  "properties": {
+    "provisioningState": "Succeeded",
+    "creationTime": "2024-01-01T00:00:00Z",
+    "minimumTlsVersion": "TLS1_2",
+    "primaryEndpoints": {"blob": "https://sa1.blob.core.windows.net/"},
+    "networkAcls": {"defaultAction": "Deny", "ipRules": [{"value": "1.1.1.1", "action": "Allow"}]},
+    "unknownProperty": "value"
+  }
+}`), &body)
+
+	var expected interface{}
+	_ = json.Unmarshal([]byte(`{
+  "name": "sa1",
+  "kind": "StorageV2",
+  "sku": {"name": "Standard_LRS"},
+  "properties": {
+    "minimumTlsVersion": "TLS1_2",
+    "networkAcls": {"defaultAction": "Deny", "ipRules": [{"value": "1.1.1.1", "action": "Allow"}]},
+    "unknownProperty": "value"
+  }
+}`), &expected)
+
+	actual := azure.StripReadOnlyProperties(resourceDef, body)
+	if !reflect.DeepEqual(actual, expected) {
+		actualJson, _ := json.Marshal(actual)
+		t.Fatalf("expect %v, got %s", expected, actualJson)
+	}
+
+	if actual := azure.StripReadOnlyProperties(nil, body); !reflect.DeepEqual(actual, body) {
+		t.Fatalf("expect the body to be kept without the resource definition, got %v", actual)
+	}
+}
@@ -0,0 +1,20 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceManagerAccountWithoutAzureCLI(t *testing.T) {
+	// the defaults can't be loaded from the Azure CLI
+	t.Setenv("PATH", "")
+
+	account := NewResourceManagerAccount("", "")
+	assert.Equal(t, "", account.GetTenantId())
+	assert.Equal(t, "", account.GetSubscriptionId())
+
+	account = NewResourceManagerAccount("00000000-0000-0000-0000-000000000001", "00000000-0000-0000-0000-000000000002")
+	assert.Equal(t, "00000000-0000-0000-0000-000000000001", account.GetTenantId())
+	assert.Equal(t, "00000000-0000-0000-0000-000000000002", account.GetSubscriptionId())
+}
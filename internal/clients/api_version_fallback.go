@@ -0,0 +1,144 @@
+package clients
+
+import (
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+)
+
+// apiVersionFallbackErrorCodes are the error codes of the requests whose api-version isn't supported, their messages list the supported api-versions.
+var apiVersionFallbackErrorCodes = []string{"NoRegisteredProviderFound", "InvalidApiVersionParameter", "InvalidResourceType"}
+
+var apiVersionRegex = regexp.MustCompile(`\d{4}-\d{2}-\d{2}(-[a-zA-Z]+)?`)
+
+// apiVersionFallbacks records the supported api-versions which the requests fall back to, keyed by the resource type and the unsupported api-version,
+// so the following requests of the same resource type are sent with the supported api-version directly.
+type apiVersionFallbacks struct {
+	mutex    sync.Mutex
+	versions map[string]string
+}
+
+func newApiVersionFallbacks() *apiVersionFallbacks {
+	return &apiVersionFallbacks{
+		versions: make(map[string]string),
+	}
+}
+
+func apiVersionFallbackKey(resourceID string, apiVersion string) string {
+	key := resourceID
+	if id, err := arm.ParseResourceID(resourceID); err == nil {
+		key = id.ResourceType.String()
+	}
+	return strings.ToLower(key + "@" + apiVersion)
+}
+
+// ApiVersionFallback returns the supported api-version which the requests of the resource fall back to, it returns false if the api-version is supported
+// or the fallback is disabled.
+func (client *ResourceClient) ApiVersionFallback(resourceID string, apiVersion string) (string, bool) {
+	if client.apiVersionFallbacks == nil {
+		return "", false
+	}
+	client.apiVersionFallbacks.mutex.Lock()
+	defer client.apiVersionFallbacks.mutex.Unlock()
+	v, ok := client.apiVersionFallbacks.versions[apiVersionFallbackKey(resourceID, apiVersion)]
+	return v, ok
+}
+
+// resolveApiVersion returns the api-version which the requests of the resource are sent with.
+func (client *ResourceClient) resolveApiVersion(resourceID string, apiVersion string) string {
+	if v, ok := client.apiVersionOverride(resourceID); ok {
+		return v
+	}
+	if v, ok := client.ApiVersionFallback(resourceID, apiVersion); ok {
+		return v
+	}
+	return apiVersion
+}
+
+// fallbackApiVersion records the nearest supported api-version if the request failed because the api-version isn't supported,
+// it returns true if the request should be sent again with the supported api-version.
+func (client *ResourceClient) fallbackApiVersion(resourceID string, apiVersion string, err error) bool {
+	if client.apiVersionFallbacks == nil {
+		return false
+	}
+	if _, ok := client.apiVersionOverride(resourceID); ok {
+		// the pinned api-version is sent as it is
+		return false
+	}
+	if _, ok := client.ApiVersionFallback(resourceID, apiVersion); ok {
+		// the request has already been sent with the supported api-version
+		return false
+	}
+	fallback, ok := nearestSupportedApiVersion(err, apiVersion)
+	if !ok {
+		return false
+	}
+	log.Printf("[WARN] The api-version %s of %s is not supported, falling back to the nearest supported api-version %s", apiVersion, resourceID, fallback)
+	client.apiVersionFallbacks.mutex.Lock()
+	defer client.apiVersionFallbacks.mutex.Unlock()
+	client.apiVersionFallbacks.versions[apiVersionFallbackKey(resourceID, apiVersion)] = fallback
+	return true
+}
+
+// nearestSupportedApiVersion returns the supported api-version in the error which is nearest to the api-version, the api-versions of the same
+// stability are preferred, i.e. a stable api-version falls back to a stable api-version if there's any, and the newer one wins the tie.
+func nearestSupportedApiVersion(err error, apiVersion string) (string, bool) {
+	var responseErr *azcore.ResponseError
+	if !errors.As(err, &responseErr) {
+		return "", false
+	}
+	matched := false
+	for _, code := range apiVersionFallbackErrorCodes {
+		if strings.EqualFold(responseErr.ErrorCode, code) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return "", false
+	}
+
+	// the supported api-versions are listed after the requested api-version in the message, e.g. `The supported api-versions are '2021-01-01, 2022-01-01'`
+	message := responseErr.Error()
+	index := strings.Index(strings.ToLower(message), "supported")
+	if index == -1 {
+		return "", false
+	}
+	supported := apiVersionRegex.FindAllString(message[index:], -1)
+
+	requestedDate, err := time.Parse(time.DateOnly, apiVersion[:min(len(apiVersion), len(time.DateOnly))])
+	if err != nil {
+		return "", false
+	}
+	isPreview := strings.Contains(apiVersion, "-preview")
+
+	nearest, nearestDistance, nearestSameStability := "", time.Duration(0), false
+	for _, v := range supported {
+		if strings.EqualFold(v, apiVersion) {
+			continue
+		}
+		date, err := time.Parse(time.DateOnly, v[:len(time.DateOnly)])
+		if err != nil {
+			continue
+		}
+		distance := date.Sub(requestedDate)
+		if distance < 0 {
+			distance = -distance
+		}
+		sameStability := strings.Contains(v, "-preview") == isPreview
+		switch {
+		case nearest == "",
+			sameStability && !nearestSameStability,
+			sameStability == nearestSameStability && distance < nearestDistance,
+			sameStability == nearestSameStability && distance == nearestDistance && v > nearest:
+			nearest, nearestDistance, nearestSameStability = v, distance, sameStability
+		}
+	}
+	return nearest, nearest != ""
+}
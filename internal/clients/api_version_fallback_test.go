@@ -0,0 +1,113 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNearestSupportedApiVersion(t *testing.T) {
+	testcases := []struct {
+		ApiVersion string
+		Supported  string
+		Expect     string
+	}{
+		{
+			ApiVersion: "2023-01-01",
+			Supported:  "2021-01-01, 2022-12-01-preview, 2022-10-01, 2023-06-01",
+			Expect:     "2022-10-01",
+		},
+		{
+			ApiVersion: "2023-01-01-preview",
+			Supported:  "2021-01-01, 2022-12-01-preview, 2022-10-01, 2023-06-01",
+			Expect:     "2022-12-01-preview",
+		},
+		{
+			ApiVersion: "2023-01-01",
+			Supported:  "2022-12-01-preview",
+			Expect:     "2022-12-01-preview",
+		},
+		{
+			// the newer api-version wins the tie
+			ApiVersion: "2023-01-01",
+			Supported:  "2022-12-01, 2023-01-31",
+			Expect:     "2023-01-31",
+		},
+	}
+	for _, testcase := range testcases {
+		err := newResponseError(newApiVersionErrorResponse(testcase.ApiVersion, testcase.Supported))
+		actual, ok := nearestSupportedApiVersion(err, testcase.ApiVersion)
+		assert.True(t, ok, testcase.ApiVersion)
+		assert.Equal(t, testcase.Expect, actual, testcase.ApiVersion)
+	}
+
+	resp := newApiVersionErrorResponse("2023-01-01", "2022-10-01")
+	resp.StatusCode = http.StatusNotFound
+	resp.Body = http.NoBody
+	_, ok := nearestSupportedApiVersion(newResponseError(resp), "2023-01-01")
+	assert.False(t, ok)
+}
+
+func newApiVersionErrorResponse(apiVersion string, supported string) *http.Response {
+	req, _ := http.NewRequest(http.MethodGet, "https://management.azure.com/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1?api-version="+apiVersion, nil)
+	recorder := httptest.NewRecorder()
+	recorder.Header().Set("Content-Type", "application/json")
+	recorder.WriteHeader(http.StatusBadRequest)
+	_, _ = recorder.WriteString(`{"error":{"code":"InvalidApiVersionParameter","message":"The api-version '` + apiVersion + `' is invalid. The supported versions are '` + supported + `'."}}`)
+	resp := recorder.Result()
+	resp.Request = req
+	return resp
+}
+
+func TestResourceClientApiVersionFallback(t *testing.T) {
+	const resourceID = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Storage/storageAccounts/account1"
+	var apiVersions []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiVersion := r.URL.Query().Get("api-version")
+		apiVersions = append(apiVersions, apiVersion)
+		w.Header().Set("Content-Type", "application/json")
+		if apiVersion != "2023-05-01" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"code":"NoRegisteredProviderFound","message":"No registered resource provider found for location 'westus' and API version '` + apiVersion + `' for type 'storageAccounts'. The supported api-versions are '2022-09-01, 2023-05-01'."}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"name":"account1"}`))
+	}))
+	defer server.Close()
+
+	newClient := func(apiVersionFallbacks *apiVersionFallbacks) *ResourceClient {
+		return &ResourceClient{
+			host: server.URL,
+			pl: runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+				Transport: server.Client(),
+				Retry: policy.RetryOptions{
+					MaxRetries: -1,
+				},
+			}),
+			apiVersionFallbacks: apiVersionFallbacks,
+		}
+	}
+
+	// the requests fail if the fallback is disabled
+	_, err := newClient(nil).Get(context.Background(), resourceID, "2099-01-01", DefaultRequestOptions())
+	assert.ErrorContains(t, err, "NoRegisteredProviderFound")
+
+	apiVersions = nil
+	client := newClient(newApiVersionFallbacks())
+	output, err := client.Get(context.Background(), resourceID, "2099-01-01", DefaultRequestOptions())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "account1"}, output)
+	fallback, ok := client.ApiVersionFallback(resourceID, "2099-01-01")
+	assert.True(t, ok)
+	assert.Equal(t, "2023-05-01", fallback)
+
+	// the following requests are sent with the supported api-version directly
+	_, err = client.CreateOrUpdate(context.Background(), resourceID, "2099-01-01", map[string]interface{}{}, DefaultRequestOptions())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2099-01-01", "2023-05-01", "2023-05-01"}, apiVersions)
+}
@@ -0,0 +1,33 @@
+package clients
+
+import (
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+)
+
+// newApiVersionOverrides returns the pinned api-versions keyed by the lower-cased resource types, it's nil if there's no override.
+func newApiVersionOverrides(overrides map[string]string) map[string]string {
+	if len(overrides) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(overrides))
+	for resourceType, apiVersion := range overrides {
+		out[strings.ToLower(resourceType)] = apiVersion
+	}
+	return out
+}
+
+// apiVersionOverride returns the api-version which is pinned for the resource type of the resource in the provider configuration,
+// it takes precedence over the api-version which the configuration declares.
+func (client *ResourceClient) apiVersionOverride(resourceID string) (string, bool) {
+	if len(client.apiVersionOverrides) == 0 {
+		return "", false
+	}
+	id, err := arm.ParseResourceID(resourceID)
+	if err != nil {
+		return "", false
+	}
+	v, ok := client.apiVersionOverrides[strings.ToLower(id.ResourceType.String())]
+	return v, ok
+}
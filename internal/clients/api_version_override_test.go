@@ -0,0 +1,54 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceClientApiVersionOverrides(t *testing.T) {
+	const accountID = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Storage/storageAccounts/account1"
+	const vnetID = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1"
+	var apiVersions []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiVersions = append(apiVersions, r.URL.Query().Get("api-version"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"test"}`))
+	}))
+	defer server.Close()
+
+	client := &ResourceClient{
+		host: server.URL,
+		pl: runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+			Transport: server.Client(),
+			Retry: policy.RetryOptions{
+				MaxRetries: -1,
+			},
+		}),
+		apiVersionFallbacks: newApiVersionFallbacks(),
+		apiVersionOverrides: newApiVersionOverrides(map[string]string{
+			"microsoft.storage/storageAccounts": "2022-09-01",
+		}),
+	}
+
+	_, err := client.Get(context.Background(), accountID, "2023-05-01", DefaultRequestOptions())
+	assert.NoError(t, err)
+	_, err = client.CreateOrUpdate(context.Background(), accountID, "2023-05-01", map[string]interface{}{}, DefaultRequestOptions())
+	assert.NoError(t, err)
+	_, err = client.Action(context.Background(), accountID, "listKeys", "2023-05-01", http.MethodPost, nil, DefaultRequestOptions())
+	assert.NoError(t, err)
+	exists, err := client.CheckExistence(context.Background(), accountID, "2023-05-01", DefaultRequestOptions())
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	// the resource types which aren't pinned are sent with the api-versions in the configurations
+	_, err = client.Get(context.Background(), vnetID, "2023-04-01", DefaultRequestOptions())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2022-09-01", "2022-09-01", "2022-09-01", "2022-09-01", "2023-04-01"}, apiVersions)
+
+	assert.Nil(t, newApiVersionOverrides(nil))
+}
@@ -0,0 +1,128 @@
+package clients
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+type auditLogPolicy struct {
+	mutex sync.Mutex
+	file  *os.File
+}
+
+type auditRecord struct {
+	Timestamp     string `json:"timestamp"`
+	Principal     string `json:"principal"`
+	Method        string `json:"method"`
+	Url           string `json:"url"`
+	StatusCode    int    `json:"status"`
+	CorrelationId string `json:"correlation_id"`
+	Error         string `json:"error,omitempty"`
+}
+
+// NewAuditLogPolicy returns a policy which appends a newline-delimited JSON record of every mutating request to the file.
+func NewAuditLogPolicy(filename string) (policy.Policy, error) {
+	file, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening the audit log file %q: %+v", filename, err)
+	}
+	return &auditLogPolicy{
+		file: file,
+	}, nil
+}
+
+func (p *auditLogPolicy) Do(req *policy.Request) (*http.Response, error) {
+	rawRequest := req.Raw()
+	if rawRequest.Method == http.MethodGet || rawRequest.Method == http.MethodHead {
+		return req.Next()
+	}
+
+	response, err := req.Next()
+
+	record := auditRecord{
+		Timestamp:     time.Now().UTC().Format(time.RFC3339Nano),
+		Principal:     principalFromAuthorization(rawRequest.Header.Get("Authorization")),
+		Method:        rawRequest.Method,
+		Url:           redactedRequestUrl(rawRequest.URL),
+		CorrelationId: rawRequest.Header.Get(HeaderCorrelationRequestID),
+	}
+	if response != nil {
+		record.StatusCode = response.StatusCode
+		if v := response.Header.Get(HeaderCorrelationRequestID); v != "" {
+			record.CorrelationId = v
+		}
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	p.write(record)
+	return response, err
+}
+
+// redactedRequestUrl returns the URL without the query except the api-version, because the query may contain the credentials, e.g. the SAS token.
+func redactedRequestUrl(u *url.URL) string {
+	requestUrl := fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, u.Path)
+	if apiVersion := u.Query().Get("api-version"); apiVersion != "" {
+		requestUrl += "?api-version=" + apiVersion
+	}
+	return requestUrl
+}
+
+func (p *auditLogPolicy) write(record auditRecord) {
+	line, err := json.Marshal(record)
+	if err != nil {
+		log.Printf("[WARN] Failed to marshal the audit record: %+v", err)
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if _, err := p.file.Write(append(line, '\n')); err != nil {
+		log.Printf("[WARN] Failed to write the audit record: %+v", err)
+	}
+}
+
+// principalFromAuthorization returns the principal of the bearer token, it doesn't verify the token because it's only used for auditing.
+func principalFromAuthorization(authorization string) string {
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok {
+		return ""
+	}
+	return tokenClaim(tokenClaims(token), "upn", "unique_name", "appid", "azp", "oid")
+}
+
+// tokenClaims returns the claims of the JWT access token without verifying it, it returns nil if the token is invalid.
+func tokenClaims(token string) map[string]interface{} {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil
+	}
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil
+	}
+	return claims
+}
+
+// tokenClaim returns the value of the first claim which is a non-empty string.
+func tokenClaim(claims map[string]interface{}, names ...string) string {
+	for _, name := range names {
+		if v, ok := claims[name].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
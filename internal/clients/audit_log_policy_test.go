@@ -0,0 +1,57 @@
+package clients
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLogPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(HeaderCorrelationRequestID, "00000000-0000-0000-0000-000000000001")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	filename := filepath.Join(t.TempDir(), "audit.log")
+	auditPolicy, err := NewAuditLogPolicy(filename)
+	assert.NoError(t, err)
+
+	claims := base64.RawURLEncoding.EncodeToString([]byte(`{"appid":"11111111-1111-1111-1111-111111111111"}`))
+	pl := runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		Transport:        server.Client(),
+		PerRetryPolicies: []policy.Policy{auditPolicy},
+	})
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		req, err := runtime.NewRequest(context.Background(), method, server.URL+"/resource?api-version=2024-01-01&sig=secret")
+		assert.NoError(t, err)
+		req.Raw().Header.Set("Authorization", "Bearer header."+claims+".signature")
+		_, err = pl.Do(req)
+		assert.NoError(t, err)
+	}
+
+	content, err := os.ReadFile(filename)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Len(t, lines, 2)
+	for i, method := range []string{http.MethodPut, http.MethodDelete} {
+		var record auditRecord
+		assert.NoError(t, json.Unmarshal([]byte(lines[i]), &record))
+		assert.Equal(t, method, record.Method)
+		assert.Equal(t, server.URL+"/resource?api-version=2024-01-01", record.Url)
+		assert.Equal(t, http.StatusCreated, record.StatusCode)
+		assert.Equal(t, "11111111-1111-1111-1111-111111111111", record.Principal)
+		assert.Equal(t, "00000000-0000-0000-0000-000000000001", record.CorrelationId)
+		assert.NotEmpty(t, record.Timestamp)
+	}
+}
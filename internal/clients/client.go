@@ -11,6 +11,7 @@ import (
 	azlog "github.com/Azure/azure-sdk-for-go/sdk/azcore/log"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/terraform-provider-azapi/internal/features"
+	"github.com/Azure/terraform-provider-azapi/internal/guardrail"
 )
 
 type Client struct {
@@ -23,18 +24,53 @@ type Client struct {
 	DataPlaneClient *DataPlaneClient
 
 	Account ResourceManagerAccount
+
+	// Environment is the lower-cased cloud environment, e.g. `public`, `usgovernment` or `china`
+	Environment string
+
+	// CorrelationRequestID is the `x-ms-correlation-request-id` header of the requests, it's empty if the header is disabled
+	CorrelationRequestID string
+
+	// Guardrails is the policy bundle which is evaluated against the planned bodies, it's nil if it's not configured
+	Guardrails *guardrail.Bundle
 }
 
 type Option struct {
-	Cred                        azcore.TokenCredential
-	ApplicationUserAgent        string
-	Features                    features.UserFeatures
-	SkipProviderRegistration    bool
-	DisableCorrelationRequestID bool
-	CloudCfg                    cloud.Configuration
-	CustomCorrelationRequestID  string
-	SubscriptionId              string
-	TenantId                    string
+	Cred                             azcore.TokenCredential
+	ApplicationUserAgent             string
+	Features                         features.UserFeatures
+	SkipProviderRegistration         bool
+	DisableCorrelationRequestID      bool
+	CloudCfg                         cloud.Configuration
+	SecondaryResourceManagerEndpoint string
+	Environment                      string
+	CustomCorrelationRequestID       string
+	SubscriptionId                   string
+	TenantId                         string
+	ManagingTenantId                 string
+	CancellationBehavior             CancellationBehavior
+	ApiVersionFallback               bool
+	AuditLogFile                     string
+	PreRequestHook                   string
+	PolicyBundle                     string
+	WebhookUrl                       string
+	WebhookSecret                    string
+	DataSourceCacheDir               string
+	DataSourceCacheTTL               time.Duration
+	// AuxiliaryTenantIds are the tenants whose tokens are sent in the `x-ms-authorization-auxiliary` header of the resource manager requests,
+	// which are required by the cross-tenant resources, e.g. the virtual network peerings across tenants
+	AuxiliaryTenantIds []string
+	// ApiVersionOverrides are the api-versions keyed by the resource types which the requests are sent with, regardless of the api-versions
+	// in the configurations
+	ApiVersionOverrides map[string]string
+	// Retry is the retry policy of the requests, its zero values mean the defaults of the SDK
+	Retry policy.RetryOptions
+	// Transport sends the HTTP requests, the default HTTP client of the SDK is used if it's nil
+	Transport policy.Transporter
+	// ManagedIdentityCredential builds the credential of the managed identity with the client ID, which is used by the data plane resources
+	// whose `use_msi` is `true`, the credential of the system-assigned identity is built with an empty client ID. The credentials are built
+	// with the default options of the SDK if it's nil.
+	ManagedIdentityCredential func(clientId string) (azcore.TokenCredential, error)
 }
 
 // NOTE: it should be possible for this method to become Private once the top level Client's removed
@@ -49,15 +85,59 @@ func (client *Client) Build(ctx context.Context, o *Option) error {
 
 	perCallPolicies := make([]policy.Policy, 0)
 	perCallPolicies = append(perCallPolicies, withUserAgent(o.ApplicationUserAgent))
+	perCallPolicies = append(perCallPolicies, DeprecationPolicy{})
+	perCallPolicies = append(perCallPolicies, NewRequestMetricsPolicy())
 	if !o.DisableCorrelationRequestID {
 		id := o.CustomCorrelationRequestID
 		if id == "" {
 			id = correlationRequestID()
 		}
 		perCallPolicies = append(perCallPolicies, withCorrelationRequestID(id))
+		client.CorrelationRequestID = id
+	}
+	if o.PreRequestHook != "" {
+		preRequestHookPolicy, err := NewPreRequestHookPolicy(o.PreRequestHook)
+		if err != nil {
+			return err
+		}
+		perCallPolicies = append(perCallPolicies, preRequestHookPolicy)
 	}
+	if o.WebhookUrl != "" {
+		webhookPolicy, err := NewWebhookPolicy(o.WebhookUrl, o.WebhookSecret)
+		if err != nil {
+			return err
+		}
+		perCallPolicies = append(perCallPolicies, webhookPolicy)
+	}
+	if o.PolicyBundle != "" {
+		bundle, err := guardrail.Load(o.PolicyBundle)
+		if err != nil {
+			return err
+		}
+		client.Guardrails = bundle
+	}
+
 	perRetryPolicies := make([]policy.Policy, 0)
+	if o.SecondaryResourceManagerEndpoint != "" {
+		// the failover policy is the first one, so the other policies see the endpoint which the request is sent to
+		endpointFailoverPolicy, err := NewEndpointFailoverPolicy(o.CloudCfg.Services[cloud.ResourceManager].Endpoint, o.SecondaryResourceManagerEndpoint)
+		if err != nil {
+			return err
+		}
+		perRetryPolicies = append(perRetryPolicies, endpointFailoverPolicy)
+	}
 	perRetryPolicies = append(perRetryPolicies, NewLiveTrafficLogPolicy())
+	perRetryPolicies = append(perRetryPolicies, NewResourceProviderThrottlingPolicy())
+	// the request metrics policy follows the throttling policy, so the requests which are held back aren't counted as in-flight
+	perRetryPolicies = append(perRetryPolicies, NewRequestMetricsRetryPolicy())
+	perRetryPolicies = append(perRetryPolicies, NewLighthousePolicy(o.ManagingTenantId))
+	if o.AuditLogFile != "" {
+		auditLogPolicy, err := NewAuditLogPolicy(o.AuditLogFile)
+		if err != nil {
+			return err
+		}
+		perRetryPolicies = append(perRetryPolicies, auditLogPolicy)
+	}
 
 	allowedHeaders := []string{
 		"Access-Control-Allow-Methods",
@@ -87,6 +167,16 @@ func (client *Client) Build(ctx context.Context, o *Option) error {
 		"$skipToken",
 	}
 
+	// the responses are only cached for the resource manager APIs, because the URLs of the data plane APIs may contain the credentials, e.g. the SAS tokens
+	resourceClientPerCallPolicies := perCallPolicies
+	if o.DataSourceCacheDir != "" {
+		responseCachePolicy, err := NewResponseCachePolicy(o.DataSourceCacheDir, o.DataSourceCacheTTL, o.Cred, []string{o.CloudCfg.Services[cloud.ResourceManager].Audience + "/.default"})
+		if err != nil {
+			return err
+		}
+		resourceClientPerCallPolicies = append(append(make([]policy.Policy, 0, len(perCallPolicies)+1), perCallPolicies...), responseCachePolicy)
+	}
+
 	resourceClient, err := NewResourceClient(o.Cred, &arm.ClientOptions{
 		ClientOptions: policy.ClientOptions{
 			Cloud: o.CloudCfg,
@@ -99,14 +189,22 @@ func (client *Client) Build(ctx context.Context, o *Option) error {
 				AllowedHeaders:     allowedHeaders,
 				AllowedQueryParams: allowedQueryParams,
 			},
-			PerCallPolicies:  perCallPolicies,
+			PerCallPolicies:  resourceClientPerCallPolicies,
 			PerRetryPolicies: perRetryPolicies,
+			Retry:            o.Retry,
+			Transport:        o.Transport,
 		},
+		AuxiliaryTenants:      o.AuxiliaryTenantIds,
 		DisableRPRegistration: o.SkipProviderRegistration,
 	})
 	if err != nil {
 		return err
 	}
+	resourceClient.cancellationBehavior = o.CancellationBehavior
+	if o.ApiVersionFallback {
+		resourceClient.apiVersionFallbacks = newApiVersionFallbacks()
+	}
+	resourceClient.apiVersionOverrides = newApiVersionOverrides(o.ApiVersionOverrides)
 	client.ResourceClient = resourceClient
 
 	dataPlaneClient, err := NewDataPlaneClient(o.Cred, &arm.ClientOptions{
@@ -123,15 +221,19 @@ func (client *Client) Build(ctx context.Context, o *Option) error {
 			},
 			PerCallPolicies:  perCallPolicies,
 			PerRetryPolicies: perRetryPolicies,
+			Retry:            o.Retry,
+			Transport:        o.Transport,
 		},
 		DisableRPRegistration: o.SkipProviderRegistration,
 	})
 	if err != nil {
 		return err
 	}
+	dataPlaneClient.newManagedIdentityCredential = o.ManagedIdentityCredential
 	client.DataPlaneClient = dataPlaneClient
 
 	client.Account = NewResourceManagerAccount(o.TenantId, o.SubscriptionId)
+	client.Environment = o.Environment
 
 	return nil
 }
@@ -0,0 +1,159 @@
+package clients
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// DataPlaneAuthentication is used to authenticate the data plane requests without the credentials of the provider.
+// Only one of the SasToken, the AccountKey and the UseManagedIdentity should be specified.
+type DataPlaneAuthentication struct {
+	// SasToken is the shared access signature which is appended to the query of the requests.
+	SasToken string
+	// AccountKey is the storage account key which is used to sign the requests with the Shared Key authorization.
+	AccountKey string
+	// UseManagedIdentity authenticates the requests with the Azure Active Directory tokens of a managed identity,
+	// e.g. when the data plane roles are assigned to a different identity than the one of the provider.
+	UseManagedIdentity bool
+	// ManagedIdentityClientId is the client ID of the user-assigned managed identity, the system-assigned managed identity is used if it's empty.
+	ManagedIdentityClientId string
+}
+
+// WithDataPlaneAuthentication returns a copy of the options with the data plane authentication.
+func (o RequestOptions) WithDataPlaneAuthentication(auth *DataPlaneAuthentication) RequestOptions {
+	o.DataPlaneAuthentication = auth
+	return o
+}
+
+func (client *DataPlaneClient) pipeline(rawUrl string, options RequestOptions) (runtime.Pipeline, error) {
+	auth := options.DataPlaneAuthentication
+	if auth == nil {
+		return client.cachedPipeline(rawUrl)
+	}
+
+	if auth.UseManagedIdentity {
+		return client.managedIdentityPipeline(rawUrl, auth.ManagedIdentityClientId)
+	}
+
+	plOpt := runtime.PipelineOptions{}
+	plOpt.APIVersion.Name = "api-version"
+	switch {
+	case auth.SasToken != "":
+		query, err := url.ParseQuery(strings.TrimPrefix(auth.SasToken, "?"))
+		if err != nil {
+			return runtime.Pipeline{}, fmt.Errorf("parsing the SAS token: %+v", err)
+		}
+		plOpt.PerRetry = append(plOpt.PerRetry, &sasTokenPolicy{query: query})
+	case auth.AccountKey != "":
+		key, err := base64.StdEncoding.DecodeString(auth.AccountKey)
+		if err != nil {
+			return runtime.Pipeline{}, fmt.Errorf("decoding the account key: %+v", err)
+		}
+		plOpt.PerRetry = append(plOpt.PerRetry, &sharedKeyPolicy{key: key})
+	default:
+		return runtime.Pipeline{}, fmt.Errorf("either the SAS token, the account key or the managed identity must be specified")
+	}
+	return runtime.NewPipeline(moduleName, moduleVersion, plOpt, &client.clientOptions.ClientOptions), nil
+}
+
+// sasTokenPolicy appends the shared access signature to the query of the requests.
+type sasTokenPolicy struct {
+	query url.Values
+}
+
+func (p *sasTokenPolicy) Do(req *policy.Request) (*http.Response, error) {
+	reqQP := req.Raw().URL.Query()
+	// the polling URLs may have already been signed
+	if reqQP.Get("sig") == "" {
+		for key, values := range p.query {
+			for _, value := range values {
+				reqQP.Add(key, value)
+			}
+		}
+		req.Raw().URL.RawQuery = reqQP.Encode()
+	}
+	return req.Next()
+}
+
+// sharedKeyPolicy signs the requests with the Shared Key authorization of the Azure Storage blob, file and queue services.
+// Docs: https://learn.microsoft.com/en-us/rest/api/storageservices/authorize-with-shared-key
+type sharedKeyPolicy struct {
+	key []byte
+}
+
+func (p *sharedKeyPolicy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	raw.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	accountName, _, _ := strings.Cut(raw.URL.Host, ".")
+	h := hmac.New(sha256.New, p.key)
+	h.Write([]byte(sharedKeyStringToSign(raw, accountName)))
+	signature := base64.StdEncoding.EncodeToString(h.Sum(nil))
+	raw.Header.Set("Authorization", fmt.Sprintf("SharedKey %s:%s", accountName, signature))
+	return req.Next()
+}
+
+func sharedKeyStringToSign(req *http.Request, accountName string) string {
+	contentLength := req.Header.Get("Content-Length")
+	if contentLength == "0" {
+		contentLength = ""
+	}
+	lines := []string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		// the date is specified by the x-ms-date header
+		"",
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+	}
+
+	// canonicalized headers
+	headerNames := make([]string, 0)
+	for name := range req.Header {
+		if lowerName := strings.ToLower(name); strings.HasPrefix(lowerName, "x-ms-") {
+			headerNames = append(headerNames, lowerName)
+		}
+	}
+	sort.Strings(headerNames)
+	for _, name := range headerNames {
+		lines = append(lines, fmt.Sprintf("%s:%s", name, strings.TrimSpace(strings.Join(req.Header.Values(name), ","))))
+	}
+
+	// canonicalized resource
+	resource := "/" + accountName + req.URL.EscapedPath()
+	if req.URL.Path == "" {
+		resource += "/"
+	}
+	query := make(map[string][]string)
+	for name, values := range req.URL.Query() {
+		query[strings.ToLower(name)] = append(query[strings.ToLower(name)], values...)
+	}
+	queryNames := make([]string, 0, len(query))
+	for name := range query {
+		queryNames = append(queryNames, name)
+	}
+	sort.Strings(queryNames)
+	for _, name := range queryNames {
+		values := query[name]
+		sort.Strings(values)
+		resource += fmt.Sprintf("\n%s:%s", name, strings.Join(values, ","))
+	}
+	lines = append(lines, resource)
+	return strings.Join(lines, "\n")
+}
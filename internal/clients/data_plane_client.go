@@ -8,7 +8,6 @@ import (
 	"regexp"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
@@ -16,6 +15,7 @@ import (
 	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/terraform-provider-azapi/internal/services/parse"
 	"github.com/cenkalti/backoff/v4"
 )
@@ -24,7 +24,11 @@ type DataPlaneClient struct {
 	credential      azcore.TokenCredential
 	clientOptions   *arm.ClientOptions
 	cachedPipelines map[string]runtime.Pipeline
-	syncMux         sync.Mutex
+	// managedIdentityCredentials are the credentials of the managed identities which are used by the resources instead of the credential, keyed by the client IDs
+	managedIdentityCredentials map[string]azcore.TokenCredential
+	// newManagedIdentityCredential builds the credentials of the managed identities, the default options of the SDK are used if it's nil
+	newManagedIdentityCredential func(clientId string) (azcore.TokenCredential, error)
+	syncMux                      sync.Mutex
 }
 
 type DataPlaneClientRetryableErrors struct {
@@ -61,10 +65,11 @@ func NewDataPlaneClient(credential azcore.TokenCredential, opt *arm.ClientOption
 		opt = &arm.ClientOptions{}
 	}
 	return &DataPlaneClient{
-		credential:      credential,
-		clientOptions:   opt,
-		cachedPipelines: make(map[string]runtime.Pipeline),
-		syncMux:         sync.Mutex{},
+		credential:                 credential,
+		clientOptions:              opt,
+		cachedPipelines:            make(map[string]runtime.Pipeline),
+		managedIdentityCredentials: make(map[string]azcore.TokenCredential),
+		syncMux:                    sync.Mutex{},
 	}, nil
 }
 
@@ -82,35 +87,90 @@ func (client *DataPlaneClient) WithRetry(bkof *backoff.ExponentialBackOff, errRe
 func (client *DataPlaneClient) cachedPipeline(rawUrl string) (runtime.Pipeline, error) {
 	client.syncMux.Lock()
 	defer client.syncMux.Unlock()
+	return client.cachedBearerTokenPipeline(rawUrl, "", client.credential)
+}
+
+// managedIdentityPipeline returns the cached pipeline which authenticates the requests with the managed identity, the system-assigned managed identity is used if the client ID is empty.
+// The credentials are cached by the client IDs, so their tokens are reused by the resources which use the same identity.
+func (client *DataPlaneClient) managedIdentityPipeline(rawUrl string, clientId string) (runtime.Pipeline, error) {
+	client.syncMux.Lock()
+	defer client.syncMux.Unlock()
+
+	credential, ok := client.managedIdentityCredentials[clientId]
+	if !ok {
+		newCredential := client.newManagedIdentityCredential
+		if newCredential == nil {
+			newCredential = client.defaultManagedIdentityCredential
+		}
+		cred, err := newCredential(clientId)
+		if err != nil {
+			return runtime.Pipeline{}, fmt.Errorf("building the managed identity credential: %+v", err)
+		}
+		credential = cred
+		client.managedIdentityCredentials[clientId] = credential
+	}
+	return client.cachedBearerTokenPipeline(rawUrl, "managed-identity/"+clientId+"/", credential)
+}
+
+func (client *DataPlaneClient) defaultManagedIdentityCredential(clientId string) (azcore.TokenCredential, error) {
+	o := &azidentity.ManagedIdentityCredentialOptions{
+		ClientOptions: azcore.ClientOptions{
+			Cloud:     client.clientOptions.Cloud,
+			Transport: client.clientOptions.Transport,
+		},
+	}
+	if clientId != "" {
+		o.ID = azidentity.ClientID(clientId)
+	}
+	return azidentity.NewManagedIdentityCredential(o)
+}
 
+// cachedBearerTokenPipeline returns the pipeline which authenticates the requests with the tokens of the credential for the audience of the URL,
+// the pipelines are cached by the key prefix of the credential and the audience. The caller must hold the lock.
+func (client *DataPlaneClient) cachedBearerTokenPipeline(rawUrl string, keyPrefix string, credential azcore.TokenCredential) (runtime.Pipeline, error) {
 	parsedUrl, err := url.Parse(rawUrl)
 	if err != nil {
 		return runtime.Pipeline{}, err
 	}
-	serviceName := cloud.ResourceManager
-	cloud := client.clientOptions.Cloud
-	host := parsedUrl.Host
-	for name, serviceConfiguration := range cloud.Services {
-		if strings.HasSuffix(host, strings.TrimPrefix(serviceConfiguration.Endpoint, "https://")) {
-			serviceName = name
-			break
-		}
-	}
+	audience := dataPlaneAudience(client.clientOptions.Cloud, parsedUrl.Host)
 
-	if pipeline, ok := client.cachedPipelines[string(serviceName)]; ok {
+	key := keyPrefix + audience
+	if pipeline, ok := client.cachedPipelines[key]; ok {
 		return pipeline, nil
 	}
 
 	plOpt := runtime.PipelineOptions{}
 	plOpt.APIVersion.Name = "api-version"
-	authPolicy := armruntime.NewBearerTokenPolicy(client.credential, &armpolicy.BearerTokenOptions{Scopes: []string{cloud.Services[serviceName].Audience + "/.default"}})
+	authPolicy := armruntime.NewBearerTokenPolicy(credential, &armpolicy.BearerTokenOptions{Scopes: []string{audience + "/.default"}})
 	plOpt.PerRetry = append(plOpt.PerRetry, authPolicy)
 	pl := runtime.NewPipeline(moduleName, moduleVersion, plOpt, &client.clientOptions.ClientOptions)
 
-	client.cachedPipelines[string(serviceName)] = pl
+	client.cachedPipelines[key] = pl
 	return pl, nil
 }
 
+// dataPlaneAudience returns the audience of the tokens used to call the data plane endpoint.
+// The service whose endpoint is the longest suffix of the host is used, and the resource manager is used if there's no match.
+// An empty audience of the service means the endpoint itself is the audience, e.g. the Azure Data Explorer clusters.
+func dataPlaneAudience(cloudCfg cloud.Configuration, host string) string {
+	serviceName := cloud.ResourceManager
+	matchedEndpoint := ""
+	for name, serviceConfiguration := range cloudCfg.Services {
+		endpoint := strings.TrimSuffix(strings.TrimPrefix(serviceConfiguration.Endpoint, "https://"), "/")
+		if endpoint == "" || len(endpoint) <= len(matchedEndpoint) {
+			continue
+		}
+		if host == endpoint || strings.HasSuffix(host, "."+endpoint) {
+			serviceName = name
+			matchedEndpoint = endpoint
+		}
+	}
+	if audience := cloudCfg.Services[serviceName].Audience; audience != "" {
+		return audience
+	}
+	return "https://" + host
+}
+
 func (client *DataPlaneClient) CreateOrUpdateThenPoll(ctx context.Context, id parse.DataPlaneResourceId, body interface{}, options RequestOptions) (interface{}, error) {
 	// build request
 	urlPath := fmt.Sprintf("https://%s", id.AzureResourceId)
@@ -128,13 +188,13 @@ func (client *DataPlaneClient) CreateOrUpdateThenPoll(ctx context.Context, id pa
 	for key, value := range options.Headers {
 		req.Raw().Header.Set(key, value)
 	}
-	err = runtime.MarshalAsJSON(req, body)
+	err = setRequestBody(req, body)
 	if err != nil {
 		return nil, err
 	}
 
 	// send request
-	pipeline, err := client.cachedPipeline(urlPath)
+	pipeline, err := client.pipeline(urlPath, options)
 	if err != nil {
 		return nil, err
 	}
@@ -143,30 +203,31 @@ func (client *DataPlaneClient) CreateOrUpdateThenPoll(ctx context.Context, id pa
 		return nil, err
 	}
 	if !runtime.HasStatusCode(resp, http.StatusOK, http.StatusCreated, http.StatusAccepted) {
-		return nil, runtime.NewResponseError(resp)
+		return nil, newResponseError(resp)
 	}
 
-	// poll until done
+	// poll until done, unless the request completed synchronously with a non-JSON payload, which the poller can't decode
+	if isSynchronousNonJSONResponse(resp) {
+		return unmarshalResponseBody(resp)
+	}
 	pt, err := runtime.NewPoller[interface{}](resp, pipeline, nil)
 	if err == nil {
-		resp, err := pt.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{
-			Frequency: 10 * time.Second,
-		})
+		resp, err := pollUntilDone(ctx, pt, resp, options.PollingInterval)
 		return resp, err
 	}
 
 	// unmarshal response
-	var responseBody interface{}
-	if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
-		return nil, err
-	}
-	return responseBody, nil
+	return unmarshalResponseBody(resp)
 }
 
 func (client *DataPlaneClient) Get(ctx context.Context, id parse.DataPlaneResourceId, options RequestOptions) (interface{}, error) {
 	// build request
 	urlPath := fmt.Sprintf("https://%s", id.AzureResourceId)
-	req, err := runtime.NewRequest(ctx, http.MethodGet, urlPath)
+	method := http.MethodGet
+	if id.ReadMethod != "" {
+		method = id.ReadMethod
+	}
+	req, err := runtime.NewRequest(ctx, method, urlPath)
 	if err != nil {
 		return nil, err
 	}
@@ -182,7 +243,7 @@ func (client *DataPlaneClient) Get(ctx context.Context, id parse.DataPlaneResour
 	}
 
 	// send request
-	pipeline, err := client.cachedPipeline(urlPath)
+	pipeline, err := client.pipeline(urlPath, options)
 	if err != nil {
 		return nil, err
 	}
@@ -191,15 +252,16 @@ func (client *DataPlaneClient) Get(ctx context.Context, id parse.DataPlaneResour
 		return nil, err
 	}
 	if !runtime.HasStatusCode(resp, http.StatusOK) {
-		return nil, runtime.NewResponseError(resp)
+		return nil, newResponseError(resp)
 	}
 
-	// unmarshal response
-	var responseBody interface{}
-	if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
-		return nil, err
+	// HEAD only checks the existence of the resource, there's no body to unmarshal
+	if method == http.MethodHead {
+		return map[string]interface{}{}, nil
 	}
-	return responseBody, nil
+
+	// unmarshal response
+	return unmarshalResponseBody(resp)
 }
 
 func (client *DataPlaneClient) DeleteThenPoll(ctx context.Context, id parse.DataPlaneResourceId, options RequestOptions) (interface{}, error) {
@@ -221,7 +283,7 @@ func (client *DataPlaneClient) DeleteThenPoll(ctx context.Context, id parse.Data
 	}
 
 	// send request
-	pipeline, err := client.cachedPipeline(urlPath)
+	pipeline, err := client.pipeline(urlPath, options)
 	if err != nil {
 		return nil, err
 	}
@@ -230,24 +292,21 @@ func (client *DataPlaneClient) DeleteThenPoll(ctx context.Context, id parse.Data
 		return nil, err
 	}
 	if !runtime.HasStatusCode(resp, http.StatusOK, http.StatusAccepted, http.StatusNoContent) {
-		return nil, runtime.NewResponseError(resp)
+		return nil, newResponseError(resp)
 	}
 
-	// poll until done
+	// poll until done, unless the request completed synchronously with a non-JSON payload, which the poller can't decode
+	if isSynchronousNonJSONResponse(resp) {
+		return unmarshalResponseBody(resp)
+	}
 	pt, err := runtime.NewPoller[interface{}](resp, pipeline, nil)
 	if err == nil {
-		resp, err := pt.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{
-			Frequency: 10 * time.Second,
-		})
+		resp, err := pollUntilDone(ctx, pt, resp, options.PollingInterval)
 		return resp, err
 	}
 
 	// unmarshal response
-	var responseBody interface{}
-	if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
-		return nil, err
-	}
-	return responseBody, nil
+	return unmarshalResponseBody(resp)
 }
 
 func (client *DataPlaneClient) Action(ctx context.Context, resourceID string, action string, apiVersion string, method string, body interface{}, options RequestOptions) (interface{}, error) {
@@ -271,14 +330,14 @@ func (client *DataPlaneClient) Action(ctx context.Context, resourceID string, ac
 		req.Raw().Header.Set(key, value)
 	}
 	if method != "GET" && body != nil {
-		err = runtime.MarshalAsJSON(req, body)
+		err = setRequestBody(req, body)
 	}
 	if err != nil {
 		return nil, err
 	}
 
 	// send request
-	pipeline, err := client.cachedPipeline(urlPath)
+	pipeline, err := client.pipeline(urlPath, options)
 	if err != nil {
 		return nil, err
 	}
@@ -287,35 +346,24 @@ func (client *DataPlaneClient) Action(ctx context.Context, resourceID string, ac
 		return nil, err
 	}
 	if !runtime.HasStatusCode(resp, http.StatusOK, http.StatusCreated, http.StatusAccepted) {
-		return nil, runtime.NewResponseError(resp)
+		return nil, newResponseError(resp)
 	}
+	recordResponseHeader(ctx, resp)
 
-	// poll until done
+	// poll until done, unless the request completed synchronously with a non-JSON payload, which the poller can't decode
+	if isSynchronousNonJSONResponse(resp) {
+		return unmarshalResponseBody(resp)
+	}
 	pt, err := runtime.NewPoller[interface{}](resp, pipeline, nil)
 	if err == nil {
-		resp, err := pt.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{
-			Frequency: 10 * time.Second,
-		})
-		return resp, err
+		result, err := pollUntilDone(ctx, pt, resp, options.PollingInterval)
+		if err != nil || !options.ReturnInitialResponse {
+			return result, err
+		}
 	}
 
 	// unmarshal response
-	var responseBody interface{}
-	contentType := resp.Header.Get("Content-Type")
-	switch {
-	case strings.Contains(contentType, "text/plain"):
-		payload, err := runtime.Payload(resp)
-		if err != nil {
-			return nil, err
-		}
-		responseBody = string(payload)
-	case strings.Contains(contentType, "application/json"):
-		if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
-			return nil, err
-		}
-	default:
-	}
-	return responseBody, nil
+	return unmarshalResponseBody(resp)
 }
 
 func (retryclient *DataPlaneClientRetryableErrors) CreateOrUpdateThenPoll(ctx context.Context, id parse.DataPlaneResourceId, body interface{}, options RequestOptions) (interface{}, error) {
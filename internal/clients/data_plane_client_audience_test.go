@@ -0,0 +1,79 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataPlaneAudience(t *testing.T) {
+	cloudCfg := cloud.Configuration{
+		Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+			cloud.ResourceManager: {
+				Audience: "https://management.core.windows.net/",
+				Endpoint: "https://management.azure.com/",
+			},
+			"Storage": {
+				Audience: "https://storage.azure.com",
+				Endpoint: "https://core.windows.net",
+			},
+			"Batch": {
+				Audience: "https://batch.core.windows.net",
+				Endpoint: "https://batch.core.windows.net",
+			},
+			"KeyVault": {
+				Audience: "https://vault.azure.net",
+				Endpoint: "https://vault.azure.net",
+			},
+			"Kusto": {
+				Audience: "",
+				Endpoint: "https://kusto.windows.net",
+			},
+			"ManagedHSM": {
+				Audience: "https://managedhsm.azure.net",
+				Endpoint: "https://managedhsm.azure.net",
+			},
+		},
+	}
+
+	testcases := []struct {
+		Host     string
+		Expected string
+	}{
+		{
+			Host:     "myvault.vault.azure.net",
+			Expected: "https://vault.azure.net",
+		},
+		{
+			Host:     "myhsm.managedhsm.azure.net",
+			Expected: "https://managedhsm.azure.net",
+		},
+		{
+			Host:     "myaccount.blob.core.windows.net",
+			Expected: "https://storage.azure.com",
+		},
+		{
+			// the longest matched endpoint wins
+			Host:     "myaccount.westus.batch.core.windows.net",
+			Expected: "https://batch.core.windows.net",
+		},
+		{
+			Host:     "mycluster.westus.kusto.windows.net",
+			Expected: "https://mycluster.westus.kusto.windows.net",
+		},
+		{
+			// the suffix must match the whole domain labels
+			Host:     "myvault.notvault.azure.net",
+			Expected: "https://management.core.windows.net/",
+		},
+		{
+			Host:     "example.com",
+			Expected: "https://management.core.windows.net/",
+		},
+	}
+
+	for _, testcase := range testcases {
+		assert.Equal(t, testcase.Expected, dataPlaneAudience(cloudCfg, testcase.Host), testcase.Host)
+	}
+}
@@ -3,9 +3,16 @@ package clients_test
 import (
 	"context"
 	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/terraform-provider-azapi/internal/clients"
 	"github.com/Azure/terraform-provider-azapi/internal/services/parse"
 	"github.com/stretchr/testify/assert"
@@ -88,3 +95,181 @@ func TestRetryDataPlaneClientContextDeadline(t *testing.T) {
 	_, ok := <-ctx.Done()
 	assert.False(t, ok)
 }
+
+type fakeTokenCredential struct{}
+
+func (fakeTokenCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "fake", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+// newTestDataPlaneClient returns a DataPlaneClient which sends requests to the given TLS test server,
+// and the host of the test server which should be used as the prefix of the data plane resource ID.
+func newTestDataPlaneClient(t *testing.T, server *httptest.Server) (*clients.DataPlaneClient, string) {
+	client, err := clients.NewDataPlaneClient(fakeTokenCredential{}, &arm.ClientOptions{
+		ClientOptions: policy.ClientOptions{
+			Transport: server.Client(),
+			Retry: policy.RetryOptions{
+				MaxRetries: -1,
+			},
+		},
+	})
+	assert.NoError(t, err)
+	return client, strings.TrimPrefix(server.URL, "https://")
+}
+
+func TestDataPlaneClientGetWithHeadReadMethod(t *testing.T) {
+	methods := make([]string, 0)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		if strings.HasSuffix(r.URL.Path, "/missing") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	client, host := newTestDataPlaneClient(t, server)
+
+	id := parse.DataPlaneResourceId{
+		AzureResourceId: host + "/api/hubs/hub1/permissions/sendToGroup/connections/conn1",
+		ApiVersion:      "2024-01-01",
+		ReadMethod:      http.MethodHead,
+	}
+	resp, err := client.Get(context.Background(), id, clients.DefaultRequestOptions())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{}, resp)
+
+	id.AzureResourceId = host + "/api/hubs/hub1/permissions/sendToGroup/connections/missing"
+	_, err = client.Get(context.Background(), id, clients.DefaultRequestOptions())
+	var responseErr *azcore.ResponseError
+	assert.True(t, errors.As(err, &responseErr))
+	assert.Equal(t, http.StatusNotFound, responseErr.StatusCode)
+	assert.Equal(t, []string{http.MethodHead, http.MethodHead}, methods)
+}
+
+func TestDataPlaneClientActionWithContentType(t *testing.T) {
+	testcases := []struct {
+		ContentType       string
+		Body              interface{}
+		ExpectContentType string
+		ExpectBody        string
+	}{
+		{
+			ContentType:       "",
+			Body:              map[string]interface{}{"key": "value"},
+			ExpectContentType: "application/json",
+			ExpectBody:        `{"key":"value"}`,
+		},
+		{
+			ContentType:       "application/merge-patch+json",
+			Body:              map[string]interface{}{"key": "value"},
+			ExpectContentType: "application/merge-patch+json",
+			ExpectBody:        `{"key":"value"}`,
+		},
+		{
+			ContentType:       "text/plain",
+			Body:              "eyJhbGciOiJub25lIn0..",
+			ExpectContentType: "text/plain",
+			ExpectBody:        "eyJhbGciOiJub25lIn0..",
+		},
+		{
+			ContentType:       "",
+			Body:              "value",
+			ExpectContentType: "application/json",
+			ExpectBody:        `"value"`,
+		},
+		{
+			ContentType:       "application/x-www-form-urlencoded",
+			Body:              map[string]interface{}{"grant_type": "client_credentials", "scope": []interface{}{"a b", "c"}, "count": float64(2)},
+			ExpectContentType: "application/x-www-form-urlencoded",
+			ExpectBody:        "count=2&grant_type=client_credentials&scope=a+b&scope=c",
+		},
+		{
+			ContentType:       "",
+			Body:              []byte{0x30, 0x82, 0x01},
+			ExpectContentType: "application/octet-stream",
+			ExpectBody:        string([]byte{0x30, 0x82, 0x01}),
+		},
+		{
+			ContentType:       "application/pkcs10",
+			Body:              []byte("certificate"),
+			ExpectContentType: "application/pkcs10",
+			ExpectBody:        "certificate",
+		},
+	}
+
+	for _, testcase := range testcases {
+		var contentType, body string
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			contentType = r.Header.Get("Content-Type")
+			payload, _ := io.ReadAll(r.Body)
+			body = string(payload)
+			w.WriteHeader(http.StatusOK)
+		}))
+		client, host := newTestDataPlaneClient(t, server)
+
+		options := clients.DefaultRequestOptions()
+		if testcase.ContentType != "" {
+			options = options.WithDefaultHeader("Content-Type", testcase.ContentType)
+		}
+		_, err := client.Action(context.Background(), host+"/resource", "", "2024-01-01", http.MethodPatch, testcase.Body, options)
+		server.Close()
+		assert.NoError(t, err)
+		assert.Equal(t, testcase.ExpectContentType, contentType)
+		assert.Equal(t, testcase.ExpectBody, body)
+	}
+}
+
+func TestDataPlaneClientGetWithDataPlaneAuthentication(t *testing.T) {
+	testcases := []struct {
+		Authentication      *clients.DataPlaneAuthentication
+		ExpectQuery         string
+		ExpectAuthorization string
+	}{
+		{
+			Authentication:      nil,
+			ExpectQuery:         "api-version=2024-01-01",
+			ExpectAuthorization: "Bearer fake",
+		},
+		{
+			Authentication: &clients.DataPlaneAuthentication{
+				SasToken: "?sv=2022-11-02&sig=signature",
+			},
+			ExpectQuery:         "api-version=2024-01-01&sig=signature&sv=2022-11-02",
+			ExpectAuthorization: "",
+		},
+		{
+			Authentication: &clients.DataPlaneAuthentication{
+				AccountKey: "a2V5",
+			},
+			ExpectQuery:         "api-version=2024-01-01",
+			ExpectAuthorization: "SharedKey 127:",
+		},
+	}
+
+	for _, testcase := range testcases {
+		var query, authorization string
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.RawQuery
+			authorization = r.Header.Get("Authorization")
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{}`))
+		}))
+		client, host := newTestDataPlaneClient(t, server)
+
+		id := parse.DataPlaneResourceId{
+			AzureResourceId: host + "/container/blob",
+			ApiVersion:      "2024-01-01",
+		}
+		_, err := client.Get(context.Background(), id, clients.DefaultRequestOptions().WithDataPlaneAuthentication(testcase.Authentication))
+		server.Close()
+		assert.NoError(t, err)
+		assert.Equal(t, testcase.ExpectQuery, query)
+		if testcase.ExpectAuthorization == "" {
+			assert.Empty(t, authorization)
+			continue
+		}
+		assert.True(t, strings.HasPrefix(authorization, testcase.ExpectAuthorization), authorization)
+	}
+}
@@ -0,0 +1,78 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// deprecationHeaders are the response headers which announce the upcoming removals of the APIs.
+var deprecationHeaders = []string{
+	"Azure-Deprecating",
+	"Deprecation",
+	"Sunset",
+}
+
+type deprecationNoticesKey struct{}
+
+// DeprecationNotices collects the deprecation headers of the responses whose requests are sent with the context.
+type DeprecationNotices struct {
+	mutex   sync.Mutex
+	notices map[string]bool
+}
+
+// WithDeprecationNotices returns a copy of the context which collects the deprecation headers of the responses.
+func WithDeprecationNotices(ctx context.Context) (context.Context, *DeprecationNotices) {
+	notices := &DeprecationNotices{
+		notices: make(map[string]bool),
+	}
+	return context.WithValue(ctx, deprecationNoticesKey{}, notices), notices
+}
+
+// Messages returns the sorted and deduplicated deprecation notices.
+func (n *DeprecationNotices) Messages() []string {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	out := make([]string, 0, len(n.notices))
+	for notice := range n.notices {
+		out = append(out, notice)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (n *DeprecationNotices) add(notice string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.notices[notice] = true
+}
+
+type DeprecationPolicy struct{}
+
+func (p DeprecationPolicy) Do(req *policy.Request) (*http.Response, error) {
+	resp, err := req.Next()
+	if resp == nil {
+		return resp, err
+	}
+	notices, _ := req.Raw().Context().Value(deprecationNoticesKey{}).(*DeprecationNotices)
+	for _, header := range deprecationHeaders {
+		value := resp.Header.Get(header)
+		if value == "" {
+			continue
+		}
+		// the query is omitted, because it may contain the credentials
+		notice := fmt.Sprintf("%s %s%s: %s: %s", req.Raw().Method, req.Raw().URL.Host, req.Raw().URL.Path, header, value)
+		log.Printf("[WARN] %s", notice)
+		if notices != nil {
+			notices.add(notice)
+		}
+	}
+	return resp, err
+}
+
+var _ policy.Policy = DeprecationPolicy{}
@@ -0,0 +1,42 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecationPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/deprecated" {
+			w.Header().Set("Azure-Deprecating", "api-version 2020-01-01 will be retired")
+			w.Header().Set("Sunset", "Wed, 01 Jan 2025 00:00:00 GMT")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	pl := runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		Transport:       server.Client(),
+		PerCallPolicies: []policy.Policy{DeprecationPolicy{}},
+	})
+
+	ctx, notices := WithDeprecationNotices(context.Background())
+	for _, path := range []string{"/deprecated", "/deprecated", "/current"} {
+		req, err := runtime.NewRequest(ctx, http.MethodGet, server.URL+path+"?sig=secret")
+		assert.NoError(t, err)
+		_, err = pl.Do(req)
+		assert.NoError(t, err)
+	}
+
+	host := server.Listener.Addr().String()
+	assert.Equal(t, []string{
+		"GET " + host + "/deprecated: Azure-Deprecating: api-version 2020-01-01 will be retired",
+		"GET " + host + "/deprecated: Sunset: Wed, 01 Jan 2025 00:00:00 GMT",
+	}, notices.Messages())
+}
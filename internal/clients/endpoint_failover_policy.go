@@ -0,0 +1,96 @@
+package clients
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// endpointFailoverThreshold is the number of the consecutive connection failures of the primary endpoint before the requests fail over to the secondary endpoint.
+const endpointFailoverThreshold = 3
+
+// endpointFailoverCooldown is the duration in which the requests are sent to the secondary endpoint before the primary endpoint is tried again.
+const endpointFailoverCooldown = 5 * time.Minute
+
+// endpointFailoverPolicy sends the requests of the primary resource manager endpoint to the secondary endpoint when the primary endpoint can't be reached repeatedly,
+// e.g. its host name can't be resolved or the connections are refused, which happens when a regional resource manager instance of Azure Stack is down.
+// The failures are the transport errors, the responses of the primary endpoint, including the server errors, mean it's reachable.
+// It's a per-retry policy, so the retries of the failed request are sent to the secondary endpoint once the threshold is reached.
+type endpointFailoverPolicy struct {
+	primary   *url.URL
+	secondary *url.URL
+
+	mutex    sync.Mutex
+	failures int
+	// failedOverUntil is the time until which the requests are sent to the secondary endpoint
+	failedOverUntil time.Time
+}
+
+// NewEndpointFailoverPolicy returns a per-retry policy which fails over the requests of the primary endpoint to the secondary endpoint.
+func NewEndpointFailoverPolicy(primaryEndpoint string, secondaryEndpoint string) (policy.Policy, error) {
+	primary, err := url.Parse(primaryEndpoint)
+	if err != nil || primary.Host == "" {
+		return nil, fmt.Errorf("the primary resource manager endpoint %q is invalid", primaryEndpoint)
+	}
+	secondary, err := url.Parse(secondaryEndpoint)
+	if err != nil || secondary.Scheme != "https" || secondary.Host == "" {
+		return nil, fmt.Errorf("the secondary resource manager endpoint %q is invalid, it must be an absolute https URL", secondaryEndpoint)
+	}
+	return &endpointFailoverPolicy{
+		primary:   primary,
+		secondary: secondary,
+	}, nil
+}
+
+func (p *endpointFailoverPolicy) Do(req *policy.Request) (*http.Response, error) {
+	rawRequest := req.Raw()
+	if !strings.EqualFold(rawRequest.URL.Host, p.primary.Host) {
+		return req.Next()
+	}
+
+	if p.failedOver() {
+		rawRequest.URL.Scheme = p.secondary.Scheme
+		rawRequest.URL.Host = p.secondary.Host
+		rawRequest.Host = ""
+		return req.Next()
+	}
+
+	resp, err := req.Next()
+	// the cancelled requests don't tell whether the primary endpoint is reachable
+	if err != nil && rawRequest.Context().Err() == nil {
+		p.recordFailure(err)
+	} else if err == nil {
+		p.recordSuccess()
+	}
+	return resp, err
+}
+
+func (p *endpointFailoverPolicy) failedOver() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return time.Now().Before(p.failedOverUntil)
+}
+
+func (p *endpointFailoverPolicy) recordFailure(err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.failures++
+	if p.failures < endpointFailoverThreshold {
+		return
+	}
+	p.failures = 0
+	p.failedOverUntil = time.Now().Add(endpointFailoverCooldown)
+	log.Printf("[WARN] The resource manager endpoint %s failed %d times in a row, the requests are sent to the secondary endpoint %s in the next %s: %+v", p.primary.Host, endpointFailoverThreshold, p.secondary.Host, endpointFailoverCooldown, err)
+}
+
+func (p *endpointFailoverPolicy) recordSuccess() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.failures = 0
+}
@@ -0,0 +1,53 @@
+package clients
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpointFailoverPolicy(t *testing.T) {
+	secondaryRequests := 0
+	secondary := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secondaryRequests++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer secondary.Close()
+
+	// the primary endpoint refuses the connections
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	primaryEndpoint := "https://" + listener.Addr().String()
+	assert.NoError(t, listener.Close())
+
+	failoverPolicy, err := NewEndpointFailoverPolicy(primaryEndpoint, secondary.URL)
+	assert.NoError(t, err)
+	pl := runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		Transport:        secondary.Client(),
+		PerRetryPolicies: []policy.Policy{failoverPolicy},
+		Retry: policy.RetryOptions{
+			MaxRetries: endpointFailoverThreshold,
+			RetryDelay: time.Millisecond,
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		req, err := runtime.NewRequest(context.Background(), http.MethodGet, primaryEndpoint+"/subscriptions?api-version=2022-12-01")
+		assert.NoError(t, err)
+		resp, err := pl.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+	// the retry of the first request and the second request are sent to the secondary endpoint
+	assert.Equal(t, 2, secondaryRequests)
+
+	_, err = NewEndpointFailoverPolicy(primaryEndpoint, "http://example.com")
+	assert.Error(t, err)
+}
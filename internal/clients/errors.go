@@ -0,0 +1,13 @@
+package clients
+
+import (
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/terraform-provider-azapi/azerrors"
+)
+
+// newResponseError returns the typed error of the unexpected response, it wraps the *azcore.ResponseError.
+func newResponseError(resp *http.Response) error {
+	return azerrors.FromResponseError(runtime.NewResponseError(resp))
+}
@@ -0,0 +1,75 @@
+package clients
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// lighthousePolicy appends the hints about Azure Lighthouse to the authentication and authorization errors.
+// The delegated subscriptions must be accessed with the tokens issued by the managing tenant, and the principals only have the roles which are granted by the delegations,
+// which are common causes of the confusing errors.
+type lighthousePolicy struct {
+	managingTenantId string
+}
+
+// NewLighthousePolicy returns a policy which appends the Lighthouse specific hints to the errors, the managing tenant ID is empty if it's not configured.
+func NewLighthousePolicy(managingTenantId string) policy.Policy {
+	return &lighthousePolicy{
+		managingTenantId: managingTenantId,
+	}
+}
+
+func (p *lighthousePolicy) Do(req *policy.Request) (*http.Response, error) {
+	resp, err := req.Next()
+	if err != nil || (resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden) {
+		return resp, err
+	}
+
+	var responseErr *azcore.ResponseError
+	if !errors.As(runtime.NewResponseError(resp), &responseErr) {
+		return resp, err
+	}
+	hint := lighthouseHint(responseErr.ErrorCode, p.managingTenantId)
+	if hint == "" {
+		return resp, err
+	}
+	return resp, &hintError{err: responseErr, hint: hint}
+}
+
+// lighthouseHint returns the hint for the error code, it returns an empty string if the error isn't related to Azure Lighthouse.
+func lighthouseHint(errorCode string, managingTenantId string) string {
+	switch errorCode {
+	case "InvalidAuthenticationTokenTenant":
+		if managingTenantId == "" {
+			return "The access token is issued by a tenant which doesn't own the subscription. If the subscription is delegated to the tenant of the credentials by Azure Lighthouse, please specify the `managing_tenant_id` in the provider."
+		}
+		return fmt.Sprintf("The access token is issued by the managing tenant %q, please check whether the subscription is delegated to it by Azure Lighthouse.", managingTenantId)
+	case "AuthorizationFailed", "LinkedAuthorizationFailed":
+		if managingTenantId == "" {
+			return ""
+		}
+		return "The principal only has the roles which are granted by the Azure Lighthouse delegation. Please note that the delegations don't support the `Owner` role and the roles with `DataActions`, and assigning the roles requires the `User Access Administrator` role with the `delegatedRoleDefinitionIds`."
+	}
+	return ""
+}
+
+// hintError is a response error with a hint, it's not retriable because the authentication and authorization errors won't be resolved by retries.
+type hintError struct {
+	err  *azcore.ResponseError
+	hint string
+}
+
+func (e *hintError) Error() string {
+	return fmt.Sprintf("%s\n\nHint: %s", e.err.Error(), e.hint)
+}
+
+func (e *hintError) Unwrap() error {
+	return e.err
+}
+
+func (e *hintError) NonRetriable() {}
@@ -0,0 +1,75 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLighthousePolicy(t *testing.T) {
+	testcases := []struct {
+		ManagingTenantId string
+		StatusCode       int
+		ErrorCode        string
+		ExpectHint       bool
+	}{
+		{
+			StatusCode: http.StatusUnauthorized,
+			ErrorCode:  "InvalidAuthenticationTokenTenant",
+			ExpectHint: true,
+		},
+		{
+			StatusCode: http.StatusForbidden,
+			ErrorCode:  "AuthorizationFailed",
+			ExpectHint: false,
+		},
+		{
+			ManagingTenantId: "00000000-0000-0000-0000-000000000000",
+			StatusCode:       http.StatusForbidden,
+			ErrorCode:        "AuthorizationFailed",
+			ExpectHint:       true,
+		},
+		{
+			ManagingTenantId: "00000000-0000-0000-0000-000000000000",
+			StatusCode:       http.StatusNotFound,
+			ErrorCode:        "ResourceNotFound",
+			ExpectHint:       false,
+		},
+	}
+
+	for _, testcase := range testcases {
+		requestCount := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestCount++
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(testcase.StatusCode)
+			_, _ = w.Write([]byte(`{"error":{"code":"` + testcase.ErrorCode + `","message":"error"}}`))
+		}))
+
+		pl := runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+			Transport:        server.Client(),
+			PerRetryPolicies: []policy.Policy{NewLighthousePolicy(testcase.ManagingTenantId)},
+		})
+		req, err := runtime.NewRequest(context.Background(), http.MethodGet, server.URL+"/subscriptions/00000000-0000-0000-0000-000000000000")
+		assert.NoError(t, err)
+		_, err = pl.Do(req)
+		server.Close()
+
+		var hintErr *hintError
+		assert.Equal(t, testcase.ExpectHint, errors.As(err, &hintErr), testcase.ErrorCode)
+		if testcase.ExpectHint {
+			var responseErr *azcore.ResponseError
+			assert.True(t, errors.As(err, &responseErr))
+			assert.Equal(t, testcase.StatusCode, responseErr.StatusCode)
+			assert.Contains(t, err.Error(), "Hint:")
+			assert.Equal(t, 1, requestCount)
+		}
+	}
+}
@@ -1,10 +1,27 @@
 package clients
 
-import "strings"
+import (
+	"strings"
+	"time"
+)
 
 type RequestOptions struct {
 	Headers         map[string]string
 	QueryParameters map[string]string
+	// DataPlaneAuthentication is only used by the data plane requests, the Azure Active Directory tokens are used if it's nil.
+	DataPlaneAuthentication *DataPlaneAuthentication
+	// ReturnInitialResponse returns the body of the initial response instead of the result of the long-running operation.
+	// It's only used by the data plane actions whose result is only returned by the initial response, e.g. the managed HSM security domain download.
+	ReturnInitialResponse bool
+	// PollingInterval is the interval between the polling requests of the long-running operations when the response doesn't contain the Retry-After header,
+	// the default interval is used if it's zero.
+	PollingInterval time.Duration
+	// Endpoint is the resource manager endpoint which the request is sent to, e.g. the regional endpoint `https://westus.management.azure.com`,
+	// the endpoint of the client is used if it's empty.
+	Endpoint string
+	// PageConcurrency is the number of pages which are fetched concurrently by the list requests whose nextLink has a numeric `$skip` or `$skipToken` offset,
+	// the pages are fetched sequentially if it's less than 2.
+	PageConcurrency int
 }
 
 func DefaultRequestOptions() RequestOptions {
@@ -25,3 +42,41 @@ func NewRequestOptions(headers map[string]string, queryParameters map[string][]s
 
 	return opts
 }
+
+// WithDefaultHeader returns a copy of the options with the header set to the value, unless the header is already specified.
+func (o RequestOptions) WithDefaultHeader(key, value string) RequestOptions {
+	headers := make(map[string]string, len(o.Headers)+1)
+	for k, v := range o.Headers {
+		if strings.EqualFold(k, key) {
+			return o
+		}
+		headers[k] = v
+	}
+	headers[key] = value
+	o.Headers = headers
+	return o
+}
+
+// WithInitialResponse returns a copy of the options which returns the body of the initial response instead of the result of the long-running operation.
+func (o RequestOptions) WithInitialResponse() RequestOptions {
+	o.ReturnInitialResponse = true
+	return o
+}
+
+// WithPollingInterval returns a copy of the options which polls the long-running operations at the interval.
+func (o RequestOptions) WithPollingInterval(interval time.Duration) RequestOptions {
+	o.PollingInterval = interval
+	return o
+}
+
+// WithPageConcurrency returns a copy of the options which fetches the pages of the list requests concurrently.
+func (o RequestOptions) WithPageConcurrency(concurrency int) RequestOptions {
+	o.PageConcurrency = concurrency
+	return o
+}
+
+// WithEndpoint returns a copy of the options which sends the request to the endpoint instead of the endpoint of the client.
+func (o RequestOptions) WithEndpoint(endpoint string) RequestOptions {
+	o.Endpoint = endpoint
+	return o
+}
@@ -0,0 +1,364 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/terraform-provider-azapi/azerrors"
+)
+
+// pollingFrequency is the interval between polling requests of long-running operations
+var pollingFrequency = 10 * time.Second
+
+// maxPollingResumes is the maximum number of times the polling is resumed after the access token expired
+const maxPollingResumes = 3
+
+// progressReportInterval is the minimum interval between the progress logs of a long-running operation, the changes of its status are always logged
+var progressReportInterval = time.Minute
+
+// pollUntilDone polls the long-running operation until it reaches a terminal state.
+// The access token may expire during a long-running operation which takes longer than the token lifetime,
+// in which case the polling request fails with ExpiredAuthenticationToken. Instead of failing the operation,
+// the polling is continued, the poller keeps its state and the next polling request is sent with a refreshed token.
+// The initial response is used to track the operation in the request metrics.
+// The Retry-After header of the responses takes precedence over the polling interval, the default polling frequency is used if the interval is zero.
+func pollUntilDone(ctx context.Context, pt *runtime.Poller[interface{}], initialResponse *http.Response, interval time.Duration) (interface{}, error) {
+	if interval <= 0 {
+		interval = pollingFrequency
+	}
+	metricsKey, operationUrl := "", ""
+	if initialResponse != nil {
+		metricsKey = requestMetricsKey(initialResponse.Request)
+		operationUrl = operationURL(initialResponse)
+	}
+	defaultRequestMetrics.update(metricsKey, func(m *resourceProviderMetrics) { m.Operations++ })
+	defer defaultRequestMetrics.update(metricsKey, func(m *resourceProviderMetrics) { m.Operations-- })
+
+	progress := &progressReporter{
+		operationUrl: operationUrl,
+		start:        time.Now(),
+	}
+	if initialResponse != nil {
+		if delay := retryAfterHeader(initialResponse); delay > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+	}
+
+	for resumes := 0; ; resumes++ {
+		resp, err := poll(ctx, pt, interval, progress)
+		if err == nil || resumes >= maxPollingResumes || pt.Done() || !isExpiredAuthenticationTokenError(err) {
+			if err != nil {
+				err = azerrors.FromPollingError(err, operationUrl)
+			}
+			return resp, err
+		}
+		log.Printf("[DEBUG] The access token expired while polling the long-running operation, resuming the polling: %+v", err)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// poll polls the long-running operation until it reaches a terminal state, the progress is reported after each polling request.
+// It waits for the duration of the Retry-After header of the polling responses if it's present, otherwise it waits for the interval.
+func poll(ctx context.Context, pt *runtime.Poller[interface{}], interval time.Duration, progress *progressReporter) (interface{}, error) {
+	for {
+		resp, err := pt.Poll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if pt.Done() {
+			return pt.Result(ctx)
+		}
+		progress.report(ctx, resp)
+
+		delay := interval
+		if v := retryAfterHeader(resp); v > 0 {
+			delay = v
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// progressReporter logs the progress of a long-running operation, so the users can tell whether it's progressing or stuck.
+type progressReporter struct {
+	operationUrl string
+	start        time.Time
+	lastReport   time.Time
+	lastStatus   string
+}
+
+// report logs the elapsed time, the status and the percentage of completion in the polling response,
+// and the remaining time before the timeout if the context has a deadline.
+func (r *progressReporter) report(ctx context.Context, resp *http.Response) {
+	status, percentComplete := operationProgress(resp)
+	now := time.Now()
+	if status == r.lastStatus && now.Sub(r.lastReport) < progressReportInterval {
+		return
+	}
+	r.lastReport, r.lastStatus = now, status
+
+	msg := fmt.Sprintf("The long-running operation %s is in progress, elapsed: %s", r.operationUrl, now.Sub(r.start).Round(time.Second))
+	if status != "" {
+		msg += fmt.Sprintf(", status: %s", status)
+	}
+	if percentComplete != nil {
+		msg += fmt.Sprintf(", percent complete: %.0f%%", *percentComplete)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		msg += fmt.Sprintf(", remaining time before the timeout: %s", time.Until(deadline).Round(time.Second))
+	}
+	log.Printf("[INFO] %s", msg)
+}
+
+// operationProgress returns the status and the percentage of completion in the polling response, they're empty if they're not found.
+// The status is the `status` of the operation status responses, or the `provisioningState` of the resource responses.
+func operationProgress(resp *http.Response) (string, *float64) {
+	if resp == nil {
+		return "", nil
+	}
+	payload, err := runtime.Payload(resp)
+	if err != nil || len(payload) == 0 {
+		return "", nil
+	}
+	var operationStatus struct {
+		Status          string   `json:"status"`
+		PercentComplete *float64 `json:"percentComplete"`
+		Properties      struct {
+			ProvisioningState string `json:"provisioningState"`
+		} `json:"properties"`
+	}
+	if json.Unmarshal(payload, &operationStatus) != nil {
+		return "", nil
+	}
+	status := operationStatus.Status
+	if status == "" {
+		status = operationStatus.Properties.ProvisioningState
+	}
+	return status, operationStatus.PercentComplete
+}
+
+// isExpiredAuthenticationTokenError returns true if the request was rejected because the access token expired.
+// The other 401 responses, e.g. the missing permissions or the wrong audience, aren't resumed, because a refreshed token doesn't fix them.
+func isExpiredAuthenticationTokenError(err error) bool {
+	var responseErr *azcore.ResponseError
+	if !errors.As(err, &responseErr) {
+		return false
+	}
+	if responseErr.ErrorCode == "ExpiredAuthenticationToken" {
+		return true
+	}
+	return responseErr.StatusCode == http.StatusUnauthorized && responseErr.RawResponse != nil && isExpiredTokenChallenge(responseErr.RawResponse.Header.Get("WWW-Authenticate"))
+}
+
+// isExpiredTokenChallenge returns true if the `WWW-Authenticate` challenge reports the access token as invalid or expired,
+// e.g. `Bearer error="invalid_token", error_description="The access token has expired"`.
+func isExpiredTokenChallenge(challenge string) bool {
+	challenge = strings.ToLower(challenge)
+	if strings.Contains(challenge, `error="invalid_token"`) {
+		return true
+	}
+	for _, claim := range []string{"token has expired", "token is expired", "token expired"} {
+		if strings.Contains(challenge, claim) {
+			return true
+		}
+	}
+	return false
+}
+
+// CancellationBehavior specifies how the long-running operation is handled when it's cancelled before it completes,
+// e.g. the user interrupts terraform or the operation exceeds the timeout.
+type CancellationBehavior string
+
+const (
+	// CancellationBehaviorAbandon abandons the operation, it might still be running in Azure.
+	CancellationBehaviorAbandon CancellationBehavior = "abandon"
+	// CancellationBehaviorRecord abandons the operation and records the operation URL, so the operation can be tracked and resumed.
+	CancellationBehaviorRecord CancellationBehavior = "record"
+	// CancellationBehaviorCancel requests the resource provider to cancel the operation if it's supported, otherwise the operation URL is recorded.
+	CancellationBehaviorCancel CancellationBehavior = "cancel"
+)
+
+// cancellationTimeout is the timeout of the request which cancels the long-running operation
+const cancellationTimeout = 2 * time.Minute
+
+// cancelActions is a map from the resource type to the action which cancels the running operation of the resource.
+var cancelActions = map[string]string{
+	"microsoft.resources/deployments": "cancel",
+}
+
+func isCancellationError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// operationURL returns the URL which is used to track the long-running operation.
+func operationURL(resp *http.Response) string {
+	for _, header := range []string{"Azure-AsyncOperation", "Operation-Location", "Location"} {
+		if v := resp.Header.Get(header); v != "" {
+			return v
+		}
+	}
+	if resp.Request != nil && resp.Request.URL != nil {
+		return resp.Request.URL.String()
+	}
+	return ""
+}
+
+// InterruptedOperationError is returned when the long-running operation is cancelled before it completes and it might still be running in Azure,
+// the resources record its operation URL in their private states, so the operation can still be tracked after terraform exits.
+type InterruptedOperationError struct {
+	OperationURL string
+	message      string
+	Err          error
+}
+
+func (e *InterruptedOperationError) Error() string {
+	return fmt.Sprintf("%s, the operation can be tracked at %s: %v", e.message, e.OperationURL, e.Err)
+}
+
+func (e *InterruptedOperationError) Unwrap() error {
+	return e.Err
+}
+
+// handleCancellation handles the long-running operation which is cancelled before it completes based on the cancellation behavior.
+func (client *ResourceClient) handleCancellation(resourceID string, apiVersion string, operationUrl string, err error) error {
+	switch client.cancellationBehavior {
+	case CancellationBehaviorRecord:
+		log.Printf("[WARN] The long-running operation of %s was cancelled before it completed, it can be tracked at %s", resourceID, operationUrl)
+		return &InterruptedOperationError{OperationURL: operationUrl, message: "the long-running operation was cancelled before it completed, it might still be running in Azure", Err: err}
+	case CancellationBehaviorCancel:
+		action, ok := "", false
+		if id, parseErr := arm.ParseResourceID(resourceID); parseErr == nil {
+			action, ok = cancelActions[strings.ToLower(id.ResourceType.String())]
+		}
+		if !ok {
+			log.Printf("[WARN] Cancelling the long-running operation of %s is not supported, it can be tracked at %s", resourceID, operationUrl)
+			return &InterruptedOperationError{OperationURL: operationUrl, message: "the long-running operation was cancelled before it completed, cancelling it in Azure is not supported for this resource type", Err: err}
+		}
+
+		// the original context is already done, use a new context to send the cancellation request
+		ctx, cancel := context.WithTimeout(context.Background(), cancellationTimeout)
+		defer cancel()
+		if _, cancelErr := client.action(ctx, resourceID, action, apiVersion, http.MethodPost, nil, DefaultRequestOptions()); cancelErr != nil {
+			return &InterruptedOperationError{OperationURL: operationUrl, message: fmt.Sprintf("the long-running operation was cancelled before it completed, and the request to cancel it in Azure failed: %+v", cancelErr), Err: err}
+		}
+		return fmt.Errorf("the long-running operation was cancelled before it completed, and it has been requested to be cancelled in Azure: %w", err)
+	default:
+		return fmt.Errorf("the long-running operation was cancelled before it completed, it has been abandoned and might still be running in Azure: %w", err)
+	}
+}
+
+// OperationCanceledSource is the party which canceled the long-running operation in Azure.
+type OperationCanceledSource string
+
+const (
+	// OperationCanceledByUser means the operation was canceled by a user, e.g. the deployment was canceled in the portal.
+	OperationCanceledByUser OperationCanceledSource = "user"
+	// OperationCanceledBySystem means the operation was canceled by the platform, e.g. it was preempted by a newer operation or denied by a policy.
+	OperationCanceledBySystem OperationCanceledSource = "system"
+	// OperationCanceledByUnknown means the cancellation source couldn't be determined from the operation status.
+	OperationCanceledByUnknown OperationCanceledSource = "unknown"
+)
+
+// OperationCanceledError is returned when the long-running operation reaches the terminal status `Canceled` in Azure.
+// Unlike the failed operations, it's never retried, because retrying would override the decision of whoever canceled it.
+type OperationCanceledError struct {
+	OperationURL string
+	Source       OperationCanceledSource
+	Code         string
+	Message      string
+	Err          error
+}
+
+func (e *OperationCanceledError) Error() string {
+	msg := fmt.Sprintf("the long-running operation was canceled in Azure, canceled by: %s", e.Source)
+	if e.Code != "" {
+		msg += fmt.Sprintf(", code: %s", e.Code)
+	}
+	if e.Message != "" {
+		msg += fmt.Sprintf(", message: %s", e.Message)
+	}
+	if e.OperationURL != "" {
+		msg += fmt.Sprintf(", the operation can be tracked at %s", e.OperationURL)
+	}
+	return msg
+}
+
+func (e *OperationCanceledError) Unwrap() error {
+	return e.Err
+}
+
+// systemCancellationKeywords are the keywords in the error code or message which indicate the operation was canceled by the platform
+var systemCancellationKeywords = []string{"preempt", "policy", "superseded", "system", "platform"}
+
+// asOperationCanceledError returns an OperationCanceledError if the polling error is caused by the terminal status `Canceled`, otherwise it returns nil.
+func asOperationCanceledError(err error, operationUrl string) *OperationCanceledError {
+	var responseErr *azcore.ResponseError
+	if !errors.As(err, &responseErr) || responseErr.RawResponse == nil {
+		return nil
+	}
+	payload, payloadErr := runtime.Payload(responseErr.RawResponse)
+	if payloadErr != nil || len(payload) == 0 {
+		return nil
+	}
+	var operationStatus struct {
+		Status     string `json:"status"`
+		Properties struct {
+			ProvisioningState string `json:"provisioningState"`
+		} `json:"properties"`
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(payload, &operationStatus) != nil {
+		return nil
+	}
+	status := operationStatus.Status
+	if status == "" {
+		status = operationStatus.Properties.ProvisioningState
+	}
+	if !strings.EqualFold(status, "Canceled") && !strings.EqualFold(status, "Cancelled") {
+		return nil
+	}
+
+	source := OperationCanceledByUnknown
+	code, message := operationStatus.Error.Code, operationStatus.Error.Message
+	reason := strings.ToLower(code + " " + message)
+	for _, keyword := range systemCancellationKeywords {
+		if strings.Contains(reason, keyword) {
+			source = OperationCanceledBySystem
+			break
+		}
+	}
+	if source == OperationCanceledByUnknown && (strings.Contains(reason, "user") || strings.EqualFold(code, "OperationCanceled") || strings.EqualFold(code, "Canceled")) {
+		source = OperationCanceledByUser
+	}
+	return &OperationCanceledError{
+		OperationURL: operationUrl,
+		Source:       source,
+		Code:         code,
+		Message:      message,
+		Err:          err,
+	}
+}
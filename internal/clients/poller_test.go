@@ -0,0 +1,393 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsExpiredAuthenticationTokenError(t *testing.T) {
+	newErr := func(errorCode string, challenge string) error {
+		resp := &http.Response{StatusCode: http.StatusUnauthorized, Header: http.Header{}}
+		if challenge != "" {
+			resp.Header.Set("WWW-Authenticate", challenge)
+		}
+		return &azcore.ResponseError{StatusCode: http.StatusUnauthorized, ErrorCode: errorCode, RawResponse: resp}
+	}
+
+	testcases := []struct {
+		Err    error
+		Expect bool
+	}{
+		{
+			Err:    newErr("ExpiredAuthenticationToken", ""),
+			Expect: true,
+		},
+		{
+			Err:    newErr("", `Bearer authorization_uri="https://login.microsoftonline.com/tenant", error="invalid_token", error_description="The access token is invalid"`),
+			Expect: true,
+		},
+		{
+			Err:    newErr("", `Bearer error="invalid_request", error_description="The access token has expired"`),
+			Expect: true,
+		},
+		{
+			Err:    newErr("InvalidAuthenticationTokenAudience", `Bearer authorization_uri="https://login.microsoftonline.com/tenant"`),
+			Expect: false,
+		},
+		{
+			Err:    newErr("AuthenticationFailed", ""),
+			Expect: false,
+		},
+		{
+			Err:    &azcore.ResponseError{StatusCode: http.StatusForbidden, ErrorCode: "AuthorizationFailed"},
+			Expect: false,
+		},
+	}
+
+	for _, testcase := range testcases {
+		assert.Equal(t, testcase.Expect, isExpiredAuthenticationTokenError(testcase.Err), testcase.Err.Error())
+	}
+}
+
+func TestPollUntilDoneResumesAfterExpiredAuthenticationToken(t *testing.T) {
+	frequency := pollingFrequency
+	pollingFrequency = time.Second
+	defer func() { pollingFrequency = frequency }()
+
+	testcases := []struct {
+		ExpiredPolls int
+		ExpectError  bool
+	}{
+		{
+			ExpiredPolls: 1,
+			ExpectError:  false,
+		},
+		{
+			ExpiredPolls: maxPollingResumes + 1,
+			ExpectError:  true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		polls := 0
+		var server *httptest.Server
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case r.Method == http.MethodPut:
+				w.Header().Set("Azure-AsyncOperation", server.URL+"/operation")
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"status":"InProgress"}`))
+			case r.URL.Path == "/operation":
+				polls++
+				if polls <= testcase.ExpiredPolls {
+					w.WriteHeader(http.StatusUnauthorized)
+					_, _ = w.Write([]byte(`{"error":{"code":"ExpiredAuthenticationToken","message":"The access token expiry UTC time is earlier than current UTC time."}}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"status":"Succeeded"}`))
+			default:
+				_, _ = w.Write([]byte(`{"name":"test"}`))
+			}
+		}))
+
+		pl := runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+			Transport: server.Client(),
+			Retry: policy.RetryOptions{
+				MaxRetries: -1,
+			},
+		})
+		req, err := runtime.NewRequest(context.Background(), http.MethodPut, server.URL+"/resource")
+		assert.NoError(t, err)
+		resp, err := pl.Do(req)
+		assert.NoError(t, err)
+		pt, err := runtime.NewPoller[interface{}](resp, pl, nil)
+		assert.NoError(t, err)
+
+		result, err := pollUntilDone(context.Background(), pt, resp, 0)
+		server.Close()
+		if testcase.ExpectError {
+			assert.ErrorContains(t, err, "ExpiredAuthenticationToken")
+			assert.Equal(t, maxPollingResumes+1, polls)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"name": "test"}, result)
+		assert.Equal(t, testcase.ExpiredPolls+1, polls)
+	}
+}
+
+func TestResourceClientCancellationBehavior(t *testing.T) {
+	frequency := pollingFrequency
+	pollingFrequency = time.Second
+	defer func() { pollingFrequency = frequency }()
+
+	testcases := []struct {
+		Behavior       CancellationBehavior
+		ResourceID     string
+		ExpectCancel   bool
+		ExpectRecorded bool
+		ExpectErrorMsg string
+	}{
+		{
+			Behavior:       CancellationBehaviorAbandon,
+			ResourceID:     "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Resources/deployments/deploy1",
+			ExpectErrorMsg: "it has been abandoned",
+		},
+		{
+			Behavior:       CancellationBehaviorRecord,
+			ResourceID:     "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Resources/deployments/deploy1",
+			ExpectRecorded: true,
+			ExpectErrorMsg: "/operation",
+		},
+		{
+			Behavior:       CancellationBehaviorCancel,
+			ResourceID:     "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Resources/deployments/deploy1",
+			ExpectCancel:   true,
+			ExpectErrorMsg: "it has been requested to be cancelled",
+		},
+		{
+			Behavior:       CancellationBehaviorCancel,
+			ResourceID:     "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Automation/automationAccounts/account1",
+			ExpectRecorded: true,
+			ExpectErrorMsg: "not supported",
+		},
+	}
+
+	for _, testcase := range testcases {
+		cancelled := false
+		var server *httptest.Server
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case r.Method == http.MethodPut:
+				w.Header().Set("Azure-AsyncOperation", server.URL+"/operation")
+				w.WriteHeader(http.StatusCreated)
+			case r.Method == http.MethodPost && r.URL.Path == testcase.ResourceID+"/cancel":
+				cancelled = true
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			_, _ = w.Write([]byte(`{"status":"InProgress"}`))
+		}))
+
+		client := &ResourceClient{
+			host: server.URL,
+			pl: runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+				Transport: server.Client(),
+				Retry: policy.RetryOptions{
+					MaxRetries: -1,
+				},
+			}),
+			cancellationBehavior: testcase.Behavior,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+		_, err := client.CreateOrUpdate(ctx, testcase.ResourceID, "2020-01-01", map[string]interface{}{}, DefaultRequestOptions())
+		cancel()
+		server.Close()
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorContains(t, err, testcase.ExpectErrorMsg)
+		assert.Equal(t, testcase.ExpectCancel, cancelled)
+		var interruptedErr *InterruptedOperationError
+		if assert.Equal(t, testcase.ExpectRecorded, errors.As(err, &interruptedErr)) && testcase.ExpectRecorded {
+			assert.Equal(t, server.URL+"/operation", interruptedErr.OperationURL)
+		}
+	}
+}
+
+func TestOperationCanceledIsNotRetried(t *testing.T) {
+	frequency := pollingFrequency
+	pollingFrequency = time.Second
+	defer func() { pollingFrequency = frequency }()
+
+	testcases := []struct {
+		OperationStatus string
+		ExpectSource    OperationCanceledSource
+	}{
+		{
+			OperationStatus: `{"status":"Canceled","error":{"code":"OperationCanceled","message":"The operation was canceled by the user."}}`,
+			ExpectSource:    OperationCanceledByUser,
+		},
+		{
+			OperationStatus: `{"status":"Canceled","error":{"code":"OperationPreempted","message":"The operation was preempted by a newer operation."}}`,
+			ExpectSource:    OperationCanceledBySystem,
+		},
+		{
+			OperationStatus: `{"status":"Canceled"}`,
+			ExpectSource:    OperationCanceledByUnknown,
+		},
+	}
+
+	for _, testcase := range testcases {
+		puts := 0
+		var server *httptest.Server
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case r.Method == http.MethodPut:
+				puts++
+				w.Header().Set("Azure-AsyncOperation", server.URL+"/operation")
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"status":"InProgress"}`))
+			case r.URL.Path == "/operation":
+				_, _ = w.Write([]byte(testcase.OperationStatus))
+			default:
+				_, _ = w.Write([]byte(`{"name":"test"}`))
+			}
+		}))
+
+		client := &ResourceClient{
+			host: server.URL,
+			pl: runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+				Transport: server.Client(),
+				Retry: policy.RetryOptions{
+					MaxRetries: -1,
+				},
+			}),
+		}
+		bkof, regexps := NewRetryableErrors(1, 1, 1, 0, []string{".*"})
+		_, err := client.WithRetry(bkof, regexps).CreateOrUpdate(context.Background(), "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1", "2020-01-01", map[string]interface{}{}, DefaultRequestOptions())
+		server.Close()
+
+		var canceledErr *OperationCanceledError
+		assert.ErrorAs(t, err, &canceledErr)
+		if canceledErr != nil {
+			assert.Equal(t, testcase.ExpectSource, canceledErr.Source)
+			assert.Equal(t, server.URL+"/operation", canceledErr.OperationURL)
+		}
+		assert.Equal(t, 1, puts)
+	}
+}
+
+func TestPollUntilDoneInterval(t *testing.T) {
+	frequency := pollingFrequency
+	pollingFrequency = time.Hour
+	defer func() { pollingFrequency = frequency }()
+
+	testcases := []struct {
+		Interval   time.Duration
+		RetryAfter string
+	}{
+		{
+			// the polling interval overrides the default polling frequency
+			Interval: time.Second,
+		},
+		{
+			// the Retry-After header takes precedence over the polling interval
+			Interval:   time.Hour,
+			RetryAfter: "1",
+		},
+	}
+
+	for _, testcase := range testcases {
+		polls := 0
+		var server *httptest.Server
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if testcase.RetryAfter != "" {
+				w.Header().Set("Retry-After", testcase.RetryAfter)
+			}
+			switch {
+			case r.Method == http.MethodPut:
+				w.Header().Set("Location", server.URL+"/operation")
+				w.WriteHeader(http.StatusAccepted)
+			case r.URL.Path == "/operation":
+				polls++
+				if polls < 2 {
+					w.Header().Set("Location", server.URL+"/operation")
+					w.WriteHeader(http.StatusAccepted)
+					return
+				}
+				_, _ = w.Write([]byte(`{"name":"test"}`))
+			}
+		}))
+
+		pl := runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+			Transport: server.Client(),
+			Retry: policy.RetryOptions{
+				MaxRetries: -1,
+			},
+		})
+		req, err := runtime.NewRequest(context.Background(), http.MethodPut, server.URL+"/resource")
+		assert.NoError(t, err)
+		resp, err := pl.Do(req)
+		assert.NoError(t, err)
+		pt, err := runtime.NewPoller[interface{}](resp, pl, nil)
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		result, err := pollUntilDone(ctx, pt, resp, testcase.Interval)
+		cancel()
+		server.Close()
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"name": "test"}, result)
+		assert.Equal(t, 2, polls)
+	}
+}
+
+func TestPollUntilDoneReportsProgress(t *testing.T) {
+	frequency, reportInterval := pollingFrequency, progressReportInterval
+	pollingFrequency, progressReportInterval = time.Second, 0
+	defer func() { pollingFrequency, progressReportInterval = frequency, reportInterval }()
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	polls := 0
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPut:
+			w.Header().Set("Azure-AsyncOperation", server.URL+"/operation")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"status":"InProgress"}`))
+		case r.URL.Path == "/operation":
+			polls++
+			if polls < 2 {
+				_, _ = w.Write([]byte(`{"status":"InProgress","percentComplete":50}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"status":"Succeeded"}`))
+		default:
+			_, _ = w.Write([]byte(`{"name":"test"}`))
+		}
+	}))
+	defer server.Close()
+
+	pl := runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		Transport: server.Client(),
+		Retry: policy.RetryOptions{
+			MaxRetries: -1,
+		},
+	})
+	req, err := runtime.NewRequest(context.Background(), http.MethodPut, server.URL+"/resource")
+	assert.NoError(t, err)
+	resp, err := pl.Do(req)
+	assert.NoError(t, err)
+	pt, err := runtime.NewPoller[interface{}](resp, pl, nil)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	result, err := pollUntilDone(ctx, pt, resp, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "test"}, result)
+	assert.Contains(t, logs.String(), "The long-running operation "+server.URL+"/operation is in progress")
+	assert.Contains(t, logs.String(), "status: InProgress, percent complete: 50%, remaining time before the timeout:")
+}
@@ -0,0 +1,100 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os/exec"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// preRequestHookPolicy invokes an external command before each request is sent.
+// The command receives a JSON object which contains the method, URL and body of the request on the standard input,
+// the query of the URL is removed except the api-version, because it may contain the credentials, e.g. the SAS token.
+// A non-zero exit code vetoes the request, and the standard error is reported as the reason.
+// The command may write a JSON object to the standard output to annotate the request, e.g. `{"headers":{"x-ms-guardrail":"approved"}}`.
+type preRequestHookPolicy struct {
+	command string
+}
+
+type preRequestHookInput struct {
+	Method string          `json:"method"`
+	Url    string          `json:"url"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+type preRequestHookOutput struct {
+	Headers map[string]string `json:"headers"`
+}
+
+// NewPreRequestHookPolicy returns a policy which runs the command before each request, so the request can be vetoed or annotated.
+func NewPreRequestHookPolicy(command string) (policy.Policy, error) {
+	if _, err := exec.LookPath(command); err != nil {
+		return nil, fmt.Errorf("looking up the pre-request hook %q: %+v", command, err)
+	}
+	return &preRequestHookPolicy{
+		command: command,
+	}, nil
+}
+
+func (p *preRequestHookPolicy) Do(req *policy.Request) (*http.Response, error) {
+	rawRequest := req.Raw()
+	input := preRequestHookInput{
+		Method: rawRequest.Method,
+		Url:    redactedRequestUrl(rawRequest.URL),
+	}
+	if body := req.Body(); body != nil {
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return nil, fmt.Errorf("reading the request body for the pre-request hook: %+v", err)
+		}
+		if err := req.RewindBody(); err != nil {
+			return nil, fmt.Errorf("rewinding the request body for the pre-request hook: %+v", err)
+		}
+		if len(data) != 0 {
+			if json.Valid(data) {
+				input.Body = data
+			} else {
+				// the non-JSON body is passed as a JSON string
+				input.Body, _ = json.Marshal(string(data))
+			}
+		}
+	}
+	stdin, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling the input of the pre-request hook: %+v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(rawRequest.Context(), p.command)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			reason := strings.TrimSpace(stderr.String())
+			if reason == "" {
+				reason = err.Error()
+			}
+			return nil, fmt.Errorf("the %s request to %s is vetoed by the pre-request hook: %s", rawRequest.Method, rawRequest.URL.Path, reason)
+		}
+		return nil, fmt.Errorf("running the pre-request hook %q: %+v", p.command, err)
+	}
+
+	if output := bytes.TrimSpace(stdout.Bytes()); len(output) != 0 {
+		var annotation preRequestHookOutput
+		if err := json.Unmarshal(output, &annotation); err != nil {
+			return nil, fmt.Errorf("unmarshalling the output of the pre-request hook %q: %+v", p.command, err)
+		}
+		for k, v := range annotation.Headers {
+			log.Printf("[DEBUG] The pre-request hook sets the header %q of the %s request to %s", k, rawRequest.Method, rawRequest.URL.Path)
+			rawRequest.Header.Set(k, v)
+		}
+	}
+	return req.Next()
+}
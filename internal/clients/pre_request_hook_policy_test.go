@@ -0,0 +1,91 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	azruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreRequestHookPolicy(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test hook is a shell script")
+	}
+
+	hook := filepath.Join(t.TempDir(), "hook.sh")
+	script := `#!/bin/sh
+input=$(cat)
+case "$input" in
+  *'"method":"DELETE"'*) echo "deleting resources is not allowed" >&2; exit 1 ;;
+  *'sig='*) echo "the query is passed to the hook" >&2; exit 1 ;;
+  *'"url":"http'*'/resource?api-version=2023-01-01"'*) echo '{"headers":{"x-guardrail":"versioned"}}' ;;
+  *'"sku":"Premium"'*) echo '{"headers":{"x-guardrail":"premium"}}' ;;
+esac
+`
+	assert.NoError(t, os.WriteFile(hook, []byte(script), 0o700))
+
+	guardrailHeader := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		guardrailHeader = r.Header.Get("x-guardrail")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	hookPolicy, err := NewPreRequestHookPolicy(hook)
+	assert.NoError(t, err)
+	pl := azruntime.NewPipeline("test", "v0.1.0", azruntime.PipelineOptions{}, &policy.ClientOptions{
+		Transport:       server.Client(),
+		PerCallPolicies: []policy.Policy{hookPolicy},
+	})
+
+	testcases := []struct {
+		Method         string
+		Query          string
+		Body           string
+		ExpectHeader   string
+		ExpectErrorMsg string
+	}{
+		{
+			Method: http.MethodGet,
+		},
+		{
+			Method:       http.MethodPut,
+			Body:         `{"sku":"Premium"}`,
+			ExpectHeader: "premium",
+		},
+		{
+			Method:         http.MethodDelete,
+			ExpectErrorMsg: "deleting resources is not allowed",
+		},
+		{
+			Method:       http.MethodGet,
+			Query:        "?api-version=2023-01-01&sv=2022-11-02&sig=secret",
+			ExpectHeader: "versioned",
+		},
+	}
+
+	for _, testcase := range testcases {
+		guardrailHeader = ""
+		req, err := azruntime.NewRequest(context.Background(), testcase.Method, server.URL+"/resource"+testcase.Query)
+		assert.NoError(t, err)
+		if testcase.Body != "" {
+			assert.NoError(t, req.SetBody(streaming.NopCloser(strings.NewReader(testcase.Body)), "application/json"))
+		}
+		_, err = pl.Do(req)
+		if testcase.ExpectErrorMsg != "" {
+			assert.ErrorContains(t, err, testcase.ExpectErrorMsg)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, testcase.ExpectHeader, guardrailHeader)
+	}
+}
@@ -0,0 +1,93 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+)
+
+// setRequestBody sets the body of the request according to the Content-Type header of the request.
+// The body is sent as is if it's a byte slice, e.g. the contents of a file, or if it's a string and the content type isn't JSON.
+// An object body is form-encoded if the content type is `application/x-www-form-urlencoded`, otherwise the body is marshaled as JSON.
+func setRequestBody(req *policy.Request, body interface{}) error {
+	contentType := req.Raw().Header.Get("Content-Type")
+	switch payload := body.(type) {
+	case []byte:
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		return req.SetBody(streaming.NopCloser(bytes.NewReader(payload)), contentType)
+	case string:
+		if contentType != "" && !strings.Contains(contentType, "json") {
+			return req.SetBody(streaming.NopCloser(strings.NewReader(payload)), contentType)
+		}
+	case map[string]interface{}:
+		if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "application/x-www-form-urlencoded" {
+			form, err := formEncode(payload)
+			if err != nil {
+				return err
+			}
+			return req.SetBody(streaming.NopCloser(strings.NewReader(form)), contentType)
+		}
+	}
+	if err := runtime.MarshalAsJSON(req, body); err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Raw().Header.Set("Content-Type", contentType)
+	}
+	return nil
+}
+
+// formEncode encodes the object as a form, the lists are encoded as repeated fields and the nested objects are encoded as JSON strings.
+func formEncode(body map[string]interface{}) (string, error) {
+	keys := make([]string, 0, len(body))
+	for key := range body {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	form := url.Values{}
+	for _, key := range keys {
+		values, ok := body[key].([]interface{})
+		if !ok {
+			values = []interface{}{body[key]}
+		}
+		for _, value := range values {
+			v, err := formValue(value)
+			if err != nil {
+				return "", fmt.Errorf("encoding the form field %q: %+v", key, err)
+			}
+			form.Add(key, v)
+		}
+	}
+	return form.Encode(), nil
+}
+
+func formValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
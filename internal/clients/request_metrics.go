@@ -0,0 +1,186 @@
+package clients
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// requestMetricsReportInterval is the interval of reporting the request metrics while there are pending requests, it's a variable so that the tests can shorten it.
+var requestMetricsReportInterval = 30 * time.Second
+
+// requestMetrics tracks the gauges and the counters of the requests by the resource provider namespace, e.g. `microsoft.network`,
+// or by the host of the data plane requests. They're reported in the logs while there are pending requests,
+// so the operators can tell whether a slow apply waits for Azure or for the provider:
+// the in-flight requests and the polled long-running operations wait for Azure, the queued requests are held back by the provider because the resource provider throttles them.
+type requestMetrics struct {
+	mutex   sync.Mutex
+	metrics map[string]*resourceProviderMetrics
+	// reporting is true while the reporting goroutine is running, it stops once there are no pending requests
+	reporting bool
+}
+
+type resourceProviderMetrics struct {
+	InFlight   int
+	Queued     int
+	Operations int
+	Requests   int
+	Retries    int
+	Throttled  int
+}
+
+func (m resourceProviderMetrics) pending() bool {
+	return m.InFlight != 0 || m.Queued != 0 || m.Operations != 0
+}
+
+func (m resourceProviderMetrics) String() string {
+	return fmt.Sprintf("in-flight=%d queued=%d operations=%d requests=%d retries=%d throttled=%d", m.InFlight, m.Queued, m.Operations, m.Requests, m.Retries, m.Throttled)
+}
+
+// defaultRequestMetrics is shared by the clients, because the resource providers throttle the requests of all the provider instances in the process.
+var defaultRequestMetrics = newRequestMetrics()
+
+func newRequestMetrics() *requestMetrics {
+	return &requestMetrics{
+		metrics: make(map[string]*resourceProviderMetrics),
+	}
+}
+
+// update applies the change to the metrics of the key, and starts reporting the metrics if there are pending requests.
+func (r *requestMetrics) update(key string, change func(m *resourceProviderMetrics)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	m, ok := r.metrics[key]
+	if !ok {
+		m = &resourceProviderMetrics{}
+		r.metrics[key] = m
+	}
+	change(m)
+	if m.pending() && !r.reporting {
+		r.reporting = true
+		go r.report()
+	}
+}
+
+// snapshot returns a copy of the metrics and whether any request is pending.
+func (r *requestMetrics) snapshot() (map[string]resourceProviderMetrics, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	out := make(map[string]resourceProviderMetrics, len(r.metrics))
+	pending := false
+	for key, m := range r.metrics {
+		out[key] = *m
+		pending = pending || m.pending()
+	}
+	if !pending {
+		r.reporting = false
+	}
+	return out, pending
+}
+
+func (r *requestMetrics) report() {
+	ticker := time.NewTicker(requestMetricsReportInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		metrics, pending := r.snapshot()
+		if !pending {
+			return
+		}
+		log.Printf("[INFO] request metrics: %s", formatRequestMetrics(metrics))
+	}
+}
+
+// formatRequestMetrics returns the total metrics followed by the metrics of the keys which have pending requests, sorted by the keys.
+func formatRequestMetrics(metrics map[string]resourceProviderMetrics) string {
+	var total resourceProviderMetrics
+	keys := make([]string, 0, len(metrics))
+	for key, m := range metrics {
+		total.InFlight += m.InFlight
+		total.Queued += m.Queued
+		total.Operations += m.Operations
+		total.Requests += m.Requests
+		total.Retries += m.Retries
+		total.Throttled += m.Throttled
+		if m.pending() {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	parts := []string{"total: " + total.String()}
+	for _, key := range keys {
+		parts = append(parts, key+": "+metrics[key].String())
+	}
+	return strings.Join(parts, "; ")
+}
+
+// requestMetricsKey returns the lower-cased resource provider namespace of the request, or its host if it doesn't target a resource provider.
+func requestMetricsKey(req *http.Request) string {
+	if req == nil || req.URL == nil {
+		return ""
+	}
+	if namespace := resourceProviderNamespace(req.URL.Path); namespace != "" {
+		return namespace
+	}
+	return strings.ToLower(req.URL.Host)
+}
+
+// requestAttempts counts the attempts of a request, it's shared by the retries of the request.
+type requestAttempts struct {
+	count int
+}
+
+// requestMetricsPolicy marks the requests, so the requestMetricsRetryPolicy could count their retries.
+type requestMetricsPolicy struct{}
+
+// NewRequestMetricsPolicy returns a per-call policy which must be used with the policy returned by NewRequestMetricsRetryPolicy.
+func NewRequestMetricsPolicy() policy.Policy {
+	return requestMetricsPolicy{}
+}
+
+func (requestMetricsPolicy) Do(req *policy.Request) (*http.Response, error) {
+	req.SetOperationValue(&requestAttempts{})
+	return req.Next()
+}
+
+// requestMetricsRetryPolicy tracks the in-flight requests and the retries.
+type requestMetricsRetryPolicy struct {
+	metrics *requestMetrics
+}
+
+// NewRequestMetricsRetryPolicy returns a per-retry policy which tracks the requests in the shared metrics.
+func NewRequestMetricsRetryPolicy() policy.Policy {
+	return requestMetricsRetryPolicy{
+		metrics: defaultRequestMetrics,
+	}
+}
+
+func (p requestMetricsRetryPolicy) Do(req *policy.Request) (*http.Response, error) {
+	key := requestMetricsKey(req.Raw())
+	retry := false
+	var attempts *requestAttempts
+	if req.OperationValue(&attempts) && attempts != nil {
+		attempts.count++
+		retry = attempts.count > 1
+	}
+	p.metrics.update(key, func(m *resourceProviderMetrics) {
+		m.InFlight++
+		m.Requests++
+		if retry {
+			m.Retries++
+		}
+	})
+	resp, err := req.Next()
+	p.metrics.update(key, func(m *resourceProviderMetrics) {
+		m.InFlight--
+		if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+			m.Throttled++
+		}
+	})
+	return resp, err
+}
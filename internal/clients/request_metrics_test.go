@@ -0,0 +1,56 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestMetricsPolicy(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	metrics := newRequestMetrics()
+	pl := runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		Transport:        server.Client(),
+		PerCallPolicies:  []policy.Policy{NewRequestMetricsPolicy()},
+		PerRetryPolicies: []policy.Policy{requestMetricsRetryPolicy{metrics: metrics}},
+		Retry: policy.RetryOptions{
+			MaxRetries: 1,
+			RetryDelay: time.Millisecond,
+		},
+	})
+	req, err := runtime.NewRequest(context.Background(), http.MethodPut, server.URL+"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1")
+	assert.NoError(t, err)
+	resp, err := pl.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	snapshot, pending := metrics.snapshot()
+	assert.False(t, pending)
+	assert.Equal(t, map[string]resourceProviderMetrics{
+		"microsoft.network": {Requests: 2, Retries: 1},
+	}, snapshot)
+}
+
+func TestFormatRequestMetrics(t *testing.T) {
+	output := formatRequestMetrics(map[string]resourceProviderMetrics{
+		"microsoft.storage": {Requests: 3},
+		"microsoft.network": {InFlight: 1, Queued: 2, Operations: 1, Requests: 5, Retries: 1, Throttled: 1},
+	})
+	assert.Equal(t, "total: in-flight=1 queued=2 operations=1 requests=8 retries=1 throttled=1; microsoft.network: in-flight=1 queued=2 operations=1 requests=5 retries=1 throttled=1", output)
+}
@@ -5,8 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
@@ -24,8 +28,13 @@ const (
 )
 
 type ResourceClient struct {
-	host string
-	pl   runtime.Pipeline
+	host                 string
+	pl                   runtime.Pipeline
+	cancellationBehavior CancellationBehavior
+	// apiVersionFallbacks is nil if the requests don't fall back to the supported api-versions
+	apiVersionFallbacks *apiVersionFallbacks
+	// apiVersionOverrides are the pinned api-versions keyed by the lower-cased resource types
+	apiVersionOverrides map[string]string
 }
 
 // ResourceClientRetryableErrors is a wrapper around ResourceClient that allows for retrying on specific errors.
@@ -117,6 +126,9 @@ func (retryclient *ResourceClientRetryableErrors) CreateOrUpdate(ctx context.Con
 		func() (interface{}, error) {
 			data, err := retryclient.client.CreateOrUpdate(ctx, resourceID, apiVersion, body, options)
 			if err != nil {
+				if errors.As(err, new(*OperationCanceledError)) {
+					return nil, &backoff.PermanentError{Err: err}
+				}
 				for _, e := range retryclient.errors {
 					if e.MatchString(err.Error()) {
 						return data, err
@@ -131,27 +143,34 @@ func (retryclient *ResourceClientRetryableErrors) CreateOrUpdate(ctx context.Con
 }
 
 func (client *ResourceClient) CreateOrUpdate(ctx context.Context, resourceID string, apiVersion string, body interface{}, options RequestOptions) (interface{}, error) {
-	resp, err := client.createOrUpdate(ctx, resourceID, apiVersion, body, options)
+	resp, err := client.createOrUpdate(ctx, resourceID, client.resolveApiVersion(resourceID, apiVersion), body, options)
+	if err != nil && client.fallbackApiVersion(resourceID, apiVersion, err) {
+		resp, err = client.createOrUpdate(ctx, resourceID, client.resolveApiVersion(resourceID, apiVersion), body, options)
+	}
 	if err != nil {
 		return nil, err
 	}
-	var responseBody interface{}
+	if isSynchronousNonJSONResponse(resp) {
+		return unmarshalResponseBody(resp)
+	}
 	pt, err := runtime.NewPoller[interface{}](resp, client.pl, nil)
 	if err == nil {
-		resp, err := pt.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{
-			Frequency: 10 * time.Second,
-		})
+		operationUrl := operationURL(resp)
+		resp, err := pollUntilDone(ctx, pt, resp, options.PollingInterval)
 		if err == nil {
 			return resp, nil
 		}
-		if !client.shouldIgnorePollingError(err) {
+		if isCancellationError(err) {
+			return nil, client.handleCancellation(resourceID, apiVersion, operationUrl, err)
+		}
+		if canceledErr := asOperationCanceledError(err, operationUrl); canceledErr != nil {
+			return nil, canceledErr
+		}
+		if !client.shouldIgnorePollingError(err, options) {
 			return nil, err
 		}
 	}
-	if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
-		return nil, err
-	}
-	return responseBody, nil
+	return unmarshalResponseBody(resp)
 }
 
 func (client *ResourceClient) createOrUpdate(ctx context.Context, resourceID string, apiVersion string, body interface{}, options RequestOptions) (*http.Response, error) {
@@ -164,14 +183,14 @@ func (client *ResourceClient) createOrUpdate(ctx context.Context, resourceID str
 		return nil, err
 	}
 	if !runtime.HasStatusCode(resp, http.StatusOK, http.StatusCreated, http.StatusAccepted) {
-		return nil, runtime.NewResponseError(resp)
+		return nil, newResponseError(resp)
 	}
 	return resp, nil
 }
 
 func (client *ResourceClient) createOrUpdateCreateRequest(ctx context.Context, resourceID string, apiVersion string, body interface{}, options RequestOptions) (*policy.Request, error) {
 	urlPath := resourceID
-	req, err := runtime.NewRequest(ctx, http.MethodPut, runtime.JoinPaths(client.host, urlPath))
+	req, err := runtime.NewRequest(ctx, http.MethodPut, runtime.JoinPaths(client.endpoint(options), urlPath))
 	if err != nil {
 		return nil, err
 	}
@@ -214,6 +233,14 @@ func (retryclient *ResourceClientRetryableErrors) Get(ctx context.Context, resou
 }
 
 func (client *ResourceClient) Get(ctx context.Context, resourceID string, apiVersion string, options RequestOptions) (interface{}, error) {
+	responseBody, err := client.get(ctx, resourceID, client.resolveApiVersion(resourceID, apiVersion), options)
+	if err != nil && client.fallbackApiVersion(resourceID, apiVersion, err) {
+		responseBody, err = client.get(ctx, resourceID, client.resolveApiVersion(resourceID, apiVersion), options)
+	}
+	return responseBody, err
+}
+
+func (client *ResourceClient) get(ctx context.Context, resourceID string, apiVersion string, options RequestOptions) (interface{}, error) {
 	req, err := client.getCreateRequest(ctx, resourceID, apiVersion, options)
 	if err != nil {
 		return nil, err
@@ -223,19 +250,48 @@ func (client *ResourceClient) Get(ctx context.Context, resourceID string, apiVer
 		return nil, err
 	}
 	if !runtime.HasStatusCode(resp, http.StatusOK) {
-		return nil, runtime.NewResponseError(resp)
+		return nil, newResponseError(resp)
 	}
+	recordResponseEtag(ctx, resp)
 
-	var responseBody interface{}
-	if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
-		return nil, err
+	return unmarshalResponseBody(resp)
+}
+
+// CheckExistence checks whether the resource exists with a HEAD request, which doesn't transfer the resource body.
+// It falls back to a GET request if the resource provider doesn't support the HEAD request.
+func (client *ResourceClient) CheckExistence(ctx context.Context, resourceID string, apiVersion string, options RequestOptions) (bool, error) {
+	req, err := client.getCreateRequest(ctx, resourceID, client.resolveApiVersion(resourceID, apiVersion), options)
+	if err != nil {
+		return false, err
+	}
+	req.Raw().Method = http.MethodHead
+	resp, err := client.pl.Do(req)
+	if err != nil {
+		return false, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented, http.StatusBadRequest:
+		_, err = client.Get(ctx, resourceID, apiVersion, options)
+		if err == nil {
+			return true, nil
+		}
+		var responseErr *azcore.ResponseError
+		if errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, err
+	default:
+		return false, newResponseError(resp)
 	}
-	return responseBody, nil
 }
 
 func (client *ResourceClient) getCreateRequest(ctx context.Context, resourceID string, apiVersion string, options RequestOptions) (*policy.Request, error) {
 	urlPath := resourceID
-	req, err := runtime.NewRequest(ctx, http.MethodGet, runtime.JoinPaths(client.host, urlPath))
+	req, err := runtime.NewRequest(ctx, http.MethodGet, runtime.JoinPaths(client.endpoint(options), urlPath))
 	if err != nil {
 		return nil, err
 	}
@@ -264,6 +320,9 @@ func (retryclient *ResourceClientRetryableErrors) Delete(ctx context.Context, re
 		func() (interface{}, error) {
 			data, err := retryclient.client.Delete(ctx, resourceID, apiVersion, options)
 			if err != nil {
+				if errors.As(err, new(*OperationCanceledError)) {
+					return nil, &backoff.PermanentError{Err: err}
+				}
 				for _, e := range retryclient.errors {
 					if e.MatchString(err.Error()) {
 						return data, err
@@ -278,27 +337,34 @@ func (retryclient *ResourceClientRetryableErrors) Delete(ctx context.Context, re
 }
 
 func (client *ResourceClient) Delete(ctx context.Context, resourceID string, apiVersion string, options RequestOptions) (interface{}, error) {
-	resp, err := client.delete(ctx, resourceID, apiVersion, options)
+	resp, err := client.delete(ctx, resourceID, client.resolveApiVersion(resourceID, apiVersion), options)
+	if err != nil && client.fallbackApiVersion(resourceID, apiVersion, err) {
+		resp, err = client.delete(ctx, resourceID, client.resolveApiVersion(resourceID, apiVersion), options)
+	}
 	if err != nil {
 		return nil, err
 	}
-	var responseBody interface{}
+	if isSynchronousNonJSONResponse(resp) {
+		return unmarshalResponseBody(resp)
+	}
 	pt, err := runtime.NewPoller[interface{}](resp, client.pl, nil)
 	if err == nil {
-		resp, err := pt.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{
-			Frequency: 10 * time.Second,
-		})
+		operationUrl := operationURL(resp)
+		resp, err := pollUntilDone(ctx, pt, resp, options.PollingInterval)
 		if err == nil {
 			return resp, nil
 		}
-		if !client.shouldIgnorePollingError(err) {
+		if isCancellationError(err) {
+			return nil, client.handleCancellation(resourceID, apiVersion, operationUrl, err)
+		}
+		if canceledErr := asOperationCanceledError(err, operationUrl); canceledErr != nil {
+			return nil, canceledErr
+		}
+		if !client.shouldIgnorePollingError(err, options) {
 			return nil, err
 		}
 	}
-	if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
-		return nil, err
-	}
-	return responseBody, nil
+	return unmarshalResponseBody(resp)
 }
 
 func (client *ResourceClient) delete(ctx context.Context, resourceID string, apiVersion string, options RequestOptions) (*http.Response, error) {
@@ -311,14 +377,14 @@ func (client *ResourceClient) delete(ctx context.Context, resourceID string, api
 		return nil, err
 	}
 	if !runtime.HasStatusCode(resp, http.StatusOK, http.StatusAccepted, http.StatusNoContent) {
-		return nil, runtime.NewResponseError(resp)
+		return nil, newResponseError(resp)
 	}
 	return resp, nil
 }
 
 func (client *ResourceClient) deleteCreateRequest(ctx context.Context, resourceID string, apiVersion string, options RequestOptions) (*policy.Request, error) {
 	urlPath := resourceID
-	req, err := runtime.NewRequest(ctx, http.MethodDelete, runtime.JoinPaths(client.host, urlPath))
+	req, err := runtime.NewRequest(ctx, http.MethodDelete, runtime.JoinPaths(client.endpoint(options), urlPath))
 	if err != nil {
 		return nil, err
 	}
@@ -347,6 +413,9 @@ func (retryclient *ResourceClientRetryableErrors) Action(ctx context.Context, re
 		func() (interface{}, error) {
 			data, err := retryclient.client.Action(ctx, resourceID, action, apiVersion, method, body, options)
 			if err != nil {
+				if errors.As(err, new(*OperationCanceledError)) {
+					return nil, &backoff.PermanentError{Err: err}
+				}
 				for _, e := range retryclient.errors {
 					if e.MatchString(err.Error()) {
 						return data, err
@@ -361,39 +430,36 @@ func (retryclient *ResourceClientRetryableErrors) Action(ctx context.Context, re
 }
 
 func (client *ResourceClient) Action(ctx context.Context, resourceID string, action string, apiVersion string, method string, body interface{}, options RequestOptions) (interface{}, error) {
-	resp, err := client.action(ctx, resourceID, action, apiVersion, method, body, options)
+	resp, err := client.action(ctx, resourceID, action, client.resolveApiVersion(resourceID, apiVersion), method, body, options)
+	if err != nil && client.fallbackApiVersion(resourceID, apiVersion, err) {
+		resp, err = client.action(ctx, resourceID, action, client.resolveApiVersion(resourceID, apiVersion), method, body, options)
+	}
 	if err != nil {
 		return nil, err
 	}
-	var responseBody interface{}
+	// the action completed synchronously with a non-JSON payload, e.g. a kubeconfig file, which the poller can't decode
+	if isSynchronousNonJSONResponse(resp) {
+		return unmarshalResponseBody(resp)
+	}
 	pt, err := runtime.NewPoller[interface{}](resp, client.pl, nil)
 	if err == nil {
-		resp, err := pt.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{
-			Frequency: 10 * time.Second,
-		})
+		operationUrl := operationURL(resp)
+		resp, err := pollUntilDone(ctx, pt, resp, options.PollingInterval)
 		if err == nil {
 			return resp, nil
 		}
-		if !client.shouldIgnorePollingError(err) {
-			return nil, err
+		if isCancellationError(err) {
+			return nil, client.handleCancellation(resourceID, apiVersion, operationUrl, err)
 		}
-	}
-
-	contentType := resp.Header.Get("Content-Type")
-	switch {
-	case strings.Contains(contentType, "text/plain"):
-		payload, err := runtime.Payload(resp)
-		if err != nil {
-			return nil, err
+		if canceledErr := asOperationCanceledError(err, operationUrl); canceledErr != nil {
+			return nil, canceledErr
 		}
-		responseBody = string(payload)
-	case strings.Contains(contentType, "application/json"):
-		if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
+		if !client.shouldIgnorePollingError(err, options) {
 			return nil, err
 		}
-	default:
 	}
-	return responseBody, nil
+
+	return unmarshalResponseBody(resp)
 }
 
 func (client *ResourceClient) action(ctx context.Context, resourceID string, action string, apiVersion string, method string, body interface{}, options RequestOptions) (*http.Response, error) {
@@ -406,7 +472,7 @@ func (client *ResourceClient) action(ctx context.Context, resourceID string, act
 		return nil, err
 	}
 	if !runtime.HasStatusCode(resp, http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent) {
-		return nil, runtime.NewResponseError(resp)
+		return nil, newResponseError(resp)
 	}
 	return resp, nil
 }
@@ -416,7 +482,7 @@ func (client *ResourceClient) actionCreateRequest(ctx context.Context, resourceI
 	if action != "" {
 		urlPath = fmt.Sprintf("%s/%s", resourceID, action)
 	}
-	req, err := runtime.NewRequest(ctx, method, runtime.JoinPaths(client.host, urlPath))
+	req, err := runtime.NewRequest(ctx, method, runtime.JoinPaths(client.endpoint(options), urlPath))
 	if err != nil {
 		return nil, err
 	}
@@ -431,7 +497,7 @@ func (client *ResourceClient) actionCreateRequest(ctx context.Context, resourceI
 		req.Raw().Header.Set(key, value)
 	}
 	if method != "GET" && body != nil {
-		err = runtime.MarshalAsJSON(req, body)
+		err = setRequestBody(req, body)
 	}
 	return req, err
 }
@@ -462,6 +528,9 @@ func (retryclient *ResourceClientRetryableErrors) List(ctx context.Context, url
 }
 
 func (client *ResourceClient) List(ctx context.Context, url string, apiVersion string, options RequestOptions) (interface{}, error) {
+	// The pages are fetched sequentially. ARM doesn't support random access to the pages, the nextLink of a page
+	// usually contains an opaque continuation token which is only known after the previous page is fetched.
+	// The pages whose nextLink has a numeric offset are fetched concurrently when the PageConcurrency of the options is set.
 	pager := runtime.NewPager[interface{}](runtime.PagingHandler[interface{}]{
 		More: func(current interface{}) bool {
 			if current == nil {
@@ -482,7 +551,7 @@ func (client *ResourceClient) List(ctx context.Context, url string, apiVersion s
 		Fetcher: func(ctx context.Context, current *interface{}) (interface{}, error) {
 			var request *policy.Request
 			if current == nil {
-				req, err := runtime.NewRequest(ctx, http.MethodGet, runtime.JoinPaths(client.host, url))
+				req, err := runtime.NewRequest(ctx, http.MethodGet, runtime.JoinPaths(client.endpoint(options), url))
 				if err != nil {
 					return nil, err
 				}
@@ -501,11 +570,7 @@ func (client *ResourceClient) List(ctx context.Context, url string, apiVersion s
 				if currentMap, ok := (*current).(map[string]interface{}); ok && currentMap["nextLink"] != nil {
 					nextLink = currentMap["nextLink"].(string)
 				}
-				req, err := runtime.NewRequest(ctx, http.MethodGet, nextLink)
-				if err != nil {
-					return nil, err
-				}
-				request = req
+				return client.listPage(ctx, nextLink)
 			}
 			request.Raw().Header.Set("Accept", "application/json")
 			resp, err := client.pl.Do(request)
@@ -513,13 +578,9 @@ func (client *ResourceClient) List(ctx context.Context, url string, apiVersion s
 				return nil, err
 			}
 			if !runtime.HasStatusCode(resp, http.StatusOK) {
-				return nil, runtime.NewResponseError(resp)
+				return nil, newResponseError(resp)
 			}
-			var responseBody interface{}
-			if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
-				return nil, err
-			}
-			return responseBody, nil
+			return unmarshalResponseBody(resp)
 		},
 	})
 
@@ -534,6 +595,17 @@ func (client *ResourceClient) List(ctx context.Context, url string, apiVersion s
 			if pageMap["value"] != nil {
 				if pageValue, ok := pageMap["value"].([]interface{}); ok {
 					value = append(value, pageValue...)
+					// the offset of the first nextLink is the page size, so the nextLinks of the following pages are predictable
+					if nextLink, _ := pageMap["nextLink"].(string); options.PageConcurrency > 1 && len(value) == len(pageValue) && nextLink != "" {
+						if _, _, step, ok := parseOffsetLink(nextLink); ok && step > 0 {
+							pagesValue, err := client.listPagesConcurrently(ctx, nextLink, step, options.PageConcurrency)
+							if err != nil {
+								return nil, err
+							}
+							value = append(value, pagesValue...)
+							break
+						}
+					}
 					continue
 				}
 			}
@@ -547,7 +619,112 @@ func (client *ResourceClient) List(ctx context.Context, url string, apiVersion s
 	}, nil
 }
 
-func (client *ResourceClient) shouldIgnorePollingError(err error) bool {
+// listPagesConcurrently fetches the pages from the nextLink whose offset is increased by the step for each page, the pages are fetched in batches of the concurrency
+// and reassembled in order. The prefetched pages are dropped if the nextLink of a page isn't the predicted one, and the listing continues from that nextLink.
+func (client *ResourceClient) listPagesConcurrently(ctx context.Context, nextLink string, step int, concurrency int) ([]interface{}, error) {
+	value := make([]interface{}, 0)
+	for nextLink != "" {
+		links := []string{nextLink}
+		if link, key, offset, ok := parseOffsetLink(nextLink); ok {
+			for i := 1; i < concurrency; i++ {
+				query := link.Query()
+				query.Set(key, strconv.Itoa(offset+step*i))
+				predicted := *link
+				predicted.RawQuery = query.Encode()
+				links = append(links, predicted.String())
+			}
+		}
+
+		pages := make([]interface{}, len(links))
+		errs := make([]error, len(links))
+		var wg sync.WaitGroup
+		for i, link := range links {
+			wg.Add(1)
+			go func(i int, link string) {
+				defer wg.Done()
+				pages[i], errs[i] = client.listPage(ctx, link)
+			}(i, link)
+		}
+		wg.Wait()
+
+		nextLink = ""
+		for i, page := range pages {
+			if errs[i] != nil {
+				return nil, errs[i]
+			}
+			pageMap, ok := page.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("the response of %s doesn't follow the ARM paging guideline", links[i])
+			}
+			pageValue, ok := pageMap["value"].([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("the response of %s doesn't follow the ARM paging guideline", links[i])
+			}
+			value = append(value, pageValue...)
+			nextLink, _ = pageMap["nextLink"].(string)
+			if nextLink == "" || i+1 == len(links) || !sameLink(nextLink, links[i+1]) {
+				break
+			}
+		}
+	}
+	return value, nil
+}
+
+// listPage fetches the page of the nextLink.
+func (client *ResourceClient) listPage(ctx context.Context, nextLink string) (interface{}, error) {
+	req, err := runtime.NewRequest(ctx, http.MethodGet, nextLink)
+	if err != nil {
+		return nil, err
+	}
+	req.Raw().Header.Set("Accept", "application/json")
+	resp, err := client.pl.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return nil, newResponseError(resp)
+	}
+	return unmarshalResponseBody(resp)
+}
+
+// parseOffsetLink returns the nextLink, the name and the value of its numeric `$skip` or `$skipToken` query parameter.
+func parseOffsetLink(nextLink string) (*url.URL, string, int, bool) {
+	link, err := url.Parse(nextLink)
+	if err != nil {
+		return nil, "", 0, false
+	}
+	for key, values := range link.Query() {
+		if !strings.EqualFold(key, "$skip") && !strings.EqualFold(key, "$skipToken") || len(values) != 1 {
+			continue
+		}
+		offset, err := strconv.Atoi(values[0])
+		if err != nil || offset < 0 {
+			return nil, "", 0, false
+		}
+		return link, key, offset, true
+	}
+	return nil, "", 0, false
+}
+
+// sameLink returns whether the links are the same regardless of the order of the query parameters.
+func sameLink(a, b string) bool {
+	linkA, errA := url.Parse(a)
+	linkB, errB := url.Parse(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return strings.EqualFold(linkA.Host, linkB.Host) && linkA.Path == linkB.Path && reflect.DeepEqual(linkA.Query(), linkB.Query())
+}
+
+// endpoint returns the endpoint which the requests are sent to, the endpoint of the options overrides the endpoint of the client.
+func (client *ResourceClient) endpoint(options RequestOptions) string {
+	if options.Endpoint != "" {
+		return strings.TrimSuffix(options.Endpoint, "/")
+	}
+	return client.host
+}
+
+func (client *ResourceClient) shouldIgnorePollingError(err error, options RequestOptions) bool {
 	if err == nil {
 		return true
 	}
@@ -558,7 +735,7 @@ func (client *ResourceClient) shouldIgnorePollingError(err error) bool {
 			// all control plane APIs must flow through ARM, ignore the polling error if it's not ARM
 			// issue: https://github.com/Azure/azure-rest-api-specs/issues/25356, in this case, the polling url is not exposed by ARM
 			pollRequest := responseErr.RawResponse.Request
-			if pollRequest.Host != strings.TrimPrefix(client.host, "https://") {
+			if pollRequest.Host != strings.TrimPrefix(client.host, "https://") && pollRequest.Host != strings.TrimPrefix(client.endpoint(options), "https://") {
 				return true
 			}
 
@@ -0,0 +1,118 @@
+package clients
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceClientActionResponseContentType(t *testing.T) {
+	kubeconfig := []byte("apiVersion: v1\nkind: Config\n\x00\x01")
+	testcases := []struct {
+		ContentType  string
+		Payload      []byte
+		ExpectOutput interface{}
+	}{
+		{
+			ContentType: "application/json; charset=utf-8",
+			Payload:     []byte(`{"kubeconfig":"abc"}`),
+			ExpectOutput: map[string]interface{}{
+				"kubeconfig": "abc",
+			},
+		},
+		{
+			ContentType: "application/problem+json",
+			Payload:     []byte(`{"title":"abc"}`),
+			ExpectOutput: map[string]interface{}{
+				"title": "abc",
+			},
+		},
+		{
+			ContentType:  "text/plain; charset=utf-8",
+			Payload:      []byte("hello world"),
+			ExpectOutput: "hello world",
+		},
+		{
+			ContentType:  "application/octet-stream",
+			Payload:      kubeconfig,
+			ExpectOutput: base64.StdEncoding.EncodeToString(kubeconfig),
+		},
+		{
+			ContentType:  "application/octet-stream",
+			Payload:      nil,
+			ExpectOutput: nil,
+		},
+	}
+
+	for _, testcase := range testcases {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", testcase.ContentType)
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(testcase.Payload)
+		}))
+
+		client := &ResourceClient{
+			host: server.URL,
+			pl: runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+				Transport: server.Client(),
+				Retry: policy.RetryOptions{
+					MaxRetries: -1,
+				},
+			}),
+		}
+
+		output, err := client.Action(context.Background(), "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.RedHatOpenShift/openShiftClusters/cluster1", "listAdminCredentials", "2023-11-22", http.MethodPost, nil, DefaultRequestOptions())
+		server.Close()
+
+		assert.NoError(t, err, testcase.ContentType)
+		assert.Equal(t, testcase.ExpectOutput, output, testcase.ContentType)
+	}
+}
+
+func TestResourceClientActionPatchLongRunningOperation(t *testing.T) {
+	frequency := pollingFrequency
+	pollingFrequency = time.Second
+	defer func() { pollingFrequency = frequency }()
+
+	resourceId := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Web/sites/site1"
+	var requests []string
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPatch:
+			w.Header().Set("Azure-AsyncOperation", server.URL+"/operations/op1")
+			w.WriteHeader(http.StatusAccepted)
+		case r.URL.Path == "/operations/op1":
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"status":"Succeeded"}`))
+		default:
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"name":"site1","properties":{"httpsOnly":true}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := &ResourceClient{
+		host: server.URL,
+		pl: runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+			Transport: server.Client(),
+			Retry: policy.RetryOptions{
+				MaxRetries: -1,
+			},
+		}),
+	}
+
+	output, err := client.Action(context.Background(), resourceId, "", "2023-12-01", http.MethodPatch, map[string]interface{}{"properties": map[string]interface{}{"httpsOnly": true}}, DefaultRequestOptions())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "site1", "properties": map[string]interface{}{"httpsOnly": true}}, output)
+	assert.Equal(t, []string{"PATCH " + resourceId, "GET /operations/op1", "GET " + resourceId}, requests)
+}
@@ -0,0 +1,82 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceClientCheckExistence(t *testing.T) {
+	testcases := []struct {
+		HeadStatusCode int
+		GetStatusCode  int
+		ExpectExists   bool
+		ExpectGet      bool
+		ExpectError    bool
+	}{
+		{
+			HeadStatusCode: http.StatusNoContent,
+			ExpectExists:   true,
+		},
+		{
+			HeadStatusCode: http.StatusNotFound,
+			ExpectExists:   false,
+		},
+		{
+			HeadStatusCode: http.StatusMethodNotAllowed,
+			GetStatusCode:  http.StatusOK,
+			ExpectExists:   true,
+			ExpectGet:      true,
+		},
+		{
+			HeadStatusCode: http.StatusMethodNotAllowed,
+			GetStatusCode:  http.StatusNotFound,
+			ExpectExists:   false,
+			ExpectGet:      true,
+		},
+		{
+			HeadStatusCode: http.StatusForbidden,
+			ExpectError:    true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		getRequested := false
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.Method == http.MethodHead {
+				w.WriteHeader(testcase.HeadStatusCode)
+				return
+			}
+			getRequested = true
+			w.WriteHeader(testcase.GetStatusCode)
+			_, _ = w.Write([]byte(`{"name":"test"}`))
+		}))
+
+		client := &ResourceClient{
+			host: server.URL,
+			pl: runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+				Transport: server.Client(),
+				Retry: policy.RetryOptions{
+					MaxRetries: -1,
+				},
+			}),
+		}
+
+		exists, err := client.CheckExistence(context.Background(), "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1", "2020-01-01", DefaultRequestOptions())
+		server.Close()
+
+		if testcase.ExpectError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, testcase.ExpectExists, exists)
+		assert.Equal(t, testcase.ExpectGet, getRequested)
+	}
+}
@@ -0,0 +1,45 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceClientWithEndpoint(t *testing.T) {
+	const resourceID = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1"
+	newServer := func(name string) *httptest.Server {
+		return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, resourceID, r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"name":"` + name + `"}`))
+		}))
+	}
+	global, regional := newServer("global"), newServer("regional")
+	defer global.Close()
+	defer regional.Close()
+
+	// the test servers share the same certificate, so the client of either one trusts both
+	client := &ResourceClient{
+		host: global.URL,
+		pl: runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+			Transport: global.Client(),
+			Retry: policy.RetryOptions{
+				MaxRetries: -1,
+			},
+		}),
+	}
+
+	output, err := client.Get(context.Background(), resourceID, "2021-04-01", DefaultRequestOptions())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "global"}, output)
+
+	output, err = client.Get(context.Background(), resourceID, "2021-04-01", DefaultRequestOptions().WithEndpoint(regional.URL+"/"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "regional"}, output)
+}
@@ -0,0 +1,100 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceClientListWithPageConcurrency(t *testing.T) {
+	const total = 10
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the page size of the roleAssignments is 3, the page size of the other resources is increased after the first page,
+		// so their nextLinks can't be predicted from the first one
+		pageSize := 3
+		skip, _ := strconv.Atoi(r.URL.Query().Get("$skip"))
+		if skipToken := r.URL.Query().Get("$skipToken"); skipToken != "" {
+			skip, _ = strconv.Atoi(strings.TrimPrefix(skipToken, "token"))
+		}
+		if r.URL.Path == "/providers/Microsoft.Resources/deployments" && skip != 0 {
+			pageSize = 4
+		}
+		value := make([]interface{}, 0)
+		for i := skip; i < skip+pageSize && i < total; i++ {
+			value = append(value, fmt.Sprintf("item%d", i))
+		}
+		page := map[string]interface{}{"value": value}
+		if skip+pageSize < total {
+			if r.URL.Path == "/providers/Microsoft.Resources/tags" {
+				page["nextLink"] = fmt.Sprintf("%s%s?api-version=2021-04-01&$skipToken=token%d", server.URL, r.URL.Path, skip+pageSize)
+			} else {
+				page["nextLink"] = fmt.Sprintf("%s%s?api-version=2021-04-01&$skip=%d", server.URL, r.URL.Path, skip+pageSize)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client := &ResourceClient{
+		host: server.URL,
+		pl: runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+			Transport: server.Client(),
+			Retry: policy.RetryOptions{
+				MaxRetries: -1,
+			},
+		}),
+	}
+
+	expected := make([]interface{}, 0)
+	for i := 0; i < total; i++ {
+		expected = append(expected, fmt.Sprintf("item%d", i))
+	}
+	testcases := []struct {
+		Url         string
+		Concurrency int
+	}{
+		{Url: "/providers/Microsoft.Authorization/roleAssignments", Concurrency: 0},
+		{Url: "/providers/Microsoft.Authorization/roleAssignments", Concurrency: 3},
+		{Url: "/providers/Microsoft.Authorization/roleAssignments", Concurrency: 8},
+		// the opaque continuation tokens are fetched sequentially
+		{Url: "/providers/Microsoft.Resources/tags", Concurrency: 3},
+		// the prefetched pages are dropped if the nextLink isn't the predicted one
+		{Url: "/providers/Microsoft.Resources/deployments", Concurrency: 3},
+	}
+	for _, testcase := range testcases {
+		actual, err := client.List(context.Background(), testcase.Url, "2021-04-01", DefaultRequestOptions().WithPageConcurrency(testcase.Concurrency))
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"value": expected}, actual, testcase)
+	}
+}
+
+func TestParseOffsetLink(t *testing.T) {
+	testcases := []struct {
+		NextLink string
+		Key      string
+		Offset   int
+		Ok       bool
+	}{
+		{NextLink: "https://management.azure.com/providers/Microsoft.Authorization/roleAssignments?api-version=2022-04-01&$skip=100", Key: "$skip", Offset: 100, Ok: true},
+		{NextLink: "https://management.azure.com/providers/Microsoft.Authorization/roleAssignments?api-version=2022-04-01&%24skiptoken=50", Key: "$skiptoken", Offset: 50, Ok: true},
+		{NextLink: "https://management.azure.com/providers/Microsoft.Authorization/roleAssignments?api-version=2022-04-01&$skipToken=abc", Ok: false},
+		{NextLink: "https://management.azure.com/providers/Microsoft.Authorization/roleAssignments?api-version=2022-04-01", Ok: false},
+	}
+	for _, testcase := range testcases {
+		_, key, offset, ok := parseOffsetLink(testcase.NextLink)
+		assert.Equal(t, testcase.Ok, ok, testcase.NextLink)
+		assert.Equal(t, testcase.Key, key, testcase.NextLink)
+		assert.Equal(t, testcase.Offset, offset, testcase.NextLink)
+	}
+}
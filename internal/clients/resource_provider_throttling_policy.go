@@ -0,0 +1,119 @@
+package clients
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// throttlingRetryInterval is the duration which the writes to a throttled resource provider wait for when the response has no retry headers.
+var throttlingRetryInterval = 5 * time.Second
+
+// resourceProviderThrottlingPolicy schedules the write requests by the resource provider namespace.
+// ARM throttles the requests of each resource provider separately, so once the writes to a namespace are throttled,
+// the following writes to the same namespace wait until the throttling window ends, while the writes to the other namespaces proceed.
+type resourceProviderThrottlingPolicy struct {
+	mutex          sync.Mutex
+	throttledUntil map[string]time.Time
+}
+
+// NewResourceProviderThrottlingPolicy returns a policy which holds back the writes to the resource providers which are throttled.
+func NewResourceProviderThrottlingPolicy() policy.Policy {
+	return &resourceProviderThrottlingPolicy{
+		throttledUntil: make(map[string]time.Time),
+	}
+}
+
+func (p *resourceProviderThrottlingPolicy) Do(req *policy.Request) (*http.Response, error) {
+	rawRequest := req.Raw()
+	if rawRequest.Method == http.MethodGet || rawRequest.Method == http.MethodHead {
+		return req.Next()
+	}
+	namespace := resourceProviderNamespace(rawRequest.URL.Path)
+	if namespace == "" {
+		return req.Next()
+	}
+
+	// the data plane APIs are throttled separately from ARM even if they have the same namespace in the path
+	key := strings.ToLower(rawRequest.URL.Host) + "/" + namespace
+	if wait := p.waitDuration(key); wait > 0 {
+		log.Printf("[DEBUG] The writes to %s are throttled, waiting %s before sending the %s request to %s", namespace, wait.Round(time.Second), rawRequest.Method, rawRequest.URL.Path)
+		metricsKey := requestMetricsKey(rawRequest)
+		defaultRequestMetrics.update(metricsKey, func(m *resourceProviderMetrics) { m.Queued++ })
+		timer := time.NewTimer(wait)
+		select {
+		case <-rawRequest.Context().Done():
+			timer.Stop()
+			defaultRequestMetrics.update(metricsKey, func(m *resourceProviderMetrics) { m.Queued-- })
+			return nil, rawRequest.Context().Err()
+		case <-timer.C:
+		}
+		defaultRequestMetrics.update(metricsKey, func(m *resourceProviderMetrics) { m.Queued-- })
+	}
+
+	resp, err := req.Next()
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		duration := retryAfter(resp)
+		log.Printf("[DEBUG] The writes to %s are throttled for %s", namespace, duration)
+		p.throttle(key, duration)
+	}
+	return resp, err
+}
+
+func (p *resourceProviderThrottlingPolicy) waitDuration(key string) time.Duration {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return time.Until(p.throttledUntil[key])
+}
+
+func (p *resourceProviderThrottlingPolicy) throttle(key string, duration time.Duration) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	until := time.Now().Add(duration)
+	if until.After(p.throttledUntil[key]) {
+		p.throttledUntil[key] = until
+	}
+}
+
+// resourceProviderNamespace returns the lower-cased namespace of the last resource provider in the URL path, e.g. `microsoft.network`.
+// It returns an empty string if the path doesn't target a resource provider.
+func resourceProviderNamespace(urlPath string) string {
+	segments := strings.Split(strings.Trim(urlPath, "/"), "/")
+	for i := len(segments) - 2; i >= 0; i-- {
+		if strings.EqualFold(segments[i], "providers") {
+			return strings.ToLower(segments[i+1])
+		}
+	}
+	return ""
+}
+
+// retryAfter returns the duration in the retry headers of the throttled response, it falls back to the throttlingRetryInterval.
+func retryAfter(resp *http.Response) time.Duration {
+	if v := retryAfterHeader(resp); v > 0 {
+		return v
+	}
+	return throttlingRetryInterval
+}
+
+// retryAfterHeader returns the duration in the retry headers of the response, it's zero if the headers are missing or invalid.
+func retryAfterHeader(resp *http.Response) time.Duration {
+	for _, header := range []string{"x-ms-retry-after-ms", "retry-after-ms"} {
+		if v, err := strconv.Atoi(resp.Header.Get(header)); err == nil && v > 0 {
+			return time.Duration(v) * time.Millisecond
+		}
+	}
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if t, err := http.ParseTime(v); err == nil && time.Until(t) > 0 {
+			return time.Until(t)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,83 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceProviderThrottlingPolicy(t *testing.T) {
+	// the throttling window is short, so the test doesn't wait for the real Retry-After of ARM
+	const throttlingWindow = 300 * time.Millisecond
+	var throttled atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "Microsoft.Network") && throttled.CompareAndSwap(0, 1) {
+			w.Header().Set("x-ms-retry-after-ms", strconv.Itoa(int(throttlingWindow.Milliseconds())))
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	throttlingPolicy := NewResourceProviderThrottlingPolicy().(*resourceProviderThrottlingPolicy)
+	pl := runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		Transport:        server.Client(),
+		PerRetryPolicies: []policy.Policy{throttlingPolicy},
+		Retry: policy.RetryOptions{
+			MaxRetries: -1,
+		},
+	})
+	send := func(method string, path string) (int, time.Duration) {
+		req, err := runtime.NewRequest(context.Background(), method, server.URL+path)
+		assert.NoError(t, err)
+		start := time.Now()
+		resp, err := pl.Do(req)
+		assert.NoError(t, err)
+		return resp.StatusCode, time.Since(start)
+	}
+
+	host := strings.ToLower(strings.TrimPrefix(server.URL, "http://"))
+	vnetPath := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1"
+	storagePath := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Storage/storageAccounts/account1"
+
+	statusCode, _ := send(http.MethodPut, vnetPath)
+	assert.Equal(t, http.StatusTooManyRequests, statusCode)
+	assert.Greater(t, throttlingPolicy.waitDuration(host+"/microsoft.network"), time.Duration(0))
+
+	// the writes to the other resource providers and the reads aren't held back
+	statusCode, _ = send(http.MethodPut, storagePath)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.LessOrEqual(t, throttlingPolicy.waitDuration(host+"/microsoft.storage"), time.Duration(0))
+	statusCode, _ = send(http.MethodGet, vnetPath)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	// the writes to the throttled resource provider wait until the throttling window ends
+	wait := throttlingPolicy.waitDuration(host + "/microsoft.network")
+	statusCode, elapsed := send(http.MethodPut, vnetPath+"/subnets/subnet1")
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.GreaterOrEqual(t, elapsed, wait)
+	assert.Equal(t, int32(1), throttled.Load())
+}
+
+func TestResourceProviderNamespace(t *testing.T) {
+	testcases := map[string]string{
+		"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1":                                               "microsoft.network",
+		"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/providers/Microsoft.Authorization/locks/lock1": "microsoft.authorization",
+		"/subscriptions/00000000-0000-0000-0000-000000000000/providers/Microsoft.Storage/checkNameAvailability":                                                                  "microsoft.storage",
+		"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1":                                                                                                 "",
+		"/secrets/secret1": "",
+	}
+	for urlPath, expected := range testcases {
+		assert.Equal(t, expected, resourceProviderNamespace(urlPath), urlPath)
+	}
+}
@@ -0,0 +1,106 @@
+package clients
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// unmarshalResponseBody decodes the response body according to its Content-Type header, the content type is detected from the payload if the header is missing.
+// JSON payloads are unmarshalled, text payloads, e.g. plain text, XML or YAML, are returned as strings, and the other payloads,
+// e.g. kubeconfig files or archives returned by some actions, are returned as base64 encoded strings.
+func unmarshalResponseBody(resp *http.Response) (interface{}, error) {
+	payload, err := runtime.Payload(resp)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) == 0 {
+		return nil, nil
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = detectContentType(payload)
+	}
+	switch {
+	case isJSONContentType(contentType):
+		var responseBody interface{}
+		if err := runtime.UnmarshalAsJSON(resp, &responseBody); err == nil {
+			return responseBody, nil
+		}
+		// some APIs declare a JSON content type but return a plain text payload, e.g. an error message
+		if utf8.Valid(payload) {
+			return string(payload), nil
+		}
+		return base64.StdEncoding.EncodeToString(payload), nil
+	case isTextContentType(contentType) && utf8.Valid(payload):
+		return string(payload), nil
+	default:
+		return base64.StdEncoding.EncodeToString(payload), nil
+	}
+}
+
+// hasJSONResponseBody returns true if the response body is JSON or empty, which could be decoded by the poller.
+func hasJSONResponseBody(resp *http.Response) bool {
+	contentType := resp.Header.Get("Content-Type")
+	if isJSONContentType(contentType) {
+		return true
+	}
+	payload, err := runtime.Payload(resp)
+	if err != nil {
+		// let the poller report the error
+		return true
+	}
+	return len(payload) == 0 || (contentType == "" && json.Valid(payload))
+}
+
+// detectContentType detects the content type of the payload whose Content-Type header is missing.
+func detectContentType(payload []byte) string {
+	if json.Valid(payload) {
+		return "application/json"
+	}
+	return http.DetectContentType(payload)
+}
+
+// isJSONContentType returns true if the content type is `application/json` or a structured syntax suffix of JSON, e.g. `application/problem+json`.
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.Contains(contentType, "application/json")
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// isTextContentType returns true if the content type is a textual format, e.g. `text/plain`, `application/xml` or `application/x-yaml`.
+func isTextContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.Contains(contentType, "text/")
+	}
+	if strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "+xml") || strings.HasSuffix(mediaType, "+yaml") {
+		return true
+	}
+	switch mediaType {
+	case "application/xml", "application/yaml", "application/x-yaml", "application/javascript", "application/x-www-form-urlencoded":
+		return true
+	}
+	return false
+}
+
+// isPollingResponse returns true if the response contains the headers used to poll a long-running operation.
+func isPollingResponse(resp *http.Response) bool {
+	return resp.Header.Get("Azure-AsyncOperation") != "" || resp.Header.Get("Operation-Location") != "" || resp.Header.Get("Location") != ""
+}
+
+// isSynchronousNonJSONResponse returns true if the request completed synchronously with a non-JSON payload, which the poller can't decode.
+func isSynchronousNonJSONResponse(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return false
+	}
+	return !isPollingResponse(resp) && !hasJSONResponseBody(resp)
+}
@@ -0,0 +1,93 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalResponseBody(t *testing.T) {
+	binary := []byte{0x1f, 0x8b, 0x08, 0x00, 0xff, 0xfe}
+	testcases := []struct {
+		Name         string
+		ContentType  string
+		Payload      []byte
+		ExpectOutput interface{}
+	}{
+		{
+			Name:         "json",
+			ContentType:  "application/json; charset=utf-8",
+			Payload:      []byte(`{"name":"test"}`),
+			ExpectOutput: map[string]interface{}{"name": "test"},
+		},
+		{
+			Name:         "json without content type",
+			Payload:      []byte(`[1,2]`),
+			ExpectOutput: []interface{}{float64(1), float64(2)},
+		},
+		{
+			Name:         "invalid json",
+			ContentType:  "application/json",
+			Payload:      []byte(`Service Unavailable`),
+			ExpectOutput: "Service Unavailable",
+		},
+		{
+			Name:         "xml",
+			ContentType:  "application/xml",
+			Payload:      []byte(`<?xml version="1.0"?><Name>test</Name>`),
+			ExpectOutput: `<?xml version="1.0"?><Name>test</Name>`,
+		},
+		{
+			Name:         "yaml",
+			ContentType:  "application/x-yaml",
+			Payload:      []byte("apiVersion: v1\nkind: Config\n"),
+			ExpectOutput: "apiVersion: v1\nkind: Config\n",
+		},
+		{
+			Name:         "text without content type",
+			Payload:      []byte("hello world"),
+			ExpectOutput: "hello world",
+		},
+		{
+			Name:         "binary",
+			ContentType:  "application/octet-stream",
+			Payload:      binary,
+			ExpectOutput: base64.StdEncoding.EncodeToString(binary),
+		},
+		{
+			Name:         "binary declared as text",
+			ContentType:  "text/plain",
+			Payload:      binary,
+			ExpectOutput: base64.StdEncoding.EncodeToString(binary),
+		},
+		{
+			Name:         "binary without content type",
+			Payload:      binary,
+			ExpectOutput: base64.StdEncoding.EncodeToString(binary),
+		},
+		{
+			Name:         "empty",
+			ContentType:  "application/json",
+			ExpectOutput: nil,
+		},
+	}
+
+	for _, testcase := range testcases {
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(bytes.NewReader(testcase.Payload)),
+		}
+		if testcase.ContentType != "" {
+			resp.Header.Set("Content-Type", testcase.ContentType)
+		}
+
+		output, err := unmarshalResponseBody(resp)
+		assert.NoError(t, err, testcase.Name)
+		assert.Equal(t, testcase.ExpectOutput, output, testcase.Name)
+	}
+}
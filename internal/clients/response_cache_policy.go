@@ -0,0 +1,207 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+type responseCacheKey struct{}
+
+// WithResponseCache returns a copy of the context whose GET requests could be served from the on-disk response cache, if it's configured.
+// It's used by the data sources, the resources always read the latest state.
+func WithResponseCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, responseCacheKey{}, true)
+}
+
+// responseCachePolicy serves the GET requests of the data sources from an on-disk cache, so the repeated plans don't fetch the unchanged reference data again,
+// e.g. the role definitions and the policy definitions. The cached responses are used without a request until the TTL expires,
+// then they're revalidated with the `If-None-Match` header if the responses have ETags.
+type responseCachePolicy struct {
+	dir string
+	ttl time.Duration
+	// credential and tokenScopes are used to resolve the identity of the requests
+	credential  azcore.TokenCredential
+	tokenScopes []string
+
+	mutex sync.Mutex
+	// identity separates the cached responses of the different tenants and principals, because the same URL may return different responses for them,
+	// e.g. the principals with different permissions. It's empty until it's resolved from the claims of the access token.
+	identity string
+}
+
+type responseCacheEntry struct {
+	URL      string      `json:"url"`
+	ETag     string      `json:"etag,omitempty"`
+	StoredAt time.Time   `json:"stored_at"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+}
+
+// NewResponseCachePolicy returns a policy which caches the successful GET responses of the data sources in the directory, the directory is created if it doesn't exist.
+// The responses are cached by the tenant and the principal of the access tokens which the credential issues for the token scopes.
+func NewResponseCachePolicy(dir string, ttl time.Duration, credential azcore.TokenCredential, tokenScopes []string) (policy.Policy, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating the response cache directory %q: %+v", dir, err)
+	}
+	return &responseCachePolicy{
+		dir:         dir,
+		ttl:         ttl,
+		credential:  credential,
+		tokenScopes: tokenScopes,
+	}, nil
+}
+
+// resolveIdentity returns the tenant ID and the object ID or the client ID of the access token, it returns an empty string if they're unknown,
+// in which case the responses aren't cached, so the principals never see the responses of each other.
+func (p *responseCachePolicy) resolveIdentity(ctx context.Context) string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.identity != "" || p.credential == nil {
+		return p.identity
+	}
+	token, err := p.credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: p.tokenScopes})
+	if err != nil {
+		log.Printf("[DEBUG] The response cache is skipped, because the access token can't be obtained: %+v", err)
+		return ""
+	}
+	claims := tokenClaims(token.Token)
+	tenantId, principalId := tokenClaim(claims, "tid"), tokenClaim(claims, "oid", "appid", "azp")
+	if tenantId == "" || principalId == "" {
+		log.Printf("[DEBUG] The response cache is skipped, because the tenant or the principal of the access token is unknown")
+		return ""
+	}
+	p.identity = tenantId + "/" + principalId
+	return p.identity
+}
+
+func (p *responseCachePolicy) Do(req *policy.Request) (*http.Response, error) {
+	rawRequest := req.Raw()
+	if cacheable, _ := rawRequest.Context().Value(responseCacheKey{}).(bool); !cacheable || rawRequest.Method != http.MethodGet {
+		return req.Next()
+	}
+	identity := p.resolveIdentity(rawRequest.Context())
+	if identity == "" {
+		return req.Next()
+	}
+
+	url := rawRequest.URL.String()
+	filename := p.filename(identity, url)
+	entry := loadResponseCacheEntry(filename)
+	if entry != nil && entry.URL != url {
+		entry = nil
+	}
+	if entry != nil {
+		if time.Since(entry.StoredAt) < p.ttl {
+			log.Printf("[DEBUG] Serving GET %s from the response cache", rawRequest.URL.Path)
+			return entry.response(rawRequest), nil
+		}
+		if entry.ETag != "" {
+			rawRequest.Header.Set("If-None-Match", entry.ETag)
+		}
+	}
+
+	resp, err := req.Next()
+	if err != nil {
+		return resp, err
+	}
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		resp.Body.Close()
+		log.Printf("[DEBUG] The cached response of GET %s is not modified", rawRequest.URL.Path)
+		entry.StoredAt = time.Now()
+		p.store(filename, entry)
+		return entry.response(rawRequest), nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	p.store(filename, &responseCacheEntry{
+		URL:      url,
+		ETag:     resp.Header.Get("ETag"),
+		StoredAt: time.Now(),
+		Header:   resp.Header.Clone(),
+		Body:     body,
+	})
+	return resp, nil
+}
+
+// filename returns the path of the cache file of the identity and the URL, the URL includes the api-version and the other query parameters.
+func (p *responseCachePolicy) filename(identity string, url string) string {
+	hash := sha256.Sum256([]byte(identity + "\n" + url))
+	return filepath.Join(p.dir, hex.EncodeToString(hash[:])+".json")
+}
+
+// store writes the entry to a temporary file and renames it, so the concurrent runs never read a partially written entry.
+// The failures are only logged, because the cache is an optimization.
+func (p *responseCachePolicy) store(filename string, entry *responseCacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("[WARN] Failed to marshal the response cache entry of %s: %+v", entry.URL, err)
+		return
+	}
+	file, err := os.CreateTemp(p.dir, "*.tmp")
+	if err != nil {
+		log.Printf("[WARN] Failed to create the response cache entry of %s: %+v", entry.URL, err)
+		return
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), filename)
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		log.Printf("[WARN] Failed to write the response cache entry of %s: %+v", entry.URL, err)
+	}
+}
+
+// loadResponseCacheEntry returns the cached entry in the file, it returns nil if the file doesn't exist or it's invalid.
+func loadResponseCacheEntry(filename string) *responseCacheEntry {
+	// #nosec G304
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil
+	}
+	var entry responseCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		log.Printf("[WARN] Ignoring the invalid response cache entry %s: %+v", filename, err)
+		return nil
+	}
+	return &entry
+}
+
+func (e *responseCacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(http.StatusOK),
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
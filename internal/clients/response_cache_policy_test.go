@@ -0,0 +1,90 @@
+package clients
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseCachePolicy(t *testing.T) {
+	requests, notModified := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"etag1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"etag1"`)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"name":"reader"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	token := func(claims string) staticTokenCredential {
+		return staticTokenCredential("header." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".signature")
+	}
+	credential := token(`{"tid":"00000000-0000-0000-0000-000000000000","oid":"11111111-1111-1111-1111-111111111111"}`)
+	newPipelineWithCredential := func(ttl time.Duration, credential azcore.TokenCredential) runtime.Pipeline {
+		cachePolicy, err := NewResponseCachePolicy(dir, ttl, credential, []string{"https://management.azure.com/.default"})
+		assert.NoError(t, err)
+		return runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+			Transport:       server.Client(),
+			PerCallPolicies: []policy.Policy{cachePolicy},
+			Retry: policy.RetryOptions{
+				MaxRetries: -1,
+			},
+		})
+	}
+	newPipeline := func(ttl time.Duration) runtime.Pipeline {
+		return newPipelineWithCredential(ttl, credential)
+	}
+	send := func(pl runtime.Pipeline, ctx context.Context, method string) string {
+		req, err := runtime.NewRequest(ctx, method, server.URL+"/providers/Microsoft.Authorization/roleDefinitions/reader?api-version=2022-04-01")
+		assert.NoError(t, err)
+		resp, err := pl.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		return string(body)
+	}
+	cacheCtx := WithResponseCache(context.Background())
+
+	// the requests without the cache context and the other methods are always sent
+	pl := newPipeline(time.Hour)
+	send(pl, context.Background(), http.MethodGet)
+	send(pl, cacheCtx, http.MethodPost)
+	assert.Equal(t, 2, requests)
+
+	// the cached response is used until the TTL expires, across the pipelines
+	assert.Equal(t, `{"name":"reader"}`, send(pl, cacheCtx, http.MethodGet))
+	assert.Equal(t, `{"name":"reader"}`, send(newPipeline(time.Hour), cacheCtx, http.MethodGet))
+	assert.Equal(t, 3, requests)
+
+	// the expired response is revalidated with its ETag
+	assert.Equal(t, `{"name":"reader"}`, send(newPipeline(0), cacheCtx, http.MethodGet))
+	assert.Equal(t, 4, requests)
+	assert.Equal(t, 1, notModified)
+
+	// the other principals don't use the cached response
+	send(newPipelineWithCredential(time.Hour, token(`{"tid":"00000000-0000-0000-0000-000000000000","appid":"22222222-2222-2222-2222-222222222222"}`)), cacheCtx, http.MethodGet)
+	assert.Equal(t, 5, requests)
+
+	// the responses aren't cached when the principal is unknown
+	unknown := newPipelineWithCredential(time.Hour, token(`{"tid":"00000000-0000-0000-0000-000000000000"}`))
+	send(unknown, cacheCtx, http.MethodGet)
+	send(unknown, cacheCtx, http.MethodGet)
+	assert.Equal(t, 7, requests)
+}
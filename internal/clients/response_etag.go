@@ -0,0 +1,39 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+type responseEtagKey struct{}
+
+// ResponseEtag records the `ETag` header of the last successful GET response whose request is sent with the context,
+// it's used when the resource provider returns the etag in the header instead of the response body.
+type ResponseEtag struct {
+	mutex sync.Mutex
+	value string
+}
+
+// WithResponseEtag returns a copy of the context which records the `ETag` header of the GET responses.
+func WithResponseEtag(ctx context.Context) (context.Context, *ResponseEtag) {
+	etag := &ResponseEtag{}
+	return context.WithValue(ctx, responseEtagKey{}, etag), etag
+}
+
+// Value returns the `ETag` header of the last successful GET response, it's empty if the response doesn't have it.
+func (e *ResponseEtag) Value() string {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.value
+}
+
+func recordResponseEtag(ctx context.Context, resp *http.Response) {
+	etag, ok := ctx.Value(responseEtagKey{}).(*ResponseEtag)
+	if !ok {
+		return
+	}
+	etag.mutex.Lock()
+	defer etag.mutex.Unlock()
+	etag.value = resp.Header.Get("ETag")
+}
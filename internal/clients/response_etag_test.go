@@ -0,0 +1,43 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseEtag(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Web/sites/site1" {
+			w.Header().Set("ETag", `"etag1"`)
+		}
+		_, _ = w.Write([]byte(`{"name":"site1"}`))
+	}))
+	defer server.Close()
+
+	client := &ResourceClient{
+		host: server.URL,
+		pl: runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+			Transport: server.Client(),
+			Retry: policy.RetryOptions{
+				MaxRetries: -1,
+			},
+		}),
+	}
+
+	ctx, etag := WithResponseEtag(context.Background())
+	_, err := client.Get(ctx, "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Web/sites/site1", "2022-03-01", DefaultRequestOptions())
+	assert.NoError(t, err)
+	assert.Equal(t, `"etag1"`, etag.Value())
+
+	// the etag of the last response is recorded
+	_, err = client.Get(ctx, "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1", "2021-04-01", DefaultRequestOptions())
+	assert.NoError(t, err)
+	assert.Equal(t, "", etag.Value())
+}
@@ -0,0 +1,40 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+type responseHeaderKey struct{}
+
+// ResponseHeader records a header of the last successful data plane action response whose request is sent with the context,
+// it's used when the service returns the generated name of the resource in a header, e.g. the transaction ID of the confidential ledger entries.
+type ResponseHeader struct {
+	mutex sync.Mutex
+	name  string
+	value string
+}
+
+// WithResponseHeader returns a copy of the context which records the named header of the data plane action responses.
+func WithResponseHeader(ctx context.Context, name string) (context.Context, *ResponseHeader) {
+	header := &ResponseHeader{name: name}
+	return context.WithValue(ctx, responseHeaderKey{}, header), header
+}
+
+// Value returns the header of the last successful response, it's empty if the response doesn't have it.
+func (h *ResponseHeader) Value() string {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.value
+}
+
+func recordResponseHeader(ctx context.Context, resp *http.Response) {
+	header, ok := ctx.Value(responseHeaderKey{}).(*ResponseHeader)
+	if !ok {
+		return
+	}
+	header.mutex.Lock()
+	defer header.mutex.Unlock()
+	header.value = resp.Header.Get(header.name)
+}
@@ -0,0 +1,33 @@
+package clients_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/terraform-provider-azapi/internal/clients"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseHeader(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.Header().Set("x-ms-ccf-transaction-id", "2.15")
+		}
+		_, _ = w.Write([]byte(`{"collectionId":"subledger:0"}`))
+	}))
+	defer server.Close()
+	client, host := newTestDataPlaneClient(t, server)
+
+	ctx, header := clients.WithResponseHeader(context.Background(), "x-ms-ccf-transaction-id")
+	_, err := client.Action(ctx, host+"/app/transactions", "", "2022-05-13", http.MethodPost, map[string]interface{}{"contents": "hello"}, clients.DefaultRequestOptions())
+	assert.NoError(t, err)
+	assert.Equal(t, "2.15", header.Value())
+
+	// the header of the last response is recorded
+	_, err = client.Action(ctx, host+"/app/transactions/2.15", "", "2022-05-13", http.MethodGet, nil, clients.DefaultRequestOptions())
+	assert.NoError(t, err)
+	assert.Equal(t, "", header.Value())
+}
@@ -0,0 +1,53 @@
+package clients
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"regexp"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// defaultRetryStatusCodes are the HTTP status codes which are retried by the SDK by default.
+var defaultRetryStatusCodes = []int{
+	http.StatusRequestTimeout,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// ShouldRetryOnErrorMessages returns the ShouldRetry function of the retry policy. Like the SDK, it retries the transport errors and the responses
+// with the status codes, the default status codes are used if they're nil. It also retries the failed responses whose bodies match any of the regexes,
+// e.g. the transient errors which are returned with the 400 or 409 status codes.
+func ShouldRetryOnErrorMessages(statusCodes []int, errorMessageRegex []*regexp.Regexp) func(*http.Response, error) bool {
+	if statusCodes == nil {
+		statusCodes = defaultRetryStatusCodes
+	}
+	return func(resp *http.Response, err error) bool {
+		if err != nil {
+			return true
+		}
+		if runtime.HasStatusCode(resp, statusCodes...) {
+			return true
+		}
+		if resp.StatusCode < http.StatusBadRequest || len(errorMessageRegex) == 0 || resp.Body == nil {
+			return false
+		}
+		// the body is read and restored, so it's still available to the caller if the response isn't retried
+		data, readErr := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		if readErr != nil {
+			return false
+		}
+		for _, regex := range errorMessageRegex {
+			if regex.Match(data) {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,36 @@
+package clients
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldRetryOnErrorMessages(t *testing.T) {
+	newResponse := func(statusCode int, body string) *http.Response {
+		return &http.Response{StatusCode: statusCode, Body: io.NopCloser(strings.NewReader(body))}
+	}
+	shouldRetry := ShouldRetryOnErrorMessages(nil, []*regexp.Regexp{regexp.MustCompile(`AnotherOperationInProgress`)})
+
+	assert.True(t, shouldRetry(nil, errors.New("connection reset by peer")))
+	assert.True(t, shouldRetry(newResponse(http.StatusTooManyRequests, ""), nil))
+	assert.False(t, shouldRetry(newResponse(http.StatusOK, `{"message":"AnotherOperationInProgress"}`), nil))
+	assert.False(t, shouldRetry(newResponse(http.StatusBadRequest, `{"error":{"code":"InvalidParameter"}}`), nil))
+
+	resp := newResponse(http.StatusConflict, `{"error":{"code":"AnotherOperationInProgress"}}`)
+	assert.True(t, shouldRetry(resp, nil))
+	// the body is still readable after it's matched
+	data, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"error":{"code":"AnotherOperationInProgress"}}`, string(data))
+
+	// the configured status codes replace the default ones
+	shouldRetry = ShouldRetryOnErrorMessages([]int{http.StatusBadGateway}, nil)
+	assert.False(t, shouldRetry(newResponse(http.StatusTooManyRequests, ""), nil))
+	assert.True(t, shouldRetry(newResponse(http.StatusBadGateway, ""), nil))
+}
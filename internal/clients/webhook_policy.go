@@ -0,0 +1,122 @@
+package clients
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+const (
+	// HeaderWebhookSignature is the header of the webhook requests which contains the HMAC-SHA256 signature of the payload, e.g. `sha256=<hex>`
+	HeaderWebhookSignature = "X-Azapi-Signature"
+
+	webhookEventPreApply  = "pre_apply"
+	webhookEventPostApply = "post_apply"
+)
+
+// webhookTimeout is the timeout of each webhook request.
+const webhookTimeout = 30 * time.Second
+
+// webhookPolicy posts a JSON summary to the webhook before and after each mutating request, e.g. to integrate with the change management systems.
+// The pre-apply webhook vetoes the request if it doesn't respond with a 2xx status code, the failures of the post-apply webhook are only logged.
+type webhookPolicy struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+type webhookPayload struct {
+	Event         string `json:"event"`
+	Timestamp     string `json:"timestamp"`
+	Method        string `json:"method"`
+	Url           string `json:"url"`
+	CorrelationId string `json:"correlation_id,omitempty"`
+	StatusCode    int    `json:"status,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// NewWebhookPolicy returns a per-call policy which posts the summaries of the mutating requests to the webhook, the payloads are signed with the secret if it's not empty.
+func NewWebhookPolicy(webhookUrl string, secret string) (policy.Policy, error) {
+	u, err := url.Parse(webhookUrl)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, fmt.Errorf("the webhook URL %q is invalid, it must be an absolute http or https URL", webhookUrl)
+	}
+	return &webhookPolicy{
+		url:    webhookUrl,
+		secret: []byte(secret),
+		client: &http.Client{Timeout: webhookTimeout},
+	}, nil
+}
+
+func (p *webhookPolicy) Do(req *policy.Request) (*http.Response, error) {
+	rawRequest := req.Raw()
+	if rawRequest.Method == http.MethodGet || rawRequest.Method == http.MethodHead {
+		return req.Next()
+	}
+
+	payload := webhookPayload{
+		Event:         webhookEventPreApply,
+		Timestamp:     time.Now().UTC().Format(time.RFC3339Nano),
+		Method:        rawRequest.Method,
+		Url:           redactedRequestUrl(rawRequest.URL),
+		CorrelationId: rawRequest.Header.Get(HeaderCorrelationRequestID),
+	}
+	if err := p.post(req, payload); err != nil {
+		return nil, fmt.Errorf("the %s request to %s is vetoed by the webhook: %+v", rawRequest.Method, rawRequest.URL.Path, err)
+	}
+
+	response, err := req.Next()
+
+	payload.Event = webhookEventPostApply
+	payload.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	if response != nil {
+		payload.StatusCode = response.StatusCode
+		if v := response.Header.Get(HeaderCorrelationRequestID); v != "" {
+			payload.CorrelationId = v
+		}
+	}
+	if err != nil {
+		payload.Error = err.Error()
+	}
+	if postErr := p.post(req, payload); postErr != nil {
+		log.Printf("[WARN] Failed to post the %s webhook of the %s request to %s: %+v", webhookEventPostApply, rawRequest.Method, rawRequest.URL.Path, postErr)
+	}
+	return response, err
+}
+
+func (p *webhookPolicy) post(req *policy.Request, payload webhookPayload) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshalling the payload: %+v", err)
+	}
+	webhookRequest, err := http.NewRequestWithContext(req.Raw().Context(), http.MethodPost, p.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	webhookRequest.Header.Set("Content-Type", "application/json")
+	if len(p.secret) != 0 {
+		mac := hmac.New(sha256.New, p.secret)
+		mac.Write(data)
+		webhookRequest.Header.Set(HeaderWebhookSignature, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	resp, err := p.client.Do(webhookRequest)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("the webhook responded with status code %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package clients
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(HeaderCorrelationRequestID, "00000000-0000-0000-0000-000000000001")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	var mutex sync.Mutex
+	var payloads []webhookPayload
+	veto := false
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write(data)
+		assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), r.Header.Get(HeaderWebhookSignature))
+		var payload webhookPayload
+		assert.NoError(t, json.Unmarshal(data, &payload))
+		mutex.Lock()
+		payloads = append(payloads, payload)
+		mutex.Unlock()
+		if veto {
+			http.Error(w, "the change is not approved", http.StatusForbidden)
+		}
+	}))
+	defer webhook.Close()
+
+	webhookPolicy, err := NewWebhookPolicy(webhook.URL, "secret")
+	assert.NoError(t, err)
+	pl := runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		Transport:       server.Client(),
+		PerCallPolicies: []policy.Policy{webhookPolicy},
+	})
+	for _, method := range []string{http.MethodGet, http.MethodPut} {
+		req, err := runtime.NewRequest(context.Background(), method, server.URL+"/resource?api-version=2024-01-01&sig=secret")
+		assert.NoError(t, err)
+		_, err = pl.Do(req)
+		assert.NoError(t, err)
+	}
+
+	assert.Len(t, payloads, 2)
+	for i, event := range []string{webhookEventPreApply, webhookEventPostApply} {
+		assert.Equal(t, event, payloads[i].Event)
+		assert.Equal(t, http.MethodPut, payloads[i].Method)
+		assert.Equal(t, server.URL+"/resource?api-version=2024-01-01", payloads[i].Url)
+		assert.NotEmpty(t, payloads[i].Timestamp)
+	}
+	assert.Equal(t, http.StatusCreated, payloads[1].StatusCode)
+	assert.Equal(t, "00000000-0000-0000-0000-000000000001", payloads[1].CorrelationId)
+
+	veto = true
+	req, err := runtime.NewRequest(context.Background(), http.MethodDelete, server.URL+"/resource?api-version=2024-01-01")
+	assert.NoError(t, err)
+	_, err = pl.Do(req)
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "the change is not approved"), err.Error())
+	assert.Len(t, payloads, 3)
+}
+
+func TestNewWebhookPolicyInvalidUrl(t *testing.T) {
+	_, err := NewWebhookPolicy("/hooks/azapi", "")
+	assert.Error(t, err)
+}
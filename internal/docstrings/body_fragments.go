@@ -0,0 +1,10 @@
+package docstrings
+
+const (
+	bodyFragmentsStr = `A list of objects which are deep-merged in order into the %sbody%s, the values in the later fragments override the earlier ones. It can be used to compose the request body from layers, e.g. a base configuration, an environment overlay and feature toggles.`
+)
+
+// BodyFragments returns the docstring for the body_fragments schema attribute.
+func BodyFragments() string {
+	return addBackquotes(bodyFragmentsStr)
+}
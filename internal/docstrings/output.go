@@ -15,6 +15,12 @@ const (
 	output "quarantine_policy" {
 		value = RESOURCE.example.output.properties.policies.quarantinePolicy.status
 	}
+
+	// when the response_export_values is a map, e.g. { login_server = "properties.loginServer" }, each key is exposed as a named export
+	// it will output "registry1.azurecr.io"
+	output "login_server_named_export" {
+		value = RESOURCE.example.output.login_server
+	}
 	%s%s%s
 `
 )
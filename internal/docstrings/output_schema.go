@@ -0,0 +1,10 @@
+package docstrings
+
+const (
+	outputSchemaStr = `A map where the key is the name of a value in the %soutput%s and the value is the type it's expected to have. The supported types are %sstring%s, %snumber%s, %sbool%s, %sany%s, %slist(<type>)%s, %sset(<type>)%s and %smap(<type>)%s. The exported values are converted to the declared types, and an error is raised if a value is missing or can't be converted. The values which are declared but not exported by the %sresponse_export_values%s are reported in the plan of the resources. Here's an example. If it sets to %s{ fqdn = "string", subnet_ids = "list(string)" }%s, the %soutput.fqdn%s will be a string and the %soutput.subnet_ids%s will be a list of strings.`
+)
+
+// OutputSchema returns the docstring for the output_schema schema attribute.
+func OutputSchema() string {
+	return addBackquotes(outputSchemaStr)
+}
@@ -0,0 +1,22 @@
+package docstrings
+
+const (
+	outputIdStr   = `The ID exported by the Azure resource, it's the same as %soutput.id%s without requiring %sid%s in %sresponse_export_values%s. It's null if the resource doesn't export it.`
+	outputNameStr = `The name exported by the Azure resource, it's the same as %soutput.name%s without requiring %sname%s in %sresponse_export_values%s. It's null if the resource doesn't export it.`
+	outputFqdnStr = `The fully qualified domain name exported by the Azure resource, it's read from the %sproperties.fqdn%s, %sproperties.fullyQualifiedDomainName%s, %sproperties.dnsSettings.fqdn%s or %sproperties.defaultHostName%s property of the response. It's null if the resource doesn't export it.`
+)
+
+// OutputId returns the docstring for the output_id schema attribute.
+func OutputId() string {
+	return addBackquotes(outputIdStr)
+}
+
+// OutputName returns the docstring for the output_name schema attribute.
+func OutputName() string {
+	return addBackquotes(outputNameStr)
+}
+
+// OutputFqdn returns the docstring for the output_fqdn schema attribute.
+func OutputFqdn() string {
+	return addBackquotes(outputFqdnStr)
+}
@@ -0,0 +1,10 @@
+package docstrings
+
+const (
+	payloadFileStr = `The path to a file whose content is sent as the request body as is, e.g. a certificate or an archive. It conflicts with %sbody%s. The %sContent-Type%s header defaults to %sapplication/octet-stream%s, it can be changed in the %sheaders%s. The %sazapi_resource%s and %sazapi_update_resource%s resources don't support it, because the request bodies of the resources are JSON objects which are merged with the other fields and compared with the responses to detect the drift.`
+)
+
+// PayloadFile returns the docstring for the payload_file schema attribute.
+func PayloadFile() string {
+	return addBackquotes(payloadFileStr)
+}
@@ -1,7 +1,7 @@
 package docstrings
 
 const (
-	schemaValidationEnabledStr = `Whether enabled the validation on %stype%s and %sbody%s with embedded schema. Defaults to %strue%s.`
+	schemaValidationEnabledStr = `Whether enabled the validation on %stype%s and %sbody%s with embedded schema. Defaults to %strue%s. It's also disabled when the provider's %sschema_validation_enabled%s is %sfalse%s.`
 )
 
 // SchemaValidationEnabled returns the docstring for the schema_validation_enabled schema attribute.
@@ -0,0 +1,17 @@
+package docstrings
+
+const (
+	selectSecretStr = `A [JMESPath](https://jmespath.org/) expression which selects the secret from the response body, e.g. %skeys[?keyName=='key1'].value | [0]%s for the %slistKeys%s actions. The secret isn't stored in the state, only its hash is exported as %sversion_tracking%s. The secret shouldn't be selected by the %sresponse_export_values%s if it mustn't be stored in the state.`
+
+	versionTrackingStr = `The HMAC-SHA256 of the secret which is selected by the %sselect_secret%s expression, it's keyed with a random key of the resource which is stored in the private state, so the secret can't be brute-forced from it and it can't be correlated across the resources. It changes when the secret is rotated, so it can be used to detect the rotation, e.g. in the %sreplace_triggers_external_values%s of the resources which depend on the secret, without storing the secret itself in the state.`
+)
+
+// SelectSecret returns the docstring for the select_secret schema attribute.
+func SelectSecret() string {
+	return addBackquotes(selectSecretStr)
+}
+
+// VersionTracking returns the docstring for the version_tracking schema attribute.
+func VersionTracking() string {
+	return addBackquotes(versionTrackingStr)
+}
@@ -1,17 +1,65 @@
 package features
 
+import "time"
+
+// ChildResourcesOnDelete specifies how the existing child resources are handled when a resource is deleted.
+type ChildResourcesOnDelete string
+
+const (
+	ChildResourcesOnDeleteIgnore ChildResourcesOnDelete = "ignore"
+	ChildResourcesOnDeleteWarn   ChildResourcesOnDelete = "warn"
+	ChildResourcesOnDeleteFail   ChildResourcesOnDelete = "fail"
+)
+
+// SoftDeletedResourcesOnCreate specifies how the soft-deleted resources which have the same names as the created resources are handled.
+type SoftDeletedResourcesOnCreate string
+
+const (
+	SoftDeletedResourcesOnCreateFail    SoftDeletedResourcesOnCreate = "fail"
+	SoftDeletedResourcesOnCreateRecover SoftDeletedResourcesOnCreate = "recover"
+	SoftDeletedResourcesOnCreatePurge   SoftDeletedResourcesOnCreate = "purge"
+)
+
 type UserFeatures struct {
-	DefaultTags     map[string]string
-	DefaultLocation string
-	DefaultNaming   string
-	EnablePreflight bool
+	DefaultTags                   map[string]string
+	MergeDefaultTags              bool
+	RequiredTags                  []string
+	DefaultLocation               string
+	DefaultNaming                 string
+	DefaultNamingPrefix           string
+	DefaultNamingSuffix           string
+	EnablePreflight               bool
+	SchemaValidationEnabled       bool
+	FailOnFailedProvisioningState bool
+	WarnOnSubscriptionMismatch    bool
+	ChildResourcesOnDelete        ChildResourcesOnDelete
+	SoftDeletedResourcesOnCreate  SoftDeletedResourcesOnCreate
+	ReadOnly                      bool
+	DefaultCreateTimeout          time.Duration
+	DefaultReadTimeout            time.Duration
+	DefaultUpdateTimeout          time.Duration
+	DefaultDeleteTimeout          time.Duration
 }
 
 func Default() UserFeatures {
 	return UserFeatures{
-		DefaultTags:     nil,
-		DefaultLocation: "",
-		DefaultNaming:   "",
-		EnablePreflight: false,
+		DefaultTags:                   nil,
+		MergeDefaultTags:              false,
+		RequiredTags:                  nil,
+		DefaultLocation:               "",
+		DefaultNaming:                 "",
+		DefaultNamingPrefix:           "",
+		DefaultNamingSuffix:           "",
+		EnablePreflight:               false,
+		SchemaValidationEnabled:       true,
+		FailOnFailedProvisioningState: false,
+		WarnOnSubscriptionMismatch:    false,
+		ChildResourcesOnDelete:        ChildResourcesOnDeleteIgnore,
+		SoftDeletedResourcesOnCreate:  SoftDeletedResourcesOnCreateFail,
+		ReadOnly:                      false,
+		DefaultCreateTimeout:          30 * time.Minute,
+		DefaultReadTimeout:            5 * time.Minute,
+		DefaultUpdateTimeout:          30 * time.Minute,
+		DefaultDeleteTimeout:          30 * time.Minute,
 	}
 }
@@ -0,0 +1,177 @@
+package guardrail
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+)
+
+type Effect string
+
+const (
+	EffectDeny Effect = "deny"
+	EffectWarn Effect = "warn"
+)
+
+// Policy is a guardrail which is evaluated against the planned request body.
+// The Condition is a CEL expression which must return a bool, the policy is violated if it's true, e.g. body.properties.allowBlobPublicAccess == true.
+type Policy struct {
+	Name          string   `json:"name"`
+	ResourceTypes []string `json:"resource_types"`
+	Condition     string   `json:"condition"`
+	Effect        Effect   `json:"effect"`
+	Message       string   `json:"message"`
+
+	program cel.Program
+}
+
+type Bundle struct {
+	Policies []Policy `json:"policies"`
+}
+
+// Input is the document which the policy conditions are evaluated against, its fields are the variables of the CEL expressions.
+// The Type is the resource_type variable, because type is a builtin function of CEL.
+type Input struct {
+	Type       string      `json:"type"`
+	ApiVersion string      `json:"api_version"`
+	Name       string      `json:"name"`
+	ParentId   string      `json:"parent_id"`
+	Location   string      `json:"location"`
+	Body       interface{} `json:"body"`
+}
+
+type Violation struct {
+	Policy  string
+	Effect  Effect
+	Message string
+}
+
+// Load reads the policy bundle, which is either a JSON file or a directory of JSON files.
+func Load(path string) (*Bundle, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading the policy bundle %q: %+v", path, err)
+	}
+	files := []string{path}
+	if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, fmt.Errorf("listing the policy files in %q: %+v", path, err)
+		}
+		sort.Strings(files)
+	}
+
+	bundle := &Bundle{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading the policy file %q: %+v", file, err)
+		}
+		var policies Bundle
+		if err := json.Unmarshal(data, &policies); err != nil {
+			return nil, fmt.Errorf("unmarshalling the policy file %q: %+v", file, err)
+		}
+		for _, policy := range policies.Policies {
+			if err := policy.compile(); err != nil {
+				return nil, fmt.Errorf("the policy file %q is invalid: %+v", file, err)
+			}
+			bundle.Policies = append(bundle.Policies, policy)
+		}
+	}
+	return bundle, nil
+}
+
+func (p *Policy) compile() error {
+	if p.Name == "" {
+		return fmt.Errorf("the policy name is required")
+	}
+	switch p.Effect {
+	case "":
+		p.Effect = EffectDeny
+	case EffectDeny, EffectWarn:
+	default:
+		return fmt.Errorf("the effect %q of the policy %q is invalid, possible values are %q and %q", p.Effect, p.Name, EffectDeny, EffectWarn)
+	}
+	env, err := cel.NewEnv(
+		cel.Variable("resource_type", cel.StringType),
+		cel.Variable("api_version", cel.StringType),
+		cel.Variable("name", cel.StringType),
+		cel.Variable("parent_id", cel.StringType),
+		cel.Variable("location", cel.StringType),
+		cel.Variable("body", cel.DynType),
+	)
+	if err != nil {
+		return err
+	}
+	ast, issues := env.Compile(p.Condition)
+	if issues != nil && issues.Err() != nil {
+		return fmt.Errorf("the condition of the policy %q is invalid: %+v", p.Name, issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return fmt.Errorf("the condition of the policy %q must return a bool, got %s", p.Name, ast.OutputType())
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return fmt.Errorf("the condition of the policy %q is invalid: %+v", p.Name, err)
+	}
+	p.program = program
+	return nil
+}
+
+// Evaluate returns the violations of the policies which apply to the resource type of the input.
+func (b *Bundle) Evaluate(input Input) ([]Violation, error) {
+	if b == nil {
+		return nil, nil
+	}
+	// the input is converted to a generic value, so the conditions can reference the json field names
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	document["resource_type"] = document["type"]
+	delete(document, "type")
+
+	violations := make([]Violation, 0)
+	for _, policy := range b.Policies {
+		if !policy.appliesTo(input.Type) {
+			continue
+		}
+		result, _, err := policy.program.Eval(document)
+		if err != nil {
+			return nil, fmt.Errorf("evaluating the policy %q: %+v", policy.Name, err)
+		}
+		violated, ok := result.Value().(bool)
+		if !ok {
+			return nil, fmt.Errorf("evaluating the policy %q: the condition must return a bool, got %v", policy.Name, result.Value())
+		}
+		if violated {
+			violations = append(violations, Violation{
+				Policy:  policy.Name,
+				Effect:  policy.Effect,
+				Message: policy.Message,
+			})
+		}
+	}
+	return violations, nil
+}
+
+func (p Policy) appliesTo(resourceType string) bool {
+	if len(p.ResourceTypes) == 0 {
+		return true
+	}
+	for _, t := range p.ResourceTypes {
+		if t == "*" || strings.EqualFold(t, resourceType) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package guardrail
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Evaluate(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "storage.json"), []byte(`{
+  "policies": [
+    {
+      "name": "deny-public-blob-access",
+      "resource_types": ["Microsoft.Storage/storageAccounts"],
+      "condition": "has(body.properties.allowBlobPublicAccess) && body.properties.allowBlobPublicAccess == true",
+      "message": "public blob access is not allowed"
+    },
+    {
+      "name": "warn-non-eu-location",
+      "resource_types": ["*"],
+      "condition": "!(location in ['westeurope', 'northeurope'])",
+      "effect": "warn",
+      "message": "resources should be deployed to EU regions"
+    },
+    {
+      "name": "deny-preview-network-api-versions",
+      "condition": "resource_type.startsWith('Microsoft.Network/') && api_version.endsWith('-preview')",
+      "message": "preview api-versions are not allowed"
+    }
+  ]
+}`), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a policy"), 0o600))
+
+	bundle, err := Load(dir)
+	assert.NoError(t, err)
+	assert.Len(t, bundle.Policies, 3)
+
+	testcases := []struct {
+		Input    Input
+		Expected []Violation
+	}{
+		{
+			Input: Input{
+				Type:     "Microsoft.Storage/storageAccounts",
+				Location: "westeurope",
+				Body: map[string]interface{}{
+					"properties": map[string]interface{}{
+						"allowBlobPublicAccess": false,
+					},
+				},
+			},
+			Expected: []Violation{},
+		},
+		{
+			Input: Input{
+				Type:     "microsoft.storage/storageaccounts",
+				Location: "eastus",
+				Body: map[string]interface{}{
+					"properties": map[string]interface{}{
+						"allowBlobPublicAccess": true,
+					},
+				},
+			},
+			Expected: []Violation{
+				{Policy: "deny-public-blob-access", Effect: EffectDeny, Message: "public blob access is not allowed"},
+				{Policy: "warn-non-eu-location", Effect: EffectWarn, Message: "resources should be deployed to EU regions"},
+			},
+		},
+		{
+			Input: Input{
+				Type:     "Microsoft.Network/virtualNetworks",
+				Location: "northeurope",
+				Body: map[string]interface{}{
+					"properties": map[string]interface{}{
+						"allowBlobPublicAccess": true,
+					},
+				},
+			},
+			Expected: []Violation{},
+		},
+		{
+			Input: Input{
+				Type:       "Microsoft.Network/virtualNetworks",
+				ApiVersion: "2024-01-01-preview",
+				Location:   "westeurope",
+			},
+			Expected: []Violation{
+				{Policy: "deny-preview-network-api-versions", Effect: EffectDeny, Message: "preview api-versions are not allowed"},
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		violations, err := bundle.Evaluate(testcase.Input)
+		assert.NoError(t, err)
+		assert.Equal(t, testcase.Expected, violations)
+	}
+}
+
+func Test_LoadInvalid(t *testing.T) {
+	testcases := []string{
+		`{"policies":[{"condition":"body"}]}`,
+		`{"policies":[{"name":"p1","condition":"body.["}]}`,
+		`{"policies":[{"name":"p1","condition":"location + 'x'"}]}`,
+		`{"policies":[{"name":"p1","condition":"unknown == 'x'"}]}`,
+		`{"policies":[{"name":"p1","condition":"body","effect":"audit"}]}`,
+		`not json`,
+	}
+	for _, testcase := range testcases {
+		file := filepath.Join(t.TempDir(), "policy.json")
+		assert.NoError(t, os.WriteFile(file, []byte(testcase), 0o600))
+		_, err := Load(file)
+		assert.Error(t, err)
+	}
+}
@@ -3,6 +3,10 @@ package provider
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
@@ -37,3 +41,31 @@ func (w *MsiCredential) GetToken(ctx context.Context, opts policy.TokenRequestOp
 	}
 	return tk, err
 }
+
+// imdsHost is the host of the Azure Instance Metadata Service, which the managed identity credential requests the tokens from by default.
+const imdsHost = "169.254.169.254"
+
+// msiEndpointPolicy sends the token requests of the Azure Instance Metadata Service to the custom managed identity endpoint.
+type msiEndpointPolicy struct {
+	endpoint *url.URL
+}
+
+// newMsiEndpointPolicy returns a policy which sends the token requests to the custom managed identity endpoint.
+func newMsiEndpointPolicy(endpoint string) (policy.Policy, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("the managed identity endpoint %q is invalid, it must be an absolute http or https URL", endpoint)
+	}
+	return &msiEndpointPolicy{endpoint: u}, nil
+}
+
+func (p *msiEndpointPolicy) Do(req *policy.Request) (*http.Response, error) {
+	rawRequest := req.Raw()
+	if strings.EqualFold(rawRequest.URL.Hostname(), imdsHost) {
+		rawRequest.URL.Scheme = p.endpoint.Scheme
+		rawRequest.URL.Host = p.endpoint.Host
+		rawRequest.URL.Path = p.endpoint.Path
+		rawRequest.Host = ""
+	}
+	return req.Next()
+}
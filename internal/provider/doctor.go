@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// doctorTimeout is the timeout of obtaining a token from each credential in the doctor mode.
+const doctorTimeout = 30 * time.Second
+
+// doctorModeEnabled returns whether the doctor mode is enabled by the `ARM_PROVIDER_DOCTOR` environment variable.
+// It's an environment variable instead of a provider argument, so it could be enabled in a misconfigured pipeline without changing the configuration.
+func doctorModeEnabled() bool {
+	return strings.EqualFold(os.Getenv("ARM_PROVIDER_DOCTOR"), "true")
+}
+
+// doctorReport returns a report of the resolved provider configuration, which helps debugging the misconfigured pipelines.
+// The credentials are tried in the same order as the chained credential, so the first one which obtains a token is the selected authentication method,
+// and the principal is read from the claims of its token. The secrets and the tokens are never included.
+func doctorReport(ctx context.Context, model providerData, cloudConfig cloud.Configuration, creds []namedTokenCredential) string {
+	var b strings.Builder
+
+	b.WriteString("Authentication:\n")
+	names := make([]string, 0, len(creds))
+	for _, cred := range creds {
+		names = append(names, cred.name)
+	}
+	fmt.Fprintf(&b, "  enabled methods (in the order they're tried): %s\n", strings.Join(names, ", "))
+
+	scope := cloudConfig.Services[cloud.ResourceManager].Audience + "/.default"
+	var claims map[string]interface{}
+	selected := ""
+	for _, cred := range creds {
+		tokenCtx, cancel := context.WithTimeout(ctx, doctorTimeout)
+		token, err := cred.cred.GetToken(tokenCtx, policy.TokenRequestOptions{Scopes: []string{scope}})
+		cancel()
+		if err != nil {
+			fmt.Fprintf(&b, "  %s: failed to obtain a token: %s\n", cred.name, firstLine(err.Error()))
+			continue
+		}
+		selected = cred.name
+		claims = tokenClaims(token.Token)
+		break
+	}
+	if selected == "" {
+		b.WriteString("  selected method: none, all the enabled methods failed to obtain a token\n")
+	} else {
+		fmt.Fprintf(&b, "  selected method: %s\n", selected)
+	}
+
+	b.WriteString("Principal:\n")
+	for _, item := range []struct {
+		label string
+		claim string
+	}{
+		{label: "object ID", claim: "oid"},
+		{label: "tenant ID", claim: "tid"},
+		{label: "application ID", claim: "appid"},
+		{label: "user principal name", claim: "upn"},
+		{label: "identity type", claim: "idtyp"},
+	} {
+		value, _ := claims[item.claim].(string)
+		if value == "" && item.claim == "appid" {
+			value, _ = claims["azp"].(string)
+		}
+		if value != "" {
+			fmt.Fprintf(&b, "  %s: %s\n", item.label, value)
+		}
+	}
+	if len(claims) == 0 {
+		b.WriteString("  unknown\n")
+	}
+
+	b.WriteString("Configuration:\n")
+	for _, item := range []struct {
+		label string
+		value string
+	}{
+		{label: "environment", value: model.Environment.ValueString()},
+		{label: "subscription ID", value: model.SubscriptionID.ValueString()},
+		{label: "subscription alias", value: model.SubscriptionAlias.ValueString()},
+		{label: "tenant ID", value: model.TenantID.ValueString()},
+		{label: "managing tenant ID", value: model.ManagingTenantID.ValueString()},
+	} {
+		if item.value != "" {
+			fmt.Fprintf(&b, "  %s: %s\n", item.label, item.value)
+		}
+	}
+
+	b.WriteString("Endpoints:\n")
+	fmt.Fprintf(&b, "  active directory authority host: %s\n", cloudConfig.ActiveDirectoryAuthorityHost)
+	serviceNames := make([]string, 0, len(cloudConfig.Services))
+	for name := range cloudConfig.Services {
+		serviceNames = append(serviceNames, string(name))
+	}
+	sort.Strings(serviceNames)
+	for _, name := range serviceNames {
+		service := cloudConfig.Services[cloud.ServiceName(name)]
+		fmt.Fprintf(&b, "  %s: endpoint %s, audience %s\n", name, service.Endpoint, service.Audience)
+	}
+
+	report := strings.TrimSuffix(b.String(), "\n")
+	log.Printf("[INFO] provider configuration report:\n%s", report)
+	return report
+}
+
+// tokenClaims returns the claims of the access token, it doesn't verify the token because the claims are only reported.
+func tokenClaims(token string) map[string]interface{} {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil
+	}
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil
+	}
+	return claims
+}
+
+// firstLine returns the first line of the message, the authentication errors usually include the multi-line troubleshooting guides.
+func firstLine(message string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	return line
+}
@@ -1,15 +1,30 @@
 package provider
 
-import "github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+import (
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+)
 
 const (
-	AppConfiguration cloud.ServiceName = "AppConfiguration"
-	DeviceUpdate     cloud.ServiceName = "DeviceUpdate"
-	DigitalTwins     cloud.ServiceName = "DigitalTwins"
-	IoTCentral       cloud.ServiceName = "IoTCentral"
-	KeyVault         cloud.ServiceName = "KeyVault"
-	Purview          cloud.ServiceName = "Purview"
-	Synapse          cloud.ServiceName = "Synapse"
+	AppConfiguration   cloud.ServiceName = "AppConfiguration"
+	Attestation        cloud.ServiceName = "Attestation"
+	Batch              cloud.ServiceName = "Batch"
+	CognitiveServices  cloud.ServiceName = "CognitiveServices"
+	ConfidentialLedger cloud.ServiceName = "ConfidentialLedger"
+	DeviceUpdate       cloud.ServiceName = "DeviceUpdate"
+	DigitalTwins       cloud.ServiceName = "DigitalTwins"
+	Grafana            cloud.ServiceName = "Grafana"
+	IoTCentral         cloud.ServiceName = "IoTCentral"
+	KeyVault           cloud.ServiceName = "KeyVault"
+	Kusto              cloud.ServiceName = "Kusto"
+	ManagedHSM         cloud.ServiceName = "ManagedHSM"
+	OpenAI             cloud.ServiceName = "OpenAI"
+	Purview            cloud.ServiceName = "Purview"
+	SignalR            cloud.ServiceName = "SignalR"
+	Storage            cloud.ServiceName = "Storage"
+	Synapse            cloud.ServiceName = "Synapse"
+	WebPubSub          cloud.ServiceName = "WebPubSub"
 )
 
 func init() {
@@ -17,6 +32,22 @@ func init() {
 		Audience: "https://azconfig.io",
 		Endpoint: "https://azconfig.io",
 	}
+	cloud.AzurePublic.Services[Attestation] = cloud.ServiceConfiguration{
+		Audience: "https://attest.azure.net",
+		Endpoint: "https://attest.azure.net",
+	}
+	cloud.AzurePublic.Services[Batch] = cloud.ServiceConfiguration{
+		Audience: "https://batch.core.windows.net",
+		Endpoint: "https://batch.azure.com",
+	}
+	cloud.AzurePublic.Services[CognitiveServices] = cloud.ServiceConfiguration{
+		Audience: "https://cognitiveservices.azure.com",
+		Endpoint: "https://cognitiveservices.azure.com",
+	}
+	cloud.AzurePublic.Services[ConfidentialLedger] = cloud.ServiceConfiguration{
+		Audience: "https://confidential-ledger.azure.com",
+		Endpoint: "https://confidential-ledger.azure.com",
+	}
 	cloud.AzurePublic.Services[DeviceUpdate] = cloud.ServiceConfiguration{
 		Audience: "https://api.adu.microsoft.com",
 		Endpoint: "https://api.adu.microsoft.com",
@@ -25,6 +56,11 @@ func init() {
 		Audience: "https://digitaltwins.azure.net",
 		Endpoint: "https://digitaltwins.azure.net",
 	}
+	cloud.AzurePublic.Services[Grafana] = cloud.ServiceConfiguration{
+		// the application ID of Azure Managed Grafana
+		Audience: "ce34e7e5-485f-4d76-964f-b3d2b16d1e4f",
+		Endpoint: "https://grafana.azure.com",
+	}
 	cloud.AzurePublic.Services[IoTCentral] = cloud.ServiceConfiguration{
 		Audience: "https://apps.azureiotcentral.com",
 		Endpoint: "https://azureiotcentral.com",
@@ -33,12 +69,125 @@ func init() {
 		Audience: "https://vault.azure.net",
 		Endpoint: "https://vault.azure.net",
 	}
+	cloud.AzurePublic.Services[Kusto] = cloud.ServiceConfiguration{
+		// the audience of Azure Data Explorer is the cluster endpoint itself
+		Audience: "",
+		Endpoint: "https://kusto.windows.net",
+	}
+	cloud.AzurePublic.Services[ManagedHSM] = cloud.ServiceConfiguration{
+		Audience: "https://managedhsm.azure.net",
+		Endpoint: "https://managedhsm.azure.net",
+	}
+	cloud.AzurePublic.Services[OpenAI] = cloud.ServiceConfiguration{
+		// the Azure OpenAI accounts use the audience of Cognitive Services
+		Audience: "https://cognitiveservices.azure.com",
+		Endpoint: "https://openai.azure.com",
+	}
 	cloud.AzurePublic.Services[Purview] = cloud.ServiceConfiguration{
 		Audience: "https://purview.azure.net",
 		Endpoint: "https://purview.azure.com",
 	}
+	cloud.AzurePublic.Services[SignalR] = cloud.ServiceConfiguration{
+		Audience: "https://signalr.azure.com",
+		Endpoint: "https://service.signalr.net",
+	}
+	cloud.AzurePublic.Services[Storage] = cloud.ServiceConfiguration{
+		Audience: "https://storage.azure.com",
+		Endpoint: "https://core.windows.net",
+	}
 	cloud.AzurePublic.Services[Synapse] = cloud.ServiceConfiguration{
 		Audience: "https://dev.azuresynapse.net",
 		Endpoint: "https://dev.azuresynapse.net",
 	}
+	cloud.AzurePublic.Services[WebPubSub] = cloud.ServiceConfiguration{
+		Audience: "https://webpubsub.azure.com",
+		Endpoint: "https://webpubsub.azure.com",
+	}
+
+	cloud.AzureChina.Services[AppConfiguration] = cloud.ServiceConfiguration{
+		Audience: "https://azconfig.azure.cn",
+		Endpoint: "https://azconfig.azure.cn",
+	}
+	cloud.AzureChina.Services[CognitiveServices] = cloud.ServiceConfiguration{
+		Audience: "https://cognitiveservices.azure.cn",
+		Endpoint: "https://cognitiveservices.azure.cn",
+	}
+	cloud.AzureChina.Services[KeyVault] = cloud.ServiceConfiguration{
+		Audience: "https://vault.azure.cn",
+		Endpoint: "https://vault.azure.cn",
+	}
+	cloud.AzureChina.Services[Kusto] = cloud.ServiceConfiguration{
+		Audience: "",
+		Endpoint: "https://kusto.chinacloudapi.cn",
+	}
+	cloud.AzureChina.Services[ManagedHSM] = cloud.ServiceConfiguration{
+		Audience: "https://managedhsm.azure.cn",
+		Endpoint: "https://managedhsm.azure.cn",
+	}
+	cloud.AzureChina.Services[OpenAI] = cloud.ServiceConfiguration{
+		Audience: "https://cognitiveservices.azure.cn",
+		Endpoint: "https://openai.azure.cn",
+	}
+	cloud.AzureChina.Services[Storage] = cloud.ServiceConfiguration{
+		Audience: "https://storage.azure.com",
+		Endpoint: "https://core.chinacloudapi.cn",
+	}
+
+	cloud.AzureGovernment.Services[AppConfiguration] = cloud.ServiceConfiguration{
+		Audience: "https://azconfig.azure.us",
+		Endpoint: "https://azconfig.azure.us",
+	}
+	cloud.AzureGovernment.Services[CognitiveServices] = cloud.ServiceConfiguration{
+		Audience: "https://cognitiveservices.azure.us",
+		Endpoint: "https://cognitiveservices.azure.us",
+	}
+	cloud.AzureGovernment.Services[KeyVault] = cloud.ServiceConfiguration{
+		Audience: "https://vault.usgovcloudapi.net",
+		Endpoint: "https://vault.usgovcloudapi.net",
+	}
+	cloud.AzureGovernment.Services[Kusto] = cloud.ServiceConfiguration{
+		Audience: "",
+		Endpoint: "https://kusto.usgovcloudapi.net",
+	}
+	cloud.AzureGovernment.Services[ManagedHSM] = cloud.ServiceConfiguration{
+		Audience: "https://managedhsm.usgovcloudapi.net",
+		Endpoint: "https://managedhsm.usgovcloudapi.net",
+	}
+	cloud.AzureGovernment.Services[OpenAI] = cloud.ServiceConfiguration{
+		Audience: "https://cognitiveservices.azure.us",
+		Endpoint: "https://openai.azure.us",
+	}
+	cloud.AzureGovernment.Services[Storage] = cloud.ServiceConfiguration{
+		Audience: "https://storage.azure.com",
+		Endpoint: "https://core.usgovcloudapi.net",
+	}
+}
+
+// withDataPlaneAudiences returns a copy of the cloud configuration whose services include the data plane endpoint suffixes and their audiences.
+// The audience of a data plane endpoint is resolved by the longest matched suffix, so a suffix overrides the shorter built-in endpoints,
+// and the audience of the built-in service is replaced if the suffix is its endpoint.
+func withDataPlaneAudiences(cloudConfig cloud.Configuration, audiences map[string]string) cloud.Configuration {
+	if len(audiences) == 0 {
+		return cloudConfig
+	}
+	services := make(map[cloud.ServiceName]cloud.ServiceConfiguration, len(cloudConfig.Services)+len(audiences))
+	for k, v := range cloudConfig.Services {
+		services[k] = v
+	}
+	for suffix, audience := range audiences {
+		endpoint := "https://" + suffix
+		serviceName := cloud.ServiceName("DataPlane:" + suffix)
+		for name, service := range services {
+			if name != cloud.ResourceManager && strings.EqualFold(strings.TrimSuffix(service.Endpoint, "/"), endpoint) {
+				serviceName = name
+				break
+			}
+		}
+		services[serviceName] = cloud.ServiceConfiguration{
+			Endpoint: endpoint,
+			Audience: audience,
+		}
+	}
+	cloudConfig.Services = services
+	return cloudConfig
 }
@@ -5,11 +5,16 @@ import (
 	"encoding/base64"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/terraform-provider-azapi/internal/azure"
 	"github.com/Azure/terraform-provider-azapi/internal/azure/location"
@@ -20,11 +25,13 @@ import (
 	"github.com/Azure/terraform-provider-azapi/internal/services/functions"
 	"github.com/Azure/terraform-provider-azapi/internal/services/myvalidator"
 	"github.com/Azure/terraform-provider-azapi/version"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -35,6 +42,7 @@ import (
 
 var _ provider.Provider = &Provider{}
 var _ provider.ProviderWithFunctions = &Provider{}
+var _ provider.ProviderWithValidateConfig = &Provider{}
 
 func AzureProvider() provider.Provider {
 	return &Provider{}
@@ -44,36 +52,70 @@ type Provider struct {
 }
 
 type providerData struct {
-	SubscriptionID               types.String `tfsdk:"subscription_id"`
-	ClientID                     types.String `tfsdk:"client_id"`
-	ClientIDFilePath             types.String `tfsdk:"client_id_file_path"`
-	TenantID                     types.String `tfsdk:"tenant_id"`
-	AuxiliaryTenantIDs           types.List   `tfsdk:"auxiliary_tenant_ids"`
-	Endpoint                     types.List   `tfsdk:"endpoint"`
-	Environment                  types.String `tfsdk:"environment"`
-	ClientCertificate            types.String `tfsdk:"client_certificate"`
-	ClientCertificatePath        types.String `tfsdk:"client_certificate_path"`
-	ClientCertificatePassword    types.String `tfsdk:"client_certificate_password"`
-	ClientSecret                 types.String `tfsdk:"client_secret"`
-	ClientSecretFilePath         types.String `tfsdk:"client_secret_file_path"`
-	SkipProviderRegistration     types.Bool   `tfsdk:"skip_provider_registration"`
-	OIDCRequestToken             types.String `tfsdk:"oidc_request_token"`
-	OIDCRequestURL               types.String `tfsdk:"oidc_request_url"`
-	OIDCToken                    types.String `tfsdk:"oidc_token"`
-	OIDCTokenFilePath            types.String `tfsdk:"oidc_token_file_path"`
-	OIDCAzureServiceConnectionID types.String `tfsdk:"oidc_azure_service_connection_id"`
-	UseOIDC                      types.Bool   `tfsdk:"use_oidc"`
-	UseCLI                       types.Bool   `tfsdk:"use_cli"`
-	UseMSI                       types.Bool   `tfsdk:"use_msi"`
-	UseAKSWorkloadIdentity       types.Bool   `tfsdk:"use_aks_workload_identity"`
-	PartnerID                    types.String `tfsdk:"partner_id"`
-	CustomCorrelationRequestID   types.String `tfsdk:"custom_correlation_request_id"`
-	DisableCorrelationRequestID  types.Bool   `tfsdk:"disable_correlation_request_id"`
-	DisableTerraformPartnerID    types.Bool   `tfsdk:"disable_terraform_partner_id"`
-	DefaultName                  types.String `tfsdk:"default_name"`
-	DefaultLocation              types.String `tfsdk:"default_location"`
-	DefaultTags                  types.Map    `tfsdk:"default_tags"`
-	EnablePreflight              types.Bool   `tfsdk:"enable_preflight"`
+	SubscriptionID                types.String `tfsdk:"subscription_id"`
+	SubscriptionAlias             types.String `tfsdk:"subscription_alias"`
+	ClientID                      types.String `tfsdk:"client_id"`
+	ClientIDFilePath              types.String `tfsdk:"client_id_file_path"`
+	TenantID                      types.String `tfsdk:"tenant_id"`
+	ManagingTenantID              types.String `tfsdk:"managing_tenant_id"`
+	AuxiliaryTenantIDs            types.List   `tfsdk:"auxiliary_tenant_ids"`
+	Endpoint                      types.List   `tfsdk:"endpoint"`
+	Environment                   types.String `tfsdk:"environment"`
+	ClientCertificate             types.String `tfsdk:"client_certificate"`
+	ClientCertificatePath         types.String `tfsdk:"client_certificate_path"`
+	ClientCertificatePassword     types.String `tfsdk:"client_certificate_password"`
+	ClientSecret                  types.String `tfsdk:"client_secret"`
+	ClientSecretFilePath          types.String `tfsdk:"client_secret_file_path"`
+	SkipProviderRegistration      types.Bool   `tfsdk:"skip_provider_registration"`
+	OIDCRequestToken              types.String `tfsdk:"oidc_request_token"`
+	OIDCRequestURL                types.String `tfsdk:"oidc_request_url"`
+	OIDCToken                     types.String `tfsdk:"oidc_token"`
+	OIDCTokenFilePath             types.String `tfsdk:"oidc_token_file_path"`
+	OIDCAzureServiceConnectionID  types.String `tfsdk:"oidc_azure_service_connection_id"`
+	UseOIDC                       types.Bool   `tfsdk:"use_oidc"`
+	UseCLI                        types.Bool   `tfsdk:"use_cli"`
+	UseMSI                        types.Bool   `tfsdk:"use_msi"`
+	MSIEndpoint                   types.String `tfsdk:"msi_endpoint"`
+	UseAKSWorkloadIdentity        types.Bool   `tfsdk:"use_aks_workload_identity"`
+	PartnerID                     types.String `tfsdk:"partner_id"`
+	CustomCorrelationRequestID    types.String `tfsdk:"custom_correlation_request_id"`
+	DisableCorrelationRequestID   types.Bool   `tfsdk:"disable_correlation_request_id"`
+	DisableTerraformPartnerID     types.Bool   `tfsdk:"disable_terraform_partner_id"`
+	DefaultName                   types.String `tfsdk:"default_name"`
+	DefaultNamingPrefix           types.String `tfsdk:"default_naming_prefix"`
+	DefaultNamingSuffix           types.String `tfsdk:"default_naming_suffix"`
+	DefaultLocation               types.String `tfsdk:"default_location"`
+	DefaultTags                   types.Map    `tfsdk:"default_tags"`
+	MergeDefaultTags              types.Bool   `tfsdk:"merge_default_tags"`
+	RequiredTags                  types.List   `tfsdk:"required_tags"`
+	DefaultCreateTimeout          types.String `tfsdk:"default_create_timeout"`
+	DefaultReadTimeout            types.String `tfsdk:"default_read_timeout"`
+	DefaultUpdateTimeout          types.String `tfsdk:"default_update_timeout"`
+	DefaultDeleteTimeout          types.String `tfsdk:"default_delete_timeout"`
+	EnablePreflight               types.Bool   `tfsdk:"enable_preflight"`
+	SchemaValidationEnabled       types.Bool   `tfsdk:"schema_validation_enabled"`
+	FailOnFailedProvisioningState types.Bool   `tfsdk:"fail_on_failed_provisioning_state"`
+	WarnOnSubscriptionMismatch    types.Bool   `tfsdk:"warn_on_subscription_mismatch"`
+	ChildResourcesOnDelete        types.String `tfsdk:"child_resources_on_delete"`
+	SoftDeletedResourcesOnCreate  types.String `tfsdk:"soft_deleted_resources_on_create"`
+	ValidateCredentials           types.Bool   `tfsdk:"validate_credentials"`
+	CancellationBehavior          types.String `tfsdk:"cancellation_behavior"`
+	ApiVersionFallbackEnabled     types.Bool   `tfsdk:"api_version_fallback_enabled"`
+	ApiVersionOverrides           types.Map    `tfsdk:"api_version_overrides"`
+	DataPlaneAudiences            types.Map    `tfsdk:"data_plane_audiences"`
+	AuditLogFile                  types.String `tfsdk:"audit_log_file"`
+	DataSourceCacheDir            types.String `tfsdk:"data_source_cache_dir"`
+	DataSourceCacheTTL            types.String `tfsdk:"data_source_cache_ttl"`
+	MaximumRetries                types.Int64  `tfsdk:"maximum_retries"`
+	RetryStatusCodes              types.List   `tfsdk:"retry_status_codes"`
+	RetryErrorMessageRegex        types.List   `tfsdk:"retry_error_message_regex"`
+	RetryDelay                    types.String `tfsdk:"retry_delay"`
+	MaximumRetryDelay             types.String `tfsdk:"maximum_retry_delay"`
+	PreRequestHook                types.String `tfsdk:"pre_request_hook"`
+	PolicyBundle                  types.String `tfsdk:"policy_bundle"`
+	WebhookUrl                    types.String `tfsdk:"webhook_url"`
+	WebhookSecret                 types.String `tfsdk:"webhook_secret"`
+	ReadOnly                      types.Bool   `tfsdk:"read_only"`
 }
 
 func (model providerData) GetClientId() (*string, error) {
@@ -143,9 +185,10 @@ func (model providerData) GetOIDCTokenFilePath() string {
 }
 
 type providerEndpointData struct {
-	ActiveDirectoryAuthorityHost types.String `tfsdk:"active_directory_authority_host"`
-	ResourceManagerEndpoint      types.String `tfsdk:"resource_manager_endpoint"`
-	ResourceManagerAudience      types.String `tfsdk:"resource_manager_audience"`
+	ActiveDirectoryAuthorityHost     types.String `tfsdk:"active_directory_authority_host"`
+	ResourceManagerEndpoint          types.String `tfsdk:"resource_manager_endpoint"`
+	ResourceManagerAudience          types.String `tfsdk:"resource_manager_audience"`
+	SecondaryResourceManagerEndpoint types.String `tfsdk:"secondary_resource_manager_endpoint"`
 }
 
 func (p Provider) Metadata(ctx context.Context, request provider.MetadataRequest, response *provider.MetadataResponse) {
@@ -161,6 +204,14 @@ func (p Provider) Schema(ctx context.Context, request provider.SchemaRequest, re
 				MarkdownDescription: "The Subscription ID which should be used. This can also be sourced from the `ARM_SUBSCRIPTION_ID` Environment Variable.",
 			},
 
+			"subscription_alias": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("subscription_id")),
+				},
+				MarkdownDescription: "The alias or the display name of the Subscription which should be used, it's resolved to the Subscription ID when the provider is configured. The subscription aliases are looked up first, then the display names of the subscriptions which are accessible by the credentials, it's an error if more than one subscription has the display name. It's useful when the subscriptions are vended dynamically and their IDs aren't known ahead of time. This can also be sourced from the `ARM_SUBSCRIPTION_ALIAS` Environment Variable. Conflicts with `subscription_id`.",
+			},
+
 			"client_id": schema.StringAttribute{
 				Optional:            true,
 				MarkdownDescription: "The Client ID which should be used. This can also be sourced from the `ARM_CLIENT_ID` Environment Variable.",
@@ -176,11 +227,19 @@ func (p Provider) Schema(ctx context.Context, request provider.SchemaRequest, re
 				MarkdownDescription: "The Tenant ID should be used. This can also be sourced from the `ARM_TENANT_ID` Environment Variable.",
 			},
 
+			"managing_tenant_id": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					myvalidator.StringIsUUID(),
+				},
+				MarkdownDescription: "The ID of the managing tenant which should be used to access the subscriptions delegated by Azure Lighthouse. When it's specified, the access tokens are issued by the managing tenant, where the credentials are registered, while the `tenant_id` is the tenant which owns the subscription. The authentication and authorization errors include the Lighthouse specific hints. This can also be sourced from the `ARM_MANAGING_TENANT_ID` Environment Variable.",
+			},
+
 			"auxiliary_tenant_ids": schema.ListAttribute{
 				ElementType:         types.StringType,
 				Optional:            true,
 				Validators:          []validator.List{listvalidator.SizeAtMost(3)},
-				MarkdownDescription: "List of auxiliary Tenant IDs required for multi-tenancy and cross-tenant scenarios. This can also be sourced from the `ARM_AUXILIARY_TENANT_IDS` Environment Variable.",
+				MarkdownDescription: "List of auxiliary Tenant IDs required for multi-tenancy and cross-tenant scenarios, e.g. the virtual network peerings across tenants. The tokens of these tenants are sent in the `x-ms-authorization-auxiliary` header of the Azure Resource Manager requests. This can also be sourced from the `ARM_AUXILIARY_TENANT_IDS` Environment Variable.",
 			},
 
 			"endpoint": schema.ListNestedAttribute{
@@ -191,17 +250,22 @@ func (p Provider) Schema(ctx context.Context, request provider.SchemaRequest, re
 					Attributes: map[string]schema.Attribute{
 						"active_directory_authority_host": schema.StringAttribute{
 							Optional:            true,
-							MarkdownDescription: "The Azure Resource Manager endpoint to use. This can also be sourced from the `ARM_RESOURCE_MANAGER_ENDPOINT` Environment Variable. Defaults to `https://management.azure.com/` for public cloud.",
+							MarkdownDescription: "The Azure Active Directory login endpoint to use. This can also be sourced from the `ARM_ACTIVE_DIRECTORY_AUTHORITY_HOST` Environment Variable. Defaults to `https://login.microsoftonline.com/` for public cloud.",
 						},
 
 						"resource_manager_endpoint": schema.StringAttribute{
 							Optional:            true,
-							MarkdownDescription: "The resource ID to obtain AD tokens for. This can also be sourced from the `ARM_RESOURCE_MANAGER_AUDIENCE` Environment Variable. Defaults to `https://management.core.windows.net/` for public cloud.",
+							MarkdownDescription: "The Azure Resource Manager endpoint to use, e.g. the endpoint of a Resource Management Private Link. The host name is resolved with the system DNS configuration. This can also be sourced from the `ARM_RESOURCE_MANAGER_ENDPOINT` Environment Variable. Defaults to `https://management.azure.com/` for public cloud.",
 						},
 
 						"resource_manager_audience": schema.StringAttribute{
 							Optional:            true,
-							MarkdownDescription: "The Azure Active Directory login endpoint to use. This can also be sourced from the `ARM_ACTIVE_DIRECTORY_AUTHORITY_HOST` Environment Variable. Defaults to `https://login.microsoftonline.com/` for public cloud.",
+							MarkdownDescription: "The resource ID to obtain AD tokens for. Resource Management Private Link endpoints accept tokens issued for the public Azure Resource Manager audience, so this usually doesn't need to be changed when `resource_manager_endpoint` is overridden. This can also be sourced from the `ARM_RESOURCE_MANAGER_AUDIENCE` Environment Variable. Defaults to `https://management.core.windows.net/` for public cloud.",
+						},
+
+						"secondary_resource_manager_endpoint": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "The secondary Azure Resource Manager endpoint to use when the `resource_manager_endpoint` can't be reached, e.g. another regional Azure Resource Manager instance of Azure Stack. The requests are sent to the secondary endpoint for 5 minutes after the primary endpoint fails to be connected 3 times in a row, e.g. its host name can't be resolved or the connections are refused, then the primary endpoint is tried again. The responses of the primary endpoint, including the server errors, aren't failures. The tokens of the `resource_manager_audience` are sent to both endpoints. This can also be sourced from the `ARM_SECONDARY_RESOURCE_MANAGER_ENDPOINT` Environment Variable.",
 						},
 					},
 				},
@@ -212,7 +276,7 @@ func (p Provider) Schema(ctx context.Context, request provider.SchemaRequest, re
 				Validators: []validator.String{
 					stringvalidator.OneOfCaseInsensitive("public", "usgovernment", "china"),
 				},
-				MarkdownDescription: "The Cloud Environment which should be used. Possible values are `public`, `usgovernment` and `china`. Defaults to `public`. This can also be sourced from the `ARM_ENVIRONMENT` Environment Variable.",
+				MarkdownDescription: "The Cloud Environment which should be used. Possible values are `public`, `usgovernment` and `china`. Defaults to `public`. The URLs of the other clouds' endpoints in the `body` of the resources and the `parent_id` of the data plane resources are warned at plan time, e.g. `https://example.blob.core.windows.net` when the environment is `china`, because they're usually copied from the configurations of another cloud. This can also be sourced from the `ARM_ENVIRONMENT` Environment Variable.",
 			},
 
 			// TODO@mgd: the metadata_host is used to retrieve metadata from Azure to identify current environment, this is used to eliminate Azure Stack usage, in which case the provider doesn't support.
@@ -303,13 +367,10 @@ func (p Provider) Schema(ctx context.Context, request provider.SchemaRequest, re
 				MarkdownDescription: "Should AKS Workload Identity be used for Authentication? This can also be sourced from the `ARM_USE_AKS_WORKLOAD_IDENTITY` Environment Variable. Defaults to `false`. When set, `client_id`, `tenant_id` and `oidc_token_file_path` will be detected from the environment and do not need to be specified.",
 			},
 
-			// TODO@mgd: azidentity doesn't support msi_endpoint
-			// "msi_endpoint": {
-			// 	Type:        schema.TypeString,
-			// 	Optional:    true,
-			// 	DefaultFunc: schema.EnvDefaultFunc("ARM_MSI_ENDPOINT", ""),
-			// 	Description: "The path to a custom endpoint for Managed Service Identity - in most circumstances this should be detected automatically. ",
-			// },
+			"msi_endpoint": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The path to a custom endpoint for Managed Service Identity - in most circumstances, this should be detected automatically. This can also be sourced from the `ARM_MSI_ENDPOINT` Environment Variable.",
+			},
 
 			// Managed Tracking GUID for User-agent
 			"partner_id": schema.StringAttribute{
@@ -340,6 +401,16 @@ func (p Provider) Schema(ctx context.Context, request provider.SchemaRequest, re
 				MarkdownDescription: "The default name to create the azure resource. The `name` in each resource block can override the `default_name`. Changing this forces new resources to be created.",
 			},
 
+			"default_naming_prefix": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The prefix which is added to the `name` in each resource block, e.g. `contoso-`. The `default_name` isn't prefixed. The name with the prefix is exported as the `full_name` of the resources. Changing this forces new resources to be created.",
+			},
+
+			"default_naming_suffix": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The suffix which is added to the `name` in each resource block, e.g. `-prod`. The `default_name` isn't suffixed. The name with the suffix is exported as the `full_name` of the resources. Changing this forces new resources to be created.",
+			},
+
 			"default_location": schema.StringAttribute{
 				Optional:            true,
 				MarkdownDescription: " The default Azure Region where the azure resource should exist. The `location` in each resource block can override the `default_location`. Changing this forces new resources to be created.",
@@ -354,14 +425,280 @@ func (p Provider) Schema(ctx context.Context, request provider.SchemaRequest, re
 				MarkdownDescription: "A mapping of tags which should be assigned to the azure resource as default tags. The`tags` in each resource block can override the `default_tags`.",
 			},
 
+			"merge_default_tags": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Whether to merge the `default_tags` into the `tags` of each resource key by key, the `tags` of the resource win on conflict. The merged tags are sent in the request body, and the `default_tags` which aren't in the `tags` argument aren't read back into it, so the plan is empty if they're not changed. By default, the `tags` in a resource block replace the `default_tags`. Defaults to `false`.",
+			},
+
+			"required_tags": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.UniqueValues(),
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+				MarkdownDescription: "A list of the tag names which every `azapi_resource` that supports tags must have, e.g. `[\"costCenter\", \"owner\"]`. The tags are checked after they're merged with the `default_tags`, and the plan fails if any of them is missing, so the governance errors are reported earlier than the denials of the Azure Policy. The tag names are case-insensitive.",
+			},
+
+			"default_create_timeout": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					myvalidator.StringIsDuration(),
+				},
+				MarkdownDescription: "The default timeout of the create operations, which is used when the `timeouts.create` isn't specified in the resource or data source block. It's a string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as \"30s\" or \"2h45m\". Defaults to `30m`.",
+			},
+
+			"default_read_timeout": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					myvalidator.StringIsDuration(),
+				},
+				MarkdownDescription: "The default timeout of the read operations, which is used when the `timeouts.read` isn't specified in the resource or data source block. It's a string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as \"30s\" or \"2h45m\". Defaults to `5m`.",
+			},
+
+			"default_update_timeout": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					myvalidator.StringIsDuration(),
+				},
+				MarkdownDescription: "The default timeout of the update operations, which is used when the `timeouts.update` isn't specified in the resource or data source block. It's a string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as \"30s\" or \"2h45m\". Defaults to `30m`.",
+			},
+
+			"default_delete_timeout": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					myvalidator.StringIsDuration(),
+				},
+				MarkdownDescription: "The default timeout of the delete operations, which is used when the `timeouts.delete` isn't specified in the resource or data source block. It's a string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as \"30s\" or \"2h45m\". Defaults to `30m`.",
+			},
+
 			"enable_preflight": schema.BoolAttribute{
 				Optional:    true,
-				Description: "Enable Preflight Validation. The default is false. When set to true, the provider will use Preflight to do static validation before really deploying a new resource. When set to false, the provider will disable this validation.",
+				Description: "Enable Preflight Validation. The default is false. When set to true, the provider will use Preflight to do static validation before really deploying a new resource, and check whether the globally unique name is available for the resource types which expose a `checkNameAvailability` API, e.g. storage accounts, key vaults, container registries, web apps and the custom subdomains of cognitive services accounts. When set to false, the provider will disable this validation.",
+			},
+
+			"schema_validation_enabled": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Whether the `type` and `body` of the `azapi_resource` resources are validated with the embedded schema at plan time, e.g. the unknown property names, the wrong types, the missing required properties and the unsupported api-versions. When it's set to `false`, the validation is disabled for all the resources, regardless of their `schema_validation_enabled` argument, it's useful when the embedded schema is outdated. This can also be sourced from the `ARM_SCHEMA_VALIDATION_ENABLED` Environment Variable. Defaults to `true`.",
+			},
+
+			"fail_on_failed_provisioning_state": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Whether the apply fails when the `properties.provisioningState` of the resource is `Failed` after it's created or updated, even though the request itself succeeded. The failure details from the `error` and `statuses` properties are included in the error message, and a newly created resource is marked as tainted. It applies to the `azapi_resource` and `azapi_update_resource` resources. Defaults to `false`.",
+			},
+
+			"warn_on_subscription_mismatch": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Whether a warning is raised when the resource ID of a resource belongs to a subscription which is different from the `subscription_id` of the provider, it's useful to detect the wrong provider aliases which result in confusing errors like 404. It isn't enabled by default, because managing the resources in the other subscriptions with one provider is a valid use case. This can also be sourced from the `ARM_WARN_ON_SUBSCRIPTION_MISMATCH` Environment Variable. Defaults to `false`.",
+			},
+
+			"child_resources_on_delete": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(features.ChildResourcesOnDeleteIgnore),
+						string(features.ChildResourcesOnDeleteWarn),
+						string(features.ChildResourcesOnDeleteFail),
+					),
+				},
+				MarkdownDescription: "Specifies how the existing child resources are handled when the `azapi_resource` is deleted, because ARM deletes the child resources with their parent, e.g. the resources in a resource group. Possible values are `ignore`, `warn` and `fail`. When it's set to `warn` or `fail`, the provider lists the child resources before the resource is deleted, and raises a warning or fails the deletion if any child resource still exists, e.g. the child resources which are managed by other workspaces. Defaults to `ignore`.",
+			},
+
+			"soft_deleted_resources_on_create": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(features.SoftDeletedResourcesOnCreateFail),
+						string(features.SoftDeletedResourcesOnCreateRecover),
+						string(features.SoftDeletedResourcesOnCreatePurge),
+					),
+				},
+				MarkdownDescription: "Specifies how a soft-deleted resource which has the same name as the `azapi_resource` is handled when the resource is created, because the creation fails with a conflict error until the soft-deleted resource is recovered or purged. It's supported by the `Microsoft.KeyVault/vaults`, `Microsoft.CognitiveServices/accounts` and `Microsoft.ApiManagement/service` resource types. Possible values are `fail`, `recover` and `purge`. `fail` reports an error which explains the conflict. `recover` recovers the soft-deleted resource, then updates it with the `body`. `purge` permanently deletes the soft-deleted resource, then creates a new resource. Defaults to `fail`.",
+			},
+
+			"cancellation_behavior": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(clients.CancellationBehaviorAbandon),
+						string(clients.CancellationBehaviorRecord),
+						string(clients.CancellationBehaviorCancel),
+					),
+				},
+				MarkdownDescription: "Specifies how the long-running operations are handled when they're cancelled before they complete, e.g. terraform is interrupted or the operation exceeds the timeout. Possible values are `abandon`, `record` and `cancel`. `abandon` leaves the operation running in Azure. `record` also reports the URL of the operation and records it in the private state of the `azapi_resource` or `azapi_update_resource` resource, the refreshes warn about the operation with its URL until the resource is applied successfully. If the resource doesn't exist yet, the operation can only be tracked by the reported URL and the resource can be imported once it completes. `cancel` requests the resource provider to cancel the operation if it's supported, e.g. the `Microsoft.Resources/deployments`, otherwise it behaves like `record`. Defaults to `abandon`.",
+			},
+
+			"api_version_fallback_enabled": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Whether the requests are sent again with the nearest supported api-version when Azure rejects the api-version, i.e. the `NoRegisteredProviderFound`, `InvalidApiVersionParameter` and `InvalidResourceType` errors which list the supported api-versions. The api-versions of the same stability are preferred, and a warning is raised for the resources whose requests fall back. This can also be sourced from the `ARM_API_VERSION_FALLBACK_ENABLED` Environment Variable. Defaults to `false`.",
+			},
+
+			"api_version_overrides": schema.MapAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "A mapping of the resource types to the api-versions which the requests of the resources are sent with, regardless of the api-versions in the `type` of the resources, e.g. `{ \"Microsoft.Storage/storageAccounts\" = \"2023-01-01\" }`. It's used to pin the known-good api-versions centrally, e.g. for the clouds whose api-versions trail the public cloud. The body is still validated against the api-version in the `type`, and the `api_version_fallback_enabled` doesn't apply to the pinned api-versions.",
+			},
+
+			"audit_log_file": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The path to a file which records every mutating request, e.g. `PUT`, `PATCH`, `POST` and `DELETE`, as a newline-delimited JSON object which contains the timestamp, principal, method, URL, status code and correlation request ID. The records are appended to the file if it already exists. This can also be sourced from the `ARM_AUDIT_LOG_FILE` Environment Variable.",
+			},
+
+			"data_plane_audiences": schema.MapAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "A mapping of the data plane endpoint suffixes to the audiences of the tokens which are sent to them, e.g. `{ \"vault.contoso.local\" = \"https://vault.contoso.local\" }`. It's used for the custom clouds and the private DNS zones whose endpoints don't match the built-in data plane endpoints of the `environment`. The audience of the longest matched suffix is used, and the suffix which is the same as a built-in endpoint replaces its audience.",
+			},
+
+			"data_source_cache_dir": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The path to a directory which caches the responses of the `GET` requests of the `azapi_resource`, `azapi_resource_list` and `azapi_resource_action` data sources, e.g. to avoid fetching the unchanged reference data like the role definitions and the policy definitions in every plan of the CI pipelines. The responses are keyed by the tenant ID, the object ID or the client ID of the authenticated principal and the URL including the api-version, they aren't cached when the principal can't be determined from the access token, they're used without a request until the `data_source_cache_ttl` expires, then they're revalidated with their ETags if the responses have ETags. The directory is created if it doesn't exist. Please note that the cached responses are stored unencrypted. This can also be sourced from the `ARM_DATA_SOURCE_CACHE_DIR` Environment Variable.",
+			},
+
+			"data_source_cache_ttl": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					myvalidator.StringIsDuration(),
+				},
+				MarkdownDescription: "The duration in which the cached responses of the data sources are used without a request, it's only used when the `data_source_cache_dir` is specified. It's a string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as \"30s\" or \"2h45m\". This can also be sourced from the `ARM_DATA_SOURCE_CACHE_TTL` Environment Variable. Defaults to `1h`.",
+			},
+
+			"maximum_retries": schema.Int64Attribute{
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.Between(0, 20),
+				},
+				MarkdownDescription: "The maximum number of times a failed request is retried, e.g. when it's throttled or the server is temporarily unavailable. Set it to `0` to disable the retries. The retries are also bounded by the timeouts of the operations. This can also be sourced from the `ARM_MAXIMUM_RETRIES` Environment Variable, which must also be between `0` and `20`. Defaults to `3`.",
+			},
+
+			"retry_status_codes": schema.ListAttribute{
+				ElementType: types.Int64Type,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.UniqueValues(),
+					listvalidator.ValueInt64sAre(int64validator.Between(400, 599)),
+				},
+				MarkdownDescription: "A list of the HTTP status codes of the responses which are retried, e.g. `[429, 500, 502]`. Defaults to `[408, 429, 500, 502, 503, 504]`.",
+			},
+
+			"retry_error_message_regex": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(myvalidator.StringIsValidRegex()),
+				},
+				MarkdownDescription: "A list of regular expressions which are matched against the bodies of the failed responses, e.g. `[\"AnotherOperationInProgress\"]`. The failed requests whose response bodies match any of them are retried, even if their status codes aren't in the `retry_status_codes`, e.g. the transient errors which are returned with the `400` or `409` status codes.",
+			},
+
+			"retry_delay": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					myvalidator.StringIsDurationAtLeast(0),
+				},
+				MarkdownDescription: "The initial delay before a failed request is retried, the delay grows exponentially with the retries, but it's overridden by the `Retry-After` header of the response. Set it to `0s` to retry without delay. It's a string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as \"30s\" or \"2h45m\". Defaults to `800ms`.",
+			},
+
+			"maximum_retry_delay": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					myvalidator.StringIsDuration(),
+				},
+				MarkdownDescription: "The maximum delay between the retries of a failed request. It's a string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as \"30s\" or \"2h45m\". Defaults to `60s`.",
+			},
+
+			"policy_bundle": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The path to a policy bundle, which is a JSON file or a directory of JSON files. Every planned body of the `azapi_resource` and `azapi_update_resource` is evaluated against the policies before anything is sent to Azure. Each policy has a `name`, an optional list of `resource_types`, a `condition` which is a [CEL](https://cel.dev) expression with the `resource_type`, `api_version`, `name`, `parent_id`, `location` and `body` variables, an `effect` which is either `deny` or `warn` and a `message`. The policy is violated if the `condition` returns `true`, e.g. `has(body.properties.allowBlobPublicAccess) && body.properties.allowBlobPublicAccess`. The `effect` defaults to `deny`, its violations are reported as errors, while the violations of the `warn` policies are reported as warnings. This can also be sourced from the `ARM_POLICY_BUNDLE` Environment Variable.",
+			},
+
+			"pre_request_hook": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The path to an executable which is invoked before each request is sent, e.g. to enforce organization-specific guardrails. The executable receives a JSON object which contains the `method`, `url` and `body` of the request on the standard input, the query of the `url` is removed except the `api-version`. A non-zero exit code vetoes the request, and the standard error is reported as the reason. The executable may write a JSON object to the standard output to annotate the request with additional headers, e.g. `{\"headers\":{\"x-guardrail\":\"approved\"}}`. This can also be sourced from the `ARM_PRE_REQUEST_HOOK` Environment Variable.",
+			},
+
+			"webhook_url": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The URL of a webhook which receives a JSON summary before and after every mutating request, e.g. `PUT`, `PATCH`, `POST` and `DELETE`, to integrate with the change management systems. The summary is posted as a JSON object which contains the `event`, which is either `pre_apply` or `post_apply`, the `timestamp`, `method`, `url` and `correlation_id` of the request, and the `status` and `error` of the response in the `post_apply` event. The request is vetoed if the webhook doesn't respond to the `pre_apply` event with a 2xx status code, while the failures of the `post_apply` event are only logged. This can also be sourced from the `ARM_WEBHOOK_URL` Environment Variable.",
+			},
+
+			"webhook_secret": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The secret which signs the payloads of the webhook. The hex-encoded HMAC-SHA256 signature of the payload is sent in the `X-Azapi-Signature` header in the format of `sha256=<signature>`, so the webhook can verify the payloads are sent by the provider. This can also be sourced from the `ARM_WEBHOOK_SECRET` Environment Variable.",
+			},
+
+			"read_only": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Whether the provider runs in the read-only mode, e.g. for the break-glass investigations with elevated credentials. When set to `true`, the plans and refreshes work as usual, but all the mutating operations of the resources, e.g. creating, updating and deleting resources and performing actions, fail immediately before any request is sent. The data sources are not affected. This can also be sourced from the `ARM_READ_ONLY` Environment Variable. Defaults to `false`.",
+			},
+
+			"validate_credentials": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Should the Provider validate the credentials when it's configured? When set to `true`, the provider reads the subscription to make sure the credentials are valid, and fails fast if they aren't. This can also be sourced from the `ARM_VALIDATE_CREDENTIALS` Environment Variable. Defaults to `false`.",
 			},
 		},
 	}
 }
 
+// ValidateConfig checks the provider configuration without the credentials, so the mistakes are reported by `terraform validate`
+// before the provider is configured. The values which are unknown or sourced from the environment variables are checked by Configure.
+func (p Provider) ValidateConfig(ctx context.Context, request provider.ValidateConfigRequest, response *provider.ValidateConfigResponse) {
+	var model providerData
+	if response.Diagnostics.Append(request.Config.Get(ctx, &model)...); response.Diagnostics.HasError() {
+		return
+	}
+
+	if !model.Endpoint.IsUnknown() {
+		for i, element := range model.Endpoint.Elements() {
+			var endpoint providerEndpointData
+			if response.Diagnostics.Append(element.(basetypes.ObjectValue).As(ctx, &endpoint, basetypes.ObjectAsOptions{UnhandledNullAsEmpty: true, UnhandledUnknownAsEmpty: true})...); response.Diagnostics.HasError() {
+				return
+			}
+			for name, value := range map[string]types.String{
+				"active_directory_authority_host":     endpoint.ActiveDirectoryAuthorityHost,
+				"resource_manager_endpoint":           endpoint.ResourceManagerEndpoint,
+				"secondary_resource_manager_endpoint": endpoint.SecondaryResourceManagerEndpoint,
+			} {
+				if v := value.ValueString(); v != "" && !isAbsoluteHttpsURL(v) {
+					response.Diagnostics.AddAttributeError(path.Root("endpoint").AtListIndex(i).AtName(name), fmt.Sprintf("Invalid `%s` value.", name), fmt.Sprintf("The `%s` value '%s' is invalid, it must be an absolute https URL.", name, v))
+				}
+			}
+		}
+	}
+
+	if model.ClientCertificate.ValueString() != "" && model.ClientCertificatePath.ValueString() != "" {
+		response.Diagnostics.AddAttributeWarning(path.Root("client_certificate_path"), "Conflicting client certificates", "Both the `client_certificate` and the `client_certificate_path` are specified, only the `client_certificate` is used.")
+	}
+	if model.ClientCertificatePassword.ValueString() != "" && model.ClientCertificate.IsNull() && model.ClientCertificatePath.IsNull() && os.Getenv("ARM_CLIENT_CERTIFICATE") == "" && os.Getenv("ARM_CLIENT_CERTIFICATE_PATH") == "" {
+		response.Diagnostics.AddAttributeWarning(path.Root("client_certificate_password"), "Unused client certificate password", "The `client_certificate_password` is specified, but neither the `client_certificate` nor the `client_certificate_path` is specified.")
+	}
+	if (model.OIDCRequestToken.ValueString() == "") != (model.OIDCRequestURL.ValueString() == "") && !model.OIDCRequestToken.IsUnknown() && !model.OIDCRequestURL.IsUnknown() {
+		response.Diagnostics.AddWarning("Incomplete OIDC request configuration", "The `oidc_request_token` and the `oidc_request_url` must be specified together to request the OIDC token, unless the other one is sourced from the environment variables.")
+	}
+	if model.WebhookSecret.ValueString() != "" && model.WebhookUrl.IsNull() && os.Getenv("ARM_WEBHOOK_URL") == "" {
+		response.Diagnostics.AddAttributeWarning(path.Root("webhook_secret"), "Unused webhook secret", "The `webhook_secret` is specified, but the `webhook_url` is not specified.")
+	}
+	if !model.UseOIDC.IsNull() && !model.UseOIDC.IsUnknown() && !model.UseOIDC.ValueBool() && !model.UseAKSWorkloadIdentity.ValueBool() {
+		for name, value := range map[string]types.String{
+			"oidc_token":                       model.OIDCToken,
+			"oidc_token_file_path":             model.OIDCTokenFilePath,
+			"oidc_request_token":               model.OIDCRequestToken,
+			"oidc_request_url":                 model.OIDCRequestURL,
+			"oidc_azure_service_connection_id": model.OIDCAzureServiceConnectionID,
+		} {
+			if value.ValueString() != "" {
+				response.Diagnostics.AddAttributeWarning(path.Root(name), "Unused OIDC configuration", fmt.Sprintf("The `%s` is specified, but it's not used because the `use_oidc` is `false`.", name))
+			}
+		}
+	}
+}
+
+// isAbsoluteHttpsURL returns whether the value is an absolute https URL.
+func isAbsoluteHttpsURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && u.Scheme == "https" && u.Host != ""
+}
+
 func (p Provider) Configure(ctx context.Context, request provider.ConfigureRequest, response *provider.ConfigureResponse) {
 	var model providerData
 	if response.Diagnostics.Append(request.Config.Get(ctx, &model)...); response.Diagnostics.HasError() {
@@ -369,7 +706,12 @@ func (p Provider) Configure(ctx context.Context, request provider.ConfigureReque
 	}
 
 	// set the defaults from environment variables
-	if model.SubscriptionID.IsNull() {
+	if model.SubscriptionAlias.IsNull() && model.SubscriptionID.IsNull() {
+		if v := os.Getenv("ARM_SUBSCRIPTION_ALIAS"); v != "" {
+			model.SubscriptionAlias = types.StringValue(v)
+		}
+	}
+	if model.SubscriptionID.IsNull() && model.SubscriptionAlias.IsNull() {
 		if v := os.Getenv("ARM_SUBSCRIPTION_ID"); v != "" {
 			model.SubscriptionID = types.StringValue(v)
 		}
@@ -407,21 +749,30 @@ func (p Provider) Configure(ctx context.Context, request provider.ConfigureReque
 		}
 	}
 
+	if model.ManagingTenantID.IsNull() {
+		if v := os.Getenv("ARM_MANAGING_TENANT_ID"); v != "" {
+			model.ManagingTenantID = types.StringValue(v)
+		}
+	}
+
 	if model.Endpoint.IsNull() {
 		activeDirectoryAuthorityHost := os.Getenv("ARM_ACTIVE_DIRECTORY_AUTHORITY_HOST")
 		resourceManagerEndpoint := os.Getenv("ARM_RESOURCE_MANAGER_ENDPOINT")
 		resourceManagerAudience := os.Getenv("ARM_RESOURCE_MANAGER_AUDIENCE")
+		secondaryResourceManagerEndpoint := os.Getenv("ARM_SECONDARY_RESOURCE_MANAGER_ENDPOINT")
 		attrTypes := make(map[string]attr.Type)
 		attrTypes["active_directory_authority_host"] = types.StringType
 		attrTypes["resource_manager_endpoint"] = types.StringType
 		attrTypes["resource_manager_audience"] = types.StringType
+		attrTypes["secondary_resource_manager_endpoint"] = types.StringType
 		model.Endpoint = types.ListValueMust(types.ObjectType{
 			AttrTypes: attrTypes,
 		}, []attr.Value{
 			types.ObjectValueMust(attrTypes, map[string]attr.Value{
-				"active_directory_authority_host": types.StringValue(activeDirectoryAuthorityHost),
-				"resource_manager_endpoint":       types.StringValue(resourceManagerEndpoint),
-				"resource_manager_audience":       types.StringValue(resourceManagerAudience),
+				"active_directory_authority_host":     types.StringValue(activeDirectoryAuthorityHost),
+				"resource_manager_endpoint":           types.StringValue(resourceManagerEndpoint),
+				"resource_manager_audience":           types.StringValue(resourceManagerAudience),
+				"secondary_resource_manager_endpoint": types.StringValue(secondaryResourceManagerEndpoint),
 			}),
 		})
 	}
@@ -540,6 +891,12 @@ func (p Provider) Configure(ctx context.Context, request provider.ConfigureReque
 		}
 	}
 
+	if model.MSIEndpoint.IsNull() {
+		if v := os.Getenv("ARM_MSI_ENDPOINT"); v != "" {
+			model.MSIEndpoint = types.StringValue(v)
+		}
+	}
+
 	if model.PartnerID.IsNull() {
 		if v := os.Getenv("ARM_PARTNER_ID"); v != "" {
 			model.PartnerID = types.StringValue(v)
@@ -572,6 +929,209 @@ func (p Provider) Configure(ctx context.Context, request provider.ConfigureReque
 		model.EnablePreflight = types.BoolValue(false)
 	}
 
+	if model.SchemaValidationEnabled.IsNull() {
+		if v := os.Getenv("ARM_SCHEMA_VALIDATION_ENABLED"); v != "" {
+			model.SchemaValidationEnabled = types.BoolValue(v == "true")
+		} else {
+			model.SchemaValidationEnabled = types.BoolValue(true)
+		}
+	}
+
+	if model.FailOnFailedProvisioningState.IsNull() {
+		model.FailOnFailedProvisioningState = types.BoolValue(false)
+	}
+
+	if model.WarnOnSubscriptionMismatch.IsNull() {
+		if v := os.Getenv("ARM_WARN_ON_SUBSCRIPTION_MISMATCH"); v != "" {
+			model.WarnOnSubscriptionMismatch = types.BoolValue(v == "true")
+		} else {
+			model.WarnOnSubscriptionMismatch = types.BoolValue(false)
+		}
+	}
+
+	if model.ChildResourcesOnDelete.IsNull() {
+		model.ChildResourcesOnDelete = types.StringValue(string(features.ChildResourcesOnDeleteIgnore))
+	}
+
+	if model.ReadOnly.IsNull() {
+		if v := os.Getenv("ARM_READ_ONLY"); v != "" {
+			model.ReadOnly = types.BoolValue(v == "true")
+		} else {
+			model.ReadOnly = types.BoolValue(false)
+		}
+	}
+
+	userFeatures := features.Default()
+	userFeatures.DefaultTags = tags.ExpandTags(model.DefaultTags)
+	userFeatures.MergeDefaultTags = model.MergeDefaultTags.ValueBool()
+	userFeatures.RequiredTags = services.AsStringList(model.RequiredTags)
+	userFeatures.DefaultLocation = location.Normalize(model.DefaultLocation.ValueString())
+	userFeatures.DefaultNaming = model.DefaultName.ValueString()
+	userFeatures.DefaultNamingPrefix = model.DefaultNamingPrefix.ValueString()
+	userFeatures.DefaultNamingSuffix = model.DefaultNamingSuffix.ValueString()
+	userFeatures.EnablePreflight = model.EnablePreflight.ValueBool()
+	userFeatures.SchemaValidationEnabled = model.SchemaValidationEnabled.ValueBool()
+	userFeatures.FailOnFailedProvisioningState = model.FailOnFailedProvisioningState.ValueBool()
+	userFeatures.WarnOnSubscriptionMismatch = model.WarnOnSubscriptionMismatch.ValueBool()
+	userFeatures.ChildResourcesOnDelete = features.ChildResourcesOnDelete(model.ChildResourcesOnDelete.ValueString())
+	if v := model.SoftDeletedResourcesOnCreate.ValueString(); v != "" {
+		userFeatures.SoftDeletedResourcesOnCreate = features.SoftDeletedResourcesOnCreate(v)
+	}
+	userFeatures.ReadOnly = model.ReadOnly.ValueBool()
+	// the default timeouts are validated by the schema validators
+	for _, defaultTimeout := range []struct {
+		value  types.String
+		output *time.Duration
+	}{
+		{value: model.DefaultCreateTimeout, output: &userFeatures.DefaultCreateTimeout},
+		{value: model.DefaultReadTimeout, output: &userFeatures.DefaultReadTimeout},
+		{value: model.DefaultUpdateTimeout, output: &userFeatures.DefaultUpdateTimeout},
+		{value: model.DefaultDeleteTimeout, output: &userFeatures.DefaultDeleteTimeout},
+	} {
+		if v := defaultTimeout.value.ValueString(); v != "" {
+			if d, err := time.ParseDuration(v); err == nil {
+				*defaultTimeout.output = d
+			}
+		}
+	}
+
+	if model.CancellationBehavior.IsNull() {
+		model.CancellationBehavior = types.StringValue(string(clients.CancellationBehaviorAbandon))
+	}
+
+	if model.ApiVersionFallbackEnabled.IsNull() {
+		if v := os.Getenv("ARM_API_VERSION_FALLBACK_ENABLED"); v != "" {
+			model.ApiVersionFallbackEnabled = types.BoolValue(v == "true")
+		} else {
+			model.ApiVersionFallbackEnabled = types.BoolValue(false)
+		}
+	}
+
+	if model.AuditLogFile.IsNull() {
+		if v := os.Getenv("ARM_AUDIT_LOG_FILE"); v != "" {
+			model.AuditLogFile = types.StringValue(v)
+		}
+	}
+
+	if model.WebhookUrl.IsNull() {
+		if v := os.Getenv("ARM_WEBHOOK_URL"); v != "" {
+			model.WebhookUrl = types.StringValue(v)
+		}
+	}
+
+	if model.WebhookSecret.IsNull() {
+		if v := os.Getenv("ARM_WEBHOOK_SECRET"); v != "" {
+			model.WebhookSecret = types.StringValue(v)
+		}
+	}
+
+	if model.DataSourceCacheDir.IsNull() {
+		if v := os.Getenv("ARM_DATA_SOURCE_CACHE_DIR"); v != "" {
+			model.DataSourceCacheDir = types.StringValue(v)
+		}
+	}
+
+	dataSourceCacheTTL := time.Hour
+	if model.DataSourceCacheTTL.IsNull() {
+		if v := os.Getenv("ARM_DATA_SOURCE_CACHE_TTL"); v != "" {
+			model.DataSourceCacheTTL = types.StringValue(v)
+		}
+	}
+	if v := model.DataSourceCacheTTL.ValueString(); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			response.Diagnostics.AddError("Invalid `data_source_cache_ttl` value", fmt.Sprintf("The `data_source_cache_ttl` %q is not a valid duration: %+v", v, err))
+			return
+		}
+		dataSourceCacheTTL = d
+	}
+
+	if model.MaximumRetries.IsNull() {
+		if v := os.Getenv("ARM_MAXIMUM_RETRIES"); v != "" {
+			// the environment variable is bounded like the maximum_retries argument
+			maximumRetries, err := strconv.ParseInt(v, 10, 32)
+			if err != nil || maximumRetries < 0 || maximumRetries > 20 {
+				response.Diagnostics.AddError("Invalid `ARM_MAXIMUM_RETRIES` value", fmt.Sprintf("The `ARM_MAXIMUM_RETRIES` %q is not an integer between 0 and 20", v))
+				return
+			}
+			model.MaximumRetries = types.Int64Value(maximumRetries)
+		}
+	}
+
+	// the zero values of the retry options mean the defaults of the SDK, the negative values disable the retries and the delays
+	retryOptions := policy.RetryOptions{}
+	if !model.MaximumRetries.IsNull() {
+		retryOptions.MaxRetries = int32(model.MaximumRetries.ValueInt64())
+		if retryOptions.MaxRetries == 0 {
+			retryOptions.MaxRetries = -1
+		}
+	}
+	if !model.RetryStatusCodes.IsNull() {
+		retryOptions.StatusCodes = make([]int, 0, len(model.RetryStatusCodes.Elements()))
+		for _, element := range model.RetryStatusCodes.Elements() {
+			if v, ok := element.(types.Int64); ok {
+				retryOptions.StatusCodes = append(retryOptions.StatusCodes, int(v.ValueInt64()))
+			}
+		}
+	}
+	for _, retryDelay := range []struct {
+		name   string
+		value  types.String
+		output *time.Duration
+	}{
+		{name: "retry_delay", value: model.RetryDelay, output: &retryOptions.RetryDelay},
+		{name: "maximum_retry_delay", value: model.MaximumRetryDelay, output: &retryOptions.MaxRetryDelay},
+	} {
+		if v := retryDelay.value.ValueString(); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				response.Diagnostics.AddError(fmt.Sprintf("Invalid `%s` value", retryDelay.name), fmt.Sprintf("The `%s` %q is not a valid duration: %+v", retryDelay.name, v, err))
+				return
+			}
+			*retryDelay.output = d
+		}
+	}
+	// the zero delay means the default delay of the SDK, so the retries without delay are configured with a negative delay
+	if v := model.RetryDelay.ValueString(); v != "" && retryOptions.RetryDelay == 0 {
+		retryOptions.RetryDelay = -1
+	}
+	if !model.RetryErrorMessageRegex.IsNull() {
+		errorMessageRegex := make([]*regexp.Regexp, 0, len(model.RetryErrorMessageRegex.Elements()))
+		for _, element := range model.RetryErrorMessageRegex.Elements() {
+			v, ok := element.(types.String)
+			if !ok || v.IsNull() || v.IsUnknown() {
+				continue
+			}
+			regex, err := regexp.Compile(v.ValueString())
+			if err != nil {
+				response.Diagnostics.AddError("Invalid `retry_error_message_regex` value", fmt.Sprintf("The `retry_error_message_regex` %q is not a valid regular expression: %+v", v.ValueString(), err))
+				return
+			}
+			errorMessageRegex = append(errorMessageRegex, regex)
+		}
+		retryOptions.ShouldRetry = clients.ShouldRetryOnErrorMessages(retryOptions.StatusCodes, errorMessageRegex)
+	}
+
+	if model.PolicyBundle.IsNull() {
+		if v := os.Getenv("ARM_POLICY_BUNDLE"); v != "" {
+			model.PolicyBundle = types.StringValue(v)
+		}
+	}
+
+	if model.PreRequestHook.IsNull() {
+		if v := os.Getenv("ARM_PRE_REQUEST_HOOK"); v != "" {
+			model.PreRequestHook = types.StringValue(v)
+		}
+	}
+
+	if model.ValidateCredentials.IsNull() {
+		if v := os.Getenv("ARM_VALIDATE_CREDENTIALS"); v != "" {
+			model.ValidateCredentials = types.BoolValue(v == "true")
+		} else {
+			model.ValidateCredentials = types.BoolValue(false)
+		}
+	}
+
 	var cloudConfig cloud.Configuration
 	env := model.Environment.ValueString()
 	switch strings.ToLower(env) {
@@ -586,6 +1146,7 @@ func (p Provider) Configure(ctx context.Context, request provider.ConfigureReque
 		return
 	}
 
+	secondaryResourceManagerEndpoint := ""
 	if elements := model.Endpoint.Elements(); len(elements) != 0 {
 		var endpoint providerEndpointData
 		diags := elements[0].(basetypes.ObjectValue).As(ctx, &endpoint, basetypes.ObjectAsOptions{
@@ -598,12 +1159,27 @@ func (p Provider) Configure(ctx context.Context, request provider.ConfigureReque
 		}
 		resourceManagerEndpoint := cloudConfig.Services[cloud.ResourceManager].Endpoint
 		resourceManagerAudience := cloudConfig.Services[cloud.ResourceManager].Audience
+		defaultAudience := resourceManagerAudience
 		if v := endpoint.ResourceManagerEndpoint.ValueString(); v != "" {
+			if !isAbsoluteHttpsURL(v) {
+				response.Diagnostics.AddError("Invalid `resource_manager_endpoint` value.", fmt.Sprintf("The `resource_manager_endpoint` value '%s' is invalid, it must be an absolute https URL.", v))
+				return
+			}
 			resourceManagerEndpoint = v
 		}
 		if v := endpoint.ResourceManagerAudience.ValueString(); v != "" {
 			resourceManagerAudience = v
 		}
+		if endpoint.ResourceManagerEndpoint.ValueString() != "" && !strings.EqualFold(strings.TrimSuffix(resourceManagerAudience, "/"), strings.TrimSuffix(defaultAudience, "/")) {
+			response.Diagnostics.AddWarning("The `resource_manager_audience` doesn't match the Azure Resource Manager audience", fmt.Sprintf("The `resource_manager_endpoint` is overridden and the `resource_manager_audience` '%s' doesn't match the Azure Resource Manager audience '%s' of the `%s` environment. Resource Management Private Link endpoints only accept tokens issued for the Azure Resource Manager audience, requests might fail with authentication errors.", resourceManagerAudience, defaultAudience, env))
+		}
+
+		// copy the services to avoid changing the shared cloud configuration, so each provider alias can have its own endpoints
+		services := make(map[cloud.ServiceName]cloud.ServiceConfiguration, len(cloudConfig.Services))
+		for k, v := range cloudConfig.Services {
+			services[k] = v
+		}
+		cloudConfig.Services = services
 		cloudConfig.Services[cloud.ResourceManager] = cloud.ServiceConfiguration{
 			Endpoint: resourceManagerEndpoint,
 			Audience: resourceManagerAudience,
@@ -611,8 +1187,27 @@ func (p Provider) Configure(ctx context.Context, request provider.ConfigureReque
 		if v := endpoint.ActiveDirectoryAuthorityHost.ValueString(); v != "" {
 			cloudConfig.ActiveDirectoryAuthorityHost = v
 		}
+		if v := endpoint.SecondaryResourceManagerEndpoint.ValueString(); v != "" {
+			if !isAbsoluteHttpsURL(v) {
+				response.Diagnostics.AddError("Invalid `secondary_resource_manager_endpoint` value.", fmt.Sprintf("The `secondary_resource_manager_endpoint` value '%s' is invalid, it must be an absolute https URL.", v))
+				return
+			}
+			secondaryResourceManagerEndpoint = v
+		}
 	}
 
+	dataPlaneAudiences := make(map[string]string)
+	for suffix, element := range model.DataPlaneAudiences.Elements() {
+		audience := element.(basetypes.StringValue).ValueString()
+		suffix = strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(suffix), "https://"), ".")
+		if suffix == "" || strings.Contains(suffix, "/") || audience == "" {
+			response.Diagnostics.AddAttributeError(path.Root("data_plane_audiences"), "Invalid data_plane_audiences", fmt.Sprintf("The audience %q of the suffix %q is invalid, the keys should be the host name suffixes like `vault.contoso.local`, and the values should be the audiences like `https://vault.contoso.local`.", audience, suffix))
+			return
+		}
+		dataPlaneAudiences[suffix] = audience
+	}
+	cloudConfig = withDataPlaneAudiences(cloudConfig, dataPlaneAudiences)
+
 	var auxTenants []string
 	if elements := model.AuxiliaryTenantIDs.Elements(); len(elements) != 0 {
 		for _, element := range elements {
@@ -620,6 +1215,16 @@ func (p Provider) Configure(ctx context.Context, request provider.ConfigureReque
 		}
 	}
 
+	apiVersionOverrides := make(map[string]string)
+	for resourceType, element := range model.ApiVersionOverrides.Elements() {
+		apiVersion := element.(basetypes.StringValue).ValueString()
+		if !strings.Contains(resourceType, "/") || strings.Contains(resourceType, "@") || apiVersion == "" {
+			response.Diagnostics.AddAttributeError(path.Root("api_version_overrides"), "Invalid api_version_overrides", fmt.Sprintf("The override %q = %q is invalid, the keys should be the resource types like `Microsoft.Storage/storageAccounts`, and the values should be the api-versions like `2023-01-01`.", resourceType, apiVersion))
+			return
+		}
+		apiVersionOverrides[resourceType] = apiVersion
+	}
+
 	option := azidentity.DefaultAzureCredentialOptions{
 		AdditionallyAllowedTenants: auxTenants,
 		ClientOptions: azcore.ClientOptions{
@@ -627,28 +1232,48 @@ func (p Provider) Configure(ctx context.Context, request provider.ConfigureReque
 		},
 		TenantID: model.TenantID.ValueString(),
 	}
+	// the credentials of the delegated subscriptions are registered in the managing tenant, so the tokens must be issued by it
+	if v := model.ManagingTenantID.ValueString(); v != "" {
+		option.TenantID = v
+	}
 
-	cred, err := buildChainedTokenCredential(model, option)
+	creds, err := buildTokenCredentials(model, option)
+	if err != nil {
+		response.Diagnostics.AddError("Failed to obtain a credential.", err.Error())
+		return
+	}
+	cred, err := buildChainedTokenCredential(creds)
 	if err != nil {
 		response.Diagnostics.AddError("Failed to obtain a credential.", err.Error())
 		return
 	}
 
 	copt := &clients.Option{
-		Cred:                 cred,
-		CloudCfg:             cloudConfig,
-		ApplicationUserAgent: buildUserAgent(request.TerraformVersion, model.PartnerID.ValueString(), model.DisableTerraformPartnerID.ValueBool()),
-		Features: features.UserFeatures{
-			DefaultTags:     tags.ExpandTags(model.DefaultTags),
-			DefaultLocation: location.Normalize(model.DefaultLocation.ValueString()),
-			DefaultNaming:   model.DefaultName.ValueString(),
-			EnablePreflight: model.EnablePreflight.ValueBool(),
-		},
-		SkipProviderRegistration:    model.SkipProviderRegistration.ValueBool(),
-		DisableCorrelationRequestID: model.DisableCorrelationRequestID.ValueBool(),
-		CustomCorrelationRequestID:  model.CustomCorrelationRequestID.ValueString(),
-		SubscriptionId:              model.SubscriptionID.ValueString(),
-		TenantId:                    model.TenantID.ValueString(),
+		Cred:                             cred,
+		CloudCfg:                         cloudConfig,
+		Environment:                      strings.ToLower(env),
+		SecondaryResourceManagerEndpoint: secondaryResourceManagerEndpoint,
+		ApplicationUserAgent:             buildUserAgent(request.TerraformVersion, model.PartnerID.ValueString(), model.DisableTerraformPartnerID.ValueBool()),
+		Features:                         userFeatures,
+		SkipProviderRegistration:         model.SkipProviderRegistration.ValueBool(),
+		DisableCorrelationRequestID:      model.DisableCorrelationRequestID.ValueBool(),
+		CustomCorrelationRequestID:       model.CustomCorrelationRequestID.ValueString(),
+		SubscriptionId:                   model.SubscriptionID.ValueString(),
+		TenantId:                         model.TenantID.ValueString(),
+		ManagingTenantId:                 model.ManagingTenantID.ValueString(),
+		AuxiliaryTenantIds:               auxTenants,
+		CancellationBehavior:             clients.CancellationBehavior(model.CancellationBehavior.ValueString()),
+		ApiVersionFallback:               model.ApiVersionFallbackEnabled.ValueBool(),
+		ApiVersionOverrides:              apiVersionOverrides,
+		AuditLogFile:                     model.AuditLogFile.ValueString(),
+		PreRequestHook:                   model.PreRequestHook.ValueString(),
+		PolicyBundle:                     model.PolicyBundle.ValueString(),
+		WebhookUrl:                       model.WebhookUrl.ValueString(),
+		WebhookSecret:                    model.WebhookSecret.ValueString(),
+		DataSourceCacheDir:               model.DataSourceCacheDir.ValueString(),
+		DataSourceCacheTTL:               dataSourceCacheTTL,
+		Retry:                            retryOptions,
+		ManagedIdentityCredential:        dataPlaneManagedIdentityCredential(model, option.ClientOptions),
 	}
 
 	client := &clients.Client{}
@@ -657,6 +1282,26 @@ func (p Provider) Configure(ctx context.Context, request provider.ConfigureReque
 		return
 	}
 
+	if doctorModeEnabled() {
+		response.Diagnostics.AddWarning("Provider configuration report", doctorReport(ctx, model, cloudConfig, creds))
+	}
+
+	if alias := model.SubscriptionAlias.ValueString(); alias != "" {
+		subscriptionId, err := resolveSubscriptionAlias(ctx, client, alias)
+		if err != nil {
+			response.Diagnostics.AddError("Failed to resolve the subscription alias", err.Error())
+			return
+		}
+		client.Account = clients.NewResourceManagerAccount(model.TenantID.ValueString(), subscriptionId)
+	}
+
+	if model.ValidateCredentials.ValueBool() {
+		if err = validateCredentials(ctx, client); err != nil {
+			response.Diagnostics.AddError("Invalid credentials", err.Error())
+			return
+		}
+	}
+
 	// load schema
 	azure.GetAzureSchema()
 
@@ -679,6 +1324,9 @@ func (p Provider) Functions(ctx context.Context) []func() function.Function {
 		func() function.Function { return &functions.ResourceGroupResourceIdFunction{} },
 		func() function.Function { return &functions.ManagementGroupResourceIdFunction{} },
 		func() function.Function { return &functions.ExtensionResourceIdFunction{} },
+		func() function.Function { return &functions.ValidateResourceNameFunction{} },
+		func() function.Function { return &functions.NormalizeResourceIdFunction{} },
+		func() function.Function { return &functions.CompareApiVersionsFunction{} },
 	}
 }
 
@@ -699,6 +1347,15 @@ func (p Provider) DataSources(ctx context.Context) []func() datasource.DataSourc
 		func() datasource.DataSource {
 			return &services.ClientConfigDataSource{}
 		},
+		func() datasource.DataSource {
+			return &services.ResourceExistsDataSource{}
+		},
+		func() datasource.DataSource {
+			return &services.ResourceImportListDataSource{}
+		},
+		func() datasource.DataSource {
+			return &services.ExportTemplateDataSource{}
+		},
 	}
 
 }
@@ -717,6 +1374,9 @@ func (p Provider) Resources(ctx context.Context) []func() resource.Resource {
 		func() resource.Resource {
 			return &services.DataPlaneResource{}
 		},
+		func() resource.Resource {
+			return &services.AzapiResourceBundle{}
+		},
 	}
 }
 
@@ -749,34 +1409,99 @@ func buildUserAgent(terraformVersion string, partnerID string, disableTerraformP
 	return userAgent
 }
 
-func buildChainedTokenCredential(model providerData, options azidentity.DefaultAzureCredentialOptions) (*azidentity.ChainedTokenCredential, error) {
-	log.Printf("[DEBUG] building chained token credential")
-	var creds []azcore.TokenCredential
+// validateCredentials sends a cheap authenticated request, which reads the subscription, to make sure the credentials are valid.
+func validateCredentials(ctx context.Context, client *clients.Client) error {
+	subscriptionId := client.Account.GetSubscriptionId()
+	if subscriptionId == "" {
+		return fmt.Errorf("validating the credentials: no subscription ID is configured, please specify the `subscription_id` field or the `ARM_SUBSCRIPTION_ID` environment variable")
+	}
+	if _, err := client.ResourceClient.Get(ctx, fmt.Sprintf("/subscriptions/%s", subscriptionId), "2022-12-01", clients.DefaultRequestOptions()); err != nil {
+		return fmt.Errorf("validating the credentials by reading the subscription %q: %+v", subscriptionId, err)
+	}
+	return nil
+}
+
+// resolveSubscriptionAlias returns the ID of the subscription whose alias or display name matches the input.
+// The subscription aliases are looked up first because they're unique in the tenant, reading them requires the permissions at the tenant scope,
+// so the display names of the subscriptions which are accessible by the credentials are used as a fallback.
+func resolveSubscriptionAlias(ctx context.Context, client *clients.Client, alias string) (string, error) {
+	responseBody, err := client.ResourceClient.Get(ctx, fmt.Sprintf("/providers/Microsoft.Subscription/aliases/%s", url.PathEscape(alias)), "2021-10-01", clients.DefaultRequestOptions())
+	if err == nil {
+		if responseMap, ok := responseBody.(map[string]interface{}); ok {
+			if properties, ok := responseMap["properties"].(map[string]interface{}); ok {
+				if subscriptionId, ok := properties["subscriptionId"].(string); ok && subscriptionId != "" {
+					return subscriptionId, nil
+				}
+			}
+		}
+	} else {
+		log.Printf("[DEBUG] reading the subscription alias %q: %+v, falling back to the display names of the subscriptions", alias, err)
+	}
+
+	responseBody, err = client.ResourceClient.List(ctx, "/subscriptions", "2022-12-01", clients.DefaultRequestOptions())
+	if err != nil {
+		return "", fmt.Errorf("listing the subscriptions to resolve the subscription alias %q: %+v", alias, err)
+	}
+	matches := make([]string, 0)
+	if responseMap, ok := responseBody.(map[string]interface{}); ok {
+		values, _ := responseMap["value"].([]interface{})
+		for _, value := range values {
+			valueMap, ok := value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if displayName, ok := valueMap["displayName"].(string); ok && strings.EqualFold(displayName, alias) {
+				if subscriptionId, ok := valueMap["subscriptionId"].(string); ok && subscriptionId != "" {
+					matches = append(matches, subscriptionId)
+				}
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no subscription is found whose alias or display name is %q, please check whether the credentials have access to it", alias)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("the display name %q matches more than one subscription: %s, please specify the `subscription_id` instead", alias, strings.Join(matches, ", "))
+	}
+}
+
+// namedTokenCredential is a credential in the chain with the name of the authentication method, e.g. `client_secret`.
+type namedTokenCredential struct {
+	name string
+	cred azcore.TokenCredential
+}
+
+// buildTokenCredentials returns the enabled credentials in the order they're tried.
+func buildTokenCredentials(model providerData, options azidentity.DefaultAzureCredentialOptions) ([]namedTokenCredential, error) {
+	log.Printf("[DEBUG] building token credentials")
+	var creds []namedTokenCredential
 
 	if model.UseOIDC.ValueBool() || model.UseAKSWorkloadIdentity.ValueBool() {
 		log.Printf("[DEBUG] oidc credential or AKS Workload Identity enabled")
 		if cred, err := buildOidcCredential(model, options); err == nil {
-			creds = append(creds, cred)
+			creds = append(creds, namedTokenCredential{name: "oidc", cred: cred})
 		} else {
 			log.Printf("[DEBUG] failed to initialize oidc credential: %v", err)
 		}
 
 		log.Printf("[DEBUG] azure pipelines credential enabled")
 		if cred, err := buildAzurePipelinesCredential(model, options); err == nil {
-			creds = append(creds, cred)
+			creds = append(creds, namedTokenCredential{name: "azure_pipelines", cred: cred})
 		} else {
 			log.Printf("[DEBUG] failed to initialize azure pipelines credential: %v", err)
 		}
 	}
 
 	if cred, err := buildClientSecretCredential(model, options); err == nil {
-		creds = append(creds, cred)
+		creds = append(creds, namedTokenCredential{name: "client_secret", cred: cred})
 	} else {
 		log.Printf("[DEBUG] failed to initialize client secret credential: %v", err)
 	}
 
 	if cred, err := buildClientCertificateCredential(model, options); err == nil {
-		creds = append(creds, cred)
+		creds = append(creds, namedTokenCredential{name: "client_certificate", cred: cred})
 	} else {
 		log.Printf("[DEBUG] failed to initialize client certificate credential: %v", err)
 	}
@@ -784,7 +1509,7 @@ func buildChainedTokenCredential(model providerData, options azidentity.DefaultA
 	if model.UseMSI.ValueBool() {
 		log.Printf("[DEBUG] msi credential enabled")
 		if cred, err := buildManagedIdentityCredential(model, options); err == nil {
-			creds = append(creds, cred)
+			creds = append(creds, namedTokenCredential{name: "msi", cred: cred})
 		} else {
 			log.Printf("[DEBUG] failed to initialize msi credential: %v", err)
 		}
@@ -793,7 +1518,7 @@ func buildChainedTokenCredential(model providerData, options azidentity.DefaultA
 	if model.UseCLI.ValueBool() {
 		log.Printf("[DEBUG] cli credential enabled")
 		if cred, err := buildAzureCLICredential(options); err == nil {
-			creds = append(creds, cred)
+			creds = append(creds, namedTokenCredential{name: "cli", cred: cred})
 		} else {
 			log.Printf("[DEBUG] failed to initialize cli credential: %v", err)
 		}
@@ -803,7 +1528,15 @@ func buildChainedTokenCredential(model providerData, options azidentity.DefaultA
 		return nil, fmt.Errorf("no credentials were successfully initialized")
 	}
 
-	return azidentity.NewChainedTokenCredential(creds, nil)
+	return creds, nil
+}
+
+func buildChainedTokenCredential(creds []namedTokenCredential) (*azidentity.ChainedTokenCredential, error) {
+	sources := make([]azcore.TokenCredential, 0, len(creds))
+	for _, cred := range creds {
+		sources = append(sources, cred.cred)
+	}
+	return azidentity.NewChainedTokenCredential(sources, nil)
 }
 
 func buildClientSecretCredential(model providerData, options azidentity.DefaultAzureCredentialOptions) (azcore.TokenCredential, error) {
@@ -898,13 +1631,45 @@ func buildManagedIdentityCredential(model providerData, options azidentity.Defau
 	if err != nil {
 		return nil, err
 	}
-	o := &azidentity.ManagedIdentityCredentialOptions{
-		ClientOptions: options.ClientOptions,
-		ID:            azidentity.ClientID(*clientId),
+	o, err := managedIdentityCredentialOptions(options.ClientOptions, model.MSIEndpoint.ValueString())
+	if err != nil {
+		return nil, err
 	}
+	o.ID = azidentity.ClientID(*clientId)
 	return NewManagedIdentityCredential(o)
 }
 
+// managedIdentityCredentialOptions returns the options of the managed identity credentials, the token requests are sent to the custom
+// managed identity endpoint if it's specified.
+func managedIdentityCredentialOptions(clientOptions azcore.ClientOptions, msiEndpoint string) (*azidentity.ManagedIdentityCredentialOptions, error) {
+	o := &azidentity.ManagedIdentityCredentialOptions{
+		ClientOptions: clientOptions,
+	}
+	if msiEndpoint != "" {
+		msiEndpointPolicy, err := newMsiEndpointPolicy(msiEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		o.ClientOptions.PerCallPolicies = append(append(make([]policy.Policy, 0, len(o.ClientOptions.PerCallPolicies)+1), o.ClientOptions.PerCallPolicies...), msiEndpointPolicy)
+	}
+	return o, nil
+}
+
+// dataPlaneManagedIdentityCredential returns the builder of the managed identity credentials which are used by the data plane resources
+// whose `use_msi` is `true`, they share the managed identity endpoint of the provider.
+func dataPlaneManagedIdentityCredential(model providerData, clientOptions azcore.ClientOptions) func(clientId string) (azcore.TokenCredential, error) {
+	return func(clientId string) (azcore.TokenCredential, error) {
+		o, err := managedIdentityCredentialOptions(clientOptions, model.MSIEndpoint.ValueString())
+		if err != nil {
+			return nil, err
+		}
+		if clientId != "" {
+			o.ID = azidentity.ClientID(clientId)
+		}
+		return azidentity.NewManagedIdentityCredential(o)
+	}
+}
+
 func buildAzureCLICredential(options azidentity.DefaultAzureCredentialOptions) (azcore.TokenCredential, error) {
 	log.Printf("[DEBUG] building azure cli credential")
 	o := &azidentity.AzureCLICredentialOptions{
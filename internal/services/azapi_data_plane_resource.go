@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"os"
 	"reflect"
 	"time"
 
@@ -22,6 +24,7 @@ import (
 	"github.com/Azure/terraform-provider-azapi/utils"
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -42,11 +45,13 @@ type DataPlaneResourceModel struct {
 	ParentID                      types.String        `tfsdk:"parent_id"`
 	Type                          types.String        `tfsdk:"type"`
 	Body                          types.Dynamic       `tfsdk:"body"`
+	Authentication                types.Object        `tfsdk:"authentication"`
 	IgnoreCasing                  types.Bool          `tfsdk:"ignore_casing"`
 	IgnoreMissingProperty         types.Bool          `tfsdk:"ignore_missing_property"`
 	ReplaceTriggersExternalValues types.Dynamic       `tfsdk:"replace_triggers_external_values"`
 	ReplaceTriggersRefs           types.List          `tfsdk:"replace_triggers_refs"`
 	ResponseExportValues          types.Dynamic       `tfsdk:"response_export_values"`
+	OutputSchema                  types.Map           `tfsdk:"output_schema"`
 	Retry                         retry.RetryValue    `tfsdk:"retry"`
 	Locks                         types.List          `tfsdk:"locks"`
 	Output                        types.Dynamic       `tfsdk:"output"`
@@ -138,6 +143,42 @@ func (r *DataPlaneResource) Schema(ctx context.Context, request resource.SchemaR
 				MarkdownDescription: docstrings.Body(),
 			},
 
+			"authentication": schema.SingleNestedAttribute{
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"sas_token_env_var": schema.StringAttribute{
+						Optional: true,
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+							stringvalidator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("sas_token_env_var"), path.MatchRelative().AtParent().AtName("account_key_env_var"), path.MatchRelative().AtParent().AtName("use_msi")),
+						},
+						MarkdownDescription: "The name of the environment variable which contains the shared access signature which is appended to the query of the requests, e.g. `sv=2022-11-02&ss=b&srt=sco&sp=rwdlac&se=2024-01-01T00:00:00Z&sig=...`.",
+					},
+					"account_key_env_var": schema.StringAttribute{
+						Optional: true,
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+						MarkdownDescription: "The name of the environment variable which contains the storage account key which is used to sign the requests with the [Shared Key authorization](https://learn.microsoft.com/en-us/rest/api/storageservices/authorize-with-shared-key). It's supported by the blob, file and queue services, and the `x-ms-version` header should be specified in the request headers.",
+					},
+					"use_msi": schema.BoolAttribute{
+						Optional: true,
+						Validators: []validator.Bool{
+							myvalidator.BoolIsTrue(),
+						},
+						MarkdownDescription: "Should the requests be authenticated with the Azure Active Directory tokens of a managed identity instead of the credentials of the provider? It's useful when the data plane roles, e.g. the `Storage Blob Data Contributor` role, are assigned to a different identity than the one of the provider. It must be `true` if it's specified. The tokens are requested from the `msi_endpoint` of the provider if it's specified.",
+					},
+					"client_id": schema.StringAttribute{
+						Optional: true,
+						Validators: []validator.String{
+							stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("use_msi")),
+						},
+						MarkdownDescription: "The client ID of the user-assigned managed identity which is used when `use_msi` is `true`. The system-assigned managed identity is used if it's not specified.",
+					},
+				},
+				MarkdownDescription: "The authentication which is used instead of the credentials of the provider, it's useful when the data plane RBAC isn't enabled on the target account, or the data plane roles are assigned to a different identity. Exactly one of `sas_token_env_var`, `account_key_env_var` and `use_msi` must be specified. Only the names of the environment variables are stored in the state, the SAS token and the account key are read from the environment variables in every operation. If the environment variable isn't set, the requests are authenticated with the credentials of the provider and a warning is raised.",
+			},
+
 			"ignore_casing": schema.BoolAttribute{
 				Optional:            true,
 				Computed:            true,
@@ -154,6 +195,8 @@ func (r *DataPlaneResource) Schema(ctx context.Context, request resource.SchemaR
 
 			"response_export_values": CommonAttributeResponseExportValues(),
 
+			"output_schema": CommonAttributeOutputSchema(),
+
 			"retry": retry.SingleNestedAttribute(ctx),
 
 			"replace_triggers_external_values": schema.DynamicAttribute{
@@ -290,12 +333,34 @@ func (r *DataPlaneResource) ModifyPlan(ctx context.Context, request resource.Mod
 		return
 	}
 
-	if state == nil || !plan.ResponseExportValues.Equal(state.ResponseExportValues) || !dynamic.SemanticallyEqual(plan.Body, state.Body) {
+	response.Diagnostics.Append(validateOutputSchema(config.ResponseExportValues, config.OutputSchema)...)
+
+	if !plan.ParentID.IsUnknown() {
+		// the parent_id is the host of the data plane endpoint
+		response.Diagnostics.Append(cloudEndpointDiagnostics(r.ProviderData.Environment, "parent_id", "https://"+plan.ParentID.ValueString())...)
+	}
+	if dynamic.IsFullyKnown(plan.Body) {
+		var body interface{}
+		if err := unmarshalBody(plan.Body, &body); err != nil {
+			response.Diagnostics.AddError("Invalid body", fmt.Sprintf(`The argument "body" is invalid: %s`, err.Error()))
+			return
+		}
+		response.Diagnostics.Append(cloudEndpointDiagnostics(r.ProviderData.Environment, "body", body)...)
+	}
+
+	if state == nil || !plan.ResponseExportValues.Equal(state.ResponseExportValues) || !plan.OutputSchema.Equal(state.OutputSchema) || !dynamic.SemanticallyEqual(plan.Body, state.Body) {
 		plan.Output = basetypes.NewDynamicUnknown()
 	} else {
 		plan.Output = state.Output
 	}
 
+	if state != nil && !plan.Type.IsUnknown() && !dynamic.SemanticallyEqual(plan.Body, state.Body) {
+		// the resources which can't be updated must be replaced to apply the changes of the body
+		if id, err := parse.NewDataPlaneResourceId(plan.Name.ValueString(), plan.ParentID.ValueString(), plan.Type.ValueString()); err == nil && id.NoUpdate {
+			response.RequiresReplace.Append(path.Root("body"))
+		}
+	}
+
 	response.Diagnostics.Append(response.Plan.Set(ctx, plan)...)
 
 	// Check if any paths in replace_triggers_refs have changed
@@ -360,6 +425,10 @@ func (r *DataPlaneResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan, s
 		return
 	}
 
+	if diagnostics.Append(readOnlyModeDiagnostics(r.ProviderData.Features, "Creating or updating", id.ID())...); diagnostics.HasError() {
+		return
+	}
+
 	var client clients.DataPlaneRequester
 	client = r.ProviderData.DataPlaneClient
 	if !model.Retry.IsNull() {
@@ -373,16 +442,23 @@ func (r *DataPlaneResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan, s
 		client = r.ProviderData.DataPlaneClient.WithRetry(bkof, regexps)
 	}
 	isNewResource := state == nil || state.Raw.IsNull()
+	// the name of the existing resource is generated by the service, so its ID is kept in the state instead of being built from the name
+	if id.GeneratedName && !isNewResource {
+		if id, err = parse.DataPlaneResourceIDWithResourceType(model.ID.ValueString(), model.Type.ValueString()); err != nil {
+			diagnostics.AddError("Error parsing ID", err.Error())
+			return
+		}
+	}
 
 	var timeout time.Duration
 	var diags diag.Diagnostics
 	if isNewResource {
-		timeout, diags = model.Timeouts.Create(ctx, 30*time.Minute)
+		timeout, diags = model.Timeouts.Create(ctx, r.ProviderData.Features.DefaultCreateTimeout)
 		if diagnostics.Append(diags...); diagnostics.HasError() {
 			return
 		}
 	} else {
-		timeout, diags = model.Timeouts.Update(ctx, 30*time.Minute)
+		timeout, diags = model.Timeouts.Update(ctx, r.ProviderData.Features.DefaultUpdateTimeout)
 		if diagnostics.Append(diags...); diagnostics.HasError() {
 			return
 		}
@@ -390,10 +466,15 @@ func (r *DataPlaneResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan, s
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	if isNewResource {
+	ctx, deprecationNotices := clients.WithDeprecationNotices(ctx)
+	defer appendDeprecationWarnings(diagnostics, deprecationNotices)
+
+	authentication := expandDataPlaneAuthentication(ctx, model.Authentication, diagnostics)
+
+	if isNewResource && !id.AlwaysExists && !id.WriteOnly && !id.GeneratedName {
 		// check if the resource already exists using the non-retry client to avoid issue where user specifies
 		// a FooResourceNotFound error as a retryable error
-		_, err = r.ProviderData.DataPlaneClient.Get(ctx, id, clients.NewRequestOptions(model.ReadHeaders, model.ReadQueryParameters))
+		_, err = r.ProviderData.DataPlaneClient.Get(ctx, id, clients.NewRequestOptions(model.ReadHeaders, model.ReadQueryParameters).WithDataPlaneAuthentication(authentication))
 		if err == nil {
 			diagnostics.AddError("Resource already exists", tf.ImportAsExistsError("azapi_data_plane_resource", id.ID()).Error())
 			return
@@ -404,7 +485,7 @@ func (r *DataPlaneResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan, s
 		}
 	}
 
-	body := make(map[string]interface{})
+	var body interface{} = make(map[string]interface{})
 	if err := unmarshalBody(model.Body, &body); err != nil {
 		diagnostics.AddError("Invalid body", fmt.Sprintf(`The argument "body" is invalid: %s`, err.Error()))
 		return
@@ -415,26 +496,62 @@ func (r *DataPlaneResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan, s
 		defer locks.UnlockByID(id)
 	}
 
-	_, err = client.CreateOrUpdateThenPoll(ctx, id, body, clients.NewRequestOptions(model.CreateHeaders, model.CreateQueryParameters))
-	if err != nil {
-		diagnostics.AddError("Failed to create/update resource", fmt.Errorf("creating/updating %q: %+v", id, err).Error())
-		return
+	responseHeader := &clients.ResponseHeader{}
+	if isNewResource && id.GeneratedNameHeader != "" {
+		ctx, responseHeader = clients.WithResponseHeader(ctx, id.GeneratedNameHeader)
+	}
+	var responseBody interface{}
+	// the resources which can't be updated are replaced when the body is changed, so the other changes are only saved to the state
+	if isNewResource || !id.NoUpdate {
+		requestUrl, requestMethod := id.CreateUrl, id.CreateMethod
+		if !isNewResource {
+			requestUrl, requestMethod = id.UpdateUrl, id.UpdateMethod
+		}
+		// the name must be specified in the body when it's not part of the request URL
+		if bodyMap, ok := body.(map[string]interface{}); ok && id.NameProperty != "" && requestUrl != id.AzureResourceId {
+			setBodyProperty(bodyMap, id.NameProperty, id.Name)
+		}
+		requestOptions := clients.NewRequestOptions(model.CreateHeaders, model.CreateQueryParameters).WithDataPlaneAuthentication(authentication)
+		if id.ContentType != "" {
+			requestOptions = requestOptions.WithDefaultHeader("Content-Type", id.ContentType)
+		}
+		if id.WriteOnly {
+			requestOptions = requestOptions.WithInitialResponse()
+		}
+		if requestUrl == id.AzureResourceId && requestMethod == http.MethodPut {
+			responseBody, err = client.CreateOrUpdateThenPoll(ctx, id, body, requestOptions)
+		} else {
+			responseBody, err = client.Action(ctx, requestUrl, "", id.ApiVersion, requestMethod, body, requestOptions)
+		}
+		if err != nil {
+			diagnostics.AddError("Failed to create/update resource", fmt.Errorf("creating/updating %q: %+v", id, err).Error())
+			return
+		}
+		if isNewResource && id.GeneratedName {
+			if id, err = generatedDataPlaneResourceId(id, responseBody, responseHeader.Value()); err != nil {
+				diagnostics.AddError("Failed to create/update resource", fmt.Errorf("creating %q: %+v", id, err).Error())
+				return
+			}
+		}
 	}
 
-	responseBody, err := client.Get(ctx, id, clients.NewRequestOptions(model.ReadHeaders, model.ReadQueryParameters))
-	if err != nil {
-		if utils.ResponseErrorWasNotFound(err) {
-			tflog.Info(ctx, fmt.Sprintf("Error reading %q - removing from state", id.ID()))
-			state.RemoveResource(ctx)
+	// the write-only resources can't be read back, the output is built from the response of the create request
+	if !id.WriteOnly {
+		responseBody, err = client.Get(ctx, id, clients.NewRequestOptions(model.ReadHeaders, model.ReadQueryParameters).WithDataPlaneAuthentication(authentication))
+		if err != nil {
+			if utils.ResponseErrorWasNotFound(err) {
+				tflog.Info(ctx, fmt.Sprintf("Error reading %q - removing from state", id.ID()))
+				state.RemoveResource(ctx)
+				return
+			}
+			diagnostics.AddError("Failed to retrieve resource", fmt.Errorf("reading %s: %+v", id, err).Error())
 			return
 		}
-		diagnostics.AddError("Failed to retrieve resource", fmt.Errorf("reading %s: %+v", id, err).Error())
-		return
 	}
 
 	model.ID = basetypes.NewStringValue(id.ID())
 
-	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues)
+	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues, model.OutputSchema)
 	if err != nil {
 		diagnostics.AddError("Failed to build output", err.Error())
 		return
@@ -450,7 +567,7 @@ func (r *DataPlaneResource) Read(ctx context.Context, request resource.ReadReque
 		return
 	}
 
-	readTimeout, diags := model.Timeouts.Read(ctx, 5*time.Minute)
+	readTimeout, diags := model.Timeouts.Read(ctx, r.ProviderData.Features.DefaultReadTimeout)
 	response.Diagnostics.Append(diags...)
 	if response.Diagnostics.HasError() {
 		return
@@ -459,11 +576,18 @@ func (r *DataPlaneResource) Read(ctx context.Context, request resource.ReadReque
 	ctx, cancel := context.WithTimeout(ctx, readTimeout)
 	defer cancel()
 
+	ctx, deprecationNotices := clients.WithDeprecationNotices(ctx)
+	defer appendDeprecationWarnings(&response.Diagnostics, deprecationNotices)
+
 	id, err := parse.DataPlaneResourceIDWithResourceType(model.ID.ValueString(), model.Type.ValueString())
 	if err != nil {
 		response.Diagnostics.AddError("Error parsing ID", err.Error())
 		return
 	}
+	if id.WriteOnly {
+		tflog.Info(ctx, fmt.Sprintf("%q can't be read back, the previous state is kept", id.ID()))
+		return
+	}
 
 	var client clients.DataPlaneRequester
 	client = r.ProviderData.DataPlaneClient
@@ -477,18 +601,22 @@ func (r *DataPlaneResource) Read(ctx context.Context, request resource.ReadReque
 		)
 		client = r.ProviderData.DataPlaneClient.WithRetry(bkof, regexps)
 	}
-	responseBody, err := client.Get(ctx, id, clients.NewRequestOptions(model.ReadHeaders, model.ReadQueryParameters))
+	responseBody, err := client.Get(ctx, id, clients.NewRequestOptions(model.ReadHeaders, model.ReadQueryParameters).WithDataPlaneAuthentication(expandDataPlaneAuthentication(ctx, model.Authentication, &response.Diagnostics)))
 	if err != nil {
 		if utils.ResponseErrorWasNotFound(err) {
 			tflog.Info(ctx, fmt.Sprintf("[INFO] Error reading %q - removing from state", id.ID()))
 			response.State.RemoveResource(ctx)
 			return
 		}
+		if utils.ResponseErrorWasThrottled(err) {
+			response.Diagnostics.AddWarning("Resource refresh is throttled", fmt.Sprintf("reading %s: the request is still throttled after retrying, the previous state is kept: %+v", id, err))
+			return
+		}
 		response.Diagnostics.AddError("Failed to retrieve resource", fmt.Errorf("reading %s: %+v", id, err).Error())
 		return
 	}
 
-	requestBody := make(map[string]interface{})
+	var requestBody interface{} = make(map[string]interface{})
 	if err := unmarshalBody(model.Body, &requestBody); err != nil {
 		response.Diagnostics.AddError("Invalid body", fmt.Sprintf(`The argument "body" is invalid: %s`, err.Error()))
 		return
@@ -498,7 +626,11 @@ func (r *DataPlaneResource) Read(ctx context.Context, request resource.ReadReque
 		IgnoreCasing:          model.IgnoreCasing.ValueBool(),
 		IgnoreMissingProperty: model.IgnoreMissingProperty.ValueBool(),
 	}
-	body := utils.UpdateObject(requestBody, responseBody, option)
+	body := requestBody
+	// a string body, e.g. a signed policy, can't be compared with the response body
+	if _, ok := requestBody.(string); !ok {
+		body = utils.UpdateObject(requestBody, responseBody, option)
+	}
 
 	data, err := json.Marshal(body)
 	if err != nil {
@@ -506,7 +638,7 @@ func (r *DataPlaneResource) Read(ctx context.Context, request resource.ReadReque
 		return
 	}
 
-	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues)
+	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues, model.OutputSchema)
 	if err != nil {
 		response.Diagnostics.AddError("Failed to build output", err.Error())
 		return
@@ -526,7 +658,10 @@ func (r *DataPlaneResource) Read(ctx context.Context, request resource.ReadReque
 		model.Body = payload
 	}
 
-	model.Name = basetypes.NewStringValue(id.Name)
+	// the configured name of the resource whose name is generated by the service is kept, unless it's imported
+	if !id.GeneratedName || model.Name.IsNull() {
+		model.Name = basetypes.NewStringValue(id.Name)
+	}
 	model.ParentID = basetypes.NewStringValue(id.ParentId)
 	model.Type = basetypes.NewStringValue(fmt.Sprintf("%s@%s", id.AzureResourceType, id.ApiVersion))
 
@@ -553,7 +688,7 @@ func (r *DataPlaneResource) Delete(ctx context.Context, request resource.DeleteR
 		client = r.ProviderData.DataPlaneClient.WithRetry(bkof, regexps)
 	}
 
-	deleteTimeout, diags := model.Timeouts.Delete(ctx, 30*time.Minute)
+	deleteTimeout, diags := model.Timeouts.Delete(ctx, r.ProviderData.Features.DefaultDeleteTimeout)
 	response.Diagnostics.Append(diags...)
 	if response.Diagnostics.HasError() {
 		return
@@ -568,13 +703,93 @@ func (r *DataPlaneResource) Delete(ctx context.Context, request resource.DeleteR
 		return
 	}
 
+	if response.Diagnostics.Append(readOnlyModeDiagnostics(r.ProviderData.Features, "Deleting", id.ID())...); response.Diagnostics.HasError() {
+		return
+	}
+
+	if id.WriteOnly || id.NoDelete {
+		tflog.Info(ctx, fmt.Sprintf("%q can't be deleted, it's only removed from the state", id.ID()))
+		return
+	}
+
 	for _, lockId := range AsStringList(model.Locks) {
 		locks.ByID(lockId)
 		defer locks.UnlockByID(lockId)
 	}
 
-	_, err = client.DeleteThenPoll(ctx, id, clients.NewRequestOptions(model.DeleteHeaders, model.DeleteQueryParameters))
+	requestOptions := clients.NewRequestOptions(model.DeleteHeaders, model.DeleteQueryParameters).WithDataPlaneAuthentication(expandDataPlaneAuthentication(ctx, model.Authentication, &response.Diagnostics))
+	if id.DeleteUrl == id.AzureResourceId && id.DeleteMethod == http.MethodDelete {
+		_, err = client.DeleteThenPoll(ctx, id, requestOptions)
+	} else {
+		var deleteBody interface{}
+		if id.DeleteBody != "" {
+			deleteBody = id.DeleteBody
+			if id.ContentType != "" {
+				requestOptions = requestOptions.WithDefaultHeader("Content-Type", id.ContentType)
+			}
+		}
+		_, err = client.Action(ctx, id.DeleteUrl, "", id.ApiVersion, id.DeleteMethod, deleteBody, requestOptions)
+	}
 	if err != nil && !utils.ResponseErrorWasNotFound(err) {
 		response.Diagnostics.AddError("Failed to delete resource", fmt.Errorf("deleting %s: %+v", id, err).Error())
 	}
 }
+
+// generatedDataPlaneResourceId returns the ID of the created resource whose name is generated by the service and returned in the `id` of the response,
+// or in the GeneratedNameHeader of the response whose value is responseHeader.
+func generatedDataPlaneResourceId(id parse.DataPlaneResourceId, responseBody interface{}, responseHeader string) (parse.DataPlaneResourceId, error) {
+	if id.GeneratedNameHeader != "" {
+		if responseHeader == "" {
+			return id, fmt.Errorf("the generated name is not found in the `%s` header of the response", id.GeneratedNameHeader)
+		}
+		return parse.NewDataPlaneResourceId(responseHeader, id.ParentId, fmt.Sprintf("%s@%s", id.AzureResourceType, id.ApiVersion))
+	}
+	bodyMap, ok := responseBody.(map[string]interface{})
+	if !ok {
+		return id, fmt.Errorf("the generated name is not found in the response")
+	}
+	name, ok := bodyMap["id"].(string)
+	if !ok || name == "" {
+		return id, fmt.Errorf("the generated name is not found in the `id` of the response")
+	}
+	return parse.NewDataPlaneResourceId(name, id.ParentId, fmt.Sprintf("%s@%s", id.AzureResourceType, id.ApiVersion))
+}
+
+type dataPlaneAuthenticationModel struct {
+	SasTokenEnvVar   types.String `tfsdk:"sas_token_env_var"`
+	AccountKeyEnvVar types.String `tfsdk:"account_key_env_var"`
+	UseMsi           types.Bool   `tfsdk:"use_msi"`
+	ClientId         types.String `tfsdk:"client_id"`
+}
+
+// expandDataPlaneAuthentication returns the data plane authentication, the SAS token and the account key are read from the environment variables,
+// so they're never stored in the state. It returns nil with a warning if the environment variable isn't set, the credentials of the provider are used then.
+func expandDataPlaneAuthentication(ctx context.Context, input types.Object, diagnostics *diag.Diagnostics) *clients.DataPlaneAuthentication {
+	if input.IsNull() || input.IsUnknown() {
+		return nil
+	}
+	var model dataPlaneAuthenticationModel
+	if diags := input.As(ctx, &model, basetypes.ObjectAsOptions{}); diags.HasError() {
+		return nil
+	}
+	if model.UseMsi.ValueBool() {
+		return &clients.DataPlaneAuthentication{
+			UseManagedIdentity:      true,
+			ManagedIdentityClientId: model.ClientId.ValueString(),
+		}
+	}
+
+	auth := &clients.DataPlaneAuthentication{}
+	envVar := model.SasTokenEnvVar.ValueString()
+	if envVar != "" {
+		auth.SasToken = os.Getenv(envVar)
+	} else {
+		envVar = model.AccountKeyEnvVar.ValueString()
+		auth.AccountKey = os.Getenv(envVar)
+	}
+	if auth.SasToken == "" && auth.AccountKey == "" {
+		diagnostics.AddWarning("Data plane authentication falls back to the provider credentials", fmt.Sprintf("The environment variable %q is not set, the requests are authenticated with the credentials of the provider instead.", envVar))
+		return nil
+	}
+	return auth
+}
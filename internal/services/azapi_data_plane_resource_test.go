@@ -89,6 +89,89 @@ func TestAccDataPlaneResource_iotAppsUser(t *testing.T) {
 	})
 }
 
+func TestAccDataPlaneResource_grafanaDashboard(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_data_plane_resource", "test")
+	r := DataPlaneResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config:            r.grafanaDashboard(data),
+			ExternalProviders: externalProvidersAzurerm(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+	})
+}
+
+func TestAccDataPlaneResource_confidentialLedgerUser(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_data_plane_resource", "test")
+	r := DataPlaneResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config:            r.confidentialLedgerUser(data),
+			ExternalProviders: externalProvidersAzurerm(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+	})
+}
+
+func TestAccDataPlaneResource_confidentialLedgerTransaction(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_data_plane_resource", "test")
+	r := DataPlaneResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config:            r.confidentialLedgerTransaction(data),
+			ExternalProviders: externalProvidersAzurerm(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+	})
+}
+
+func TestAccDataPlaneResource_contentSafetyBlocklist(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_data_plane_resource", "test")
+	r := DataPlaneResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config:            r.contentSafetyBlocklist(data),
+			ExternalProviders: externalProvidersAzurerm(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+	})
+}
+
+func TestAccDataPlaneResource_openAIAssistant(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_data_plane_resource", "test")
+	r := DataPlaneResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config:            r.openAIAssistant(data, "acceptance test"),
+			ExternalProviders: externalProvidersAzurerm(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("name").HasValue("acctest"+data.RandomString),
+			),
+		},
+		{
+			Config:            r.openAIAssistant(data, "updated acceptance test"),
+			ExternalProviders: externalProvidersAzurerm(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+	})
+}
+
 func TestAccDataPlaneResource_timeouts(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azapi_data_plane_resource", "test")
 	r := DataPlaneResource{}
@@ -410,6 +493,230 @@ resource "azapi_data_plane_resource" "test" {
 `, data.LocationPrimary, data.RandomString)
 }
 
+func (r DataPlaneResource) grafanaDashboard(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+data "azurerm_client_config" "current" {}
+
+resource "azurerm_resource_group" "example" {
+  name     = "acctest%[2]s"
+  location = "%[1]s"
+}
+
+resource "azurerm_dashboard_grafana" "example" {
+  name                  = "acctest%[2]s"
+  resource_group_name   = azurerm_resource_group.example.name
+  location              = azurerm_resource_group.example.location
+  grafana_major_version = 10
+}
+
+resource "azurerm_role_assignment" "example" {
+  scope                = azurerm_dashboard_grafana.example.id
+  role_definition_name = "Grafana Admin"
+  principal_id         = data.azurerm_client_config.current.object_id
+}
+
+resource "azapi_data_plane_resource" "test" {
+  type      = "Microsoft.Dashboard/grafana/dashboards@2023-09-01"
+  parent_id = replace(azurerm_dashboard_grafana.example.endpoint, "https://", "")
+  name      = "acctest%[2]s"
+  body = {
+    dashboard = {
+      title         = "acctest%[2]s"
+      panels        = []
+      schemaVersion = 39
+    }
+    overwrite = true
+  }
+  depends_on = [
+    azurerm_role_assignment.example
+  ]
+}
+`, data.LocationPrimary, data.RandomString)
+}
+
+func (r DataPlaneResource) confidentialLedgerUser(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+data "azurerm_client_config" "current" {}
+
+resource "azurerm_resource_group" "example" {
+  name     = "acctest%[2]s"
+  location = "%[1]s"
+}
+
+resource "azurerm_confidential_ledger" "example" {
+  name                = "acctest%[2]s"
+  resource_group_name = azurerm_resource_group.example.name
+  location            = azurerm_resource_group.example.location
+  ledger_type         = "Private"
+
+  azuread_based_service_principal {
+    principal_id     = data.azurerm_client_config.current.object_id
+    tenant_id        = data.azurerm_client_config.current.tenant_id
+    ledger_role_name = "Administrator"
+  }
+}
+
+resource "azapi_data_plane_resource" "test" {
+  type      = "Microsoft.ConfidentialLedger/ledgers/users@2022-05-13"
+  parent_id = "${azurerm_confidential_ledger.example.name}.confidential-ledger.azure.com"
+  name      = "00000000-0000-0000-0000-000000000000"
+  body = {
+    assignedRole = "Reader"
+  }
+}
+`, data.LocationPrimary, data.RandomString)
+}
+
+func (r DataPlaneResource) confidentialLedgerTransaction(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+data "azurerm_client_config" "current" {}
+
+resource "azurerm_resource_group" "example" {
+  name     = "acctest%[2]s"
+  location = "%[1]s"
+}
+
+resource "azurerm_confidential_ledger" "example" {
+  name                = "acctest%[2]s"
+  resource_group_name = azurerm_resource_group.example.name
+  location            = azurerm_resource_group.example.location
+  ledger_type         = "Private"
+
+  azuread_based_service_principal {
+    principal_id     = data.azurerm_client_config.current.object_id
+    tenant_id        = data.azurerm_client_config.current.tenant_id
+    ledger_role_name = "Administrator"
+  }
+}
+
+resource "azapi_data_plane_resource" "test" {
+  type      = "Microsoft.ConfidentialLedger/ledgers/transactions@2022-05-13"
+  parent_id = "${azurerm_confidential_ledger.example.name}.confidential-ledger.azure.com"
+  name      = "entry1"
+  body = {
+    contents = "hello"
+  }
+  create_query_parameters = {
+    collectionId = ["collection1"]
+  }
+  read_query_parameters = {
+    collectionId = ["collection1"]
+  }
+}
+`, data.LocationPrimary, data.RandomString)
+}
+
+func (r DataPlaneResource) contentSafetyBlocklist(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+data "azurerm_client_config" "current" {}
+
+resource "azurerm_resource_group" "example" {
+  name     = "acctest%[2]s"
+  location = "%[1]s"
+}
+
+resource "azurerm_cognitive_account" "example" {
+  name                  = "acctest%[2]s"
+  resource_group_name   = azurerm_resource_group.example.name
+  location              = azurerm_resource_group.example.location
+  kind                  = "ContentSafety"
+  sku_name              = "S0"
+  custom_subdomain_name = "acctest%[2]s"
+}
+
+resource "azurerm_role_assignment" "example" {
+  scope                = azurerm_cognitive_account.example.id
+  role_definition_name = "Cognitive Services User"
+  principal_id         = data.azurerm_client_config.current.object_id
+}
+
+resource "azapi_data_plane_resource" "test" {
+  type      = "Microsoft.CognitiveServices/accounts/ContentSafety/blocklists@2023-10-01"
+  parent_id = "${azurerm_cognitive_account.example.custom_subdomain_name}.cognitiveservices.azure.com"
+  name      = "acctest%[2]s"
+  body = {
+    description = "acceptance test"
+  }
+  depends_on = [
+    azurerm_role_assignment.example
+  ]
+}
+`, data.LocationPrimary, data.RandomString)
+}
+
+func (r DataPlaneResource) openAIAssistant(data acceptance.TestData, instructions string) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+data "azurerm_client_config" "current" {}
+
+resource "azurerm_resource_group" "example" {
+  name     = "acctest%[2]s"
+  location = "%[1]s"
+}
+
+resource "azurerm_cognitive_account" "example" {
+  name                  = "acctest%[2]s"
+  resource_group_name   = azurerm_resource_group.example.name
+  location              = azurerm_resource_group.example.location
+  kind                  = "OpenAI"
+  sku_name              = "S0"
+  custom_subdomain_name = "acctest%[2]s"
+}
+
+resource "azurerm_cognitive_deployment" "example" {
+  name                 = "gpt-4o"
+  cognitive_account_id = azurerm_cognitive_account.example.id
+  model {
+    format  = "OpenAI"
+    name    = "gpt-4o"
+    version = "2024-08-06"
+  }
+  sku {
+    name = "Standard"
+  }
+}
+
+resource "azurerm_role_assignment" "example" {
+  scope                = azurerm_cognitive_account.example.id
+  role_definition_name = "Cognitive Services OpenAI Contributor"
+  principal_id         = data.azurerm_client_config.current.object_id
+}
+
+resource "azapi_data_plane_resource" "test" {
+  type      = "Microsoft.CognitiveServices/accounts/OpenAI/assistants@2024-05-01-preview"
+  parent_id = "${azurerm_cognitive_account.example.custom_subdomain_name}.openai.azure.com"
+  name      = "acctest%[2]s"
+  body = {
+    name         = "acctest%[2]s"
+    model        = azurerm_cognitive_deployment.example.name
+    instructions = "%[3]s"
+  }
+  depends_on = [
+    azurerm_role_assignment.example
+  ]
+}
+`, data.LocationPrimary, data.RandomString, instructions)
+}
+
 func (r DataPlaneResource) timeouts(data acceptance.TestData) string {
 	return fmt.Sprintf(`
 provider "azurerm" {
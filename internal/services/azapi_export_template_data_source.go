@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/terraform-provider-azapi/internal/clients"
+	"github.com/Azure/terraform-provider-azapi/internal/services/dynamic"
+	"github.com/Azure/terraform-provider-azapi/internal/services/myvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// exportTemplateApiVersion is the api-version of the API which exports the ARM template of a resource group
+const exportTemplateApiVersion = "2021-04-01"
+
+// exportTemplateOptions are the options which are supported by the exportTemplate API
+var exportTemplateOptions = []string{
+	"IncludeComments",
+	"IncludeParameterDefaultValue",
+	"SkipAllParameterization",
+	"SkipResourceNameParameterization",
+}
+
+type ExportTemplateDataSourceModel struct {
+	ID              types.String   `tfsdk:"id"`
+	ResourceGroupID types.String   `tfsdk:"resource_group_id"`
+	ResourceIDs     types.List     `tfsdk:"resource_ids"`
+	Options         types.List     `tfsdk:"options"`
+	Template        types.Dynamic  `tfsdk:"template"`
+	Timeouts        timeouts.Value `tfsdk:"timeouts"`
+}
+
+type ExportTemplateDataSource struct {
+	ProviderData *clients.Client
+}
+
+var _ datasource.DataSource = &ExportTemplateDataSource{}
+var _ datasource.DataSourceWithConfigure = &ExportTemplateDataSource{}
+
+func (r *ExportTemplateDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if v, ok := request.ProviderData.(*clients.Client); ok {
+		r.ProviderData = v
+	}
+}
+
+func (r *ExportTemplateDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_export_template"
+}
+
+func (r *ExportTemplateDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		MarkdownDescription: "This data source exports the ARM template of a resource group or some resources in it, e.g. to snapshot the infrastructure for the audits.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The URL of the action which exports the template.",
+			},
+
+			"resource_group_id": schema.StringAttribute{
+				Required: true,
+				Validators: []validator.String{
+					myvalidator.StringIsResourceID(),
+				},
+				MarkdownDescription: "The ID of the resource group whose template is exported, e.g. `/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/example`.",
+			},
+
+			"resource_ids": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(myvalidator.StringIsResourceID()),
+				},
+				MarkdownDescription: "A list of the IDs of the resources which are exported, they must be in the resource group. If it's not specified, all the resources in the resource group are exported.",
+			},
+
+			"options": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.OneOf(exportTemplateOptions...)),
+				},
+				MarkdownDescription: "A list of the options of the export. Possible values are `IncludeComments`, `IncludeParameterDefaultValue`, `SkipAllParameterization` and `SkipResourceNameParameterization`.",
+			},
+
+			"template": schema.DynamicAttribute{
+				Computed:            true,
+				MarkdownDescription: "The exported ARM template. It's an HCL object, which could be converted to the template JSON by the `jsonencode` function.",
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Read: true,
+			}),
+		},
+	}
+}
+
+func (r *ExportTemplateDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var model ExportTemplateDataSourceModel
+	if response.Diagnostics.Append(request.Config.Get(ctx, &model)...); response.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := model.Timeouts.Read(ctx, r.ProviderData.Features.DefaultReadTimeout)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	resourceGroupId, err := arm.ParseResourceID(model.ResourceGroupID.ValueString())
+	if err != nil {
+		response.Diagnostics.AddError("Invalid configuration", fmt.Sprintf(`The argument "resource_group_id" is invalid: %s`, err.Error()))
+		return
+	}
+	if !strings.EqualFold(resourceGroupId.ResourceType.String(), arm.ResourceGroupResourceType.String()) {
+		response.Diagnostics.AddError("Invalid configuration", fmt.Sprintf(`The argument "resource_group_id" is invalid: %q is not a resource group`, model.ResourceGroupID.ValueString()))
+		return
+	}
+
+	resourceIds := AsStringList(model.ResourceIDs)
+	if len(resourceIds) == 0 {
+		resourceIds = []string{"*"}
+	}
+	resources := make([]interface{}, 0, len(resourceIds))
+	for _, resourceId := range resourceIds {
+		resources = append(resources, map[string]interface{}{
+			"id": resourceId,
+		})
+	}
+	requestBody := map[string]interface{}{
+		"resources": resources,
+	}
+	if options := AsStringList(model.Options); len(options) != 0 {
+		requestBody["options"] = strings.Join(options, ",")
+	}
+
+	resourceGroupUrl := strings.TrimSuffix(model.ResourceGroupID.ValueString(), "/")
+	responseBody, err := r.ProviderData.ResourceClient.Action(ctx, resourceGroupUrl, "exportTemplate", exportTemplateApiVersion, "POST", requestBody, clients.DefaultRequestOptions())
+	if err != nil {
+		response.Diagnostics.AddError(operationErrorSummary(err, "Failed to export template"), fmt.Sprintf("Failed to export the template of %q: %+v", resourceGroupUrl, err))
+		return
+	}
+
+	responseMap, _ := responseBody.(map[string]interface{})
+	response.Diagnostics.Append(exportTemplateWarnings(responseMap["error"])...)
+
+	data, err := json.Marshal(responseMap["template"])
+	if err != nil {
+		response.Diagnostics.AddError("Failed to marshal template", err.Error())
+		return
+	}
+	template, err := dynamic.FromJSONImplied(data)
+	if err != nil {
+		response.Diagnostics.AddError("Failed to build template", err.Error())
+		return
+	}
+
+	model.ID = basetypes.NewStringValue(resourceGroupUrl + "/exportTemplate")
+	model.Template = template
+
+	response.Diagnostics.Append(response.State.Set(ctx, &model)...)
+}
+
+// exportTemplateWarnings returns the warnings about the resources which couldn't be exported, the exportTemplate API still returns the template without them.
+func exportTemplateWarnings(exportError interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	errorMap, ok := exportError.(map[string]interface{})
+	if !ok {
+		return diags
+	}
+	details, _ := errorMap["details"].([]interface{})
+	if len(details) == 0 {
+		details = []interface{}{errorMap}
+	}
+	for _, detail := range details {
+		detailMap, ok := detail.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		code, _ := detailMap["code"].(string)
+		message, _ := detailMap["message"].(string)
+		target, _ := detailMap["target"].(string)
+		summary := "The template is incomplete"
+		if target != "" {
+			summary = fmt.Sprintf("The resource %q couldn't be exported", target)
+		}
+		diags.AddWarning(summary, fmt.Sprintf("%s: %s", code, message))
+	}
+	return diags
+}
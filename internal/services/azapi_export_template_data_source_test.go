@@ -0,0 +1,66 @@
+package services_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Azure/terraform-provider-azapi/internal/acceptance"
+	"github.com/Azure/terraform-provider-azapi/internal/acceptance/check"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+type ExportTemplateDataSource struct{}
+
+func TestAccExportTemplateDataSource_basic(t *testing.T) {
+	data := acceptance.BuildTestData(t, "data.azapi_export_template", "test")
+	r := ExportTemplateDataSource{}
+
+	data.DataSourceTest(t, []resource.TestStep{
+		{
+			Config: r.basic(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Key("template.resources.#").HasValue("1"),
+				check.That(data.ResourceName).Key("template.resources.0.type").HasValue("Microsoft.Automation/automationAccounts"),
+			),
+		},
+	})
+}
+
+func TestAccExportTemplateDataSource_resourceIds(t *testing.T) {
+	data := acceptance.BuildTestData(t, "data.azapi_export_template", "test")
+	r := ExportTemplateDataSource{}
+
+	data.DataSourceTest(t, []resource.TestStep{
+		{
+			Config: r.resourceIds(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Key("template.resources.#").HasValue("1"),
+				check.That(data.ResourceName).Key("template.parameters.%").HasValue("0"),
+			),
+		},
+	})
+}
+
+func (r ExportTemplateDataSource) basic(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+data "azapi_export_template" "test" {
+  resource_group_id = azapi_resource.test.parent_id
+
+  depends_on = [azapi_resource.test]
+}
+`, GenericResource{}.complete(data))
+}
+
+func (r ExportTemplateDataSource) resourceIds(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+data "azapi_export_template" "test" {
+  resource_group_id = azapi_resource.test.parent_id
+  resource_ids      = [azapi_resource.test.id]
+  options           = ["SkipAllParameterization"]
+}
+`, GenericResource{}.complete(data))
+}
@@ -1,11 +1,13 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/url"
+	"net/http"
 	"reflect"
+	"regexp"
 	"strings"
 	"time"
 
@@ -17,6 +19,8 @@ import (
 	aztypes "github.com/Azure/terraform-provider-azapi/internal/azure/types"
 	"github.com/Azure/terraform-provider-azapi/internal/clients"
 	"github.com/Azure/terraform-provider-azapi/internal/docstrings"
+	"github.com/Azure/terraform-provider-azapi/internal/features"
+	"github.com/Azure/terraform-provider-azapi/internal/guardrail"
 	"github.com/Azure/terraform-provider-azapi/internal/locks"
 	"github.com/Azure/terraform-provider-azapi/internal/retry"
 	"github.com/Azure/terraform-provider-azapi/internal/services/defaults"
@@ -31,6 +35,7 @@ import (
 	"github.com/Azure/terraform-provider-azapi/utils"
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -47,7 +52,17 @@ import (
 )
 
 type AzapiResourceModel struct {
+	ApplyDefaultNaming            types.Bool          `tfsdk:"apply_default_naming"`
+	AuthoritativePaths            types.List          `tfsdk:"authoritative_paths"`
 	Body                          types.Dynamic       `tfsdk:"body"`
+	BodyFile                      types.String        `tfsdk:"body_file"`
+	BodyFileHash                  types.String        `tfsdk:"body_file_hash"`
+	BodyFragments                 types.Dynamic       `tfsdk:"body_fragments"`
+	BodyVars                      types.Map           `tfsdk:"body_vars"`
+	Endpoint                      types.String        `tfsdk:"endpoint"`
+	Etag                          types.String        `tfsdk:"etag"`
+	ExistenceCheckMethod          types.String        `tfsdk:"existence_check_method"`
+	FullName                      types.String        `tfsdk:"full_name"`
 	ID                            types.String        `tfsdk:"id"`
 	Identity                      types.List          `tfsdk:"identity"`
 	IgnoreCasing                  types.Bool          `tfsdk:"ignore_casing"`
@@ -56,15 +71,26 @@ type AzapiResourceModel struct {
 	Locks                         types.List          `tfsdk:"locks"`
 	Name                          types.String        `tfsdk:"name"`
 	Output                        types.Dynamic       `tfsdk:"output"`
+	OutputFqdn                    types.String        `tfsdk:"output_fqdn"`
+	OutputId                      types.String        `tfsdk:"output_id"`
+	OutputName                    types.String        `tfsdk:"output_name"`
+	OutputSchema                  types.Map           `tfsdk:"output_schema"`
+	OutputWaitFor                 types.List          `tfsdk:"output_wait_for"`
+	PurgeOnDestroy                types.Bool          `tfsdk:"purge_on_destroy"`
 	ParentID                      types.String        `tfsdk:"parent_id"`
+	PollingInterval               types.String        `tfsdk:"polling_interval"`
+	PreviousBody                  types.Dynamic       `tfsdk:"previous_body"`
 	ReplaceTriggersExternalValues types.Dynamic       `tfsdk:"replace_triggers_external_values"`
 	ReplaceTriggersRefs           types.List          `tfsdk:"replace_triggers_refs"`
 	ResponseExportValues          types.Dynamic       `tfsdk:"response_export_values"`
 	Retry                         retry.RetryValue    `tfsdk:"retry"`
 	SchemaValidationEnabled       types.Bool          `tfsdk:"schema_validation_enabled"`
+	StripReadOnly                 types.Bool          `tfsdk:"strip_read_only"`
 	Tags                          types.Map           `tfsdk:"tags"`
 	Timeouts                      timeouts.Value      `tfsdk:"timeouts"`
 	Type                          types.String        `tfsdk:"type"`
+	UpdateMethod                  types.String        `tfsdk:"update_method"`
+	UseEtag                       types.Bool          `tfsdk:"use_etag"`
 	CreateHeaders                 map[string]string   `tfsdk:"create_headers"`
 	CreateQueryParameters         map[string][]string `tfsdk:"create_query_parameters"`
 	UpdateHeaders                 map[string]string   `tfsdk:"update_headers"`
@@ -75,6 +101,14 @@ type AzapiResourceModel struct {
 	ReadQueryParameters           map[string][]string `tfsdk:"read_query_parameters"`
 }
 
+// setOutputUnknown marks the output and its shortcuts as unknown, they'll be updated by the response of the next request.
+func (model *AzapiResourceModel) setOutputUnknown() {
+	model.Output = basetypes.NewDynamicUnknown()
+	model.OutputId = basetypes.NewStringUnknown()
+	model.OutputName = basetypes.NewStringUnknown()
+	model.OutputFqdn = basetypes.NewStringUnknown()
+}
+
 var _ resource.Resource = &AzapiResource{}
 var _ resource.ResourceWithConfigure = &AzapiResource{}
 var _ resource.ResourceWithModifyPlan = &AzapiResource{}
@@ -121,7 +155,20 @@ func (r *AzapiResource) Schema(ctx context.Context, _ resource.SchemaRequest, re
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
-				MarkdownDescription: "Specifies the name of the azure resource. Changing this forces a new resource to be created.",
+				MarkdownDescription: "Specifies the name of the azure resource. The `default_naming_prefix` and `default_naming_suffix` of the provider are added to it in the `full_name` unless `apply_default_naming` is `false`. Changing this forces a new resource to be created.",
+			},
+
+			"full_name": schema.StringAttribute{
+				Computed: true,
+				// the resource is replaced in the ModifyPlan if the full name is changed, e.g. the default naming prefix is changed
+				MarkdownDescription: "The name of the azure resource which is used in its `id`. It's the `name` with the `default_naming_prefix` and `default_naming_suffix` of the provider unless `apply_default_naming` is `false`, the names which are fixed by the resource type and the GUIDs are used as is. Changing this forces a new resource to be created.",
+			},
+
+			"apply_default_naming": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             defaults.BoolDefault(true),
+				MarkdownDescription: "Whether to add the `default_naming_prefix` and `default_naming_suffix` of the provider to the `name`. They're never added to the names which are fixed by the resource type, e.g. `default` of the `Microsoft.Storage/storageAccounts/blobServices`, or the names which are GUIDs, e.g. the names of the role assignments. Defaults to `true`.",
 			},
 
 			"parent_id": schema.StringAttribute{
@@ -164,9 +211,43 @@ func (r *AzapiResource) Schema(ctx context.Context, _ resource.SchemaRequest, re
 				PlanModifiers: []planmodifier.Dynamic{
 					myplanmodifier.DynamicUseStateWhen(dynamic.SemanticallyEqual),
 				},
+				Validators: []validator.Dynamic{
+					myvalidator.BodyIsObject(),
+				},
 				MarkdownDescription: docstrings.Body(),
 			},
 
+			"body_file": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					myvalidator.StringIsNotEmpty(),
+					stringvalidator.ConflictsWith(path.MatchRoot("body")),
+				},
+				MarkdownDescription: "The path to a JSON file which contains the request body. It's an alternative to the `body`, which is useful to keep large documents like policies as separate files. The changes of the file content are detected by the `body_file_hash`.",
+			},
+
+			"body_file_hash": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The SHA256 hash of the content of the `body_file`.",
+			},
+
+			"body_fragments": schema.DynamicAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.Dynamic{
+					myplanmodifier.DynamicUseStateWhen(dynamic.SemanticallyEqual),
+				},
+				MarkdownDescription: docstrings.BodyFragments(),
+			},
+
+			"body_vars": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.Map{
+					mapvalidator.AlsoRequires(path.MatchRoot("body_file")),
+				},
+				MarkdownDescription: "A map of variables which are used to render the content of the `body_file`. The `${name}` placeholders in the file are replaced by the values, and `$${name}` is used to write a literal `${name}`. The values are JSON-escaped but not quoted, so the string values must be quoted in the file, e.g. `\"location\": \"${location}\"`.",
+			},
+
 			"replace_triggers_external_values": schema.DynamicAttribute{
 				Optional: true,
 				MarkdownDescription: "Will trigger a replace of the resource when the value changes and is not `null`. This can be used by practitioners to force a replace of the resource when certain values change, e.g. changing the SKU of a virtual machine based on the value of variables or locals. " +
@@ -218,8 +299,63 @@ func (r *AzapiResource) Schema(ctx context.Context, _ resource.SchemaRequest, re
 				MarkdownDescription: docstrings.IgnoreMissingProperty(),
 			},
 
+			"authoritative_paths": schema.ListAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				MarkdownDescription: "A list of dot-separated paths in the `body`, e.g. `properties.appSettings`, whose values are replaced by the remote values when the resource is read, instead of being merged with the configuration. By default, the properties which are removed remotely are kept as configured when `ignore_missing_property` is enabled and the properties which are added remotely are ignored, so the drift of the maps like the app settings is hidden. The paths of the properties in the array items don't contain the indexes, e.g. `properties.subnets.properties.routeTable`.",
+			},
+
+			"endpoint": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					myvalidator.StringIsHttpsURL(),
+				},
+				MarkdownDescription: "The Azure Resource Manager endpoint which the requests of this resource are sent to instead of the endpoint of the provider, e.g. the regional endpoint `https://westus.management.azure.com` for the latency-sensitive or the regional preview scenarios. The tokens of the provider's `resource_manager_audience` are sent to it, and the `secondary_resource_manager_endpoint` of the provider isn't used for it.",
+			},
+
+			"etag": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The ETag of the resource, which is changed whenever the resource is changed. It's read from the `etag` in the response body or the `ETag` response header, and it's null if the resource type returns neither.",
+			},
+
+			"existence_check_method": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  defaults.StringDefault(existenceCheckMethodGet),
+				Validators: []validator.String{
+					stringvalidator.OneOf(existenceCheckMethodGet, existenceCheckMethodHead),
+				},
+				MarkdownDescription: "The HTTP method which is used to check whether the resource exists. Possible values are `GET` and `HEAD`. When it's set to `HEAD`, the existence check before the resource is created doesn't transfer the resource body, and the refresh only checks whether the resource still exists, which is cheaper for the large resources. Please note that the `HEAD` refresh keeps the previous state, so the changes made outside of Terraform to the `body`, the `tags`, the `location` and the `identity` aren't detected, and the `output` isn't refreshed. If the resource provider doesn't support the `HEAD` request, it falls back to the `GET` request. Defaults to `GET`.",
+			},
+
+			"output_wait_for": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(myvalidator.StringIsJMESPath()),
+				},
+				MarkdownDescription: "A list of paths in the response body, e.g. `properties.fqdn`, which are populated by the resource provider a while after the resource is provisioned. After the resource is created, it's read again with the exponential backoff until all the paths are non-null, so the `output` contains them. The paths are [JMESPath](https://jmespath.org/) expressions. If the paths are still null when the `create` timeout is reached, a warning is raised and the resource is created with the current values.",
+			},
+
+			"polling_interval": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					myvalidator.StringIsDurationAtLeast(time.Second),
+				},
+				MarkdownDescription: "The interval between the polling requests of the long-running operations, e.g. `1m` for the resources whose provisioning takes hours, like the HDInsight clusters. The `Retry-After` header of the responses takes precedence over it. It's a string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as \"30s\" or \"2h45m\", and it must be at least `1s`. Defaults to `10s`.",
+			},
+
+			"purge_on_destroy": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             defaults.BoolDefault(false),
+				MarkdownDescription: "Whether the soft-deleted resource is purged after the resource is deleted, so the name could be reused immediately, e.g. in the test environments. It's supported by the `Microsoft.KeyVault/vaults`, `Microsoft.CognitiveServices/accounts` and `Microsoft.ApiManagement/service` resource types, and it requires the permission to purge the soft-deleted resources. Defaults to `false`.",
+			},
+
 			"response_export_values": CommonAttributeResponseExportValues(),
 
+			"output_schema": CommonAttributeOutputSchema(),
+
 			"locks": schema.ListAttribute{
 				ElementType: types.StringType,
 				Optional:    true,
@@ -236,11 +372,47 @@ func (r *AzapiResource) Schema(ctx context.Context, _ resource.SchemaRequest, re
 				MarkdownDescription: docstrings.SchemaValidationEnabled(),
 			},
 
+			"strip_read_only": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             defaults.BoolDefault(false),
+				MarkdownDescription: "Whether to remove the properties which are read-only in the embedded schema from the `body` before it's validated and sent, e.g. the `id`, `type` and `properties.provisioningState`, so the body copied from the response of a `GET` request or an export can be applied directly. The properties which aren't in the embedded schema are kept. The stripped properties aren't compared with the remote values when the resource is read, they keep their configured values. Defaults to `false`.",
+			},
+
 			"output": schema.DynamicAttribute{
 				Computed:            true,
 				MarkdownDescription: docstrings.Output("azapi_resource"),
 			},
 
+			"output_id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: docstrings.OutputId(),
+			},
+
+			"output_name": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: docstrings.OutputName(),
+			},
+
+			"output_fqdn": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: docstrings.OutputFqdn(),
+			},
+
+			"previous_body": schema.DynamicAttribute{
+				Computed:            true,
+				MarkdownDescription: "The request body which was successfully applied before the current one. It's updated only when the request body is changed, so it can be used to roll back the resource by applying it again.",
+			},
+
 			"tags": schema.MapAttribute{
 				ElementType: types.StringType,
 				Optional:    true,
@@ -281,6 +453,23 @@ func (r *AzapiResource) Schema(ctx context.Context, _ resource.SchemaRequest, re
 				MarkdownDescription: "A mapping of query parameters to be sent with the update request.",
 			},
 
+			"update_method": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  defaults.StringDefault(http.MethodPut),
+				Validators: []validator.String{
+					stringvalidator.OneOf(http.MethodPut, http.MethodPatch),
+				},
+				MarkdownDescription: "The HTTP method which is used to update the resource. Possible values are `PUT` and `PATCH`. `PUT` replaces the resource with the `body`, `PATCH` only updates the properties in the `body`, so the properties which aren't managed by the configuration are kept, e.g. the resource types whose `PUT` resets the unspecified properties. Please note that the properties which are removed from the `body` aren't removed from the resource when it's `PATCH`. The resource is always created by `PUT`. Defaults to `PUT`.",
+			},
+
+			"use_etag": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             defaults.BoolDefault(false),
+				MarkdownDescription: "Whether to send the `etag` of the resource in the `If-Match` header of the update and delete requests, so they fail instead of overwriting the changes which are made outside of Terraform since the resource was last read. It only takes effect for the resource types which return the `etag` in the response body. Defaults to `false`.",
+			},
+
 			"delete_headers": schema.MapAttribute{
 				ElementType:         types.StringType,
 				Optional:            true,
@@ -380,7 +569,7 @@ func (r *AzapiResource) ValidateConfig(ctx context.Context, request resource.Val
 		}
 	}
 
-	if !dynamic.IsFullyKnown(config.Body) {
+	if !dynamic.IsFullyKnown(config.Body) || !dynamic.IsFullyKnown(config.BodyFragments) || config.BodyFile.IsUnknown() || !dynamic.IsFullyKnown(config.BodyVars) {
 		return
 	}
 
@@ -390,6 +579,18 @@ func (r *AzapiResource) ValidateConfig(ctx context.Context, request resource.Val
 		return
 	}
 
+	body, err := bodyWithBodyFile(body, config.BodyFile, config.BodyVars)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid body_file", fmt.Sprintf(`The argument "body_file" is invalid: %s`, err.Error()))
+		return
+	}
+
+	body, err = mergeBodyFragments(body, config.BodyFragments)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid body_fragments", fmt.Sprintf(`The argument "body_fragments" is invalid: %s`, err.Error()))
+		return
+	}
+
 	if diags := validateDuplicatedDefinitions(config, body); diags.HasError() {
 		response.Diagnostics.Append(diags...)
 		return
@@ -410,14 +611,23 @@ func (r *AzapiResource) ModifyPlan(ctx context.Context, request resource.ModifyP
 		return
 	}
 
+	response.Diagnostics.Append(validateOutputSchema(config.ResponseExportValues, config.OutputSchema)...)
+
 	defer func() {
 		response.Plan.Set(ctx, plan)
+		// PreviousBody is a computed field, it's set to unknown only if the resource will be updated
+		if state != nil && !response.Plan.Raw.Equal(request.State.Raw) {
+			response.Plan.SetAttribute(ctx, path.Root("previous_body"), basetypes.NewDynamicUnknown())
+		}
 	}()
 
 	// Output is a computed field, it defaults to unknown if there's any plan change
 	// It sets to the state if the state exists, and will set to unknown if the output needs to be updated
 	if state != nil {
 		plan.Output = state.Output
+		plan.PreviousBody = state.PreviousBody
+	} else {
+		plan.PreviousBody = types.DynamicNull()
 	}
 
 	azureResourceType, apiVersion, err := utils.GetAzureResourceTypeApiVersion(config.Type.ValueString())
@@ -431,12 +641,18 @@ func (r *AzapiResource) ModifyPlan(ctx context.Context, request resource.ModifyP
 	if config.ParentID.IsNull() && strings.EqualFold(azureResourceType, arm.ResourceGroupResourceType.String()) {
 		plan.ParentID = types.StringValue(fmt.Sprintf("/subscriptions/%s", r.ProviderData.Account.GetSubscriptionId()))
 	}
+	response.Diagnostics.Append(subscriptionMismatchWarning("parent_id", plan.ParentID, r.ProviderData)...)
 
-	if name, diags := r.nameWithDefaultNaming(config.Name); !diags.HasError() {
-		plan.Name = name
-		// replace the resource if the name is changed
-		if state != nil && !state.Name.Equal(plan.Name) {
-			response.RequiresReplace.Append(path.Root("name"))
+	if fullName, diags := r.fullNameWithDefaultNaming(config.Name, plan.ApplyDefaultNaming.ValueBool(), resourceDef); !diags.HasError() {
+		// the planned name must be the configured name, the default name is used if it's not configured
+		plan.Name = config.Name
+		if config.Name.IsNull() {
+			plan.Name = fullName
+		}
+		plan.FullName = fullName
+		// replace the resource if the full name is changed, the changes of the name are handled by its plan modifier
+		if state != nil && !state.FullName.Equal(plan.FullName) {
+			response.RequiresReplace.Append(path.Root("full_name"))
 		}
 	} else {
 		response.Diagnostics.Append(diags...)
@@ -452,12 +668,28 @@ func (r *AzapiResource) ModifyPlan(ctx context.Context, request resource.ModifyP
 		}
 	}
 
+	// the changes of the body file content are detected by its hash
+	switch {
+	case config.BodyFile.IsUnknown() || !dynamic.IsFullyKnown(config.BodyVars):
+		plan.BodyFileHash = basetypes.NewStringUnknown()
+	case config.BodyFile.IsNull():
+		plan.BodyFileHash = types.StringNull()
+	default:
+		_, hash, err := readBodyFile(config.BodyFile.ValueString(), AsMapOfString(config.BodyVars))
+		if err != nil {
+			response.Diagnostics.AddError("Invalid body_file", fmt.Sprintf(`The argument "body_file" is invalid: %s`, err.Error()))
+			return
+		}
+		plan.BodyFileHash = types.StringValue(hash)
+	}
+
 	isNewResource := state == nil
-	if !dynamic.IsFullyKnown(plan.Body) || isNewResource || !plan.Identity.Equal(state.Identity) ||
-		!plan.ResponseExportValues.Equal(state.ResponseExportValues) || !dynamic.SemanticallyEqual(plan.Body, state.Body) {
-		plan.Output = basetypes.NewDynamicUnknown()
+	if !dynamic.IsFullyKnown(plan.Body) || !dynamic.IsFullyKnown(plan.BodyFragments) || plan.BodyFileHash.IsUnknown() || isNewResource || !plan.Identity.Equal(state.Identity) ||
+		!plan.ResponseExportValues.Equal(state.ResponseExportValues) || !plan.OutputSchema.Equal(state.OutputSchema) || !plan.BodyFileHash.Equal(state.BodyFileHash) ||
+		!dynamic.SemanticallyEqual(plan.Body, state.Body) || !dynamic.SemanticallyEqual(plan.BodyFragments, state.BodyFragments) {
+		plan.setOutputUnknown()
 	}
-	if !dynamic.IsFullyKnown(plan.Body) {
+	if !dynamic.IsFullyKnown(plan.Body) || !dynamic.IsFullyKnown(plan.BodyFragments) || plan.BodyFileHash.IsUnknown() {
 		if config.Tags.IsNull() {
 			plan.Tags = basetypes.NewMapUnknown(types.StringType)
 		}
@@ -466,16 +698,44 @@ func (r *AzapiResource) ModifyPlan(ctx context.Context, request resource.ModifyP
 		}
 	}
 
-	if dynamic.IsFullyKnown(plan.Body) {
+	if dynamic.IsFullyKnown(plan.Body) && dynamic.IsFullyKnown(plan.BodyFragments) && !plan.BodyFileHash.IsUnknown() {
 		body := make(map[string]interface{})
 		if err := unmarshalBody(config.Body, &body); err != nil {
 			response.Diagnostics.AddError("Invalid body", fmt.Sprintf(`The argument "body" is invalid: %s`, err.Error()))
 			return
 		}
+		body, err = bodyWithBodyFile(body, config.BodyFile, config.BodyVars)
+		if err != nil {
+			response.Diagnostics.AddError("Invalid body_file", fmt.Sprintf(`The argument "body_file" is invalid: %s`, err.Error()))
+			return
+		}
+		body, err = mergeBodyFragments(body, config.BodyFragments)
+		if err != nil {
+			response.Diagnostics.AddError("Invalid body_fragments", fmt.Sprintf(`The argument "body_fragments" is invalid: %s`, err.Error()))
+			return
+		}
+		if plan.StripReadOnly.ValueBool() {
+			body = stripReadOnlyProperties(resourceDef, body)
+		}
+		if err := validateMergedRequestBodySize(body); err != nil {
+			response.Diagnostics.AddError("Invalid body", fmt.Sprintf(`The argument "body" is invalid: %s`, err.Error()))
+			return
+		}
+
+		response.Diagnostics.Append(cloudEndpointDiagnostics(r.ProviderData.Environment, "body", body)...)
 
 		plan.Tags = r.tagsWithDefaultTags(config.Tags, body, state, resourceDef)
+		if len(r.ProviderData.Features.RequiredTags) != 0 && canResourceHaveProperty(resourceDef, "tags") {
+			requestTags := plan.Tags
+			if r.ProviderData.Features.MergeDefaultTags && !requestTags.IsNull() && !requestTags.IsUnknown() {
+				requestTags = tags.FlattenTags(mergeDefaultTags(r.ProviderData.Features.DefaultTags, tags.ExpandTags(requestTags)))
+			}
+			if response.Diagnostics.Append(requiredTagsDiagnostics(r.ProviderData.Features.RequiredTags, requestTags)...); response.Diagnostics.HasError() {
+				return
+			}
+		}
 		if state == nil || !state.Tags.Equal(plan.Tags) {
-			plan.Output = basetypes.NewDynamicUnknown()
+			plan.setOutputUnknown()
 		}
 
 		// location field has a field level plan modifier which suppresses the diff if the location is not actually changed
@@ -488,15 +748,21 @@ func (r *AzapiResource) ModifyPlan(ctx context.Context, request resource.ModifyP
 		}
 		// locationWithDefaultLocation will return the location in config if it's not null, otherwise it will return the default location if it supports location
 		plan.Location = r.locationWithDefaultLocation(locationValue, body, state, resourceDef)
+		if !plan.ParentID.IsUnknown() && !plan.Location.IsUnknown() {
+			if err := validateLocationForScope(azureResourceType, plan.ParentID.ValueString(), plan.Location.ValueString()); err != nil {
+				response.Diagnostics.AddError("Invalid configuration", err.Error())
+				return
+			}
+		}
 		if state != nil && location.Normalize(state.Location.ValueString()) != location.Normalize(plan.Location.ValueString()) {
 			// if the location is changed, replace the resource
 			response.RequiresReplace.Append(path.Root("location"))
 		}
-		if plan.SchemaValidationEnabled.ValueBool() {
+		if plan.SchemaValidationEnabled.ValueBool() && r.ProviderData.Features.SchemaValidationEnabled {
 			if response.Diagnostics.Append(expandBody(body, *plan)...); response.Diagnostics.HasError() {
 				return
 			}
-			body["name"] = plan.Name.ValueString()
+			body["name"] = plan.FullName.ValueString()
 			err = schemaValidation(azureResourceType, apiVersion, resourceDef, body)
 			if err != nil {
 				response.Diagnostics.AddError("Invalid configuration", err.Error())
@@ -504,6 +770,26 @@ func (r *AzapiResource) ModifyPlan(ctx context.Context, request resource.ModifyP
 			}
 		}
 
+		if r.ProviderData.Guardrails != nil {
+			response.Diagnostics.Append(guardrailDiagnostics(r.ProviderData.Guardrails, guardrail.Input{
+				Type:       azureResourceType,
+				ApiVersion: apiVersion,
+				Name:       plan.FullName.ValueString(),
+				ParentId:   plan.ParentID.ValueString(),
+				Location:   location.Normalize(plan.Location.ValueString()),
+				Body:       body,
+			})...)
+			if response.Diagnostics.HasError() {
+				return
+			}
+		}
+
+		if isNewResource && !plan.FullName.IsUnknown() && !plan.ParentID.IsUnknown() {
+			if id, err := parse.NewResourceID(plan.FullName.ValueString(), plan.ParentID.ValueString(), plan.Type.ValueString()); err == nil {
+				response.Diagnostics.Append(hardCodedDependencyWarnings(id.ID(), body)...)
+			}
+		}
+
 		// Check if any paths in replace_triggers_refs have changed
 		if state != nil && plan != nil && !plan.ReplaceTriggersRefs.IsNull() {
 			refPaths := make(map[string]string)
@@ -556,7 +842,7 @@ func (r *AzapiResource) ModifyPlan(ctx context.Context, request resource.ModifyP
 			parentId = placeholder
 		}
 
-		name := plan.Name.ValueString()
+		name := plan.FullName.ValueString()
 		if name == "" {
 			name = preflight.NamePlaceholder()
 		}
@@ -567,17 +853,44 @@ func (r *AzapiResource) ModifyPlan(ctx context.Context, request resource.ModifyP
 			return
 		}
 	}
+
+	// the globally unique names are checked by the checkNameAvailability API of the resource provider, so the taken names fail at plan time
+	if r.ProviderData.Features.EnablePreflight && isNewResource && preflight.IsNameAvailabilityCheckSupported(azureResourceType) && !plan.FullName.IsUnknown() {
+		subscriptionId := r.ProviderData.Account.GetSubscriptionId()
+		if matches := subscriptionIdRegex.FindStringSubmatch(plan.ParentID.ValueString()); len(matches) == 2 {
+			subscriptionId = matches[1]
+		}
+		err = preflight.CheckNameAvailability(ctx, r.ProviderData.ResourceClient, subscriptionId, azureResourceType, plan.FullName.ValueString(), plan.Body)
+		if err != nil {
+			response.Diagnostics.AddError("Preflight Validation: Name is not available", err.Error())
+			return
+		}
+	}
+}
+
+const (
+	existenceCheckMethodGet  = "GET"
+	existenceCheckMethodHead = "HEAD"
+)
+
+// privateState is the private state of the resource, it's implemented by the framework's ProviderData.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
 }
 
+// lastAppliedBodyKey is the private state key of the last successfully applied request body.
+const lastAppliedBodyKey = "last_applied_body"
+
 func (r *AzapiResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
-	r.CreateUpdate(ctx, request.Plan, &response.State, &response.Diagnostics)
+	r.CreateUpdate(ctx, request.Plan, &response.State, response.Private, &response.Diagnostics)
 }
 
 func (r *AzapiResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
-	r.CreateUpdate(ctx, request.Plan, &response.State, &response.Diagnostics)
+	r.CreateUpdate(ctx, request.Plan, &response.State, response.Private, &response.Diagnostics)
 }
 
-func (r *AzapiResource) CreateUpdate(ctx context.Context, requestPlan tfsdk.Plan, responseState *tfsdk.State, diagnostics *diag.Diagnostics) {
+func (r *AzapiResource) CreateUpdate(ctx context.Context, requestPlan tfsdk.Plan, responseState *tfsdk.State, private privateState, diagnostics *diag.Diagnostics) {
 	var plan, state *AzapiResourceModel
 	diagnostics.Append(requestPlan.Get(ctx, &plan)...)
 	diagnostics.Append(responseState.Get(ctx, &state)...)
@@ -585,12 +898,16 @@ func (r *AzapiResource) CreateUpdate(ctx context.Context, requestPlan tfsdk.Plan
 		return
 	}
 
-	id, err := parse.NewResourceID(plan.Name.ValueString(), plan.ParentID.ValueString(), plan.Type.ValueString())
+	id, err := parse.NewResourceID(plan.FullName.ValueString(), plan.ParentID.ValueString(), plan.Type.ValueString())
 	if err != nil {
 		diagnostics.AddError("Invalid configuration", err.Error())
 		return
 	}
 
+	if diagnostics.Append(readOnlyModeDiagnostics(r.ProviderData.Features, "Creating or updating", id.ID())...); diagnostics.HasError() {
+		return
+	}
+
 	var client clients.Requester
 	client = r.ProviderData.ResourceClient
 	if !plan.Retry.IsNull() {
@@ -608,12 +925,12 @@ func (r *AzapiResource) CreateUpdate(ctx context.Context, requestPlan tfsdk.Plan
 	var timeout time.Duration
 	var diags diag.Diagnostics
 	if isNewResource {
-		timeout, diags = plan.Timeouts.Create(ctx, 30*time.Minute)
+		timeout, diags = plan.Timeouts.Create(ctx, r.ProviderData.Features.DefaultCreateTimeout)
 		if diagnostics.Append(diags...); diagnostics.HasError() {
 			return
 		}
 	} else {
-		timeout, diags = plan.Timeouts.Update(ctx, 30*time.Minute)
+		timeout, diags = plan.Timeouts.Update(ctx, r.ProviderData.Features.DefaultUpdateTimeout)
 		if diagnostics.Append(diags...); diagnostics.HasError() {
 			return
 		}
@@ -621,19 +938,39 @@ func (r *AzapiResource) CreateUpdate(ctx context.Context, requestPlan tfsdk.Plan
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	ctx, deprecationNotices := clients.WithDeprecationNotices(ctx)
+	defer appendDeprecationWarnings(diagnostics, deprecationNotices)
+	ctx, responseEtag := clients.WithResponseEtag(ctx)
+
 	if isNewResource {
 		// check if the resource already exists using the non-retry client to avoid issue where user specifies
 		// a FooResourceNotFound error as a retryable error
-		_, err = r.ProviderData.ResourceClient.Get(ctx, id.AzureResourceId, id.ApiVersion, clients.NewRequestOptions(plan.ReadHeaders, plan.ReadQueryParameters))
-		if err == nil {
-			diagnostics.AddError("Resource already exists", tf.ImportAsExistsError("azapi_resource", id.ID()).Error())
-			return
-		}
+		if plan.ExistenceCheckMethod.ValueString() == existenceCheckMethodHead {
+			exists, err := r.ProviderData.ResourceClient.CheckExistence(ctx, id.AzureResourceId, id.ApiVersion, clients.NewRequestOptions(plan.ReadHeaders, plan.ReadQueryParameters).WithEndpoint(plan.Endpoint.ValueString()))
+			// 403 is returned if group does not exist, bug tracked at: https://github.com/Azure/azure-rest-api-specs/issues/9549
+			if err != nil && utils.ResponseWasForbidden(err) && strings.EqualFold("Microsoft.Management/managementGroups", id.AzureResourceType) {
+				exists, err = false, nil
+			}
+			if err != nil {
+				diagnostics.AddError("Failed to retrieve resource", fmt.Errorf("checking for presence of existing %s: %+v", id, err).Error())
+				return
+			}
+			if exists {
+				diagnostics.AddError("Resource already exists", tf.ImportAsExistsError("azapi_resource", id.ID()).Error())
+				return
+			}
+		} else {
+			_, err = r.ProviderData.ResourceClient.Get(ctx, id.AzureResourceId, id.ApiVersion, clients.NewRequestOptions(plan.ReadHeaders, plan.ReadQueryParameters).WithEndpoint(plan.Endpoint.ValueString()))
+			if err == nil {
+				diagnostics.AddError("Resource already exists", tf.ImportAsExistsError("azapi_resource", id.ID()).Error())
+				return
+			}
 
-		// 403 is returned if group does not exist, bug tracked at: https://github.com/Azure/azure-rest-api-specs/issues/9549
-		if !utils.ResponseErrorWasNotFound(err) && !(utils.ResponseWasForbidden(err) && strings.EqualFold("Microsoft.Management/managementGroups", id.AzureResourceType)) {
-			diagnostics.AddError("Failed to retrieve resource", fmt.Errorf("checking for presence of existing %s: %+v", id, err).Error())
-			return
+			// 403 is returned if group does not exist, bug tracked at: https://github.com/Azure/azure-rest-api-specs/issues/9549
+			if !utils.ResponseErrorWasNotFound(err) && !(utils.ResponseWasForbidden(err) && strings.EqualFold("Microsoft.Management/managementGroups", id.AzureResourceType)) {
+				diagnostics.AddError("Failed to retrieve resource", fmt.Errorf("checking for presence of existing %s: %+v", id, err).Error())
+				return
+			}
 		}
 	}
 
@@ -643,9 +980,29 @@ func (r *AzapiResource) CreateUpdate(ctx context.Context, requestPlan tfsdk.Plan
 		diagnostics.AddError("Invalid body", fmt.Sprintf(`The argument "body" is invalid: %s`, err.Error()))
 		return
 	}
+	body, err = bodyWithBodyFile(body, plan.BodyFile, plan.BodyVars)
+	if err != nil {
+		diagnostics.AddError("Invalid body_file", fmt.Sprintf(`The argument "body_file" is invalid: %s`, err.Error()))
+		return
+	}
+	body, err = mergeBodyFragments(body, plan.BodyFragments)
+	if err != nil {
+		diagnostics.AddError("Invalid body_fragments", fmt.Sprintf(`The argument "body_fragments" is invalid: %s`, err.Error()))
+		return
+	}
+	if plan.StripReadOnly.ValueBool() {
+		body = stripReadOnlyProperties(id.ResourceDef, body)
+	}
 	if diagnostics.Append(expandBody(body, *plan)...); diagnostics.HasError() {
 		return
 	}
+	if r.ProviderData.Features.MergeDefaultTags && body["tags"] != nil && canResourceHaveProperty(id.ResourceDef, "tags") {
+		body["tags"] = mergeDefaultTags(r.ProviderData.Features.DefaultTags, body["tags"])
+	}
+	if err := validateMergedRequestBodySize(body); err != nil {
+		diagnostics.AddError("Invalid body", fmt.Sprintf(`The argument "body" is invalid: %s`, err.Error()))
+		return
+	}
 
 	if !isNewResource {
 		// handle the case that identity block was once set, now it's removed
@@ -662,23 +1019,51 @@ func (r *AzapiResource) CreateUpdate(ctx context.Context, requestPlan tfsdk.Plan
 		defer locks.UnlockByID(lockId)
 	}
 
-	options := clients.NewRequestOptions(plan.CreateHeaders, plan.CreateQueryParameters)
+	options := clients.NewRequestOptions(plan.CreateHeaders, plan.CreateQueryParameters).WithEndpoint(plan.Endpoint.ValueString())
 	if !isNewResource {
-		options = clients.NewRequestOptions(plan.UpdateHeaders, plan.UpdateQueryParameters)
+		options = clients.NewRequestOptions(plan.UpdateHeaders, plan.UpdateQueryParameters).WithEndpoint(plan.Endpoint.ValueString())
+	}
+	options = options.WithPollingInterval(pollingInterval(plan.PollingInterval))
+	etag := ""
+	if !isNewResource && plan.UseEtag.ValueBool() {
+		etag = state.Etag.ValueString()
+	}
+	if etag != "" {
+		// the update fails with 412 if the resource has been changed since it was last read
+		options = options.WithDefaultHeader("If-Match", etag)
+	}
+	if isNewResource {
+		if diagnostics.Append(handleSoftDeletedResource(ctx, client, id, body, r.ProviderData.Features.SoftDeletedResourcesOnCreate, options)...); diagnostics.HasError() {
+			return
+		}
+	}
+	if !isNewResource && plan.UpdateMethod.ValueString() == http.MethodPatch {
+		// the PATCH is an action on the resource itself, which polls the long-running operation if it's accepted
+		_, err = client.Action(ctx, id.AzureResourceId, "", id.ApiVersion, http.MethodPatch, body, options)
+	} else {
+		_, err = client.CreateOrUpdate(ctx, id.AzureResourceId, id.ApiVersion, body, options)
 	}
-	_, err = client.CreateOrUpdate(ctx, id.AzureResourceId, id.ApiVersion, body, options)
 	if err != nil {
 		if isNewResource {
-			if responseBody, err := client.Get(ctx, id.AzureResourceId, id.ApiVersion, clients.NewRequestOptions(plan.ReadHeaders, plan.ReadQueryParameters)); err == nil {
+			// the context is already done if the operation is interrupted, the resource is still read so it's tracked in the state
+			getCtx := ctx
+			if ctx.Err() != nil {
+				var cancel context.CancelFunc
+				getCtx, cancel = context.WithTimeout(context.WithoutCancel(ctx), time.Minute)
+				defer cancel()
+			}
+			if responseBody, err := client.Get(getCtx, id.AzureResourceId, id.ApiVersion, clients.NewRequestOptions(plan.ReadHeaders, plan.ReadQueryParameters).WithEndpoint(plan.Endpoint.ValueString())); err == nil {
 				// generate the computed fields
 				plan.ID = types.StringValue(id.ID())
 
-				output, err := buildOutputFromBody(responseBody, plan.ResponseExportValues)
+				output, err := buildOutputFromBody(responseBody, plan.ResponseExportValues, plan.OutputSchema)
 				if err != nil {
 					diagnostics.AddError("Failed to build output", err.Error())
 					return
 				}
 				plan.Output = output
+				plan.OutputId, plan.OutputName, plan.OutputFqdn = flattenOutputShortcuts(responseBody)
+				plan.Etag = flattenEtag(responseBody, responseEtag.Value())
 
 				if bodyMap, ok := responseBody.(map[string]interface{}); ok {
 					if !plan.Identity.IsNull() {
@@ -696,11 +1081,53 @@ func (r *AzapiResource) CreateUpdate(ctx context.Context, requestPlan tfsdk.Plan
 				diagnostics.Append(responseState.Set(ctx, plan)...)
 			}
 		}
-		diagnostics.AddError("Failed to create/update resource", fmt.Errorf("creating/updating %s: %+v", id, err).Error())
+		diagnostics.Append(recordInterruptedOperation(ctx, private, err)...)
+		if utils.ResponseErrorWasStatusCode(err, http.StatusPreconditionFailed) {
+			diagnostics.AddError("Resource has been changed outside of Terraform", etagConflictDetail(id.ID(), etag, err))
+			return
+		}
+		diagnostics.AddError(operationErrorSummary(err, "Failed to create/update resource"), fmt.Errorf("creating/updating %s: %+v", id, err).Error())
 		return
 	}
+	if isNewResource {
+		plannedResources.markCreated(id.ID())
+	}
+	if diagnostics.Append(private.SetKey(ctx, interruptedOperationKey, nil)...); diagnostics.HasError() {
+		return
+	}
+	if fallback, ok := r.ProviderData.ResourceClient.ApiVersionFallback(id.AzureResourceId, id.ApiVersion); ok {
+		diagnostics.AddAttributeWarning(path.Root("type"), "Unsupported api-version", fmt.Sprintf("The api-version %s of %s is not supported by Azure, the requests are sent with the nearest supported api-version %s instead. Please update the api-version in the `type`.", id.ApiVersion, id.AzureResourceId, fallback))
+	}
 
-	responseBody, err := client.Get(ctx, id.AzureResourceId, id.ApiVersion, clients.NewRequestOptions(plan.ReadHeaders, plan.ReadQueryParameters))
+	appliedBody, err := json.Marshal(body)
+	if err != nil {
+		diagnostics.AddError("Invalid body", fmt.Sprintf(`The argument "body" is invalid: %s`, err.Error()))
+		return
+	}
+	if plan.PreviousBody.IsUnknown() {
+		plan.PreviousBody = types.DynamicNull()
+		if state != nil {
+			plan.PreviousBody = state.PreviousBody
+		}
+		lastAppliedBody, diags := private.GetKey(ctx, lastAppliedBodyKey)
+		diagnostics.Append(diags...)
+		if len(lastAppliedBody) != 0 && !bytes.Equal(lastAppliedBody, appliedBody) {
+			previousBody, err := dynamic.FromJSONImplied(lastAppliedBody)
+			if err != nil {
+				diagnostics.AddError("Failed to parse the previous body", err.Error())
+				return
+			}
+			plan.PreviousBody = previousBody
+		}
+	}
+	if diagnostics.Append(private.SetKey(ctx, lastAppliedBodyKey, appliedBody)...); diagnostics.HasError() {
+		return
+	}
+	if diagnostics.Append(writeResourceMetadata(ctx, private, newResourceMetadata(r.ProviderData, id.AzureResourceType, id.ApiVersion))...); diagnostics.HasError() {
+		return
+	}
+
+	responseBody, err := client.Get(ctx, id.AzureResourceId, id.ApiVersion, clients.NewRequestOptions(plan.ReadHeaders, plan.ReadQueryParameters).WithEndpoint(plan.Endpoint.ValueString()))
 	if err != nil {
 		if utils.ResponseErrorWasNotFound(err) {
 			tflog.Info(ctx, fmt.Sprintf("Error reading %q - removing from state", id.ID()))
@@ -711,15 +1138,43 @@ func (r *AzapiResource) CreateUpdate(ctx context.Context, requestPlan tfsdk.Plan
 		return
 	}
 
+	if paths := AsStringList(plan.OutputWaitFor); state == nil && len(paths) != 0 {
+		var missing []string
+		responseBody, missing = waitForOutputPaths(ctx, client, id, paths, responseBody, clients.NewRequestOptions(plan.ReadHeaders, plan.ReadQueryParameters).WithEndpoint(plan.Endpoint.ValueString()))
+		if len(missing) != 0 {
+			diagnostics.AddWarning("Output is incomplete", fmt.Sprintf("The paths %s in the response body of %s are still null when the waiting is stopped, the `output` doesn't contain them.", strings.Join(missing, ", "), id))
+		}
+	}
+
+	// warn about the properties which are silently dropped or rewritten by the resource provider, e.g. the misspelled property names
+	// the location and identity are excluded, because they're normalized by the resource provider
+	sentBody := utils.NormalizeObject(body)
+	if sentMap, ok := sentBody.(map[string]interface{}); ok {
+		delete(sentMap, "location")
+		delete(sentMap, "identity")
+	}
+	if dropped, rewritten := ignoredProperties(sentBody, responseBody, "", plan.IgnoreCasing.ValueBool()); len(dropped) != 0 || len(rewritten) != 0 {
+		details := make([]string, 0)
+		if len(dropped) != 0 {
+			details = append(details, fmt.Sprintf("dropped: %s", strings.Join(dropped, ", ")))
+		}
+		if len(rewritten) != 0 {
+			details = append(details, fmt.Sprintf("rewritten: %s", strings.Join(rewritten, ", ")))
+		}
+		diagnostics.AddWarning("Properties are ignored by the resource provider", fmt.Sprintf("The following properties in the request body of %s are not returned as they're sent, please check whether they're misspelled or read-only. The write-only properties like the credentials are expected to be dropped.\n%s", id, strings.Join(details, "\n")))
+	}
+
 	// generate the computed fields
 	plan.ID = types.StringValue(id.ID())
 
-	output, err := buildOutputFromBody(responseBody, plan.ResponseExportValues)
+	output, err := buildOutputFromBody(responseBody, plan.ResponseExportValues, plan.OutputSchema)
 	if err != nil {
 		diagnostics.AddError("Failed to build output", err.Error())
 		return
 	}
 	plan.Output = output
+	plan.OutputId, plan.OutputName, plan.OutputFqdn = flattenOutputShortcuts(responseBody)
+	plan.Etag = flattenEtag(responseBody, responseEtag.Value())
 
 	if bodyMap, ok := responseBody.(map[string]interface{}); ok {
 		if !plan.Identity.IsNull() {
@@ -736,6 +1191,13 @@ func (r *AzapiResource) CreateUpdate(ctx context.Context, requestPlan tfsdk.Plan
 	}
 
 	diagnostics.Append(responseState.Set(ctx, plan)...)
+
+	// the resource is kept in the state, so a newly created resource will be marked as tainted
+	if r.ProviderData.Features.FailOnFailedProvisioningState {
+		if err := provisioningStateFailure(responseBody); err != nil {
+			diagnostics.AddError("Failed to create/update resource", fmt.Errorf("creating/updating %s: %+v", id, err).Error())
+		}
+	}
 }
 
 func (r *AzapiResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
@@ -744,7 +1206,7 @@ func (r *AzapiResource) Read(ctx context.Context, request resource.ReadRequest,
 		return
 	}
 
-	readTimeout, diags := model.Timeouts.Read(ctx, 5*time.Minute)
+	readTimeout, diags := model.Timeouts.Read(ctx, r.ProviderData.Features.DefaultReadTimeout)
 	response.Diagnostics.Append(diags...)
 	if response.Diagnostics.HasError() {
 		return
@@ -753,11 +1215,16 @@ func (r *AzapiResource) Read(ctx context.Context, request resource.ReadRequest,
 	ctx, cancel := context.WithTimeout(ctx, readTimeout)
 	defer cancel()
 
+	ctx, deprecationNotices := clients.WithDeprecationNotices(ctx)
+	defer appendDeprecationWarnings(&response.Diagnostics, deprecationNotices)
+	ctx, responseEtag := clients.WithResponseEtag(ctx)
+
 	id, err := parse.ResourceIDWithResourceType(model.ID.ValueString(), model.Type.ValueString())
 	if err != nil {
 		response.Diagnostics.AddError("Error parsing ID", err.Error())
 		return
 	}
+	response.Diagnostics.Append(interruptedOperationWarning(ctx, request.Private, id.ID())...)
 
 	var client clients.Requester
 	client = r.ProviderData.ResourceClient
@@ -772,19 +1239,45 @@ func (r *AzapiResource) Read(ctx context.Context, request resource.ReadRequest,
 		client = r.ProviderData.ResourceClient.WithRetry(bkof, regexps)
 	}
 
-	responseBody, err := client.Get(ctx, id.AzureResourceId, id.ApiVersion, clients.NewRequestOptions(model.ReadHeaders, model.ReadQueryParameters))
+	// the HEAD refresh only checks whether the resource still exists, the previous state is kept, so the drift of the body isn't detected
+	if model.ExistenceCheckMethod.ValueString() == existenceCheckMethodHead {
+		exists, err := r.ProviderData.ResourceClient.CheckExistence(ctx, id.AzureResourceId, id.ApiVersion, clients.NewRequestOptions(model.ReadHeaders, model.ReadQueryParameters).WithEndpoint(model.Endpoint.ValueString()))
+		if err != nil {
+			if utils.ResponseErrorWasThrottled(err) {
+				response.Diagnostics.AddWarning("Resource refresh is throttled", fmt.Sprintf("reading %s: the request is still throttled after retrying, the previous state is kept: %+v", id, err))
+				return
+			}
+			response.Diagnostics.AddError("Failed to retrieve resource", fmt.Errorf("reading %s: %+v", id, err).Error())
+			return
+		}
+		if !exists {
+			tflog.Info(ctx, fmt.Sprintf("Error reading %q - removing from state", id.ID()))
+			response.State.RemoveResource(ctx)
+		}
+		return
+	}
+
+	responseBody, err := client.Get(ctx, id.AzureResourceId, id.ApiVersion, clients.NewRequestOptions(model.ReadHeaders, model.ReadQueryParameters).WithEndpoint(model.Endpoint.ValueString()))
 	if err != nil {
 		if utils.ResponseErrorWasNotFound(err) {
 			tflog.Info(ctx, fmt.Sprintf("Error reading %q - removing from state", id.ID()))
 			response.State.RemoveResource(ctx)
 			return
 		}
+		if utils.ResponseErrorWasThrottled(err) {
+			response.Diagnostics.AddWarning("Resource refresh is throttled", fmt.Sprintf("reading %s: the request is still throttled after retrying, the previous state is kept: %+v", id, err))
+			return
+		}
 		response.Diagnostics.AddError("Failed to retrieve resource", fmt.Errorf("reading %s: %+v", id, err).Error())
 		return
 	}
 
 	state := model
-	state.Name = types.StringValue(id.Name)
+	// the configured name is kept, the full name may have the default naming prefix and suffix
+	if state.Name.IsNull() || state.Name.IsUnknown() {
+		state.Name = types.StringValue(id.Name)
+	}
+	state.FullName = types.StringValue(id.Name)
 	state.ParentID = types.StringValue(id.ParentId)
 	state.Type = types.StringValue(fmt.Sprintf("%s@%s", id.AzureResourceType, id.ApiVersion))
 
@@ -793,12 +1286,35 @@ func (r *AzapiResource) Read(ctx context.Context, request resource.ReadRequest,
 		response.Diagnostics.AddError("Invalid body", fmt.Sprintf(`The argument "body" is invalid: %s`, err.Error()))
 		return
 	}
+	if fileBody, err := bodyWithBodyFile(requestBody, model.BodyFile, model.BodyVars); err == nil {
+		requestBody = fileBody
+	} else {
+		tflog.Warn(ctx, fmt.Sprintf("Failed to read the body file: %s", err.Error()))
+	}
+	fragmentsBody, err := mergeBodyFragments(make(map[string]interface{}), model.BodyFragments)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid body_fragments", fmt.Sprintf(`The argument "body_fragments" is invalid: %s`, err.Error()))
+		return
+	}
+	// the properties in the body which are overridden by the body fragments keep their configured values
+	bodyOverrides := overriddenProperties(requestBody, fragmentsBody)
+	requestBody, _ = utils.MergeObject(requestBody, fragmentsBody).(map[string]interface{})
+	// the read-only properties which are stripped from the request aren't compared with the remote values, they keep their configured values
+	var readOnlyProperties interface{}
+	if model.StripReadOnly.ValueBool() {
+		strippedBody := stripReadOnlyProperties(id.ResourceDef, requestBody)
+		readOnlyProperties = strippedProperties(requestBody, strippedBody)
+		requestBody = strippedBody
+	}
 
 	if bodyMap, ok := responseBody.(map[string]interface{}); ok {
 		if v, ok := bodyMap["location"]; ok && v != nil && location.Normalize(v.(string)) != location.Normalize(model.Location.ValueString()) {
 			state.Location = types.StringValue(v.(string))
 		}
 		if output := tags.FlattenTags(bodyMap["tags"]); len(output.Elements()) != 0 || len(state.Tags.Elements()) != 0 {
+			if r.ProviderData.Features.MergeDefaultTags && !model.Tags.IsNull() && !model.Tags.IsUnknown() {
+				output = withoutMergedDefaultTags(output, model.Tags, r.ProviderData.Features.DefaultTags)
+			}
 			state.Tags = output
 		}
 		if requestBody["identity"] == nil {
@@ -831,6 +1347,9 @@ func (r *AzapiResource) Read(ctx context.Context, request resource.ReadRequest,
 				if len(stateIdentity.IdentityIDs.Elements()) == 0 && len(identityFromResponse.IdentityIDs.Elements()) == 0 {
 					// to suppress the diff of identity_ids = [] and identity_ids = null
 					identityFromResponse.IdentityIDs = stateIdentity.IdentityIDs
+				} else if identity.SameIdentityIds(stateIdentity.IdentityIDs, identityFromResponse.IdentityIDs) {
+					// to suppress the diff of the order and the casing of the identity_ids
+					identityFromResponse.IdentityIDs = stateIdentity.IdentityIDs
 				}
 				state.Identity = identity.ToList(*identityFromResponse)
 			}
@@ -840,8 +1359,15 @@ func (r *AzapiResource) Read(ctx context.Context, request resource.ReadRequest,
 	option := utils.UpdateJsonOption{
 		IgnoreCasing:          model.IgnoreCasing.ValueBool(),
 		IgnoreMissingProperty: model.IgnoreMissingProperty.ValueBool(),
+		ReplacePaths:          make(map[string]bool),
+	}
+	for _, replacePath := range AsStringList(model.AuthoritativePaths) {
+		option.ReplacePaths[replacePath] = true
 	}
 	body := utils.UpdateObject(requestBody, responseBody, option)
+	if readOnlyProperties != nil {
+		body = restoreStrippedProperties(body, readOnlyProperties)
+	}
 
 	data, err := json.Marshal(body)
 	if err != nil {
@@ -849,18 +1375,27 @@ func (r *AzapiResource) Read(ctx context.Context, request resource.ReadRequest,
 		return
 	}
 
-	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues)
+	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues, model.OutputSchema)
 	if err != nil {
 		response.Diagnostics.AddError("Failed to build output", err.Error())
 		return
 	}
 	state.Output = output
+	state.OutputId, state.OutputName, state.OutputFqdn = flattenOutputShortcuts(responseBody)
+	state.Etag = flattenEtag(responseBody, responseEtag.Value())
 
 	if !model.Body.IsNull() {
-		payload, err := dynamic.FromJSON(data, model.Body.UnderlyingValue().Type(ctx))
+		bodyData := data
+		if !model.BodyFragments.IsNull() {
+			if bodyData, err = json.Marshal(utils.MergeObject(body, bodyOverrides)); err != nil {
+				response.Diagnostics.AddError("Invalid body", err.Error())
+				return
+			}
+		}
+		payload, err := dynamic.FromJSON(bodyData, model.Body.UnderlyingValue().Type(ctx))
 		if err != nil {
 			tflog.Warn(ctx, fmt.Sprintf("Failed to parse payload: %s", err.Error()))
-			payload, err = dynamic.FromJSONImplied(data)
+			payload, err = dynamic.FromJSONImplied(bodyData)
 			if err != nil {
 				response.Diagnostics.AddError("Invalid payload", err.Error())
 				return
@@ -869,6 +1404,15 @@ func (r *AzapiResource) Read(ctx context.Context, request resource.ReadRequest,
 		state.Body = payload
 	}
 
+	if !model.BodyFragments.IsNull() {
+		fragments, err := flattenBodyFragments(data, model.BodyFragments)
+		if err != nil {
+			response.Diagnostics.AddError("Invalid payload", err.Error())
+			return
+		}
+		state.BodyFragments = fragments
+	}
+
 	response.Diagnostics.Append(response.State.Set(ctx, state)...)
 }
 
@@ -891,7 +1435,7 @@ func (r *AzapiResource) Delete(ctx context.Context, request resource.DeleteReque
 		client = r.ProviderData.ResourceClient.WithRetry(bkof, regexps)
 	}
 
-	deleteTimeout, diags := model.Timeouts.Delete(ctx, 30*time.Minute)
+	deleteTimeout, diags := model.Timeouts.Delete(ctx, r.ProviderData.Features.DefaultDeleteTimeout)
 	response.Diagnostics.Append(diags...)
 	if response.Diagnostics.HasError() {
 		return
@@ -906,34 +1450,55 @@ func (r *AzapiResource) Delete(ctx context.Context, request resource.DeleteReque
 		return
 	}
 
+	if response.Diagnostics.Append(readOnlyModeDiagnostics(r.ProviderData.Features, "Deleting", id.ID())...); response.Diagnostics.HasError() {
+		return
+	}
+
 	for _, lockId := range AsStringList(model.Locks) {
 		locks.ByID(lockId)
 		defer locks.UnlockByID(lockId)
 	}
 
-	_, err = client.Delete(ctx, id.AzureResourceId, id.ApiVersion, clients.NewRequestOptions(model.DeleteHeaders, model.DeleteQueryParameters))
+	// the child resources which are managed in the same configuration are already deleted, because they depend on the parent resource
+	if behavior := r.ProviderData.Features.ChildResourcesOnDelete; behavior == features.ChildResourcesOnDeleteWarn || behavior == features.ChildResourcesOnDeleteFail {
+		if childIds := childResourceIds(ctx, r.ProviderData.ResourceClient, id); len(childIds) != 0 {
+			summary := "Resource contains child resources"
+			detail := fmt.Sprintf("%s contains %d child resources which are not managed by this configuration and will be deleted with it:\n%s", id, len(childIds), strings.Join(childIds, "\n"))
+			if behavior == features.ChildResourcesOnDeleteFail {
+				response.Diagnostics.AddError(summary, detail+"\n\nPlease delete or move the child resources first, or set the `child_resources_on_delete` to `warn` or `ignore` in the provider block.")
+				return
+			}
+			response.Diagnostics.AddWarning(summary, detail)
+		}
+	}
+
+	options := clients.NewRequestOptions(model.DeleteHeaders, model.DeleteQueryParameters).WithEndpoint(model.Endpoint.ValueString()).WithPollingInterval(pollingInterval(model.PollingInterval))
+	if model.UseEtag.ValueBool() && model.Etag.ValueString() != "" {
+		options = options.WithDefaultHeader("If-Match", model.Etag.ValueString())
+	}
+	_, err = client.Delete(ctx, id.AzureResourceId, id.ApiVersion, options)
+	if utils.ResponseErrorWasStatusCode(err, http.StatusPreconditionFailed) {
+		response.Diagnostics.AddError("Resource has been changed outside of Terraform", etagConflictDetail(id.ID(), model.Etag.ValueString(), err))
+		return
+	}
 	if err != nil && !utils.ResponseErrorWasNotFound(err) {
-		response.Diagnostics.AddError("Failed to delete resource", fmt.Errorf("deleting %s: %+v", id, err).Error())
+		response.Diagnostics.AddError(operationErrorSummary(err, "Failed to delete resource"), fmt.Errorf("deleting %s: %+v", id, err).Error())
+		return
+	}
+
+	if model.PurgeOnDestroy.ValueBool() {
+		response.Diagnostics.Append(purgeOnDestroy(ctx, client, id, model.Location.ValueString())...)
 	}
 }
 
 func (r *AzapiResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
 	tflog.Debug(ctx, fmt.Sprintf("Importing Resource - parsing %q", request.ID))
 
-	input := request.ID
-	idUrl, err := url.Parse(input)
+	input, err := importIdWithApiVersion(request.ID)
 	if err != nil {
-		response.Diagnostics.AddError("Invalid Resource ID", fmt.Errorf("parsing Resource ID %q: %+v", input, err).Error())
+		response.Diagnostics.AddError("Invalid Resource ID", fmt.Errorf("parsing Resource ID %q: %+v", request.ID, err).Error())
 		return
 	}
-	apiVersion := idUrl.Query().Get("api-version")
-	if apiVersion == "" {
-		resourceType := utils.GetResourceType(input)
-		apiVersions := azure.GetApiVersions(resourceType)
-		if len(apiVersions) != 0 {
-			input = fmt.Sprintf("%s?api-version=%s", input, apiVersions[len(apiVersions)-1])
-		}
-	}
 
 	id, err := parse.ResourceIDWithApiVersion(input)
 	if err != nil {
@@ -945,17 +1510,38 @@ func (r *AzapiResource) ImportState(ctx context.Context, request resource.Import
 
 	state := AzapiResourceModel{
 		ID:                            types.StringValue(id.ID()),
-		Name:                          types.StringValue(id.Name),
+		Name:                          types.StringValue(r.nameWithoutDefaultNaming(id.Name, id.ResourceDef)),
+		FullName:                      types.StringValue(id.Name),
 		ParentID:                      types.StringValue(id.ParentId),
 		Type:                          types.StringValue(fmt.Sprintf("%s@%s", id.AzureResourceType, id.ApiVersion)),
+		UpdateMethod:                  types.StringValue(http.MethodPut),
+		UseEtag:                       types.BoolValue(false),
 		Locks:                         types.ListNull(types.StringType),
 		Identity:                      types.ListNull(identity.Model{}.ModelType()),
 		Body:                          types.DynamicNull(),
+		BodyFile:                      types.StringNull(),
+		BodyFileHash:                  types.StringNull(),
+		BodyFragments:                 types.DynamicNull(),
+		BodyVars:                      types.MapNull(types.StringType),
+		Endpoint:                      types.StringNull(),
+		Etag:                          types.StringNull(),
+		ExistenceCheckMethod:          types.StringValue(existenceCheckMethodGet),
 		SchemaValidationEnabled:       types.BoolValue(true),
+		StripReadOnly:                 types.BoolValue(false),
+		ApplyDefaultNaming:            types.BoolValue(true),
 		IgnoreCasing:                  types.BoolValue(false),
 		IgnoreMissingProperty:         types.BoolValue(true),
+		AuthoritativePaths:            types.ListNull(types.StringType),
 		ResponseExportValues:          types.DynamicNull(),
 		Output:                        types.DynamicNull(),
+		OutputFqdn:                    types.StringNull(),
+		OutputId:                      types.StringNull(),
+		OutputName:                    types.StringNull(),
+		OutputSchema:                  types.MapNull(types.StringType),
+		OutputWaitFor:                 types.ListNull(types.StringType),
+		PollingInterval:               types.StringNull(),
+		PurgeOnDestroy:                types.BoolValue(false),
+		PreviousBody:                  types.DynamicNull(),
 		ReplaceTriggersExternalValues: types.DynamicNull(),
 		ReplaceTriggersRefs:           types.ListNull(types.StringType),
 		Tags:                          types.MapNull(types.StringType),
@@ -969,7 +1555,7 @@ func (r *AzapiResource) ImportState(ctx context.Context, request resource.Import
 		},
 	}
 
-	responseBody, err := client.Get(ctx, id.AzureResourceId, id.ApiVersion, clients.NewRequestOptions(state.ReadHeaders, state.ReadQueryParameters))
+	responseBody, err := client.Get(ctx, id.AzureResourceId, id.ApiVersion, clients.NewRequestOptions(state.ReadHeaders, state.ReadQueryParameters).WithEndpoint(state.Endpoint.ValueString()))
 	if err != nil {
 		if utils.ResponseErrorWasNotFound(err) {
 			tflog.Info(ctx, fmt.Sprintf("[INFO] Error reading %q - removing from state", id.ID()))
@@ -1029,9 +1615,14 @@ func (r *AzapiResource) ImportState(ctx context.Context, request resource.Import
 	response.Diagnostics.Append(response.State.Set(ctx, state)...)
 }
 
-func (r *AzapiResource) nameWithDefaultNaming(config types.String) (types.String, diag.Diagnostics) {
+// fullNameWithDefaultNaming returns the name with the default naming prefix and suffix. They aren't added if the default naming isn't applied to the resource,
+// or the name is fixed by the resource type or is a GUID, because the resource providers reject the other names.
+func (r *AzapiResource) fullNameWithDefaultNaming(config types.String, applyDefaultNaming bool, resourceDef *aztypes.ResourceType) (types.String, diag.Diagnostics) {
 	if !config.IsNull() {
-		return config, diag.Diagnostics{}
+		if config.IsUnknown() || !applyDefaultNaming || azure.IsSingletonResourceType(resourceDef) || guidRegex.MatchString(config.ValueString()) {
+			return config, diag.Diagnostics{}
+		}
+		return types.StringValue(r.ProviderData.Features.DefaultNamingPrefix + config.ValueString() + r.ProviderData.Features.DefaultNamingSuffix), diag.Diagnostics{}
 	}
 	if r.ProviderData.Features.DefaultNaming != "" {
 		return types.StringValue(r.ProviderData.Features.DefaultNaming), diag.Diagnostics{}
@@ -1041,9 +1632,63 @@ func (r *AzapiResource) nameWithDefaultNaming(config types.String) (types.String
 	}
 }
 
+// nameWithoutDefaultNaming returns the name of the imported resource without the default naming prefix and suffix,
+// so the configuration which specifies the name without them doesn't replace the imported resource.
+func (r *AzapiResource) nameWithoutDefaultNaming(fullName string, resourceDef *aztypes.ResourceType) string {
+	prefix, suffix := r.ProviderData.Features.DefaultNamingPrefix, r.ProviderData.Features.DefaultNamingSuffix
+	if (prefix == "" && suffix == "") || len(fullName) <= len(prefix)+len(suffix) || azure.IsSingletonResourceType(resourceDef) || guidRegex.MatchString(fullName) {
+		return fullName
+	}
+	if !strings.HasPrefix(fullName, prefix) || !strings.HasSuffix(fullName, suffix) {
+		return fullName
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(fullName, prefix), suffix)
+}
+
+// mergeDefaultTags returns the default tags merged with the tags of the resource key by key, the tags of the resource win on conflict.
+func mergeDefaultTags(defaultTags map[string]string, resourceTags interface{}) map[string]string {
+	out := make(map[string]string, len(defaultTags))
+	for k, v := range defaultTags {
+		out[k] = v
+	}
+	switch v := resourceTags.(type) {
+	case map[string]string:
+		for key, value := range v {
+			out[key] = value
+		}
+	case map[string]interface{}:
+		for key, value := range v {
+			if value, ok := value.(string); ok {
+				out[key] = value
+			}
+		}
+	}
+	return out
+}
+
+// withoutMergedDefaultTags removes the default tags which are merged into the request body from the remote tags,
+// so the tags argument only reflects the configured tags and the remote changes of them.
+func withoutMergedDefaultTags(remoteTags types.Map, configTags types.Map, defaultTags map[string]string) types.Map {
+	configured := tags.ExpandTags(configTags)
+	out := make(map[string]string)
+	for key, value := range tags.ExpandTags(remoteTags) {
+		if _, ok := configured[key]; !ok {
+			if defaultValue, ok := defaultTags[key]; ok && defaultValue == value {
+				continue
+			}
+		}
+		out[key] = value
+	}
+	return tags.FlattenTags(out)
+}
+
+var guidRegex = regexp.MustCompile(`^[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{12}$`)
+
 func (r *AzapiResource) tagsWithDefaultTags(config types.Map, body map[string]interface{}, state *AzapiResourceModel, resourceDef *aztypes.ResourceType) types.Map {
 	if config.IsNull() {
 		switch {
+		case body["tags"] != nil && r.ProviderData.Features.MergeDefaultTags && canResourceHaveProperty(resourceDef, "tags"):
+			return tags.FlattenTags(mergeDefaultTags(r.ProviderData.Features.DefaultTags, body["tags"]))
 		case body["tags"] != nil:
 			return tags.FlattenTags(body["tags"])
 		case len(r.ProviderData.Features.DefaultTags) != 0 && canResourceHaveProperty(resourceDef, "tags"):
@@ -1128,3 +1773,9 @@ func validateDuplicatedDefinitions(model *AzapiResourceModel, body map[string]in
 	}
 	return diags
 }
+
+// pollingInterval returns the interval between the polling requests of the long-running operations, it's zero if it's not specified.
+func pollingInterval(input types.String) time.Duration {
+	interval, _ := time.ParseDuration(input.ValueString())
+	return interval
+}
@@ -3,7 +3,6 @@ package services
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"github.com/Azure/terraform-provider-azapi/internal/clients"
 	"github.com/Azure/terraform-provider-azapi/internal/docstrings"
@@ -14,6 +13,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
@@ -26,7 +26,9 @@ type ResourceActionDataSourceModel struct {
 	Action               types.String        `tfsdk:"action"`
 	Method               types.String        `tfsdk:"method"`
 	Body                 types.Dynamic       `tfsdk:"body"`
+	PayloadFile          types.String        `tfsdk:"payload_file"`
 	ResponseExportValues types.Dynamic       `tfsdk:"response_export_values"`
+	OutputSchema         types.Map           `tfsdk:"output_schema"`
 	Output               types.Dynamic       `tfsdk:"output"`
 	Timeouts             timeouts.Value      `tfsdk:"timeouts"`
 	Retry                retry.RetryValue    `tfsdk:"retry"`
@@ -94,8 +96,19 @@ func (r *ResourceActionDataSource) Schema(ctx context.Context, request datasourc
 				Optional: true,
 			},
 
+			"payload_file": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					myvalidator.StringIsNotEmpty(),
+					stringvalidator.ConflictsWith(path.MatchRoot("body")),
+				},
+				MarkdownDescription: docstrings.PayloadFile(),
+			},
+
 			"response_export_values": CommonAttributeResponseExportValues(),
 
+			"output_schema": CommonAttributeOutputSchema(),
+
 			"output": schema.DynamicAttribute{
 				Computed:            true,
 				MarkdownDescription: docstrings.Output("data.azapi_resource_action"),
@@ -134,7 +147,7 @@ func (r *ResourceActionDataSource) Read(ctx context.Context, request datasource.
 
 	model.Retry = model.Retry.AddDefaultValuesIfUnknownOrNull()
 
-	readTimeout, diags := model.Timeouts.Read(ctx, 5*time.Minute)
+	readTimeout, diags := model.Timeouts.Read(ctx, r.ProviderData.Features.DefaultReadTimeout)
 	response.Diagnostics.Append(diags...)
 	if response.Diagnostics.HasError() {
 		return
@@ -142,6 +155,10 @@ func (r *ResourceActionDataSource) Read(ctx context.Context, request datasource.
 
 	ctx, cancel := context.WithTimeout(ctx, readTimeout)
 	defer cancel()
+	ctx = clients.WithResponseCache(ctx)
+
+	ctx, deprecationNotices := clients.WithDeprecationNotices(ctx)
+	defer appendDeprecationWarnings(&response.Diagnostics, deprecationNotices)
 
 	id, err := parse.ResourceIDWithResourceType(model.ResourceID.ValueString(), model.Type.ValueString())
 	if err != nil {
@@ -154,6 +171,14 @@ func (r *ResourceActionDataSource) Read(ctx context.Context, request datasource.
 		response.Diagnostics.AddError("Invalid body", fmt.Sprintf(`The argument "body" is invalid: %s`, err.Error()))
 		return
 	}
+	if !model.PayloadFile.IsNull() {
+		payload, _, err := readPayloadFile(model.PayloadFile.ValueString())
+		if err != nil {
+			response.Diagnostics.AddError("Invalid payload_file", fmt.Sprintf(`The argument "payload_file" is invalid: %s`, err.Error()))
+			return
+		}
+		requestBody = payload
+	}
 
 	method := model.Method.ValueString()
 	if method == "" {
@@ -175,13 +200,13 @@ func (r *ResourceActionDataSource) Read(ctx context.Context, request datasource.
 
 	responseBody, err := client.Action(ctx, id.AzureResourceId, model.Action.ValueString(), id.ApiVersion, method, requestBody, clients.NewRequestOptions(model.Headers, model.QueryParameters))
 	if err != nil {
-		response.Diagnostics.AddError("Failed to perform action", fmt.Errorf("performing action %s of %q: %+v", model.Action.ValueString(), id, err).Error())
+		response.Diagnostics.AddError(operationErrorSummary(err, "Failed to perform action"), fmt.Errorf("performing action %s of %q: %+v", model.Action.ValueString(), id, err).Error())
 		return
 	}
 
 	model.ID = basetypes.NewStringValue(id.ID())
 
-	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues)
+	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues, model.OutputSchema)
 	if err != nil {
 		response.Diagnostics.AddError("Failed to build output", err.Error())
 		return
@@ -2,7 +2,10 @@ package services
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/Azure/terraform-provider-azapi/internal/clients"
@@ -15,10 +18,14 @@ import (
 	"github.com/Azure/terraform-provider-azapi/internal/services/myplanmodifier"
 	"github.com/Azure/terraform-provider-azapi/internal/services/myvalidator"
 	"github.com/Azure/terraform-provider-azapi/internal/services/parse"
+	"github.com/Azure/terraform-provider-azapi/utils"
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -27,6 +34,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jmespath/go-jmespath"
 )
 
 type ActionResourceModel struct {
@@ -36,16 +45,31 @@ type ActionResourceModel struct {
 	Action               types.String        `tfsdk:"action"`
 	Method               types.String        `tfsdk:"method"`
 	Body                 types.Dynamic       `tfsdk:"body"`
+	PayloadFile          types.String        `tfsdk:"payload_file"`
+	PayloadFileHash      types.String        `tfsdk:"payload_file_hash"`
 	When                 types.String        `tfsdk:"when"`
+	CompletionCondition  types.Object        `tfsdk:"completion_condition"`
 	Locks                types.List          `tfsdk:"locks"`
 	ResponseExportValues types.Dynamic       `tfsdk:"response_export_values"`
+	OutputSchema         types.Map           `tfsdk:"output_schema"`
 	Output               types.Dynamic       `tfsdk:"output"`
 	Timeouts             timeouts.Value      `tfsdk:"timeouts"`
 	Retry                retry.RetryValue    `tfsdk:"retry"`
 	Headers              map[string]string   `tfsdk:"headers"`
 	QueryParameters      map[string][]string `tfsdk:"query_parameters"`
+	Triggers             types.Map           `tfsdk:"triggers"`
+	RerunInterval        types.String        `tfsdk:"rerun_interval"`
+	LastPerformedAt      types.String        `tfsdk:"last_performed_at"`
+	SelectSecret         types.String        `tfsdk:"select_secret"`
+	VersionTracking      types.String        `tfsdk:"version_tracking"`
 }
 
+// actionFingerprintKey is the private state key of the fingerprint of the inputs of the last performed action.
+const actionFingerprintKey = "action_fingerprint"
+
+// importedActionKey is the private state key which marks the imported actions, they're not performed until the next change of the inputs.
+const importedActionKey = "imported_action"
+
 type ActionResource struct {
 	ProviderData *clients.Client
 }
@@ -54,6 +78,7 @@ var _ resource.Resource = &ActionResource{}
 var _ resource.ResourceWithConfigure = &ActionResource{}
 var _ resource.ResourceWithModifyPlan = &ActionResource{}
 var _ resource.ResourceWithUpgradeState = &ActionResource{}
+var _ resource.ResourceWithImportState = &ActionResource{}
 
 func (r *ActionResource) Configure(ctx context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
 	if v, ok := request.ProviderData.(*clients.Client); ok {
@@ -132,6 +157,20 @@ func (r *ActionResource) Schema(ctx context.Context, request resource.SchemaRequ
 				MarkdownDescription: docstrings.Body(),
 			},
 
+			"payload_file": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					myvalidator.StringIsNotEmpty(),
+					stringvalidator.ConflictsWith(path.MatchRoot("body")),
+				},
+				MarkdownDescription: docstrings.PayloadFile(),
+			},
+
+			"payload_file_hash": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The SHA256 hash of the content of the `payload_file`. The action is performed again when it's changed.",
+			},
+
 			"when": schema.StringAttribute{
 				Optional: true,
 				Computed: true,
@@ -142,6 +181,48 @@ func (r *ActionResource) Schema(ctx context.Context, request resource.SchemaRequ
 				MarkdownDescription: "When to perform the action, value must be one of: `apply`, `destroy`. Default is `apply`.",
 			},
 
+			"completion_condition": schema.SingleNestedAttribute{
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"expression": schema.StringAttribute{
+						Required: true,
+						Validators: []validator.String{
+							myvalidator.StringIsJMESPath(),
+						},
+						MarkdownDescription: "The [JMESPath](https://jmespath.org/) expression which is evaluated against the polled status body, the action is completed when it evaluates to a truthy value, e.g. `properties.status == 'Completed'`.",
+					},
+					"failure_expression": schema.StringAttribute{
+						Optional: true,
+						Validators: []validator.String{
+							myvalidator.StringIsJMESPath(),
+						},
+						MarkdownDescription: "The [JMESPath](https://jmespath.org/) expression which is evaluated against the polled status body, the action fails when it evaluates to a truthy value, e.g. `properties.status == 'Failed'`.",
+					},
+					"url": schema.StringAttribute{
+						Optional: true,
+						Validators: []validator.String{
+							myvalidator.StringIsNotEmpty(),
+						},
+						MarkdownDescription: "The URL which is polled by the `GET` requests, e.g. the ID of the resource or of an operation status. Defaults to the `resource_id`.",
+					},
+					"api_version": schema.StringAttribute{
+						Optional: true,
+						Validators: []validator.String{
+							myvalidator.StringIsNotEmpty(),
+						},
+						MarkdownDescription: "The api-version of the polling requests. Defaults to the api-version in the `type`.",
+					},
+					"interval_seconds": schema.Int64Attribute{
+						Optional: true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+						MarkdownDescription: "The interval between the polling requests in seconds. Defaults to `10`.",
+					},
+				},
+				MarkdownDescription: "The condition which is polled after the action is performed, it's useful for the actions whose completion isn't signaled by the long-running operation, but by a custom status field. The polling is bounded by the `create` or `update` timeout.",
+			},
+
 			"locks": schema.ListAttribute{
 				ElementType: types.StringType,
 				Optional:    true,
@@ -153,6 +234,8 @@ func (r *ActionResource) Schema(ctx context.Context, request resource.SchemaRequ
 
 			"response_export_values": CommonAttributeResponseExportValues(),
 
+			"output_schema": CommonAttributeOutputSchema(),
+
 			"output": schema.DynamicAttribute{
 				Computed:            true,
 				MarkdownDescription: docstrings.Output("azapi_resource_action"),
@@ -173,6 +256,38 @@ func (r *ActionResource) Schema(ctx context.Context, request resource.SchemaRequ
 				Optional:            true,
 				MarkdownDescription: "A map of query parameters to include in the request",
 			},
+
+			"triggers": schema.MapAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				MarkdownDescription: "A map of arbitrary strings that, when changed, performs the action again. When it's specified, the changes of the other arguments don't perform the action again and the `output` of the last performed action is kept, it protects the actions which aren't idempotent, e.g. `regenerateKey`, from being replayed. It only applies when `when` is `apply`.",
+			},
+
+			"rerun_interval": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					myvalidator.StringIsDuration(),
+				},
+				MarkdownDescription: "The interval after which the action is performed again, e.g. `720h` to regenerate a key every 30 days. When the last run is older than the interval, the action is performed again at the next apply, even if the `triggers` are not changed. It's a string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration) consisting of numbers and unit suffixes, such as \"30s\" or \"2h45m\". It only applies when `when` is `apply`.",
+			},
+
+			"last_performed_at": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The time when the action was last performed, in RFC3339 format. If it's not recorded, e.g. the resource was created by an earlier version of the provider, the action is performed again at the next apply when the `rerun_interval` is specified.",
+			},
+
+			"select_secret": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					myvalidator.StringIsJMESPath(),
+				},
+				MarkdownDescription: docstrings.SelectSecret(),
+			},
+
+			"version_tracking": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: docstrings.VersionTracking(),
+			},
 		},
 
 		Blocks: map[string]schema.Block{
@@ -202,10 +317,66 @@ func (r *ActionResource) ModifyPlan(ctx context.Context, request resource.Modify
 		return
 	}
 
-	if state == nil || !plan.ResponseExportValues.Equal(state.ResponseExportValues) || !dynamic.SemanticallyEqual(plan.Body, state.Body) {
+	response.Diagnostics.Append(validateOutputSchema(config.ResponseExportValues, config.OutputSchema)...)
+
+	if err := validateRequestBodySize(plan.Body); err != nil {
+		response.Diagnostics.AddError("Invalid body", fmt.Sprintf(`The argument "body" is invalid: %s`, err.Error()))
+		return
+	}
+
+	response.Diagnostics.Append(subscriptionMismatchWarning("resource_id", config.ResourceId, r.ProviderData)...)
+
+	switch {
+	case config.PayloadFile.IsUnknown():
+		plan.PayloadFileHash = basetypes.NewStringUnknown()
+	case config.PayloadFile.IsNull():
+		plan.PayloadFileHash = types.StringNull()
+	default:
+		_, hash, err := readPayloadFile(config.PayloadFile.ValueString())
+		if err != nil {
+			response.Diagnostics.AddError("Invalid payload_file", fmt.Sprintf(`The argument "payload_file" is invalid: %s`, err.Error()))
+			return
+		}
+		plan.PayloadFileHash = types.StringValue(hash)
+	}
+
+	switch {
+	case state != nil && actionImported(ctx, request.Private):
+		// the next apply adopts the imported action without performing it
+		plan.Output = state.Output
+		plan.LastPerformedAt = basetypes.NewStringUnknown()
+	case actionRerunDue(*plan, state):
 		plan.Output = basetypes.NewDynamicUnknown()
-	} else {
+		plan.LastPerformedAt = basetypes.NewStringUnknown()
+	case actionTriggersUnchanged(plan, state) && actionSelectSecretChanged(plan, state):
+		// the version_tracking can only be computed from a new response, so the action is performed again like the due reruns
+		plan.Output = basetypes.NewDynamicUnknown()
+		plan.LastPerformedAt = basetypes.NewStringUnknown()
+	case actionTriggersUnchanged(plan, state):
 		plan.Output = state.Output
+		plan.LastPerformedAt = state.LastPerformedAt
+		var fingerprint string
+		if data, _ := request.Private.GetKey(ctx, actionFingerprintKey); len(data) != 0 && json.Unmarshal(data, &fingerprint) == nil {
+			if planFingerprint, ok := plannedActionFingerprint(*plan); ok && planFingerprint != fingerprint {
+				response.Diagnostics.AddAttributeWarning(path.Root("triggers"), "The action will not be performed again", "The inputs of the action are changed, but the `triggers` are not, so the action will not be performed again. Please change the `triggers` to perform it again with the new inputs.")
+			}
+		}
+	case state == nil || !plan.ResponseExportValues.Equal(state.ResponseExportValues) || !plan.OutputSchema.Equal(state.OutputSchema) || !plan.SelectSecret.Equal(state.SelectSecret) || !dynamic.SemanticallyEqual(plan.Body, state.Body) || !plan.PayloadFileHash.Equal(state.PayloadFileHash):
+		plan.Output = basetypes.NewDynamicUnknown()
+	default:
+		plan.Output = state.Output
+	}
+	if plan.When.ValueString() != "apply" {
+		plan.LastPerformedAt = types.StringNull()
+	}
+	// the secret is selected from the same response as the output
+	switch {
+	case plan.SelectSecret.IsNull():
+		plan.VersionTracking = types.StringNull()
+	case plan.Output.IsUnknown() || state == nil:
+		plan.VersionTracking = basetypes.NewStringUnknown()
+	default:
+		plan.VersionTracking = state.VersionTracking
 	}
 
 	response.Diagnostics.Append(response.Plan.Set(ctx, plan)...)
@@ -217,15 +388,18 @@ func (r *ActionResource) Create(ctx context.Context, request resource.CreateRequ
 		return
 	}
 
-	timeout, diags := model.Timeouts.Create(ctx, 30*time.Minute)
+	timeout, diags := model.Timeouts.Create(ctx, r.ProviderData.Features.DefaultCreateTimeout)
 	if response.Diagnostics.Append(diags...); response.Diagnostics.HasError() {
 		return
 	}
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	ctx, deprecationNotices := clients.WithDeprecationNotices(ctx)
+	defer appendDeprecationWarnings(&response.Diagnostics, deprecationNotices)
+
 	if model.When.ValueString() == "apply" {
-		r.Action(ctx, model, &response.State, &response.Diagnostics)
+		r.Action(ctx, model, &response.State, response.Private, &response.Diagnostics)
 	} else {
 		id, err := parse.ResourceIDWithResourceType(model.ResourceId.ValueString(), model.Type.ValueString())
 		if err != nil {
@@ -238,26 +412,58 @@ func (r *ActionResource) Create(ctx context.Context, request resource.CreateRequ
 		}
 		model.ID = basetypes.NewStringValue(resourceId)
 		model.Output = basetypes.NewDynamicNull()
+		model.LastPerformedAt = types.StringNull()
+		model.VersionTracking = types.StringNull()
 		response.Diagnostics.Append(response.State.Set(ctx, model)...)
 	}
 }
 
 func (r *ActionResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
 	var model ActionResourceModel
+	var state *ActionResourceModel
 	if response.Diagnostics.Append(request.Plan.Get(ctx, &model)...); response.Diagnostics.HasError() {
 		return
 	}
+	if response.Diagnostics.Append(request.State.Get(ctx, &state)...); response.Diagnostics.HasError() {
+		return
+	}
 
-	timeout, diags := model.Timeouts.Update(ctx, 30*time.Minute)
+	timeout, diags := model.Timeouts.Update(ctx, r.ProviderData.Features.DefaultUpdateTimeout)
 	if response.Diagnostics.Append(diags...); response.Diagnostics.HasError() {
 		return
 	}
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	if model.When.ValueString() == "apply" {
-		r.Action(ctx, model, &response.State, &response.Diagnostics)
+	ctx, deprecationNotices := clients.WithDeprecationNotices(ctx)
+	defer appendDeprecationWarnings(&response.Diagnostics, deprecationNotices)
+
+	if model.When.ValueString() != "apply" {
+		return
+	}
+
+	if actionImported(ctx, request.Private) {
+		tflog.Info(ctx, fmt.Sprintf("the action %q is imported, the action is not performed", model.ID.ValueString()))
+		model.Output = state.Output
+		model.LastPerformedAt = state.LastPerformedAt
+		model.VersionTracking = state.VersionTracking
+		if response.Diagnostics.Append(response.Private.SetKey(ctx, importedActionKey, nil)...); response.Diagnostics.HasError() {
+			return
+		}
+		response.Diagnostics.Append(response.State.Set(ctx, model)...)
+		return
 	}
+
+	// the unknown time of the last run means the action is due to be performed again
+	if actionTriggersUnchanged(&model, state) && !model.LastPerformedAt.IsUnknown() {
+		tflog.Info(ctx, fmt.Sprintf("the triggers of the action %q are not changed, the action is not performed again", model.ID.ValueString()))
+		model.Output = state.Output
+		model.VersionTracking = state.VersionTracking
+		response.Diagnostics.Append(response.State.Set(ctx, model)...)
+		return
+	}
+
+	r.Action(ctx, model, &response.State, response.Private, &response.Diagnostics)
 }
 
 func (r *ActionResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
@@ -267,7 +473,7 @@ func (r *ActionResource) Delete(ctx context.Context, request resource.DeleteRequ
 	}
 
 	if model.When.ValueString() == "destroy" {
-		r.Action(ctx, model, &response.State, &response.Diagnostics)
+		r.Action(ctx, model, &response.State, response.Private, &response.Diagnostics)
 	}
 }
 
@@ -284,8 +490,83 @@ func (r *ActionResource) Read(ctx context.Context, request resource.ReadRequest,
 	response.Diagnostics.Append(response.State.Set(ctx, state)...)
 }
 
-func (r *ActionResource) Action(ctx context.Context, model ActionResourceModel, state *tfsdk.State, diagnostics *diag.Diagnostics) {
-	actionTimeout, diags := model.Timeouts.Create(ctx, 30*time.Minute)
+// ImportState imports the action as a placeholder of the action which was performed outside of Terraform, it's not performed until the inputs are changed after the next apply.
+// The import ID is the resource ID with the optional `api-version` and `action` query parameters, e.g. `<resource id>?api-version=2021-06-22&action=listKeys`.
+func (r *ActionResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	tflog.Debug(ctx, fmt.Sprintf("Importing Resource - parsing %q", request.ID))
+
+	idUrl, err := url.Parse(request.ID)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid Resource ID", fmt.Errorf("parsing Resource ID %q: %+v", request.ID, err).Error())
+		return
+	}
+	query := idUrl.Query()
+	actionName := query.Get("action")
+	input := idUrl.Path
+	if apiVersion := query.Get("api-version"); apiVersion != "" {
+		input = fmt.Sprintf("%s?api-version=%s", input, apiVersion)
+	}
+	input, err = importIdWithApiVersion(input)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid Resource ID", fmt.Errorf("parsing Resource ID %q: %+v", request.ID, err).Error())
+		return
+	}
+
+	id, err := parse.ResourceIDWithApiVersion(input)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid Resource ID", fmt.Errorf("parsing Resource ID %q: %+v", input, err).Error())
+		return
+	}
+
+	resourceId := id.ID()
+	action := types.StringNull()
+	if actionName != "" {
+		resourceId = fmt.Sprintf("%s/%s", id.ID(), actionName)
+		action = types.StringValue(actionName)
+	}
+
+	state := ActionResourceModel{
+		ID:                   types.StringValue(resourceId),
+		Type:                 types.StringValue(fmt.Sprintf("%s@%s", id.AzureResourceType, id.ApiVersion)),
+		ResourceId:           types.StringValue(id.ID()),
+		Action:               action,
+		Method:               types.StringValue("POST"),
+		Body:                 types.DynamicNull(),
+		PayloadFile:          types.StringNull(),
+		PayloadFileHash:      types.StringNull(),
+		When:                 types.StringValue("apply"),
+		CompletionCondition:  types.ObjectNull(map[string]attr.Type{"expression": types.StringType, "failure_expression": types.StringType, "url": types.StringType, "api_version": types.StringType, "interval_seconds": types.Int64Type}),
+		Locks:                types.ListNull(types.StringType),
+		ResponseExportValues: types.DynamicNull(),
+		OutputSchema:         types.MapNull(types.StringType),
+		Output:               types.DynamicNull(),
+		Retry:                retry.NewRetryValueNull(),
+		Triggers:             types.MapNull(types.StringType),
+		RerunInterval:        types.StringNull(),
+		// the time of the import is recorded as the time of the last run, so the rerun interval is counted from it
+		LastPerformedAt: types.StringValue(time.Now().UTC().Format(time.RFC3339)),
+		SelectSecret:    types.StringNull(),
+		VersionTracking: types.StringNull(),
+		Timeouts: timeouts.Value{
+			Object: types.ObjectNull(map[string]attr.Type{
+				"create": types.StringType,
+				"update": types.StringType,
+				"read":   types.StringType,
+				"delete": types.StringType,
+			}),
+		},
+	}
+
+	if response.Diagnostics.Append(response.Private.SetKey(ctx, importedActionKey, []byte("true"))...); response.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("action %q is imported", resourceId))
+	response.Diagnostics.Append(response.State.Set(ctx, state)...)
+}
+
+func (r *ActionResource) Action(ctx context.Context, model ActionResourceModel, state *tfsdk.State, private privateState, diagnostics *diag.Diagnostics) {
+	actionTimeout, diags := model.Timeouts.Create(ctx, r.ProviderData.Features.DefaultCreateTimeout)
 	diagnostics.Append(diags...)
 	if diagnostics.HasError() {
 		return
@@ -300,11 +581,28 @@ func (r *ActionResource) Action(ctx context.Context, model ActionResourceModel,
 		return
 	}
 
+	actionUrl := id.ID()
+	if actionName := model.Action.ValueString(); actionName != "" {
+		actionUrl = fmt.Sprintf("%s/%s", id.ID(), actionName)
+	}
+	if diagnostics.Append(readOnlyModeDiagnostics(r.ProviderData.Features, fmt.Sprintf("Sending the %s request to", model.Method.ValueString()), actionUrl)...); diagnostics.HasError() {
+		return
+	}
+
 	var requestBody interface{}
 	if err := unmarshalBody(model.Body, &requestBody); err != nil {
 		diagnostics.AddError("Invalid body", fmt.Sprintf(`The argument "body" is invalid: %s`, err.Error()))
 		return
 	}
+	if !model.PayloadFile.IsNull() {
+		payload, hash, err := readPayloadFile(model.PayloadFile.ValueString())
+		if err != nil {
+			diagnostics.AddError("Invalid payload_file", fmt.Sprintf(`The argument "payload_file" is invalid: %s`, err.Error()))
+			return
+		}
+		requestBody = payload
+		model.PayloadFileHash = types.StringValue(hash)
+	}
 
 	for _, id := range AsStringList(model.Locks) {
 		locks.ByID(id)
@@ -326,7 +624,7 @@ func (r *ActionResource) Action(ctx context.Context, model ActionResourceModel,
 
 	responseBody, err := client.Action(ctx, id.AzureResourceId, model.Action.ValueString(), id.ApiVersion, model.Method.ValueString(), requestBody, clients.NewRequestOptions(model.Headers, model.QueryParameters))
 	if err != nil {
-		diagnostics.AddError("Failed to perform action", fmt.Errorf("performing action %s of %q: %+v", model.Action.ValueString(), id, err).Error())
+		diagnostics.AddError(operationErrorSummary(err, "Failed to perform action"), fmt.Errorf("performing action %s of %q: %+v", model.Action.ValueString(), id, err).Error())
 		return
 	}
 
@@ -336,12 +634,181 @@ func (r *ActionResource) Action(ctx context.Context, model ActionResourceModel,
 	}
 	model.ID = basetypes.NewStringValue(resourceId)
 
-	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues)
+	if condition := expandCompletionCondition(ctx, model.CompletionCondition); condition != nil {
+		if err := waitForCompletionCondition(ctx, client, *condition, id.AzureResourceId, id.ApiVersion); err != nil {
+			diagnostics.AddError("Failed to wait for the completion condition", fmt.Errorf("performing action %s of %q: %+v", model.Action.ValueString(), id, err).Error())
+			return
+		}
+	}
+
+	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues, model.OutputSchema)
 	if err != nil {
 		diagnostics.AddError("Failed to build output", err.Error())
 		return
 	}
 	model.Output = output
+	model.LastPerformedAt = types.StringValue(time.Now().UTC().Format(time.RFC3339))
+	if !model.SelectSecret.IsNull() {
+		key, diags := secretVersionKey(ctx, private)
+		if diagnostics.Append(diags...); diagnostics.HasError() {
+			return
+		}
+		if model.VersionTracking, err = secretVersion(responseBody, model.SelectSecret, key); err != nil {
+			diagnostics.AddAttributeError(path.Root("select_secret"), "Failed to select the secret", err.Error())
+			return
+		}
+	} else {
+		model.VersionTracking = types.StringNull()
+	}
+
+	// the private state values must be valid JSON
+	fingerprint, _ := json.Marshal(actionFingerprint(model, actionUrl, requestBody))
+	if diagnostics.Append(private.SetKey(ctx, actionFingerprintKey, fingerprint)...); diagnostics.HasError() {
+		return
+	}
+	if diagnostics.Append(writeResourceMetadata(ctx, private, newResourceMetadata(r.ProviderData, id.AzureResourceType, id.ApiVersion))...); diagnostics.HasError() {
+		return
+	}
 
 	diagnostics.Append(state.Set(ctx, model)...)
 }
+
+// actionTriggersUnchanged returns whether the action is protected by the triggers which are not changed since it's performed, so it must not be performed again.
+func actionTriggersUnchanged(plan *ActionResourceModel, state *ActionResourceModel) bool {
+	return state != nil && !plan.Triggers.IsNull() && plan.Triggers.Equal(state.Triggers)
+}
+
+// actionSelectSecretChanged returns whether a new secret is selected, its version_tracking can only be computed from a new response of the action.
+func actionSelectSecretChanged(plan *ActionResourceModel, state *ActionResourceModel) bool {
+	return state != nil && !plan.SelectSecret.IsNull() && !plan.SelectSecret.Equal(state.SelectSecret)
+}
+
+// actionImported returns whether the action is imported and hasn't been updated since, the imported actions are placeholders of the actions which were performed outside of Terraform.
+func actionImported(ctx context.Context, private privateState) bool {
+	data, _ := private.GetKey(ctx, importedActionKey)
+	return len(data) != 0
+}
+
+// actionRerunDue returns whether the last run of the action is older than the rerun interval, the action is due if the time of the last run isn't recorded.
+func actionRerunDue(plan ActionResourceModel, state *ActionResourceModel) bool {
+	if state == nil || plan.When.ValueString() != "apply" || plan.RerunInterval.IsNull() || plan.RerunInterval.IsUnknown() {
+		return false
+	}
+	interval, err := time.ParseDuration(plan.RerunInterval.ValueString())
+	if err != nil {
+		return false
+	}
+	lastPerformedAt, err := time.Parse(time.RFC3339, state.LastPerformedAt.ValueString())
+	if err != nil {
+		return true
+	}
+	return time.Since(lastPerformedAt) >= interval
+}
+
+// actionFingerprint returns the SHA256 hash of the inputs of the action request.
+func actionFingerprint(model ActionResourceModel, actionUrl string, requestBody interface{}) string {
+	data, _ := json.Marshal(map[string]interface{}{
+		"method":           model.Method.ValueString(),
+		"url":              actionUrl,
+		"body":             requestBody,
+		"headers":          model.Headers,
+		"query_parameters": model.QueryParameters,
+	})
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+// plannedActionFingerprint returns the fingerprint of the planned action request, it returns false if the inputs are not known yet.
+func plannedActionFingerprint(model ActionResourceModel) (string, bool) {
+	if !dynamic.IsFullyKnown(model.Body) || model.PayloadFile.IsUnknown() || model.ResourceId.IsUnknown() || model.Action.IsUnknown() || model.Method.IsUnknown() {
+		return "", false
+	}
+	id, err := parse.ResourceIDWithResourceType(model.ResourceId.ValueString(), model.Type.ValueString())
+	if err != nil {
+		return "", false
+	}
+	actionUrl := id.ID()
+	if actionName := model.Action.ValueString(); actionName != "" {
+		actionUrl = fmt.Sprintf("%s/%s", id.ID(), actionName)
+	}
+	var requestBody interface{}
+	if err := unmarshalBody(model.Body, &requestBody); err != nil {
+		return "", false
+	}
+	if !model.PayloadFile.IsNull() {
+		payload, _, err := readPayloadFile(model.PayloadFile.ValueString())
+		if err != nil {
+			return "", false
+		}
+		requestBody = payload
+	}
+	return actionFingerprint(model, actionUrl, requestBody), true
+}
+
+type completionConditionModel struct {
+	Expression        types.String `tfsdk:"expression"`
+	FailureExpression types.String `tfsdk:"failure_expression"`
+	Url               types.String `tfsdk:"url"`
+	ApiVersion        types.String `tfsdk:"api_version"`
+	IntervalSeconds   types.Int64  `tfsdk:"interval_seconds"`
+}
+
+func expandCompletionCondition(ctx context.Context, input types.Object) *completionConditionModel {
+	if input.IsNull() || input.IsUnknown() {
+		return nil
+	}
+	var model completionConditionModel
+	if diags := input.As(ctx, &model, basetypes.ObjectAsOptions{}); diags.HasError() {
+		return nil
+	}
+	return &model
+}
+
+// waitForCompletionCondition polls the status body until the expression evaluates to a truthy value,
+// it returns an error if the failure expression evaluates to a truthy value or the context is done.
+func waitForCompletionCondition(ctx context.Context, client clients.Requester, condition completionConditionModel, defaultUrl string, defaultApiVersion string) error {
+	url := defaultUrl
+	if v := condition.Url.ValueString(); v != "" {
+		url = v
+	}
+	apiVersion := defaultApiVersion
+	if v := condition.ApiVersion.ValueString(); v != "" {
+		apiVersion = v
+	}
+	interval := 10 * time.Second
+	if v := condition.IntervalSeconds.ValueInt64(); v > 0 {
+		interval = time.Duration(v) * time.Second
+	}
+
+	for {
+		responseBody, err := client.Get(ctx, url, apiVersion, clients.DefaultRequestOptions())
+		if err != nil {
+			return fmt.Errorf("polling the status of %s: %+v", url, err)
+		}
+
+		if expression := condition.FailureExpression.ValueString(); expression != "" {
+			result, err := jmespath.Search(expression, responseBody)
+			if err != nil {
+				return fmt.Errorf("evaluating the failure expression %q: %+v", expression, err)
+			}
+			if utils.IsJMESPathTruthy(result) {
+				status, _ := json.Marshal(responseBody)
+				return fmt.Errorf("the failure expression %q is met, the status is: %s", expression, status)
+			}
+		}
+
+		result, err := jmespath.Search(condition.Expression.ValueString(), responseBody)
+		if err != nil {
+			return fmt.Errorf("evaluating the expression %q: %+v", condition.Expression.ValueString(), err)
+		}
+		if utils.IsJMESPathTruthy(result) {
+			return nil
+		}
+
+		tflog.Debug(ctx, fmt.Sprintf("the completion condition %q of %s isn't met, polling again in %s", condition.Expression.ValueString(), url, interval))
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for the completion condition %q: %+v", condition.Expression.ValueString(), ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
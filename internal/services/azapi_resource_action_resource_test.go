@@ -8,6 +8,7 @@ import (
 
 	"github.com/Azure/terraform-provider-azapi/internal/acceptance"
 	"github.com/Azure/terraform-provider-azapi/internal/clients"
+	"github.com/Azure/terraform-provider-azapi/internal/services/parse"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -28,6 +29,7 @@ func TestAccActionResource_basic(t *testing.T) {
 			Config: r.basic(data),
 			Check:  resource.ComposeTestCheckFunc(),
 		},
+		data.ImportStepWithImportStateIdFunc(r.ImportIdFunc, "body", "output", "last_performed_at"),
 	})
 }
 
@@ -121,6 +123,35 @@ func TestAccActionResource_queryParameters(t *testing.T) {
 	})
 }
 
+func TestAccActionResource_triggers(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_resource_action", "test")
+	r := ActionResource{}
+
+	data.DataSourceTest(t, []resource.TestStep{
+		{
+			Config: r.triggers(data, "primary", "2024-01"),
+			Check:  resource.ComposeTestCheckFunc(),
+		},
+		{
+			Config: r.triggers(data, "secondary", "2024-01"),
+			Check:  resource.ComposeTestCheckFunc(),
+		},
+		{
+			Config: r.triggers(data, "secondary", "2024-02"),
+			Check:  resource.ComposeTestCheckFunc(),
+		},
+	})
+}
+
+func (ActionResource) ImportIdFunc(tfState *terraform.State) (string, error) {
+	state := tfState.RootModule().Resources["azapi_resource_action.test"].Primary
+	id, err := parse.ResourceIDWithResourceType(state.Attributes["resource_id"], state.Attributes["type"])
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s?api-version=%s&action=%s", id.AzureResourceId, id.ApiVersion, state.Attributes["action"]), nil
+}
+
 func (r ActionResource) basic(data acceptance.TestData) string {
 	return fmt.Sprintf(`
 %[1]s
@@ -361,3 +392,21 @@ resource "azapi_resource_action" "test" {
   response_export_values = ["*"]
 }`
 }
+
+func (r ActionResource) triggers(data acceptance.TestData, keyName string, rotation string) string {
+	return fmt.Sprintf(`
+%[1]s
+
+resource "azapi_resource_action" "test" {
+  type        = "Microsoft.Automation/automationAccounts@2021-06-22"
+  resource_id = azapi_resource.test.id
+  action      = "agentRegistrationInformation/regenerateKey"
+  body = {
+    keyName = "%[2]s"
+  }
+  triggers = {
+    rotation = "%[3]s"
+  }
+}
+`, GenericResource{}.identityNone(data), keyName, rotation)
+}
@@ -0,0 +1,440 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/terraform-provider-azapi/internal/clients"
+	"github.com/Azure/terraform-provider-azapi/internal/services/dynamic"
+	"github.com/Azure/terraform-provider-azapi/internal/services/parse"
+	"github.com/Azure/terraform-provider-azapi/internal/tf"
+	"github.com/Azure/terraform-provider-azapi/utils"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// bundleRollbackTimeout is the timeout of deleting the created members when the creation of the bundle fails.
+const bundleRollbackTimeout = 30 * time.Minute
+
+type AzapiResourceBundleModel struct {
+	ID          types.String   `tfsdk:"id"`
+	Resources   types.Dynamic  `tfsdk:"resources"`
+	ResourceIDs types.List     `tfsdk:"resource_ids"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
+}
+
+// bundleMember is a resource in the bundle, the members are created in order and deleted in the reverse order.
+type bundleMember struct {
+	Type     string      `json:"type"`
+	Name     string      `json:"name"`
+	ParentId string      `json:"parent_id"`
+	Body     interface{} `json:"body"`
+}
+
+var _ resource.Resource = &AzapiResourceBundle{}
+var _ resource.ResourceWithConfigure = &AzapiResourceBundle{}
+var _ resource.ResourceWithModifyPlan = &AzapiResourceBundle{}
+
+type AzapiResourceBundle struct {
+	ProviderData *clients.Client
+}
+
+func (r *AzapiResourceBundle) Configure(ctx context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
+	if v, ok := request.ProviderData.(*clients.Client); ok {
+		r.ProviderData = v
+	}
+}
+
+func (r *AzapiResourceBundle) Metadata(ctx context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_resource_bundle"
+}
+
+func (r *AzapiResourceBundle) Schema(ctx context.Context, request resource.SchemaRequest, response *resource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		MarkdownDescription: "This resource can manage a small group of tightly coupled Azure resource manager resources as an atomic unit, e.g. a private endpoint and its private DNS zone group.\n\n" +
+			"-> **Note** The resources are created in order, and the resources which are created by the failed apply are deleted in the reverse order, so a failed creation doesn't leave a partial group behind. The creation fails if any resource already exists.",
+		Description: "This resource can manage a small group of tightly coupled Azure resource manager resources as an atomic unit.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "The ID of the first resource in the bundle.",
+			},
+
+			"resources": schema.DynamicAttribute{
+				Required: true,
+				Validators: []validator.Dynamic{
+					bundleResourcesValidator{},
+				},
+				MarkdownDescription: "A list of the resources in the bundle, they're created and updated in order, and deleted in the reverse order. Each resource is an object which contains the following fields:\n" +
+					"  - `type` - (Required) The type of the resource in the format `<resource-type>@<api-version>`.\n" +
+					"  - `name` - (Required) The name of the resource.\n" +
+					"  - `parent_id` - (Required) The ID of the parent resource.\n" +
+					"  - `body` - (Optional) The body of the resource, it's an HCL object.\n\n" +
+					"Changing the `type`, `name` or `parent_id` of any resource, or the number of the resources forces a new bundle to be created.",
+			},
+
+			"resource_ids": schema.ListAttribute{
+				ElementType:         types.StringType,
+				Computed:            true,
+				MarkdownDescription: "The IDs of the resources in the bundle, in the same order as the `resources`.",
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Read:   true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// bundleResourcesValidator validates the resources of the bundle when they're known.
+type bundleResourcesValidator struct{}
+
+func (v bundleResourcesValidator) Description(ctx context.Context) string {
+	return "validate the resources of the bundle"
+}
+
+func (v bundleResourcesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v bundleResourcesValidator) ValidateDynamic(ctx context.Context, request validator.DynamicRequest, response *validator.DynamicResponse) {
+	if !dynamic.IsFullyKnown(request.ConfigValue) {
+		return
+	}
+	if _, _, err := expandBundleMembers(request.ConfigValue); err != nil {
+		response.Diagnostics.AddAttributeError(request.Path, "Invalid resources", err.Error())
+	}
+}
+
+// expandBundleMembers returns the members of the bundle and their IDs, the members must have distinct IDs.
+func expandBundleMembers(input types.Dynamic) ([]bundleMember, []parse.ResourceId, error) {
+	data, err := dynamic.ToJSON(input)
+	if err != nil {
+		return nil, nil, err
+	}
+	var members []bundleMember
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, nil, fmt.Errorf("the resources must be a list of objects which contain the `type`, `name`, `parent_id` and `body` fields: %+v", err)
+	}
+	if len(members) == 0 {
+		return nil, nil, fmt.Errorf("at least one resource must be specified")
+	}
+	ids := make([]parse.ResourceId, 0, len(members))
+	seen := make(map[string]bool)
+	for i, member := range members {
+		if member.Type == "" || member.Name == "" || member.ParentId == "" {
+			return nil, nil, fmt.Errorf("the resource at index %d must specify the `type`, `name` and `parent_id`", i)
+		}
+		if member.Body != nil {
+			if _, ok := member.Body.(map[string]interface{}); !ok {
+				return nil, nil, fmt.Errorf("the `body` of the resource at index %d must be an object", i)
+			}
+		}
+		id, err := parse.NewResourceID(member.Name, member.ParentId, member.Type)
+		if err != nil {
+			return nil, nil, fmt.Errorf("the resource at index %d is invalid: %+v", i, err)
+		}
+		if seen[strings.ToLower(id.AzureResourceId)] {
+			return nil, nil, fmt.Errorf("the resource %s is specified more than once", id.AzureResourceId)
+		}
+		seen[strings.ToLower(id.AzureResourceId)] = true
+		ids = append(ids, id)
+	}
+	return members, ids, nil
+}
+
+// bundleResourceIDs returns the IDs of the members in the bundle.
+func bundleResourceIDs(ids []parse.ResourceId) types.List {
+	out := make([]attr.Value, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, types.StringValue(id.AzureResourceId))
+	}
+	return types.ListValueMust(types.StringType, out)
+}
+
+func (r *AzapiResourceBundle) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	var config, state, plan *AzapiResourceBundleModel
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// destroy doesn't need to modify plan
+	if config == nil {
+		return
+	}
+
+	if !dynamic.IsFullyKnown(plan.Resources) {
+		plan.ID = types.StringUnknown()
+		plan.ResourceIDs = types.ListUnknown(types.StringType)
+		response.Diagnostics.Append(response.Plan.Set(ctx, plan)...)
+		return
+	}
+	_, ids, err := expandBundleMembers(plan.Resources)
+	if err != nil {
+		response.Diagnostics.AddAttributeError(path.Root("resources"), "Invalid resources", err.Error())
+		return
+	}
+	for _, id := range ids {
+		response.Diagnostics.Append(subscriptionMismatchWarning("resources", types.StringValue(id.ParentId), r.ProviderData)...)
+	}
+	plan.ID = types.StringValue(ids[0].AzureResourceId)
+	plan.ResourceIDs = bundleResourceIDs(ids)
+
+	// the members are identified by their positions, adding, removing, renaming or reordering them recreates the bundle
+	if state != nil && !plan.ResourceIDs.Equal(state.ResourceIDs) {
+		response.RequiresReplace = append(response.RequiresReplace, path.Root("resources"))
+	}
+
+	response.Diagnostics.Append(response.Plan.Set(ctx, plan)...)
+}
+
+func (r *AzapiResourceBundle) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
+	var model AzapiResourceBundleModel
+	if response.Diagnostics.Append(request.Plan.Get(ctx, &model)...); response.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := model.Timeouts.Create(ctx, r.ProviderData.Features.DefaultCreateTimeout)
+	if response.Diagnostics.Append(diags...); response.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ctx, deprecationNotices := clients.WithDeprecationNotices(ctx)
+	defer appendDeprecationWarnings(&response.Diagnostics, deprecationNotices)
+
+	members, ids, err := expandBundleMembers(model.Resources)
+	if err != nil {
+		response.Diagnostics.AddAttributeError(path.Root("resources"), "Invalid resources", err.Error())
+		return
+	}
+	for _, id := range ids {
+		if response.Diagnostics.Append(readOnlyModeDiagnostics(r.ProviderData.Features, "Creating", id.ID())...); response.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if err := createBundle(ctx, r.ProviderData.ResourceClient, members, ids); err != nil {
+		response.Diagnostics.AddError(operationErrorSummary(err, "Failed to create resource bundle"), err.Error())
+		return
+	}
+
+	model.ID = types.StringValue(ids[0].AzureResourceId)
+	model.ResourceIDs = bundleResourceIDs(ids)
+	response.Diagnostics.Append(response.State.Set(ctx, model)...)
+}
+
+// createBundle creates the members of the bundle in order, if any member fails, the created members are deleted in the reverse order.
+// It fails before creating any member if one of them already exists, because the bundle would delete the resource which it doesn't create.
+func createBundle(ctx context.Context, client clients.Requester, members []bundleMember, ids []parse.ResourceId) error {
+	for _, id := range ids {
+		_, err := client.Get(ctx, id.AzureResourceId, id.ApiVersion, clients.DefaultRequestOptions())
+		if err == nil {
+			return tf.ImportAsExistsError("azapi_resource_bundle", id.ID())
+		}
+		if !utils.ResponseErrorWasNotFound(err) {
+			return fmt.Errorf("checking for presence of existing %s: %w", id, err)
+		}
+	}
+
+	created := make([]parse.ResourceId, 0, len(ids))
+	for i, member := range members {
+		id := ids[i]
+		// the failed creation may still leave the resource behind, e.g. in the failed provisioning state
+		created = append(created, id)
+		if _, err := client.CreateOrUpdate(ctx, id.AzureResourceId, id.ApiVersion, member.Body, clients.DefaultRequestOptions()); err != nil {
+			return rollbackBundle(client, created, fmt.Errorf("creating %s: %w", id, err))
+		}
+	}
+	return nil
+}
+
+// rollbackBundle deletes the created members in the reverse order, the errors of the deletions are appended to the error of the creation.
+func rollbackBundle(client clients.Requester, created []parse.ResourceId, err error) error {
+	// the original context may be done, e.g. the creation exceeds the timeout
+	ctx, cancel := context.WithTimeout(context.Background(), bundleRollbackTimeout)
+	defer cancel()
+
+	errs := []error{err}
+	for i := len(created) - 1; i >= 0; i-- {
+		id := created[i]
+		tflog.Info(ctx, fmt.Sprintf("Rolling back the resource bundle, deleting %s", id.AzureResourceId))
+		if _, deleteErr := client.Delete(ctx, id.AzureResourceId, id.ApiVersion, clients.DefaultRequestOptions()); deleteErr != nil && !utils.ResponseErrorWasNotFound(deleteErr) {
+			errs = append(errs, fmt.Errorf("rolling back %s: %w", id, deleteErr))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *AzapiResourceBundle) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
+	var model AzapiResourceBundleModel
+	if response.Diagnostics.Append(request.State.Get(ctx, &model)...); response.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := model.Timeouts.Read(ctx, r.ProviderData.Features.DefaultReadTimeout)
+	if response.Diagnostics.Append(diags...); response.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	ctx, deprecationNotices := clients.WithDeprecationNotices(ctx)
+	defer appendDeprecationWarnings(&response.Diagnostics, deprecationNotices)
+
+	_, ids, err := expandBundleMembers(model.Resources)
+	if err != nil {
+		response.Diagnostics.AddAttributeError(path.Root("resources"), "Invalid resources", err.Error())
+		return
+	}
+
+	// the bundle is recreated if any member is deleted outside of terraform
+	responseBodies := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		responseBody, err := r.ProviderData.ResourceClient.Get(ctx, id.AzureResourceId, id.ApiVersion, clients.DefaultRequestOptions())
+		if err != nil {
+			if utils.ResponseErrorWasNotFound(err) {
+				tflog.Info(ctx, fmt.Sprintf("Error reading %q - removing the resource bundle from state", id.ID()))
+				response.State.RemoveResource(ctx)
+				return
+			}
+			response.Diagnostics.AddError("Failed to retrieve resource", fmt.Errorf("reading %s: %+v", id, err).Error())
+			return
+		}
+		responseBodies = append(responseBodies, responseBody)
+	}
+
+	resources, err := flattenBundleResources(ctx, model.Resources, responseBodies)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid payload", err.Error())
+		return
+	}
+	model.Resources = resources
+
+	response.Diagnostics.Append(response.State.Set(ctx, model)...)
+}
+
+// flattenBundleResources updates the bodies of the members with the values of the response bodies, so the drift of the members is detected.
+// Only the properties which are specified in the bodies are updated, the same as the body of the azapi_resource.
+func flattenBundleResources(ctx context.Context, resources types.Dynamic, responseBodies []interface{}) (types.Dynamic, error) {
+	data, err := dynamic.ToJSON(resources)
+	if err != nil {
+		return resources, err
+	}
+	var members []map[string]interface{}
+	if err := json.Unmarshal(data, &members); err != nil {
+		return resources, err
+	}
+	if len(members) != len(responseBodies) {
+		return resources, fmt.Errorf("expect %d response bodies, got %d", len(members), len(responseBodies))
+	}
+	for i, member := range members {
+		if member["body"] != nil {
+			member["body"] = utils.UpdateObject(member["body"], responseBodies[i], utils.UpdateJsonOption{})
+		}
+	}
+	if data, err = json.Marshal(members); err != nil {
+		return resources, err
+	}
+	payload, err := dynamic.FromJSON(data, resources.UnderlyingValue().Type(ctx))
+	if err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Failed to parse payload: %s", err.Error()))
+		return dynamic.FromJSONImplied(data)
+	}
+	return payload, nil
+}
+
+func (r *AzapiResourceBundle) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
+	var model AzapiResourceBundleModel
+	if response.Diagnostics.Append(request.Plan.Get(ctx, &model)...); response.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := model.Timeouts.Update(ctx, r.ProviderData.Features.DefaultUpdateTimeout)
+	if response.Diagnostics.Append(diags...); response.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ctx, deprecationNotices := clients.WithDeprecationNotices(ctx)
+	defer appendDeprecationWarnings(&response.Diagnostics, deprecationNotices)
+
+	members, ids, err := expandBundleMembers(model.Resources)
+	if err != nil {
+		response.Diagnostics.AddAttributeError(path.Root("resources"), "Invalid resources", err.Error())
+		return
+	}
+
+	// the members are the same as the existing ones, otherwise the bundle is recreated, so the updates are not rolled back
+	for i, member := range members {
+		id := ids[i]
+		if response.Diagnostics.Append(readOnlyModeDiagnostics(r.ProviderData.Features, "Updating", id.ID())...); response.Diagnostics.HasError() {
+			return
+		}
+		if _, err := r.ProviderData.ResourceClient.CreateOrUpdate(ctx, id.AzureResourceId, id.ApiVersion, member.Body, clients.DefaultRequestOptions()); err != nil {
+			response.Diagnostics.AddError(operationErrorSummary(err, "Failed to update resource bundle"), fmt.Errorf("updating %s: %+v", id, err).Error())
+			return
+		}
+	}
+
+	response.Diagnostics.Append(response.State.Set(ctx, model)...)
+}
+
+func (r *AzapiResourceBundle) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
+	var model AzapiResourceBundleModel
+	if response.Diagnostics.Append(request.State.Get(ctx, &model)...); response.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := model.Timeouts.Delete(ctx, r.ProviderData.Features.DefaultDeleteTimeout)
+	if response.Diagnostics.Append(diags...); response.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	ctx, deprecationNotices := clients.WithDeprecationNotices(ctx)
+	defer appendDeprecationWarnings(&response.Diagnostics, deprecationNotices)
+
+	_, ids, err := expandBundleMembers(model.Resources)
+	if err != nil {
+		response.Diagnostics.AddAttributeError(path.Root("resources"), "Invalid resources", err.Error())
+		return
+	}
+
+	for i := len(ids) - 1; i >= 0; i-- {
+		id := ids[i]
+		if response.Diagnostics.Append(readOnlyModeDiagnostics(r.ProviderData.Features, "Deleting", id.ID())...); response.Diagnostics.HasError() {
+			return
+		}
+		if _, err := r.ProviderData.ResourceClient.Delete(ctx, id.AzureResourceId, id.ApiVersion, clients.DefaultRequestOptions()); err != nil && !utils.ResponseErrorWasNotFound(err) {
+			response.Diagnostics.AddError(operationErrorSummary(err, "Failed to delete resource"), fmt.Errorf("deleting %s: %+v", id, err).Error())
+			return
+		}
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/terraform-provider-azapi/internal/azure/identity"
@@ -31,9 +30,13 @@ type AzapiResourceDataSourceModel struct {
 	ResourceID           types.String        `tfsdk:"resource_id"`
 	Type                 types.String        `tfsdk:"type"`
 	ResponseExportValues types.Dynamic       `tfsdk:"response_export_values"`
+	OutputSchema         types.Map           `tfsdk:"output_schema"`
 	Location             types.String        `tfsdk:"location"`
 	Identity             types.List          `tfsdk:"identity"`
 	Output               types.Dynamic       `tfsdk:"output"`
+	OutputFqdn           types.String        `tfsdk:"output_fqdn"`
+	OutputId             types.String        `tfsdk:"output_id"`
+	OutputName           types.String        `tfsdk:"output_name"`
 	Tags                 types.Map           `tfsdk:"tags"`
 	Timeouts             timeouts.Value      `tfsdk:"timeouts"`
 	Retry                retry.RetryValue    `tfsdk:"retry"`
@@ -60,7 +63,7 @@ func (r *AzapiResourceDataSource) Metadata(ctx context.Context, request datasour
 
 func (r *AzapiResourceDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
 	response.Schema = schema.Schema{
-		MarkdownDescription: "This resource can access any existing Azure resource manager resource.",
+		MarkdownDescription: "This data source can read any existing Azure resource manager resource, e.g. the resources which are managed outside Terraform. The exported properties of the resource are available in the `output`.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed:            true,
@@ -137,11 +140,28 @@ func (r *AzapiResourceDataSource) Schema(ctx context.Context, request datasource
 
 			"response_export_values": CommonAttributeResponseExportValues(),
 
+			"output_schema": CommonAttributeOutputSchema(),
+
 			"output": schema.DynamicAttribute{
 				Computed:            true,
 				MarkdownDescription: docstrings.Output("data.azapi_resource"),
 			},
 
+			"output_id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: docstrings.OutputId(),
+			},
+
+			"output_name": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: docstrings.OutputName(),
+			},
+
+			"output_fqdn": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: docstrings.OutputFqdn(),
+			},
+
 			"tags": schema.MapAttribute{
 				Computed:            true,
 				ElementType:         types.StringType,
@@ -181,7 +201,7 @@ func (r *AzapiResourceDataSource) Read(ctx context.Context, request datasource.R
 
 	model.Retry = model.Retry.AddDefaultValuesIfUnknownOrNull()
 
-	readTimeout, diags := model.Timeouts.Read(ctx, 5*time.Minute)
+	readTimeout, diags := model.Timeouts.Read(ctx, r.ProviderData.Features.DefaultReadTimeout)
 	response.Diagnostics.Append(diags...)
 	if response.Diagnostics.HasError() {
 		return
@@ -189,6 +209,10 @@ func (r *AzapiResourceDataSource) Read(ctx context.Context, request datasource.R
 
 	ctx, cancel := context.WithTimeout(ctx, readTimeout)
 	defer cancel()
+	ctx = clients.WithResponseCache(ctx)
+
+	ctx, deprecationNotices := clients.WithDeprecationNotices(ctx)
+	defer appendDeprecationWarnings(&response.Diagnostics, deprecationNotices)
 
 	var id parse.ResourceId
 	resourceType := model.Type.ValueString()
@@ -255,12 +279,13 @@ func (r *AzapiResourceDataSource) Read(ctx context.Context, request datasource.R
 		}
 	}
 
-	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues)
+	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues, model.OutputSchema)
 	if err != nil {
 		response.Diagnostics.AddError("Failed to build output", err.Error())
 		return
 	}
 	model.Output = output
+	model.OutputId, model.OutputName, model.OutputFqdn = flattenOutputShortcuts(responseBody)
 
 	response.Diagnostics.Append(response.State.Set(ctx, &model)...)
 }
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/terraform-provider-azapi/internal/azure/location"
+	"github.com/Azure/terraform-provider-azapi/internal/azure/tags"
+	"github.com/Azure/terraform-provider-azapi/internal/clients"
+	"github.com/Azure/terraform-provider-azapi/internal/docstrings"
+	"github.com/Azure/terraform-provider-azapi/internal/services/myvalidator"
+	"github.com/Azure/terraform-provider-azapi/internal/services/parse"
+	"github.com/Azure/terraform-provider-azapi/utils"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+type ResourceExistsDataSourceModel struct {
+	ID              types.String        `tfsdk:"id"`
+	Name            types.String        `tfsdk:"name"`
+	ParentID        types.String        `tfsdk:"parent_id"`
+	ResourceID      types.String        `tfsdk:"resource_id"`
+	Type            types.String        `tfsdk:"type"`
+	Exists          types.Bool          `tfsdk:"exists"`
+	Location        types.String        `tfsdk:"location"`
+	Tags            types.Map           `tfsdk:"tags"`
+	Timeouts        timeouts.Value      `tfsdk:"timeouts"`
+	Headers         map[string]string   `tfsdk:"headers"`
+	QueryParameters map[string][]string `tfsdk:"query_parameters"`
+}
+
+type ResourceExistsDataSource struct {
+	ProviderData *clients.Client
+}
+
+var _ datasource.DataSource = &ResourceExistsDataSource{}
+var _ datasource.DataSourceWithConfigure = &ResourceExistsDataSource{}
+
+func (r *ResourceExistsDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if v, ok := request.ProviderData.(*clients.Client); ok {
+		r.ProviderData = v
+	}
+}
+
+func (r *ResourceExistsDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_resource_exists"
+}
+
+func (r *ResourceExistsDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		MarkdownDescription: "This data source checks whether an Azure resource manager resource exists. Unlike the `azapi_resource` data source, it doesn't fail when the resource doesn't exist.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: docstrings.ID(),
+			},
+
+			"type": schema.StringAttribute{
+				Required: true,
+				Validators: []validator.String{
+					myvalidator.StringIsResourceType(),
+				},
+				MarkdownDescription: docstrings.Type(),
+			},
+
+			"name": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Validators: []validator.String{
+					myvalidator.StringIsNotEmpty(),
+				},
+				MarkdownDescription: "Specifies the name of the Azure resource.",
+			},
+
+			"parent_id": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Validators: []validator.String{
+					myvalidator.StringIsResourceID(),
+				},
+				MarkdownDescription: docstrings.ParentID(),
+			},
+
+			"resource_id": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Validators: []validator.String{
+					myvalidator.StringIsResourceID(),
+				},
+				MarkdownDescription: "The ID of the Azure resource to check.",
+			},
+
+			"exists": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether the Azure resource exists.",
+			},
+
+			"location": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The location of the Azure resource, it's null if the resource doesn't exist or it doesn't have a location.",
+			},
+
+			"tags": schema.MapAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "A mapping of tags which are assigned to the Azure resource, it's null if the resource doesn't exist.",
+			},
+
+			"headers": schema.MapAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				MarkdownDescription: "A map of headers to include in the request",
+			},
+
+			"query_parameters": schema.MapAttribute{
+				ElementType: types.ListType{
+					ElemType: types.StringType,
+				},
+				Optional:            true,
+				MarkdownDescription: "A map of query parameters to include in the request",
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Read: true,
+			}),
+		},
+	}
+}
+
+func (r *ResourceExistsDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var model ResourceExistsDataSourceModel
+	if response.Diagnostics.Append(request.Config.Get(ctx, &model)...); response.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := model.Timeouts.Read(ctx, r.ProviderData.Features.DefaultReadTimeout)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	ctx, deprecationNotices := clients.WithDeprecationNotices(ctx)
+	defer appendDeprecationWarnings(&response.Diagnostics, deprecationNotices)
+
+	var id parse.ResourceId
+	resourceType := model.Type.ValueString()
+	azureResourceType, _, _ := utils.GetAzureResourceTypeApiVersion(resourceType)
+	if name := model.Name.ValueString(); len(name) != 0 {
+		parentId := model.ParentID.ValueString()
+		if parentId == "" && strings.EqualFold(azureResourceType, arm.ResourceGroupResourceType.String()) {
+			parentId = fmt.Sprintf("/subscriptions/%s", r.ProviderData.Account.GetSubscriptionId())
+		}
+		buildId, err := parse.NewResourceID(name, parentId, resourceType)
+		if err != nil {
+			response.Diagnostics.AddError("Invalid configuration", err.Error())
+			return
+		}
+		id = buildId
+	} else {
+		buildId, err := parse.ResourceIDWithResourceType(model.ResourceID.ValueString(), resourceType)
+		if err != nil {
+			response.Diagnostics.AddError("Invalid configuration", err.Error())
+			return
+		}
+		id = buildId
+	}
+
+	model.ID = basetypes.NewStringValue(id.ID())
+	model.Name = basetypes.NewStringValue(id.Name)
+	model.ParentID = basetypes.NewStringValue(id.ParentId)
+	model.ResourceID = basetypes.NewStringValue(id.AzureResourceId)
+	model.Exists = basetypes.NewBoolValue(false)
+	model.Location = basetypes.NewStringNull()
+	model.Tags = basetypes.NewMapNull(types.StringType)
+
+	responseBody, err := r.ProviderData.ResourceClient.Get(ctx, id.AzureResourceId, id.ApiVersion, clients.NewRequestOptions(model.Headers, model.QueryParameters))
+	if err != nil {
+		if utils.ResponseErrorWasNotFound(err) {
+			response.Diagnostics.Append(response.State.Set(ctx, &model)...)
+			return
+		}
+		response.Diagnostics.AddError("Failed to retrieve resource", fmt.Errorf("retrieving resource %q: %+v", id, err).Error())
+		return
+	}
+
+	model.Exists = basetypes.NewBoolValue(true)
+	if bodyMap, ok := responseBody.(map[string]interface{}); ok {
+		model.Tags = tags.FlattenTags(bodyMap["tags"])
+		if v, ok := bodyMap["location"].(string); ok {
+			model.Location = basetypes.NewStringValue(location.Normalize(v))
+		}
+	}
+
+	response.Diagnostics.Append(response.State.Set(ctx, &model)...)
+}
@@ -0,0 +1,68 @@
+package services_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Azure/terraform-provider-azapi/internal/acceptance"
+	"github.com/Azure/terraform-provider-azapi/internal/acceptance/check"
+	"github.com/Azure/terraform-provider-azapi/internal/azure/location"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+type ResourceExistsDataSource struct{}
+
+func TestAccResourceExistsDataSource_basic(t *testing.T) {
+	data := acceptance.BuildTestData(t, "data.azapi_resource_exists", "test")
+	r := ResourceExistsDataSource{}
+
+	data.DataSourceTest(t, []resource.TestStep{
+		{
+			Config: r.basic(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Key("exists").HasValue("true"),
+				check.That(data.ResourceName).Key("location").HasValue(location.Normalize(data.LocationPrimary)),
+				check.That(data.ResourceName).Key("tags.%").HasValue("1"),
+			),
+		},
+	})
+}
+
+func TestAccResourceExistsDataSource_notExists(t *testing.T) {
+	data := acceptance.BuildTestData(t, "data.azapi_resource_exists", "test")
+	r := ResourceExistsDataSource{}
+
+	data.DataSourceTest(t, []resource.TestStep{
+		{
+			Config: r.notExists(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Key("exists").HasValue("false"),
+				check.That(data.ResourceName).Key("name").HasValue(fmt.Sprintf("acctest-notexist-%d", data.RandomInteger)),
+				check.That(data.ResourceName).Key("location").DoesNotExist(),
+			),
+		},
+	})
+}
+
+func (r ResourceExistsDataSource) basic(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+data "azapi_resource_exists" "test" {
+  type        = azapi_resource.test.type
+  resource_id = azapi_resource.test.id
+}
+`, GenericResource{}.complete(data))
+}
+
+func (r ResourceExistsDataSource) notExists(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+data "azapi_client_config" "current" {}
+
+data "azapi_resource_exists" "test" {
+  type      = "Microsoft.Resources/resourceGroups@2024-03-01"
+  parent_id = "/subscriptions/${data.azapi_client_config.current.subscription_id}"
+  name      = "acctest-notexist-%d"
+}
+`, data.RandomInteger)
+}
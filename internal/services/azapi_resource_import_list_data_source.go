@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/terraform-provider-azapi/internal/azure"
+	"github.com/Azure/terraform-provider-azapi/internal/clients"
+	"github.com/Azure/terraform-provider-azapi/internal/services/myvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// resourcesApiVersion is the api-version of the API which lists the resources in a subscription or a resource group
+const resourcesApiVersion = "2021-04-01"
+
+type ResourceImportListDataSourceModel struct {
+	ID        types.String                  `tfsdk:"id"`
+	ParentID  types.String                  `tfsdk:"parent_id"`
+	Types     types.List                    `tfsdk:"types"`
+	Resources []ResourceImportListItemModel `tfsdk:"resources"`
+	Timeouts  timeouts.Value                `tfsdk:"timeouts"`
+}
+
+type ResourceImportListItemModel struct {
+	ID               types.String `tfsdk:"id"`
+	Type             types.String `tfsdk:"type"`
+	ApiVersion       types.String `tfsdk:"api_version"`
+	SuggestedAddress types.String `tfsdk:"suggested_address"`
+}
+
+type ResourceImportListDataSource struct {
+	ProviderData *clients.Client
+}
+
+var _ datasource.DataSource = &ResourceImportListDataSource{}
+var _ datasource.DataSourceWithConfigure = &ResourceImportListDataSource{}
+
+func (r *ResourceImportListDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if v, ok := request.ProviderData.(*clients.Client); ok {
+		r.ProviderData = v
+	}
+}
+
+func (r *ResourceImportListDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_resource_import_list"
+}
+
+func (r *ResourceImportListDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		MarkdownDescription: "This data source lists the existing resources in a subscription or a resource group, which could be used by the `import` blocks with `for_each` to bring the existing resources under the management of the `azapi_resource` resources.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The URL which is used to list the resources.",
+			},
+
+			"parent_id": schema.StringAttribute{
+				Required: true,
+				Validators: []validator.String{
+					myvalidator.StringIsResourceID(),
+				},
+				MarkdownDescription: "The ID of the subscription or the resource group in which the resources are listed, e.g. `/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/example`.",
+			},
+
+			"types": schema.ListAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				MarkdownDescription: "A list of the Azure resource types without the api-versions, e.g. `Microsoft.Network/virtualNetworks`. Only the resources of these types are listed. If it's not specified, all the resources are listed.",
+			},
+
+			"resources": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "A list of the resources which could be imported, they're sorted by the IDs.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The ID of the Azure resource.",
+						},
+						"type": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The Azure resource type, e.g. `Microsoft.Network/virtualNetworks`.",
+						},
+						"api_version": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The latest stable api-version of the resource type which is supported by the provider, it's null if the resource type isn't supported.",
+						},
+						"suggested_address": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The suggested address of the `azapi_resource` resource, e.g. `azapi_resource.virtual_networks_example`. It's unique in the list.",
+						},
+					},
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Read: true,
+			}),
+		},
+	}
+}
+
+func (r *ResourceImportListDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var model ResourceImportListDataSourceModel
+	if response.Diagnostics.Append(request.Config.Get(ctx, &model)...); response.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := model.Timeouts.Read(ctx, r.ProviderData.Features.DefaultReadTimeout)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	parentId, err := arm.ParseResourceID(model.ParentID.ValueString())
+	if err != nil {
+		response.Diagnostics.AddError("Invalid configuration", fmt.Sprintf(`The argument "parent_id" is invalid: %s`, err.Error()))
+		return
+	}
+	if !strings.EqualFold(parentId.ResourceType.String(), arm.SubscriptionResourceType.String()) && !strings.EqualFold(parentId.ResourceType.String(), arm.ResourceGroupResourceType.String()) {
+		response.Diagnostics.AddError("Invalid configuration", fmt.Sprintf(`The argument "parent_id" is invalid: %q is neither a subscription nor a resource group`, model.ParentID.ValueString()))
+		return
+	}
+
+	listUrl := strings.TrimSuffix(model.ParentID.ValueString(), "/") + "/resources"
+	options := clients.DefaultRequestOptions()
+	if filter := resourceTypesFilter(AsStringList(model.Types)); filter != "" {
+		options = clients.NewRequestOptions(nil, map[string][]string{"$filter": {filter}})
+	}
+	responseBody, err := r.ProviderData.ResourceClient.List(ctx, listUrl, resourcesApiVersion, options)
+	if err != nil {
+		response.Diagnostics.AddError("Failed to list resources", fmt.Sprintf("Failed to list resources, url: %s, error: %s", listUrl, err.Error()))
+		return
+	}
+
+	model.ID = basetypes.NewStringValue(listUrl)
+	model.Resources = importListItems(responseBody)
+
+	response.Diagnostics.Append(response.State.Set(ctx, &model)...)
+}
+
+// resourceTypesFilter returns the OData filter which matches any of the resource types, it returns an empty string if there's no resource type.
+func resourceTypesFilter(resourceTypes []string) string {
+	conditions := make([]string, 0, len(resourceTypes))
+	for _, resourceType := range resourceTypes {
+		conditions = append(conditions, fmt.Sprintf("resourceType eq '%s'", strings.ReplaceAll(resourceType, "'", "''")))
+	}
+	return strings.Join(conditions, " or ")
+}
+
+// importListItems builds the import list from the response of the resources API, the suggested addresses are made unique by the numeric suffixes.
+func importListItems(responseBody interface{}) []ResourceImportListItemModel {
+	out := make([]ResourceImportListItemModel, 0)
+	responseMap, ok := responseBody.(map[string]interface{})
+	if !ok {
+		return out
+	}
+	values, ok := responseMap["value"].([]interface{})
+	if !ok {
+		return out
+	}
+
+	type resource struct {
+		id, resourceType, name string
+	}
+	resources := make([]resource, 0)
+	for _, value := range values {
+		valueMap, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := valueMap["id"].(string)
+		resourceType, _ := valueMap["type"].(string)
+		name, _ := valueMap["name"].(string)
+		if id == "" || resourceType == "" {
+			continue
+		}
+		resources = append(resources, resource{id: id, resourceType: resourceType, name: name})
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		return strings.ToLower(resources[i].id) < strings.ToLower(resources[j].id)
+	})
+
+	apiVersions := make(map[string]types.String)
+	addresses := make(map[string]bool)
+	for _, res := range resources {
+		key := strings.ToLower(res.resourceType)
+		if _, ok := apiVersions[key]; !ok {
+			apiVersions[key] = types.StringNull()
+			if v := latestStableApiVersion(azure.GetApiVersions(res.resourceType)); v != "" {
+				apiVersions[key] = types.StringValue(v)
+			}
+		}
+
+		address := suggestedAddress(res.resourceType, res.name)
+		for i := 2; addresses[address]; i++ {
+			address = fmt.Sprintf("%s_%d", suggestedAddress(res.resourceType, res.name), i)
+		}
+		addresses[address] = true
+
+		out = append(out, ResourceImportListItemModel{
+			ID:               types.StringValue(res.id),
+			Type:             types.StringValue(res.resourceType),
+			ApiVersion:       apiVersions[key],
+			SuggestedAddress: types.StringValue(address),
+		})
+	}
+	return out
+}
+
+// latestStableApiVersion returns the latest api-version which isn't a preview version, it falls back to the latest preview version.
+func latestStableApiVersion(apiVersions []string) string {
+	sorted := make([]string, len(apiVersions))
+	copy(sorted, apiVersions)
+	sort.Strings(sorted)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if !strings.Contains(strings.ToLower(sorted[i]), "preview") {
+			return sorted[i]
+		}
+	}
+	if len(sorted) != 0 {
+		return sorted[len(sorted)-1]
+	}
+	return ""
+}
+
+var (
+	camelCaseBoundaryRegex   = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+	invalidIdentifierRegex   = regexp.MustCompile(`[^a-z0-9_]+`)
+	duplicateUnderscoreRegex = regexp.MustCompile(`_{2,}`)
+)
+
+// suggestedAddress returns the address of the azapi_resource resource, which consists of the last segment of the resource type and the resource name in snake case,
+// e.g. `azapi_resource.virtual_networks_example` for the virtual network `example`.
+func suggestedAddress(resourceType string, name string) string {
+	typeName := resourceType[strings.LastIndex(resourceType, "/")+1:]
+	identifier := strings.ToLower(camelCaseBoundaryRegex.ReplaceAllString(typeName+"_"+name, "${1}_${2}"))
+	identifier = invalidIdentifierRegex.ReplaceAllString(identifier, "_")
+	identifier = strings.Trim(duplicateUnderscoreRegex.ReplaceAllString(identifier, "_"), "_")
+	if identifier == "" || (identifier[0] >= '0' && identifier[0] <= '9') {
+		identifier = "_" + identifier
+	}
+	return "azapi_resource." + identifier
+}
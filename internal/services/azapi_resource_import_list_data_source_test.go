@@ -0,0 +1,42 @@
+package services_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Azure/terraform-provider-azapi/internal/acceptance"
+	"github.com/Azure/terraform-provider-azapi/internal/acceptance/check"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+type ResourceImportListDataSource struct{}
+
+func TestAccResourceImportListDataSource_basic(t *testing.T) {
+	data := acceptance.BuildTestData(t, "data.azapi_resource_import_list", "test")
+	r := ResourceImportListDataSource{}
+
+	data.DataSourceTest(t, []resource.TestStep{
+		{
+			Config: r.basic(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Key("resources.#").HasValue("1"),
+				check.That(data.ResourceName).Key("resources.0.type").HasValue("Microsoft.Automation/automationAccounts"),
+				check.That(data.ResourceName).Key("resources.0.suggested_address").HasValue("azapi_resource.automation_accounts_acctest"+data.RandomString),
+				check.That(data.ResourceName).Key("resources.0.api_version").Exists(),
+			),
+		},
+	})
+}
+
+func (r ResourceImportListDataSource) basic(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+data "azapi_resource_import_list" "test" {
+  parent_id = azapi_resource.test.parent_id
+  types     = ["Microsoft.Automation/automationAccounts"]
+
+  depends_on = [azapi_resource.test]
+}
+`, GenericResource{}.complete(data))
+}
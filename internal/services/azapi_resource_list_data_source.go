@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/Azure/terraform-provider-azapi/internal/clients"
 	"github.com/Azure/terraform-provider-azapi/internal/docstrings"
@@ -12,6 +11,7 @@ import (
 	"github.com/Azure/terraform-provider-azapi/internal/services/myvalidator"
 	"github.com/Azure/terraform-provider-azapi/internal/services/parse"
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -24,11 +24,13 @@ type ResourceListDataSourceModel struct {
 	Type                 types.String        `tfsdk:"type"`
 	ParentID             types.String        `tfsdk:"parent_id"`
 	ResponseExportValues types.Dynamic       `tfsdk:"response_export_values"`
+	OutputSchema         types.Map           `tfsdk:"output_schema"`
 	Output               types.Dynamic       `tfsdk:"output"`
 	Timeouts             timeouts.Value      `tfsdk:"timeouts"`
 	Retry                retry.RetryValue    `tfsdk:"retry"`
 	Headers              map[string]string   `tfsdk:"headers"`
 	QueryParameters      map[string][]string `tfsdk:"query_parameters"`
+	PageFetchConcurrency types.Int64         `tfsdk:"page_fetch_concurrency"`
 }
 
 type ResourceListDataSource struct {
@@ -74,6 +76,8 @@ func (r *ResourceListDataSource) Schema(ctx context.Context, request datasource.
 
 			"response_export_values": CommonAttributeResponseExportValues(),
 
+			"output_schema": CommonAttributeOutputSchema(),
+
 			"output": schema.DynamicAttribute{
 				Computed:            true,
 				MarkdownDescription: docstrings.Output("data.azapi_resource_list"),
@@ -94,6 +98,14 @@ func (r *ResourceListDataSource) Schema(ctx context.Context, request datasource.
 				Optional:            true,
 				MarkdownDescription: "A map of query parameters to include in the request",
 			},
+
+			"page_fetch_concurrency": schema.Int64Attribute{
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 16),
+				},
+				MarkdownDescription: "The number of pages which are fetched concurrently when the `nextLink` of the list response has a numeric `$skip` or `$skipToken` offset, e.g. the role assignments. The following pages are requested ahead by increasing the offset and are reassembled in order. The pages whose `nextLink` has an opaque continuation token are always fetched one by one. Defaults to `1`, which fetches the pages sequentially.",
+			},
 		},
 
 		Blocks: map[string]schema.Block{
@@ -112,7 +124,7 @@ func (r *ResourceListDataSource) Read(ctx context.Context, request datasource.Re
 
 	model.Retry = model.Retry.AddDefaultValuesIfUnknownOrNull()
 
-	readTimeout, diags := model.Timeouts.Read(ctx, 5*time.Minute)
+	readTimeout, diags := model.Timeouts.Read(ctx, r.ProviderData.Features.DefaultReadTimeout)
 	response.Diagnostics.Append(diags...)
 	if response.Diagnostics.HasError() {
 		return
@@ -120,6 +132,10 @@ func (r *ResourceListDataSource) Read(ctx context.Context, request datasource.Re
 
 	ctx, cancel := context.WithTimeout(ctx, readTimeout)
 	defer cancel()
+	ctx = clients.WithResponseCache(ctx)
+
+	ctx, deprecationNotices := clients.WithDeprecationNotices(ctx)
+	defer appendDeprecationWarnings(&response.Diagnostics, deprecationNotices)
 
 	id, err := parse.NewResourceIDSkipScopeValidation("", model.ParentID.ValueString(), model.Type.ValueString())
 	if err != nil {
@@ -142,7 +158,8 @@ func (r *ResourceListDataSource) Read(ctx context.Context, request datasource.Re
 		client = r.ProviderData.ResourceClient.WithRetry(bkof, regexps)
 	}
 
-	responseBody, err := client.List(ctx, listUrl, id.ApiVersion, clients.NewRequestOptions(model.Headers, model.QueryParameters))
+	options := clients.NewRequestOptions(model.Headers, model.QueryParameters).WithPageConcurrency(int(model.PageFetchConcurrency.ValueInt64()))
+	responseBody, err := client.List(ctx, listUrl, id.ApiVersion, options)
 	if err != nil {
 		response.Diagnostics.AddError("Failed to list resources", fmt.Sprintf("Failed to list resources, url: %s, error: %s", listUrl, err.Error()))
 		return
@@ -150,7 +167,7 @@ func (r *ResourceListDataSource) Read(ctx context.Context, request datasource.Re
 
 	model.ID = basetypes.NewStringValue(listUrl)
 
-	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues)
+	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues, model.OutputSchema)
 	if err != nil {
 		response.Diagnostics.AddError("Failed to build output", err.Error())
 		return
@@ -90,6 +90,18 @@ func TestAccGenericResource_preflightWithIdentity(t *testing.T) {
 	})
 }
 
+func TestAccGenericResource_preflightNameNotAvailable(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_resource", "test")
+	r := GenericResource{}
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config:      r.preflightNameNotAvailable(data),
+			PlanOnly:    true,
+			ExpectError: regexp.MustCompile("is not available"),
+		},
+	})
+}
+
 func (r GenericResource) preflightMockPropertyValue(data acceptance.TestData) string {
 	return fmt.Sprintf(`
 provider "azapi" {
@@ -206,3 +218,26 @@ resource "azapi_resource" "aksCluster" {
 }
 `, r.template(data))
 }
+
+func (r GenericResource) preflightNameNotAvailable(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+provider "azapi" {
+  enable_preflight = true
+}
+
+%[1]s
+
+resource "azapi_resource" "storageAccount" {
+  type      = "Microsoft.Storage/storageAccounts@2023-05-01"
+  parent_id = azapi_resource.resourceGroup.id
+  name      = "storage"
+  location  = "westus"
+  body = {
+    kind = "StorageV2"
+    sku = {
+      name = "Standard_LRS"
+    }
+  }
+}
+`, r.template(data))
+}
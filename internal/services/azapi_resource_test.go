@@ -213,6 +213,32 @@ func TestAccGenericResource_defaultTags(t *testing.T) {
 	})
 }
 
+func TestAccGenericResource_mergeDefaultTags(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_resource", "test")
+	r := GenericResource{}
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			// the default tags are sent in the body but aren't read back into the tags argument
+			Config: r.mergeDefaultTagsInHcl(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("tags.%").HasValue("2"),
+				check.That(data.ResourceName).Key("tags.key").HasValue("override"),
+				check.That(data.ResourceName).Key("tags.owner").HasValue("team1"),
+			),
+		},
+		{
+			Config: r.mergeDefaultTagsInBody(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("tags.%").HasValue("3"),
+				check.That(data.ResourceName).Key("tags.key").HasValue("override"),
+				check.That(data.ResourceName).Key("tags.costCenter").HasValue("1234"),
+			),
+		},
+	})
+}
+
 func TestAccGenericResource_defaultsNotApplicable(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azapi_resource", "test")
 	r := GenericResource{}
@@ -544,6 +570,118 @@ func TestAccGenericResource_replaceTriggersRefs(t *testing.T) {
 	})
 }
 
+func TestAccGenericResource_bodyFragments(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_resource", "test")
+	r := GenericResource{}
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.bodyFragments(data, false),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStepWithImportStateIdFunc(r.ImportIdFunc, append(defaultIgnores(), "body_fragments")...),
+		{
+			Config: r.bodyFragments(data, true),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStepWithImportStateIdFunc(r.ImportIdFunc, append(defaultIgnores(), "body_fragments")...),
+	})
+}
+
+func TestAccGenericResource_updateMethodPatch(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_resource", "test")
+	r := GenericResource{}
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.updateMethodPatch(data, false),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("update_method").HasValue("PATCH"),
+			),
+		},
+		data.ImportStepWithImportStateIdFunc(r.ImportIdFunc, append(defaultIgnores(), "update_method")...),
+		{
+			Config: r.updateMethodPatch(data, true),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStepWithImportStateIdFunc(r.ImportIdFunc, append(defaultIgnores(), "update_method")...),
+	})
+}
+
+func TestAccGenericResource_bodyFile(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_resource", "test")
+	r := GenericResource{}
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.bodyFile(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("body_file_hash").Exists(),
+			),
+		},
+		data.ImportStepWithImportStateIdFunc(r.ImportIdFunc, append(defaultIgnores(), "body_file", "body_file_hash")...),
+	})
+}
+
+func TestAccGenericResource_previousBody(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_resource", "test")
+	r := GenericResource{}
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.previousBody(data, "Basic"),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("previous_body").DoesNotExist(),
+			),
+		},
+		{
+			Config: r.previousBody(data, "Free"),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("previous_body.properties.sku.name").HasValue("Basic"),
+			),
+		},
+		data.ImportStepWithImportStateIdFunc(r.ImportIdFunc, append(defaultIgnores(), "previous_body")...),
+	})
+}
+
+func TestAccGenericResource_existenceCheckMethodHead(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_resource", "test")
+	r := GenericResource{}
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.existenceCheckMethodHead(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("existence_check_method").HasValue("HEAD"),
+			),
+		},
+		data.ImportStepWithImportStateIdFunc(r.ImportIdFunc, append(defaultIgnores(), "existence_check_method")...),
+	})
+}
+
+func TestAccGenericResource_outputShortcuts(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_resource", "test")
+	r := GenericResource{}
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.outputShortcuts(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("output_id").IsSet(),
+				check.That(data.ResourceName).Key("output_name").HasValue(fmt.Sprintf("acctest%s", data.RandomString)),
+				check.That(data.ResourceName).Key("output_fqdn").HasValue(fmt.Sprintf("acctest%s.%s.cloudapp.azure.com", data.RandomString, location.Normalize(data.LocationPrimary))),
+			),
+		},
+		data.ImportStepWithImportStateIdFunc(r.ImportIdFunc, defaultIgnores()...),
+	})
+}
+
 func (GenericResource) Exists(ctx context.Context, client *clients.Client, state *terraform.InstanceState) (*bool, error) {
 	resourceType := state.Attributes["type"]
 	id, err := parse.ResourceIDWithResourceType(state.ID, resourceType)
@@ -980,6 +1118,69 @@ resource "azapi_resource" "test" {
 `, r.template(data), data.RandomString, data.LocationPrimary)
 }
 
+func (r GenericResource) mergeDefaultTagsInHcl(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+provider "azapi" {
+  merge_default_tags = true
+  default_tags = {
+    key        = "default"
+    costCenter = "1234"
+  }
+}
+
+resource "azapi_resource" "test" {
+  type      = "Microsoft.Automation/automationAccounts@2023-11-01"
+  name      = "acctest%[2]s"
+  parent_id = azapi_resource.resourceGroup.id
+  location  = azapi_resource.resourceGroup.location
+  body = {
+    properties = {
+      sku = {
+        name = "Basic"
+      }
+    }
+  }
+
+  tags = {
+    key   = "override"
+    owner = "team1"
+  }
+}
+`, r.template(data), data.RandomString)
+}
+
+func (r GenericResource) mergeDefaultTagsInBody(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+provider "azapi" {
+  merge_default_tags = true
+  default_tags = {
+    key        = "default"
+    costCenter = "1234"
+  }
+}
+
+resource "azapi_resource" "test" {
+  type      = "Microsoft.Automation/automationAccounts@2023-11-01"
+  name      = "acctest%[2]s"
+  parent_id = azapi_resource.resourceGroup.id
+  location  = azapi_resource.resourceGroup.location
+  body = {
+    properties = {
+      sku = {
+        name = "Basic"
+      }
+    }
+    tags = {
+      key   = "override"
+      owner = "team1"
+    }
+  }
+}
+`, r.template(data), data.RandomString)
+}
+
 func (r GenericResource) defaultTagOverrideInHcl(data acceptance.TestData) string {
 	return fmt.Sprintf(`
 %s
@@ -1130,6 +1331,32 @@ resource "azapi_resource" "test" {
 `, r.template(data))
 }
 
+func (r GenericResource) defaultNamingPrefixNotApplied(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+provider "azapi" {
+  default_name          = "acctestdefaultNaming"
+  default_naming_prefix = "contoso-"
+  default_naming_suffix = "-prod"
+}
+
+resource "azapi_resource" "test" {
+  type                 = "Microsoft.Automation/automationAccounts@2023-11-01"
+  name                 = "hclNaming"
+  parent_id            = azapi_resource.resourceGroup.id
+  location             = azapi_resource.resourceGroup.location
+  apply_default_naming = false
+  body = {
+    properties = {
+      sku = {
+        name = "Basic"
+      }
+    }
+  }
+}
+`, r.template(data))
+}
+
 func (r GenericResource) defaultsNotApplicable(data acceptance.TestData) string {
 	return fmt.Sprintf(`
 %s
@@ -1936,3 +2163,141 @@ resource "azapi_resource" "test" {
 }
 `, r.template(data), data.RandomInteger, skuName)
 }
+
+func (r GenericResource) bodyFragments(data acceptance.TestData, publicNetworkAccess bool) string {
+	return fmt.Sprintf(`
+%s
+
+resource "azapi_resource" "test" {
+  type      = "Microsoft.Automation/automationAccounts@2023-11-01"
+  name      = "acctest%[2]s"
+  parent_id = azapi_resource.resourceGroup.id
+  location  = azapi_resource.resourceGroup.location
+  body = {
+    properties = {
+      sku = {
+        name = "Basic"
+      }
+      publicNetworkAccess = false
+    }
+  }
+  body_fragments = [
+    {
+      properties = {
+        disableLocalAuth = true
+      }
+    },
+    {
+      properties = {
+        publicNetworkAccess = %[3]t
+      }
+    },
+  ]
+}
+`, r.template(data), data.RandomString, publicNetworkAccess)
+}
+
+func (r GenericResource) bodyFile(data acceptance.TestData) string {
+	bodyFile, _ := filepath.Abs(filepath.Join("testdata", "automation_account_body.json"))
+	return fmt.Sprintf(`
+%s
+
+resource "azapi_resource" "test" {
+  type      = "Microsoft.Automation/automationAccounts@2023-11-01"
+  name      = "acctest%[2]s"
+  parent_id = azapi_resource.resourceGroup.id
+  location  = azapi_resource.resourceGroup.location
+  body_file = "%[3]s"
+  body_vars = {
+    sku_name = "Basic"
+  }
+}
+`, r.template(data), data.RandomString, filepath.ToSlash(bodyFile))
+}
+
+func (r GenericResource) previousBody(data acceptance.TestData, skuName string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "azapi_resource" "test" {
+  type      = "Microsoft.Automation/automationAccounts@2023-11-01"
+  name      = "acctest%[2]s"
+  parent_id = azapi_resource.resourceGroup.id
+  location  = azapi_resource.resourceGroup.location
+  body = {
+    properties = {
+      sku = {
+        name = "%[3]s"
+      }
+    }
+  }
+}
+`, r.template(data), data.RandomString, skuName)
+}
+
+func (r GenericResource) existenceCheckMethodHead(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+resource "azapi_resource" "test" {
+  type                   = "Microsoft.Automation/automationAccounts@2023-11-01"
+  name                   = "acctest%[2]s"
+  parent_id              = azapi_resource.resourceGroup.id
+  location               = azapi_resource.resourceGroup.location
+  existence_check_method = "HEAD"
+  body = {
+    properties = {
+      sku = {
+        name = "Basic"
+      }
+    }
+  }
+}
+`, r.template(data), data.RandomString)
+}
+
+func (r GenericResource) outputShortcuts(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+resource "azapi_resource" "test" {
+  type      = "Microsoft.Network/publicIPAddresses@2023-04-01"
+  name      = "acctest%[2]s"
+  parent_id = azapi_resource.resourceGroup.id
+  location  = azapi_resource.resourceGroup.location
+  body = {
+    sku = {
+      name = "Standard"
+    }
+    properties = {
+      publicIPAllocationMethod = "Static"
+      dnsSettings = {
+        domainNameLabel = "acctest%[2]s"
+      }
+    }
+  }
+}
+`, r.template(data), data.RandomString)
+}
+
+func (r GenericResource) updateMethodPatch(data acceptance.TestData, publicNetworkAccess bool) string {
+	return fmt.Sprintf(`
+%s
+
+resource "azapi_resource" "test" {
+  type          = "Microsoft.Automation/automationAccounts@2023-11-01"
+  name          = "acctest%[2]s"
+  parent_id     = azapi_resource.resourceGroup.id
+  location      = azapi_resource.resourceGroup.location
+  update_method = "PATCH"
+  body = {
+    properties = {
+      sku = {
+        name = "Basic"
+      }
+      publicNetworkAccess = %[3]t
+    }
+  }
+}
+`, r.template(data), data.RandomString, publicNetworkAccess)
+}
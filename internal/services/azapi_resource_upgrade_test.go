@@ -265,6 +265,28 @@ func TestAccAzapiResourceUpgrade_defaultNamingOverrideInHcl(t *testing.T) {
 	})
 }
 
+func TestAccAzapiResourceUpgrade_applyDefaultNaming(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_resource", "test")
+	r := GenericResource{}
+
+	data.UpgradeTest(t, r, []resource.TestStep{
+		data.UpgradeTestDeployStep(resource.TestStep{
+			Config: r.defaultNamingOverrideInHcl(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		}, PreviousVersion),
+		// the migrated state has the apply_default_naming set to true, so the unchanged config has no diff
+		data.UpgradeTestPlanStep(resource.TestStep{
+			Config: r.defaultNamingOverrideInHcl(data),
+		}),
+		// the existing resource opts out of the default naming prefix and suffix which are introduced to the provider
+		data.UpgradeTestPlanStep(resource.TestStep{
+			Config: r.defaultNamingPrefixNotApplied(data),
+		}),
+	})
+}
+
 func TestAccAzapiResourceUpgrade_defaultsNotApplicable(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azapi_resource", "test")
 	r := GenericResource{}
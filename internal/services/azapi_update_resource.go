@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"time"
 
+	aztypes "github.com/Azure/terraform-provider-azapi/internal/azure/types"
 	"github.com/Azure/terraform-provider-azapi/internal/clients"
 	"github.com/Azure/terraform-provider-azapi/internal/docstrings"
+	"github.com/Azure/terraform-provider-azapi/internal/guardrail"
 	"github.com/Azure/terraform-provider-azapi/internal/locks"
 	"github.com/Azure/terraform-provider-azapi/internal/retry"
 	"github.com/Azure/terraform-provider-azapi/internal/services/defaults"
@@ -19,6 +21,7 @@ import (
 	"github.com/Azure/terraform-provider-azapi/utils"
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -41,10 +44,12 @@ type AzapiUpdateResourceModel struct {
 	IgnoreCasing          types.Bool          `tfsdk:"ignore_casing"`
 	IgnoreMissingProperty types.Bool          `tfsdk:"ignore_missing_property"`
 	ResponseExportValues  types.Dynamic       `tfsdk:"response_export_values"`
+	OutputSchema          types.Map           `tfsdk:"output_schema"`
 	Locks                 types.List          `tfsdk:"locks"`
 	Output                types.Dynamic       `tfsdk:"output"`
 	Timeouts              timeouts.Value      `tfsdk:"timeouts"`
 	Retry                 retry.RetryValue    `tfsdk:"retry"`
+	RestoreOnDestroy      types.Bool          `tfsdk:"restore_on_destroy"`
 	UpdateHeaders         map[string]string   `tfsdk:"update_headers"`
 	UpdateQueryParameters map[string][]string `tfsdk:"update_query_parameters"`
 	ReadHeaders           map[string]string   `tfsdk:"read_headers"`
@@ -60,6 +65,7 @@ var _ resource.ResourceWithConfigure = &AzapiUpdateResource{}
 var _ resource.ResourceWithValidateConfig = &AzapiUpdateResource{}
 var _ resource.ResourceWithModifyPlan = &AzapiUpdateResource{}
 var _ resource.ResourceWithUpgradeState = &AzapiUpdateResource{}
+var _ resource.ResourceWithImportState = &AzapiUpdateResource{}
 
 func (r *AzapiUpdateResource) Configure(ctx context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
 	if v, ok := request.ProviderData.(*clients.Client); ok {
@@ -81,7 +87,7 @@ func (r *AzapiUpdateResource) UpgradeState(ctx context.Context) map[int64]resour
 func (r *AzapiUpdateResource) Schema(ctx context.Context, request resource.SchemaRequest, response *resource.SchemaResponse) {
 	response.Schema = schema.Schema{
 		MarkdownDescription: "This resource can manage a subset of any existing Azure resource manager resource's properties.\n\n" +
-			"-> **Note** This resource is used to add or modify properties on an existing resource. When delete `azapi_update_resource`, no operation will be performed, and these properties will stay unchanged. If you want to restore the modified properties to some values, you must apply the restored properties before deleting.",
+			"-> **Note** This resource is used to add or modify properties on an existing resource. When delete `azapi_update_resource`, no operation will be performed, and these properties will stay unchanged, unless the `restore_on_destroy` is `true`. If you want to restore the modified properties to some other values, you must apply the restored properties before deleting.",
 		Description: "This resource can manage a subset of any existing Azure resource manager resource's properties.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -145,6 +151,9 @@ func (r *AzapiUpdateResource) Schema(ctx context.Context, request resource.Schem
 				PlanModifiers: []planmodifier.Dynamic{
 					myplanmodifier.DynamicUseStateWhen(dynamic.SemanticallyEqual),
 				},
+				Validators: []validator.Dynamic{
+					myvalidator.BodyIsObject(),
+				},
 				MarkdownDescription: docstrings.Body(),
 			},
 
@@ -164,6 +173,8 @@ func (r *AzapiUpdateResource) Schema(ctx context.Context, request resource.Schem
 
 			"response_export_values": CommonAttributeResponseExportValues(),
 
+			"output_schema": CommonAttributeOutputSchema(),
+
 			"locks": schema.ListAttribute{
 				ElementType: types.StringType,
 				Optional:    true,
@@ -180,6 +191,13 @@ func (r *AzapiUpdateResource) Schema(ctx context.Context, request resource.Schem
 
 			"retry": retry.SingleNestedAttribute(ctx),
 
+			"restore_on_destroy": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             defaults.BoolDefault(false),
+				MarkdownDescription: "Whether the properties in the `body` are restored to their original values when the resource is destroyed. The original values are recorded when the properties are added to the `body`, the properties which didn't exist are set to `null`. Defaults to `false`.",
+			},
+
 			"update_headers": schema.MapAttribute{
 				ElementType:         types.StringType,
 				Optional:            true,
@@ -277,7 +295,48 @@ func (r *AzapiUpdateResource) ModifyPlan(ctx context.Context, request resource.M
 		return
 	}
 
-	if state == nil || !plan.ResponseExportValues.Equal(state.ResponseExportValues) || !dynamic.SemanticallyEqual(plan.Body, state.Body) {
+	response.Diagnostics.Append(validateOutputSchema(config.ResponseExportValues, config.OutputSchema)...)
+
+	if err := validateRequestBodySize(plan.Body); err != nil {
+		response.Diagnostics.AddError("Invalid body", fmt.Sprintf(`The argument "body" is invalid: %s`, err.Error()))
+		return
+	}
+
+	response.Diagnostics.Append(subscriptionMismatchWarning("parent_id", config.ParentID, r.ProviderData)...)
+	response.Diagnostics.Append(subscriptionMismatchWarning("resource_id", config.ResourceID, r.ProviderData)...)
+	if dynamic.IsFullyKnown(plan.Body) {
+		var body interface{}
+		if err := unmarshalBody(plan.Body, &body); err != nil {
+			response.Diagnostics.AddError("Invalid body", fmt.Sprintf(`The argument "body" is invalid: %s`, err.Error()))
+			return
+		}
+		response.Diagnostics.Append(cloudEndpointDiagnostics(r.ProviderData.Environment, "body", body)...)
+	}
+
+	if r.ProviderData.Guardrails != nil && dynamic.IsFullyKnown(plan.Body) {
+		var body interface{}
+		if err := unmarshalBody(plan.Body, &body); err != nil {
+			response.Diagnostics.AddError("Invalid body", fmt.Sprintf(`The argument "body" is invalid: %s`, err.Error()))
+			return
+		}
+		azureResourceType, apiVersion, err := utils.GetAzureResourceTypeApiVersion(plan.Type.ValueString())
+		if err != nil {
+			response.Diagnostics.AddError("Invalid configuration", fmt.Sprintf(`The argument "type" is invalid: %s`, err.Error()))
+			return
+		}
+		response.Diagnostics.Append(guardrailDiagnostics(r.ProviderData.Guardrails, guardrail.Input{
+			Type:       azureResourceType,
+			ApiVersion: apiVersion,
+			Name:       plan.Name.ValueString(),
+			ParentId:   plan.ParentID.ValueString(),
+			Body:       body,
+		})...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if state == nil || !plan.ResponseExportValues.Equal(state.ResponseExportValues) || !plan.OutputSchema.Equal(state.OutputSchema) || !dynamic.SemanticallyEqual(plan.Body, state.Body) {
 		plan.Output = basetypes.NewDynamicUnknown()
 	} else {
 		plan.Output = state.Output
@@ -287,14 +346,17 @@ func (r *AzapiUpdateResource) ModifyPlan(ctx context.Context, request resource.M
 }
 
 func (r *AzapiUpdateResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
-	r.CreateUpdate(ctx, request.Plan, &response.State, &response.Diagnostics)
+	r.CreateUpdate(ctx, request.Plan, &response.State, response.Private, &response.Diagnostics)
 }
 
 func (r *AzapiUpdateResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
-	r.CreateUpdate(ctx, request.Plan, &response.State, &response.Diagnostics)
+	r.CreateUpdate(ctx, request.Plan, &response.State, response.Private, &response.Diagnostics)
 }
 
-func (r *AzapiUpdateResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan, state *tfsdk.State, diagnostics *diag.Diagnostics) {
+// originalBodyKey is the private state key of the original values of the properties in the body, which are restored on destroy.
+const originalBodyKey = "original_body"
+
+func (r *AzapiUpdateResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan, state *tfsdk.State, private privateState, diagnostics *diag.Diagnostics) {
 	var model AzapiUpdateResourceModel
 	if diagnostics.Append(plan.Get(ctx, &model)...); diagnostics.HasError() {
 		return
@@ -305,12 +367,12 @@ func (r *AzapiUpdateResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan,
 	var timeout time.Duration
 	var diags diag.Diagnostics
 	if isNewResource {
-		timeout, diags = model.Timeouts.Create(ctx, 30*time.Minute)
+		timeout, diags = model.Timeouts.Create(ctx, r.ProviderData.Features.DefaultCreateTimeout)
 		if diagnostics.Append(diags...); diagnostics.HasError() {
 			return
 		}
 	} else {
-		timeout, diags = model.Timeouts.Update(ctx, 30*time.Minute)
+		timeout, diags = model.Timeouts.Update(ctx, r.ProviderData.Features.DefaultUpdateTimeout)
 		if diagnostics.Append(diags...); diagnostics.HasError() {
 			return
 		}
@@ -318,6 +380,9 @@ func (r *AzapiUpdateResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan,
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	ctx, deprecationNotices := clients.WithDeprecationNotices(ctx)
+	defer appendDeprecationWarnings(diagnostics, deprecationNotices)
+
 	var id parse.ResourceId
 	// We need to ensure that the ID parsed in create and update is the same to produce consistent results.
 	// In update, all these fields are set, using resource_id and type is able to parse the parent_id and name which are used to build it.
@@ -338,6 +403,10 @@ func (r *AzapiUpdateResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan,
 		id = buildId
 	}
 
+	if diagnostics.Append(readOnlyModeDiagnostics(r.ProviderData.Features, "Updating", id.ID())...); diagnostics.HasError() {
+		return
+	}
+
 	var client clients.Requester
 	client = r.ProviderData.ResourceClient
 	if !model.Retry.IsNull() && !model.Retry.IsUnknown() {
@@ -366,6 +435,20 @@ func (r *AzapiUpdateResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan,
 		return
 	}
 
+	// the values which are recorded earlier are kept, because the existing values of their properties have been updated
+	var originalBody interface{}
+	storedOriginalBody, diags := private.GetKey(ctx, originalBodyKey)
+	if diagnostics.Append(diags...); diagnostics.HasError() {
+		return
+	}
+	if len(storedOriginalBody) != 0 {
+		if err := json.Unmarshal(storedOriginalBody, &originalBody); err != nil {
+			diagnostics.AddError("Invalid private state", fmt.Sprintf("The original values of the properties are invalid: %+v", err))
+			return
+		}
+	}
+	originalBody = mergeOriginalValues(originalBody, originalValues(requestBody, existing))
+
 	requestBody = utils.MergeObject(existing, requestBody)
 
 	if id.ResourceDef != nil {
@@ -379,7 +462,20 @@ func (r *AzapiUpdateResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan,
 
 	_, err = client.CreateOrUpdate(ctx, id.AzureResourceId, id.ApiVersion, requestBody, clients.NewRequestOptions(model.UpdateHeaders, model.UpdateQueryParameters))
 	if err != nil {
-		diagnostics.AddError("Failed to update resource", fmt.Errorf("updating %q: %+v", id, err).Error())
+		diagnostics.Append(recordInterruptedOperation(ctx, private, err)...)
+		diagnostics.AddError(operationErrorSummary(err, "Failed to update resource"), fmt.Errorf("updating %q: %+v", id, err).Error())
+		return
+	}
+	if diagnostics.Append(private.SetKey(ctx, interruptedOperationKey, nil)...); diagnostics.HasError() {
+		return
+	}
+
+	if data, err := json.Marshal(originalBody); err == nil {
+		if diagnostics.Append(private.SetKey(ctx, originalBodyKey, data)...); diagnostics.HasError() {
+			return
+		}
+	}
+	if diagnostics.Append(writeResourceMetadata(ctx, private, newResourceMetadata(r.ProviderData, id.AzureResourceType, id.ApiVersion))...); diagnostics.HasError() {
 		return
 	}
 
@@ -399,7 +495,7 @@ func (r *AzapiUpdateResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan,
 	model.ParentID = basetypes.NewStringValue(id.ParentId)
 	model.ResourceID = basetypes.NewStringValue(id.AzureResourceId)
 
-	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues)
+	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues, model.OutputSchema)
 	if err != nil {
 		diagnostics.AddError("Failed to build output", err.Error())
 		return
@@ -407,6 +503,12 @@ func (r *AzapiUpdateResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan,
 	model.Output = output
 
 	diagnostics.Append(state.Set(ctx, model)...)
+
+	if r.ProviderData.Features.FailOnFailedProvisioningState {
+		if err := provisioningStateFailure(responseBody); err != nil {
+			diagnostics.AddError("Failed to update resource", fmt.Errorf("updating %s: %+v", id, err).Error())
+		}
+	}
 }
 
 func (r *AzapiUpdateResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
@@ -415,7 +517,7 @@ func (r *AzapiUpdateResource) Read(ctx context.Context, request resource.ReadReq
 		return
 	}
 
-	readTimeout, diags := model.Timeouts.Read(ctx, 5*time.Minute)
+	readTimeout, diags := model.Timeouts.Read(ctx, r.ProviderData.Features.DefaultReadTimeout)
 	response.Diagnostics.Append(diags...)
 	if response.Diagnostics.HasError() {
 		return
@@ -424,11 +526,15 @@ func (r *AzapiUpdateResource) Read(ctx context.Context, request resource.ReadReq
 	ctx, cancel := context.WithTimeout(ctx, readTimeout)
 	defer cancel()
 
+	ctx, deprecationNotices := clients.WithDeprecationNotices(ctx)
+	defer appendDeprecationWarnings(&response.Diagnostics, deprecationNotices)
+
 	id, err := parse.ResourceIDWithResourceType(model.ID.ValueString(), model.Type.ValueString())
 	if err != nil {
 		response.Diagnostics.AddError("Invalid resource id", err.Error())
 		return
 	}
+	response.Diagnostics.Append(interruptedOperationWarning(ctx, request.Private, id.ID())...)
 
 	var client clients.Requester
 	client = r.ProviderData.ResourceClient
@@ -450,6 +556,10 @@ func (r *AzapiUpdateResource) Read(ctx context.Context, request resource.ReadReq
 			response.State.RemoveResource(ctx)
 			return
 		}
+		if utils.ResponseErrorWasThrottled(err) {
+			response.Diagnostics.AddWarning("Resource refresh is throttled", fmt.Sprintf("reading %s: the request is still throttled after retrying, the previous state is kept: %+v", id, err))
+			return
+		}
 		response.Diagnostics.AddError("Failed to retrieve resource", fmt.Errorf("reading %q: %+v", id, err).Error())
 		return
 	}
@@ -479,7 +589,7 @@ func (r *AzapiUpdateResource) Read(ctx context.Context, request resource.ReadReq
 		return
 	}
 
-	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues)
+	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues, model.OutputSchema)
 	if err != nil {
 		response.Diagnostics.AddError("Failed to build output", err.Error())
 		return
@@ -503,5 +613,191 @@ func (r *AzapiUpdateResource) Read(ctx context.Context, request resource.ReadReq
 }
 
 func (r *AzapiUpdateResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
+	var model AzapiUpdateResourceModel
+	if response.Diagnostics.Append(request.State.Get(ctx, &model)...); response.Diagnostics.HasError() {
+		return
+	}
+	if !model.RestoreOnDestroy.ValueBool() {
+		return
+	}
+
+	deleteTimeout, diags := model.Timeouts.Delete(ctx, r.ProviderData.Features.DefaultDeleteTimeout)
+	if response.Diagnostics.Append(diags...); response.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	id, err := parse.ResourceIDWithResourceType(model.ID.ValueString(), model.Type.ValueString())
+	if err != nil {
+		response.Diagnostics.AddError("Invalid resource id", err.Error())
+		return
+	}
+
+	if response.Diagnostics.Append(readOnlyModeDiagnostics(r.ProviderData.Features, "Restoring", id.ID())...); response.Diagnostics.HasError() {
+		return
+	}
+
+	storedOriginalBody, diags := request.Private.GetKey(ctx, originalBodyKey)
+	if response.Diagnostics.Append(diags...); response.Diagnostics.HasError() {
+		return
+	}
+	var originalBody interface{}
+	if len(storedOriginalBody) != 0 {
+		if err := json.Unmarshal(storedOriginalBody, &originalBody); err != nil {
+			response.Diagnostics.AddError("Invalid private state", fmt.Sprintf("The original values of the properties are invalid: %+v", err))
+			return
+		}
+	}
+	if originalBody == nil {
+		response.Diagnostics.AddWarning("The properties are not restored", fmt.Sprintf("The original values of the properties of %s are not recorded, e.g. the resource is updated by an earlier version of the provider, they're not restored.", id))
+		return
+	}
+
+	var client clients.Requester
+	client = r.ProviderData.ResourceClient
+	if !model.Retry.IsNull() && !model.Retry.IsUnknown() {
+		bkof, regexps := clients.NewRetryableErrors(
+			model.Retry.GetIntervalSeconds(),
+			model.Retry.GetMaxIntervalSeconds(),
+			model.Retry.GetMultiplier(),
+			model.Retry.GetRandomizationFactor(),
+			model.Retry.GetErrorMessageRegex(),
+		)
+		client = r.ProviderData.ResourceClient.WithRetry(bkof, regexps)
+	}
+
+	for _, id := range AsStringList(model.Locks) {
+		locks.ByID(id)
+		defer locks.UnlockByID(id)
+	}
+
+	existing, err := client.Get(ctx, id.AzureResourceId, id.ApiVersion, clients.NewRequestOptions(model.ReadHeaders, model.ReadQueryParameters))
+	if err != nil {
+		if utils.ResponseErrorWasNotFound(err) {
+			return
+		}
+		response.Diagnostics.AddError("Failed to retrieve resource", fmt.Errorf("reading %s: %+v", id, err).Error())
+		return
+	}
+
+	requestBody := utils.MergeObject(existing, originalBody)
+	if id.ResourceDef != nil {
+		requestBody = (*id.ResourceDef).GetWriteOnly(utils.NormalizeObject(requestBody))
+	}
+
+	_, err = client.CreateOrUpdate(ctx, id.AzureResourceId, id.ApiVersion, requestBody, clients.NewRequestOptions(model.UpdateHeaders, model.UpdateQueryParameters))
+	if err != nil && !utils.ResponseErrorWasNotFound(err) {
+		response.Diagnostics.AddError(operationErrorSummary(err, "Failed to restore resource"), fmt.Errorf("restoring %q: %+v", id, err).Error())
+	}
+}
+
+// originalValues returns the existing values of the properties in the body, the properties which don't exist are null.
+// The nested objects are traversed, so only the properties in the body are restored, but the arrays are restored as a whole.
+func originalValues(body interface{}, existing interface{}) interface{} {
+	bodyMap, ok := body.(map[string]interface{})
+	if !ok {
+		return existing
+	}
+	existingMap, _ := existing.(map[string]interface{})
+	out := make(map[string]interface{}, len(bodyMap))
+	for key, value := range bodyMap {
+		if _, ok := value.(map[string]interface{}); ok {
+			out[key] = originalValues(value, existingMap[key])
+			continue
+		}
+		out[key] = existingMap[key]
+	}
+	return out
+}
+
+// mergeOriginalValues returns the recorded original values with the original values of the properties which are added to the body.
+func mergeOriginalValues(recorded interface{}, added interface{}) interface{} {
+	if recorded == nil {
+		return added
+	}
+	return utils.MergeObject(added, recorded)
+}
+
+func (r *AzapiUpdateResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	tflog.Debug(ctx, fmt.Sprintf("Importing Resource - parsing %q", request.ID))
+
+	input, err := importIdWithApiVersion(request.ID)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid Resource ID", fmt.Errorf("parsing Resource ID %q: %+v", request.ID, err).Error())
+		return
+	}
+
+	id, err := parse.ResourceIDWithApiVersion(input)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid Resource ID", fmt.Errorf("parsing Resource ID %q: %+v", input, err).Error())
+		return
+	}
+
+	state := AzapiUpdateResourceModel{
+		ID:                    types.StringValue(id.ID()),
+		Name:                  types.StringValue(id.Name),
+		ParentID:              types.StringValue(id.ParentId),
+		ResourceID:            types.StringValue(id.AzureResourceId),
+		Type:                  types.StringValue(fmt.Sprintf("%s@%s", id.AzureResourceType, id.ApiVersion)),
+		Body:                  types.DynamicNull(),
+		IgnoreCasing:          types.BoolValue(false),
+		IgnoreMissingProperty: types.BoolValue(true),
+		ResponseExportValues:  types.DynamicNull(),
+		OutputSchema:          types.MapNull(types.StringType),
+		Locks:                 types.ListNull(types.StringType),
+		Output:                types.DynamicNull(),
+		Retry:                 retry.NewRetryValueNull(),
+		RestoreOnDestroy:      types.BoolValue(false),
+		Timeouts: timeouts.Value{
+			Object: types.ObjectNull(map[string]attr.Type{
+				"create": types.StringType,
+				"update": types.StringType,
+				"read":   types.StringType,
+				"delete": types.StringType,
+			}),
+		},
+	}
+
+	responseBody, err := r.ProviderData.ResourceClient.Get(ctx, id.AzureResourceId, id.ApiVersion, clients.DefaultRequestOptions())
+	if err != nil {
+		if utils.ResponseErrorWasNotFound(err) {
+			response.Diagnostics.AddError("Resource not found", fmt.Errorf("reading %s: the resource doesn't exist", id).Error())
+			return
+		}
+		response.Diagnostics.AddError("Failed to retrieve resource", fmt.Errorf("reading %s: %+v", id, err).Error())
+		return
+	}
 
+	// the body is populated with the current values of the writable properties, so the read after the import refreshes them
+	body, err := importedUpdateBody(id.ResourceDef, responseBody)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid body", err.Error())
+		return
+	}
+	state.Body = body
+
+	tflog.Info(ctx, fmt.Sprintf("resource %q is imported", id.ID()))
+	response.Diagnostics.Append(response.State.Set(ctx, state)...)
+}
+
+// importedUpdateBody builds the body of an imported azapi_update_resource from the GET response. Only the writable properties are kept
+// when the resource definition is known, the identifying fields are dropped because they're configured by the resource id.
+func importedUpdateBody(resourceDef *aztypes.ResourceType, responseBody interface{}) (types.Dynamic, error) {
+	body := utils.NormalizeObject(responseBody)
+	if resourceDef != nil {
+		body = (*resourceDef).GetWriteOnly(body)
+	}
+	if bodyMap, ok := body.(map[string]interface{}); ok {
+		delete(bodyMap, "id")
+		delete(bodyMap, "name")
+		delete(bodyMap, "type")
+		body = bodyMap
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return types.DynamicNull(), err
+	}
+	return dynamic.FromJSONImplied(data)
 }
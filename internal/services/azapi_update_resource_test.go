@@ -30,6 +30,7 @@ func TestAccGenericUpdateResource_automationAccount(t *testing.T) {
 				check.That(data.ResourceName).Key("name").Exists(),
 			),
 		},
+		data.ImportStepWithImportStateIdFunc(r.ImportIdFunc, defaultIgnores()...),
 	})
 }
 
@@ -179,6 +180,15 @@ func (r GenericUpdateResource) Exists(ctx context.Context, client *clients.Clien
 	return &exist, nil
 }
 
+func (GenericUpdateResource) ImportIdFunc(tfState *terraform.State) (string, error) {
+	state := tfState.RootModule().Resources["azapi_update_resource.test"].Primary
+	id, err := parse.ResourceIDWithResourceType(state.ID, state.Attributes["type"])
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s?api-version=%s", id.AzureResourceId, id.ApiVersion), nil
+}
+
 func (r GenericUpdateResource) automationAccount(data acceptance.TestData) string {
 	return fmt.Sprintf(`
 %[1]s
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// cloudEndpointSuffixes maps the cloud environments to the DNS suffixes of their well-known endpoints.
+var cloudEndpointSuffixes = map[string][]string{
+	"public": {
+		"azure-api.net",
+		"azure-devices.net",
+		"azurecr.io",
+		"azuresynapse.net",
+		"azurewebsites.net",
+		"core.windows.net",
+		"database.windows.net",
+		"documents.azure.com",
+		"login.microsoftonline.com",
+		"management.azure.com",
+		"servicebus.windows.net",
+		"vault.azure.net",
+	},
+	"usgovernment": {
+		"azure-api.us",
+		"azure.us",
+		"azurecr.us",
+		"azurewebsites.us",
+		"login.microsoftonline.us",
+		"usgovcloudapi.net",
+	},
+	"china": {
+		"azure-api.cn",
+		"azure.cn",
+		"azurecr.cn",
+		"chinacloudapi.cn",
+		"chinacloudsites.cn",
+		"login.partner.microsoftonline.cn",
+	},
+}
+
+// otherCloudEndpoints returns the host names of the URLs in the value which belong to the clouds other than the environment, mapped to their clouds.
+// The strings in the nested objects and arrays are searched, only the HTTP and HTTPS URLs are checked, because the other strings like the resource names,
+// e.g. the private DNS zone `privatelink.blob.core.windows.net`, may contain the DNS suffixes intentionally. It returns nothing if the environment is unknown.
+func otherCloudEndpoints(environment string, value interface{}) map[string]string {
+	out := make(map[string]string)
+	if _, ok := cloudEndpointSuffixes[environment]; !ok {
+		return out
+	}
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		case string:
+			u, err := url.Parse(strings.TrimSpace(v))
+			if err != nil || (!strings.EqualFold(u.Scheme, "https") && !strings.EqualFold(u.Scheme, "http")) || u.Hostname() == "" {
+				return
+			}
+			host := strings.Trim(strings.ToLower(u.Hostname()), ".")
+			for cloud, suffixes := range cloudEndpointSuffixes {
+				if cloud == environment {
+					continue
+				}
+				for _, suffix := range suffixes {
+					if host == suffix || strings.HasSuffix(host, "."+suffix) {
+						out[host] = cloud
+					}
+				}
+			}
+		}
+	}
+	walk(value)
+	return out
+}
+
+// cloudEndpointDiagnostics returns the warnings about the endpoints of the other clouds in the value of the attribute, they're usually copy-pasted
+// from the configurations of another cloud, and result in confusing DNS failures. They're not errors, because the endpoints may be referenced intentionally.
+func cloudEndpointDiagnostics(environment string, attribute string, value interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	endpoints := otherCloudEndpoints(environment, value)
+	hosts := make([]string, 0, len(endpoints))
+	for host := range endpoints {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		diags.AddAttributeWarning(path.Root(attribute), "Endpoint of another cloud",
+			fmt.Sprintf("The `%s` contains the endpoint %q of the %s cloud, but the provider is configured for the %s cloud. Please check whether it's copied from the configuration of another cloud.", attribute, host, endpoints[host], environment))
+	}
+	return diags
+}
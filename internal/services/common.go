@@ -1,15 +1,24 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/Azure/terraform-provider-azapi/internal/docstrings"
 	"github.com/Azure/terraform-provider-azapi/internal/services/dynamic"
 	"github.com/Azure/terraform-provider-azapi/internal/services/myplanmodifier"
+	"github.com/Azure/terraform-provider-azapi/internal/services/myvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -23,7 +32,15 @@ func CommonAttributeResponseExportValues() schema.DynamicAttribute {
 	}
 }
 
-func buildOutputFromBody(responseBody interface{}, modelResponseExportValues types.Dynamic) (types.Dynamic, error) {
+func buildOutputFromBody(responseBody interface{}, modelResponseExportValues types.Dynamic, modelOutputSchema types.Map) (types.Dynamic, error) {
+	output, err := flattenOutputFromBody(responseBody, modelResponseExportValues)
+	if err != nil {
+		return types.DynamicNull(), err
+	}
+	return applyOutputSchema(output, modelOutputSchema)
+}
+
+func flattenOutputFromBody(responseBody interface{}, modelResponseExportValues types.Dynamic) (types.Dynamic, error) {
 	if modelResponseExportValues.IsNull() {
 		return types.DynamicValue(types.ObjectValueMust(map[string]attr.Type{}, map[string]attr.Value{})), nil
 	}
@@ -52,3 +69,127 @@ func buildOutputFromBody(responseBody interface{}, modelResponseExportValues typ
 		return types.DynamicNull(), errors.New("unsupported type for response_export_values, must be a list or map")
 	}
 }
+
+func CommonAttributeOutputSchema() schema.MapAttribute {
+	return schema.MapAttribute{
+		ElementType: types.StringType,
+		Optional:    true,
+		Validators: []validator.Map{
+			mapvalidator.ValueStringsAre(myvalidator.StringIsTypeExpression()),
+		},
+		MarkdownDescription: docstrings.OutputSchema(),
+	}
+}
+
+// validateOutputSchema returns the errors of the outputs which are declared in the output schema but not exported by the response_export_values,
+// so they're reported in the plan instead of after the resource is applied. The unknown values are validated in the later plans.
+func validateOutputSchema(modelResponseExportValues types.Dynamic, modelOutputSchema types.Map) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if modelOutputSchema.IsNull() || modelOutputSchema.IsUnknown() || !dynamic.IsFullyKnown(modelResponseExportValues) {
+		return diags
+	}
+
+	exported := make(map[string]bool)
+	if !modelResponseExportValues.IsNull() {
+		data, err := dynamic.ToJSON(modelResponseExportValues)
+		if err != nil {
+			return diags
+		}
+		switch modelResponseExportValues.UnderlyingValue().(type) {
+		case types.List, types.Tuple, types.Set:
+			var responseExportValues []string
+			if err = json.Unmarshal(data, &responseExportValues); err != nil {
+				return diags
+			}
+			for _, exportPath := range responseExportValues {
+				// all the top level properties are exported
+				if exportPath == "*" {
+					return diags
+				}
+				key, _, _ := strings.Cut(exportPath, ".")
+				exported[key] = true
+			}
+		case types.Map, types.Object:
+			var responseExportValues map[string]string
+			if err = json.Unmarshal(data, &responseExportValues); err != nil {
+				return diags
+			}
+			for key := range responseExportValues {
+				exported[key] = true
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(modelOutputSchema.Elements()))
+	for key := range modelOutputSchema.Elements() {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !exported[key] {
+			diags.AddAttributeError(path.Root("output_schema").AtMapKey(key), "Invalid output_schema", fmt.Sprintf("The output %q is declared in `output_schema` but it's not exported by the `response_export_values`.", key))
+		}
+	}
+	return diags
+}
+
+// applyOutputSchema converts the values in the output to the types declared in the output schema.
+// The values which are not declared in the output schema are kept as is.
+func applyOutputSchema(output types.Dynamic, modelOutputSchema types.Map) (types.Dynamic, error) {
+	if modelOutputSchema.IsNull() || modelOutputSchema.IsUnknown() || len(modelOutputSchema.Elements()) == 0 {
+		return output, nil
+	}
+
+	// the output might be a nested dynamic value, e.g. the output built from the JMESPath queries
+	underlyingValue := output.UnderlyingValue()
+	for {
+		v, ok := underlyingValue.(types.Dynamic)
+		if !ok {
+			break
+		}
+		underlyingValue = v.UnderlyingValue()
+	}
+
+	data, err := dynamic.ToJSON(types.DynamicValue(underlyingValue))
+	if err != nil {
+		return types.DynamicNull(), err
+	}
+	var outputValues map[string]json.RawMessage
+	if err = json.Unmarshal(data, &outputValues); err != nil {
+		return types.DynamicNull(), err
+	}
+
+	var outputSchema map[string]string
+	if diags := modelOutputSchema.ElementsAs(context.Background(), &outputSchema, false); diags.HasError() {
+		return types.DynamicNull(), fmt.Errorf("%s: %s", diags.Errors()[0].Summary(), diags.Errors()[0].Detail())
+	}
+
+	attrTypes := make(map[string]attr.Type)
+	attrValues := make(map[string]attr.Value)
+	if object, ok := underlyingValue.(types.Object); ok {
+		attrTypes = object.AttributeTypes(context.Background())
+		attrValues = object.Attributes()
+	}
+	for key, typeExpr := range outputSchema {
+		typ, err := dynamic.ParseTypeExpression(typeExpr)
+		if err != nil {
+			return types.DynamicNull(), err
+		}
+		raw, ok := outputValues[key]
+		if !ok {
+			return types.DynamicNull(), fmt.Errorf("the output %q is declared in `output_schema` but it's not found in the response", key)
+		}
+		value, err := dynamic.FromJSON(raw, typ)
+		if err != nil {
+			return types.DynamicNull(), fmt.Errorf("the output %q doesn't match the type %q declared in `output_schema`: %+v", key, typeExpr, err)
+		}
+		attrTypes[key] = value.UnderlyingValue().Type(context.Background())
+		attrValues[key] = value.UnderlyingValue()
+	}
+
+	object, diags := types.ObjectValue(attrTypes, attrValues)
+	if diags.HasError() {
+		return types.DynamicNull(), fmt.Errorf("%s: %s", diags.Errors()[0].Summary(), diags.Errors()[0].Detail())
+	}
+	return types.DynamicValue(object), nil
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// plannedResources tracks the resources which are planned in the current run, it's shared by all the provider instances in the process.
+var plannedResources = newPlannedResourceRegistry()
+
+// plannedResourceRegistry records the IDs of the resources which will be created and the resource IDs which are embedded in the bodies.
+// A body which refers to a resource that will be created in the same run via a reference has an unknown value at plan time,
+// so a known resource ID matching a planned creation is hard-coded, and Terraform doesn't know it must create the referenced resource first.
+// The lookups are indexed by the ancestor IDs of the referenced IDs, so planning thousands of resources doesn't scan the registry for each of them.
+type plannedResourceRegistry struct {
+	mutex sync.Mutex
+	// creations maps the lower-cased IDs of the resources which will be created to their IDs
+	creations map[string]string
+	// references maps the ID of a resource to the lower-cased resource IDs which are embedded in its body
+	references map[string][]string
+	// referrers maps the lower-cased referenced IDs and their ancestor IDs to the IDs of the resources which refer to them
+	referrers map[string]map[string]bool
+}
+
+func newPlannedResourceRegistry() *plannedResourceRegistry {
+	return &plannedResourceRegistry{
+		creations:  make(map[string]string),
+		references: make(map[string][]string),
+		referrers:  make(map[string]map[string]bool),
+	}
+}
+
+// planCreation records that the resource will be created, it returns the IDs of the planned resources whose bodies hard-code the resource ID.
+func (r *plannedResourceRegistry) planCreation(resourceId string) []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	key := strings.ToLower(resourceId)
+	r.creations[key] = resourceId
+
+	out := make([]string, 0)
+	for referrer := range r.referrers[key] {
+		if !strings.EqualFold(referrer, resourceId) {
+			out = append(out, referrer)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// markCreated records that the resource has been created, so it's no longer a planned creation and its references are dropped.
+// At apply time, the resources are planned again in the dependency order, so a resource which refers to a created resource is not warned.
+func (r *plannedResourceRegistry) markCreated(resourceId string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.creations, strings.ToLower(resourceId))
+	for _, referencedId := range r.references[resourceId] {
+		for _, key := range sameOrParentResourceIds(referencedId) {
+			delete(r.referrers[key], resourceId)
+		}
+	}
+	delete(r.references, resourceId)
+}
+
+// planReferences records the resource IDs which are embedded in the body of the resource, it returns the IDs of the planned creations which are referred by them.
+func (r *plannedResourceRegistry) planReferences(resourceId string, referencedIds []string) []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, referencedId := range r.references[resourceId] {
+		for _, key := range sameOrParentResourceIds(referencedId) {
+			delete(r.referrers[key], resourceId)
+		}
+	}
+	r.references[resourceId] = referencedIds
+
+	found := make(map[string]bool)
+	for _, referencedId := range referencedIds {
+		for _, key := range sameOrParentResourceIds(referencedId) {
+			if r.referrers[key] == nil {
+				r.referrers[key] = make(map[string]bool)
+			}
+			r.referrers[key][resourceId] = true
+			if createdId, ok := r.creations[key]; ok && !strings.EqualFold(key, resourceId) {
+				found[createdId] = true
+			}
+		}
+	}
+	out := make([]string, 0, len(found))
+	for createdId := range found {
+		out = append(out, createdId)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// sameOrParentResourceIds returns the lower-cased resource ID and all the prefixes of it which end before a slash,
+// a resource ID is the same as or belongs to a child resource of another ID if and only if the other ID is one of them.
+func sameOrParentResourceIds(resourceId string) []string {
+	resourceId = strings.ToLower(resourceId)
+	out := make([]string, 0, strings.Count(resourceId, "/")+1)
+	for i := 1; i < len(resourceId); i++ {
+		if resourceId[i] == '/' {
+			out = append(out, resourceId[:i])
+		}
+	}
+	return append(out, resourceId)
+}
+
+var embeddedResourceIdRegex = regexp.MustCompile(`(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+(/.*)?$|^(/subscriptions/[^/]+|/providers/Microsoft\.Management/managementGroups/[^/]+)?/providers/[^/]+/[^/]+/[^/]+`)
+
+// embeddedResourceIds returns the resource IDs which are embedded as string values in the body, the IDs are lower-cased and trimmed.
+func embeddedResourceIds(body interface{}) []string {
+	out := make([]string, 0)
+	switch v := body.(type) {
+	case map[string]interface{}:
+		for _, value := range v {
+			out = append(out, embeddedResourceIds(value)...)
+		}
+	case []interface{}:
+		for _, value := range v {
+			out = append(out, embeddedResourceIds(value)...)
+		}
+	case string:
+		if embeddedResourceIdRegex.MatchString(v) {
+			out = append(out, strings.ToLower(strings.TrimSuffix(v, "/")))
+		}
+	}
+	return out
+}
+
+// hardCodedDependencyWarnings returns the warnings about the hard-coded resource IDs in the body of the resource, which refer to the resources that will be created in the same run.
+// Without a reference, Terraform may create the resources in any order, which causes errors like `ResourceNotFound` or `InvalidResourceReference`.
+// It's only called for the resources which are not created yet and whose bodies are known in the config, the existing resources don't depend on the creation order.
+func hardCodedDependencyWarnings(resourceId string, body interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, createdId := range plannedResources.planReferences(resourceId, embeddedResourceIds(body)) {
+		diags.AddAttributeWarning(path.Root("body"), "Hard-coded resource ID",
+			fmt.Sprintf("The body of the resource %q contains the hard-coded ID of the resource %q, which will be created in the same run. Terraform doesn't know the dependency, so the resources may be created in a wrong order. Please use a reference to the resource instead, e.g. `azapi_resource.example.id`.", resourceId, createdId))
+	}
+	for _, referrerId := range plannedResources.planCreation(resourceId) {
+		diags.AddWarning("Hard-coded resource ID",
+			fmt.Sprintf("The ID of the resource %q is hard-coded in the body of the resource %q, which is planned in the same run. Terraform doesn't know the dependency, so the resources may be created in a wrong order. Please use a reference to the resource instead, e.g. `azapi_resource.example.id`.", resourceId, referrerId))
+	}
+	return diags
+}
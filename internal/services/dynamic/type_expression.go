@@ -0,0 +1,54 @@
+package dynamic
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ParseTypeExpression parses a terraform type constraint expression into an attr.Type.
+// The supported expressions are:
+// - primitive types: string, number, bool
+// - collection types: list(<type>), set(<type>), map(<type>)
+// - any: the type is implied from the value, it's only allowed as the top level type
+func ParseTypeExpression(expr string) (attr.Type, error) {
+	if strings.TrimSpace(expr) == "any" {
+		return types.DynamicType, nil
+	}
+	return parseTypeExpression(expr)
+}
+
+func parseTypeExpression(expr string) (attr.Type, error) {
+	expr = strings.TrimSpace(expr)
+	switch expr {
+	case "string":
+		return types.StringType, nil
+	case "number":
+		return types.NumberType, nil
+	case "bool":
+		return types.BoolType, nil
+	case "any":
+		return nil, fmt.Errorf("invalid type expression %q, the element type of a collection must not be any", expr)
+	}
+
+	name, elemExpr, ok := strings.Cut(expr, "(")
+	if !ok || !strings.HasSuffix(elemExpr, ")") {
+		return nil, fmt.Errorf("invalid type expression %q, supported types are string, number, bool, any, list(<type>), set(<type>) and map(<type>)", expr)
+	}
+	elemType, err := parseTypeExpression(strings.TrimSuffix(elemExpr, ")"))
+	if err != nil {
+		return nil, err
+	}
+	switch strings.TrimSpace(name) {
+	case "list":
+		return types.ListType{ElemType: elemType}, nil
+	case "set":
+		return types.SetType{ElemType: elemType}, nil
+	case "map":
+		return types.MapType{ElemType: elemType}, nil
+	default:
+		return nil, fmt.Errorf("invalid type expression %q, unsupported collection type %q", expr, name)
+	}
+}
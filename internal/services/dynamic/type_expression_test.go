@@ -0,0 +1,79 @@
+package dynamic
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseTypeExpression(t *testing.T) {
+	cases := []struct {
+		input       string
+		expect      attr.Type
+		expectError bool
+	}{
+		{
+			input:  "string",
+			expect: types.StringType,
+		},
+		{
+			input:  " number ",
+			expect: types.NumberType,
+		},
+		{
+			input:  "bool",
+			expect: types.BoolType,
+		},
+		{
+			input:  "any",
+			expect: types.DynamicType,
+		},
+		{
+			input:  "list(string)",
+			expect: types.ListType{ElemType: types.StringType},
+		},
+		{
+			input:  "set(number)",
+			expect: types.SetType{ElemType: types.NumberType},
+		},
+		{
+			input:  "map(list(bool))",
+			expect: types.MapType{ElemType: types.ListType{ElemType: types.BoolType}},
+		},
+		{
+			input:       "list(any)",
+			expectError: true,
+		},
+		{
+			input:       "object",
+			expectError: true,
+		},
+		{
+			input:       "tuple(string)",
+			expectError: true,
+		},
+		{
+			input:       "list(string",
+			expectError: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.input, func(t *testing.T) {
+			actual, err := ParseTypeExpression(c.input)
+			if c.expectError {
+				if err == nil {
+					t.Fatalf("expect error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !actual.Equal(c.expect) {
+				t.Fatalf("expect %s, got %s", c.expect, actual)
+			}
+		})
+	}
+}
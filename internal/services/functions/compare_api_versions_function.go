@@ -0,0 +1,90 @@
+package functions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/terraform-provider-azapi/internal/azure"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type CompareApiVersionsFunction struct {
+}
+
+var CompareApiVersionsResultAttrTypes = map[string]attr.Type{
+	"path":   types.StringType,
+	"kind":   types.StringType,
+	"detail": types.StringType,
+}
+
+func (f *CompareApiVersionsFunction) Metadata(ctx context.Context, request function.MetadataRequest, response *function.MetadataResponse) {
+	response.Name = "compare_api_versions"
+}
+
+func (f *CompareApiVersionsFunction) Definition(ctx context.Context, request function.DefinitionRequest, response *function.DefinitionResponse) {
+	response.Definition = function.Definition{
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				AllowNullValue:      false,
+				AllowUnknownValues:  false,
+				Name:                "resource_type",
+				Description:         "The resource type of the Azure resource, the api-version is ignored.",
+				MarkdownDescription: "The resource type of the Azure resource, the api-version is ignored.",
+			},
+			function.StringParameter{
+				AllowNullValue:      false,
+				AllowUnknownValues:  false,
+				Name:                "from_api_version",
+				Description:         "The api-version which the configuration is written for.",
+				MarkdownDescription: "The api-version which the configuration is written for.",
+			},
+			function.StringParameter{
+				AllowNullValue:      false,
+				AllowUnknownValues:  false,
+				Name:                "to_api_version",
+				Description:         "The api-version to upgrade to.",
+				MarkdownDescription: "The api-version to upgrade to.",
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.ObjectType{
+				AttrTypes: CompareApiVersionsResultAttrTypes,
+			},
+		},
+		Summary:             "Reports the breaking changes of a resource type between two api-versions.",
+		Description:         "This function compares the embedded schemas of a resource type in two api-versions, and returns the breaking changes of the properties sorted by their paths, so the upgrades of the api-version can be evaluated before the configurations are changed. Each change contains the `path` of the property, e.g. `properties.sku.name`, the `kind` of the change, which is one of `removed`, `renamed`, `type_changed`, `required` and `enum_value_removed`, and the `detail`. Only the properties whose names differ in casing are reported as renamed, the other renamed properties are reported as removed.",
+		MarkdownDescription: "This function compares the embedded schemas of a resource type in two api-versions, and returns the breaking changes of the properties sorted by their paths, so the upgrades of the api-version can be evaluated before the configurations are changed. Each change contains the `path` of the property, e.g. `properties.sku.name`, the `kind` of the change, which is one of `removed`, `renamed`, `type_changed`, `required` and `enum_value_removed`, and the `detail`. Only the properties whose names differ in casing are reported as renamed, the other renamed properties are reported as removed.",
+		DeprecationMessage:  "",
+	}
+}
+
+func (f *CompareApiVersionsFunction) Run(ctx context.Context, request function.RunRequest, response *function.RunResponse) {
+	var resourceType, fromApiVersion, toApiVersion string
+
+	if response.Error = request.Arguments.Get(ctx, &resourceType, &fromApiVersion, &toApiVersion); response.Error != nil {
+		return
+	}
+
+	resourceType, _, _ = strings.Cut(resourceType, "@")
+	changes, err := azure.GetBreakingChanges(resourceType, fromApiVersion, toApiVersion)
+	if err != nil {
+		response.Error = function.NewFuncError(fmt.Errorf("failed to compare the api-versions %s and %s of %s: %w", fromApiVersion, toApiVersion, resourceType, err).Error())
+		return
+	}
+
+	elements := make([]attr.Value, 0, len(changes))
+	for _, change := range changes {
+		elements = append(elements, types.ObjectValueMust(CompareApiVersionsResultAttrTypes, map[string]attr.Value{
+			"path":   types.StringValue(change.Path),
+			"kind":   types.StringValue(change.Kind),
+			"detail": types.StringValue(change.Detail),
+		}))
+	}
+
+	response.Error = response.Result.Set(ctx, types.ListValueMust(types.ObjectType{AttrTypes: CompareApiVersionsResultAttrTypes}, elements))
+}
+
+var _ function.Function = &CompareApiVersionsFunction{}
@@ -0,0 +1,78 @@
+package functions_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/terraform-provider-azapi/internal/services/functions"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCompareApiVersionsFunction(t *testing.T) {
+	changeType := types.ObjectType{AttrTypes: functions.CompareApiVersionsResultAttrTypes}
+	testCases := map[string]struct {
+		request  function.RunRequest
+		expected function.RunResponse
+	}{
+		"removed-property": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue("Microsoft.ContainerService/managedClusters@2021-05-01"),
+					types.StringValue("2021-05-01"),
+					types.StringValue("2024-02-01"),
+				}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.ListValueMust(changeType, []attr.Value{
+					types.ObjectValueMust(functions.CompareApiVersionsResultAttrTypes, map[string]attr.Value{
+						"path":   types.StringValue("properties.networkProfile.dockerBridgeCidr"),
+						"kind":   types.StringValue("removed"),
+						"detail": types.StringValue("the property is removed"),
+					}),
+				})),
+			},
+		},
+		"no-breaking-changes": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue("Microsoft.Storage/storageAccounts"),
+					types.StringValue("2021-01-01"),
+					types.StringValue("2023-01-01"),
+				}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.ListValueMust(changeType, []attr.Value{})),
+			},
+		},
+		"unknown-api-version": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue("Microsoft.Storage/storageAccounts"),
+					types.StringValue("2021-01-01"),
+					types.StringValue("2099-01-01"),
+				}),
+			},
+			expected: function.RunResponse{
+				Error:  function.NewFuncError("failed to compare the api-versions 2021-01-01 and 2099-01-01 of Microsoft.Storage/storageAccounts: failed to find resource type Microsoft.Storage/storageAccounts api-version 2099-01-01 in azure schema index"),
+				Result: function.NewResultData(types.ListUnknown(changeType)),
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got := function.RunResponse{
+				Result: function.NewResultData(types.ListUnknown(changeType)),
+			}
+
+			compareApiVersionsFunction := functions.CompareApiVersionsFunction{}
+			compareApiVersionsFunction.Run(context.Background(), testCase.request, &got)
+			if diff := cmp.Diff(got, testCase.expected); diff != "" {
+				t.Errorf("unexpected difference: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package functions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/terraform-provider-azapi/internal/azure"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type NormalizeResourceIdFunction struct {
+}
+
+func (f *NormalizeResourceIdFunction) Metadata(ctx context.Context, request function.MetadataRequest, response *function.MetadataResponse) {
+	response.Name = "normalize_resource_id"
+}
+
+func (f *NormalizeResourceIdFunction) Definition(ctx context.Context, request function.DefinitionRequest, response *function.DefinitionResponse) {
+	response.Definition = function.Definition{
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				AllowNullValue:      false,
+				AllowUnknownValues:  false,
+				Name:                "resource_id",
+				Description:         "The resource ID of the Azure resource to normalize.",
+				MarkdownDescription: "The resource ID of the Azure resource to normalize.",
+			},
+		},
+		Return:              function.StringReturn{},
+		Summary:             "Normalizes an Azure resource ID into its canonical form.",
+		Description:         "This function normalizes an Azure resource ID into its canonical form, so the IDs from different sources can be used as stable map keys or for_each sets. The leading slash is added, the duplicated and trailing slashes are removed, the subscription ID is lower-cased, and the segment keywords, provider namespaces and resource types are converted to their canonical casing, e.g. `microsoft.sql/SERVERS` becomes `Microsoft.Sql/servers`. The resource names are kept as they are, because some of them are case-sensitive.",
+		MarkdownDescription: "This function normalizes an Azure resource ID into its canonical form, so the IDs from different sources can be used as stable map keys or `for_each` sets. The leading slash is added, the duplicated and trailing slashes are removed, the subscription ID is lower-cased, and the segment keywords, provider namespaces and resource types are converted to their canonical casing, e.g. `microsoft.sql/SERVERS` becomes `Microsoft.Sql/servers`. The resource names are kept as they are, because some of them are case-sensitive.",
+		DeprecationMessage:  "",
+	}
+}
+
+func (f *NormalizeResourceIdFunction) Run(ctx context.Context, request function.RunRequest, response *function.RunResponse) {
+	var resourceId string
+
+	if response.Error = request.Arguments.Get(ctx, &resourceId); response.Error != nil {
+		return
+	}
+
+	normalized, err := normalizeResourceId(resourceId)
+	if err != nil {
+		response.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	response.Error = response.Result.Set(ctx, types.StringValue(normalized))
+}
+
+func normalizeResourceId(resourceId string) (string, error) {
+	segments := make([]string, 0)
+	for _, segment := range strings.Split(resourceId, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) == 0 {
+		return "/", nil
+	}
+	if len(segments)%2 != 0 {
+		return "", fmt.Errorf("the resource ID %q is invalid, it must consist of key/value pairs", resourceId)
+	}
+
+	// resourceType is the resource type of the current provider segment, e.g. `Microsoft.Sql/servers/databases`
+	resourceType := ""
+	for i := 0; i < len(segments); i += 2 {
+		key, value := segments[i], segments[i+1]
+		switch {
+		case strings.EqualFold(key, "providers"):
+			segments[i] = "providers"
+			resourceType = value
+			if v, ok := azure.GetCanonicalResourceType(value); ok {
+				segments[i+1] = v
+			}
+		case resourceType != "":
+			resourceType += "/" + key
+			if v, ok := azure.GetCanonicalResourceType(resourceType); ok {
+				segments[i] = v[strings.LastIndex(v, "/")+1:]
+			}
+		case strings.EqualFold(key, "subscriptions"):
+			segments[i] = "subscriptions"
+			segments[i+1] = strings.ToLower(value)
+		case strings.EqualFold(key, "resourceGroups"):
+			segments[i] = "resourceGroups"
+		}
+	}
+	return "/" + strings.Join(segments, "/"), nil
+}
+
+var _ function.Function = &NormalizeResourceIdFunction{}
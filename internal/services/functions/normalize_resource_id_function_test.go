@@ -0,0 +1,95 @@
+package functions_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/terraform-provider-azapi/internal/services/functions"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestNormalizeResourceIdFunction(t *testing.T) {
+	testCases := map[string]struct {
+		request  function.RunRequest
+		expected function.RunResponse
+	}{
+		"mixed-casing": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue("/SUBSCRIPTIONS/0000000A-0000-0000-0000-000000000000/resourcegroups/MyRG/providers/microsoft.sql/SERVERS/server1/Databases/db1"),
+				}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("/subscriptions/0000000a-0000-0000-0000-000000000000/resourceGroups/MyRG/providers/Microsoft.Sql/servers/server1/databases/db1")),
+			},
+		},
+		"trailing-and-duplicated-slashes": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue("subscriptions/00000000-0000-0000-0000-000000000000//resourceGroups/rg1/"),
+				}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1")),
+			},
+		},
+		"extension-resource": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/MICROSOFT.NETWORK/virtualnetworks/vnet1/providers/microsoft.authorization/LOCKS/lock1"),
+				}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/providers/Microsoft.Authorization/locks/lock1")),
+			},
+		},
+		"unknown-resource-type": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue("/providers/Microsoft.Foo/BARS/bar1/"),
+				}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("/providers/Microsoft.Foo/BARS/bar1")),
+			},
+		},
+		"tenant": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue("/"),
+				}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("/")),
+			},
+		},
+		"invalid-resource-id": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups"),
+				}),
+			},
+			expected: function.RunResponse{
+				Error:  function.NewArgumentFuncError(0, `the resource ID "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups" is invalid, it must consist of key/value pairs`),
+				Result: function.NewResultData(types.StringUnknown()),
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got := function.RunResponse{
+				Result: function.NewResultData(types.StringUnknown()),
+			}
+
+			normalizeResourceIdFunction := functions.NormalizeResourceIdFunction{}
+			normalizeResourceIdFunction.Run(context.Background(), testCase.request, &got)
+			if diff := cmp.Diff(got, testCase.expected); diff != "" {
+				t.Errorf("unexpected difference: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package functions
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type ValidateResourceNameFunction struct {
+}
+
+// namingRule is the naming rule of a resource type, it follows https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/resource-name-rules
+type namingRule struct {
+	MinLength            int
+	MaxLength            int
+	Pattern              *regexp.Regexp
+	PatternDescription   string
+	NoConsecutiveHyphens bool
+	GloballyUnique       bool
+}
+
+var (
+	alphanumericsHyphensPattern              = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?$`)
+	alphanumericsHyphensDescription          = "it can contain only alphanumerics and hyphens, and must start and end with an alphanumeric"
+	letterAlphanumericsHyphensPattern        = regexp.MustCompile(`^[a-zA-Z]([a-zA-Z0-9-]*[a-zA-Z0-9])?$`)
+	letterAlphanumericsHyphensDescription    = "it can contain only alphanumerics and hyphens, and must start with a letter and end with an alphanumeric"
+	lowercaseAlphanumericsHyphensPattern     = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+	lowercaseAlphanumericsHyphensDescription = "it can contain only lowercase letters, numbers and hyphens, and must start and end with a lowercase letter or number"
+	networkNamePattern                       = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9_.-]*[a-zA-Z0-9_])?$`)
+	networkNameDescription                   = "it can contain only alphanumerics, underscores, periods and hyphens, and must start with an alphanumeric and end with an alphanumeric or underscore"
+)
+
+var namingRules = map[string]namingRule{
+	"microsoft.app/containerapps": {
+		MinLength:            2,
+		MaxLength:            32,
+		Pattern:              regexp.MustCompile(`^[a-z]([a-z0-9-]*[a-z0-9])?$`),
+		PatternDescription:   "it can contain only lowercase letters, numbers and hyphens, and must start with a letter and end with a letter or number",
+		NoConsecutiveHyphens: true,
+	},
+	"microsoft.automation/automationaccounts": {
+		MinLength:          6,
+		MaxLength:          50,
+		Pattern:            letterAlphanumericsHyphensPattern,
+		PatternDescription: letterAlphanumericsHyphensDescription,
+	},
+	"microsoft.cache/redis": {
+		MinLength:            1,
+		MaxLength:            63,
+		Pattern:              alphanumericsHyphensPattern,
+		PatternDescription:   alphanumericsHyphensDescription,
+		NoConsecutiveHyphens: true,
+		GloballyUnique:       true,
+	},
+	"microsoft.cognitiveservices/accounts": {
+		MinLength:          2,
+		MaxLength:          64,
+		Pattern:            alphanumericsHyphensPattern,
+		PatternDescription: alphanumericsHyphensDescription,
+		GloballyUnique:     true,
+	},
+	"microsoft.compute/virtualmachines": {
+		MinLength:          1,
+		MaxLength:          64,
+		Pattern:            regexp.MustCompile(`^[^_\\/"'\[\]:|<>+=;,?*@&~!#$%^(){}\s]([^\\/"'\[\]:|<>+=;,?*@&~!#$%^(){}\s]*[^\\/"'\[\]:|<>+=;,?*@&~!#$%^(){}\s.-])?$`),
+		PatternDescription: "it can't contain spaces or the characters `\\/\"'[]:|<>+=;,?*@&~!#$%^(){}`, can't start with an underscore, and can't end with a period or hyphen",
+	},
+	"microsoft.containerregistry/registries": {
+		MinLength:          5,
+		MaxLength:          50,
+		Pattern:            regexp.MustCompile(`^[a-zA-Z0-9]+$`),
+		PatternDescription: "it can contain only alphanumerics",
+		GloballyUnique:     true,
+	},
+	"microsoft.containerservice/managedclusters": {
+		MinLength:          1,
+		MaxLength:          63,
+		Pattern:            regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9_-]*[a-zA-Z0-9])?$`),
+		PatternDescription: "it can contain only alphanumerics, underscores and hyphens, and must start and end with an alphanumeric",
+	},
+	"microsoft.documentdb/databaseaccounts": {
+		MinLength:          3,
+		MaxLength:          44,
+		Pattern:            lowercaseAlphanumericsHyphensPattern,
+		PatternDescription: lowercaseAlphanumericsHyphensDescription,
+		GloballyUnique:     true,
+	},
+	"microsoft.eventhub/namespaces": {
+		MinLength:          6,
+		MaxLength:          50,
+		Pattern:            letterAlphanumericsHyphensPattern,
+		PatternDescription: letterAlphanumericsHyphensDescription,
+		GloballyUnique:     true,
+	},
+	"microsoft.keyvault/vaults": {
+		MinLength:            3,
+		MaxLength:            24,
+		Pattern:              letterAlphanumericsHyphensPattern,
+		PatternDescription:   letterAlphanumericsHyphensDescription,
+		NoConsecutiveHyphens: true,
+		GloballyUnique:       true,
+	},
+	"microsoft.managedidentity/userassignedidentities": {
+		MinLength:          3,
+		MaxLength:          128,
+		Pattern:            regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`),
+		PatternDescription: "it can contain only alphanumerics, hyphens and underscores, and must start with an alphanumeric",
+	},
+	"microsoft.network/networksecuritygroups": {
+		MinLength:          1,
+		MaxLength:          80,
+		Pattern:            networkNamePattern,
+		PatternDescription: networkNameDescription,
+	},
+	"microsoft.network/publicipaddresses": {
+		MinLength:          1,
+		MaxLength:          80,
+		Pattern:            networkNamePattern,
+		PatternDescription: networkNameDescription,
+	},
+	"microsoft.network/virtualnetworks": {
+		MinLength:          2,
+		MaxLength:          64,
+		Pattern:            networkNamePattern,
+		PatternDescription: networkNameDescription,
+	},
+	"microsoft.network/virtualnetworks/subnets": {
+		MinLength:          1,
+		MaxLength:          80,
+		Pattern:            networkNamePattern,
+		PatternDescription: networkNameDescription,
+	},
+	"microsoft.operationalinsights/workspaces": {
+		MinLength:          4,
+		MaxLength:          63,
+		Pattern:            alphanumericsHyphensPattern,
+		PatternDescription: alphanumericsHyphensDescription,
+	},
+	"microsoft.resources/resourcegroups": {
+		MinLength:          1,
+		MaxLength:          90,
+		Pattern:            regexp.MustCompile(`^[-\p{L}\p{N}_.()]*[-\p{L}\p{N}_()]$`),
+		PatternDescription: "it can contain only underscores, hyphens, periods, parentheses, letters and digits, and can't end with a period",
+	},
+	"microsoft.search/searchservices": {
+		MinLength:            2,
+		MaxLength:            60,
+		Pattern:              lowercaseAlphanumericsHyphensPattern,
+		PatternDescription:   lowercaseAlphanumericsHyphensDescription,
+		NoConsecutiveHyphens: true,
+		GloballyUnique:       true,
+	},
+	"microsoft.servicebus/namespaces": {
+		MinLength:          6,
+		MaxLength:          50,
+		Pattern:            letterAlphanumericsHyphensPattern,
+		PatternDescription: letterAlphanumericsHyphensDescription,
+		GloballyUnique:     true,
+	},
+	"microsoft.sql/servers": {
+		MinLength:          1,
+		MaxLength:          63,
+		Pattern:            lowercaseAlphanumericsHyphensPattern,
+		PatternDescription: lowercaseAlphanumericsHyphensDescription,
+		GloballyUnique:     true,
+	},
+	"microsoft.storage/storageaccounts": {
+		MinLength:          3,
+		MaxLength:          24,
+		Pattern:            regexp.MustCompile(`^[a-z0-9]+$`),
+		PatternDescription: "it can contain only lowercase letters and numbers",
+		GloballyUnique:     true,
+	},
+	"microsoft.web/serverfarms": {
+		MinLength:          1,
+		MaxLength:          60,
+		Pattern:            regexp.MustCompile(`^[a-zA-Z0-9-]+$`),
+		PatternDescription: "it can contain only alphanumerics and hyphens",
+	},
+	"microsoft.web/sites": {
+		MinLength:          2,
+		MaxLength:          60,
+		Pattern:            alphanumericsHyphensPattern,
+		PatternDescription: alphanumericsHyphensDescription,
+		GloballyUnique:     true,
+	},
+}
+
+// defaultNamingRule applies to the resource types which don't have a specific naming rule, the characters are restricted by ARM for all resource types.
+var defaultNamingRule = namingRule{
+	MinLength:          1,
+	MaxLength:          260,
+	Pattern:            regexp.MustCompile(`^[^<>*%&:\\?/#\x00-\x1f]*[^<>*%&:\\?/#\x00-\x1f. ]$`),
+	PatternDescription: "it can't contain the characters `<>*%&:\\?/#` or control characters, and can't end with a period or space",
+}
+
+func (f *ValidateResourceNameFunction) Metadata(ctx context.Context, request function.MetadataRequest, response *function.MetadataResponse) {
+	response.Name = "validate_resource_name"
+}
+
+func (f *ValidateResourceNameFunction) Definition(ctx context.Context, request function.DefinitionRequest, response *function.DefinitionResponse) {
+	response.Definition = function.Definition{
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				AllowNullValue:      false,
+				AllowUnknownValues:  false,
+				Name:                "resource_type",
+				Description:         "The resource type of the Azure resource, the api-version is optional.",
+				MarkdownDescription: "The resource type of the Azure resource, the api-version is optional.",
+			},
+			function.StringParameter{
+				AllowNullValue:      false,
+				AllowUnknownValues:  false,
+				Name:                "name",
+				Description:         "The name of the Azure resource to validate.",
+				MarkdownDescription: "The name of the Azure resource to validate.",
+			},
+		},
+		Return:              function.StringReturn{},
+		Summary:             "Validates the name of an Azure resource against the naming rules of its resource type.",
+		Description:         "This function validates the name of an Azure resource against the ARM naming rules of its resource type, including the length and the allowed characters. It returns the name if it's valid, otherwise it fails with the reason, so invalid names fail at plan time. The error also mentions if the name must be globally unique. The resource types which don't have a specific naming rule are validated against the restrictions which apply to all resource types.",
+		MarkdownDescription: "This function validates the name of an Azure resource against the ARM naming rules of its resource type, including the length and the allowed characters. It returns the name if it's valid, otherwise it fails with the reason, so invalid names fail at plan time. The error also mentions if the name must be globally unique. The resource types which don't have a specific naming rule are validated against the restrictions which apply to all resource types.",
+		DeprecationMessage:  "",
+	}
+}
+
+func (f *ValidateResourceNameFunction) Run(ctx context.Context, request function.RunRequest, response *function.RunResponse) {
+	var resourceType, name string
+
+	if response.Error = request.Arguments.Get(ctx, &resourceType, &name); response.Error != nil {
+		return
+	}
+
+	resourceType, _, _ = strings.Cut(resourceType, "@")
+	if err := validateResourceName(resourceType, name); err != nil {
+		response.Error = function.NewArgumentFuncError(1, err.Error())
+		return
+	}
+
+	response.Error = response.Result.Set(ctx, types.StringValue(name))
+}
+
+func validateResourceName(resourceType string, name string) error {
+	rule, ok := namingRules[strings.ToLower(resourceType)]
+	if !ok {
+		rule = defaultNamingRule
+	}
+
+	var uniquenessHint string
+	if rule.GloballyUnique {
+		uniquenessHint = fmt.Sprintf(" Note: the name of %s must be globally unique across Azure.", resourceType)
+	}
+	length := len([]rune(name))
+	if length < rule.MinLength || length > rule.MaxLength {
+		return fmt.Errorf("the name %q of %s is invalid, its length must be between %d and %d characters, but got %d.%s", name, resourceType, rule.MinLength, rule.MaxLength, length, uniquenessHint)
+	}
+	if !rule.Pattern.MatchString(name) {
+		return fmt.Errorf("the name %q of %s is invalid, %s.%s", name, resourceType, rule.PatternDescription, uniquenessHint)
+	}
+	if rule.NoConsecutiveHyphens && strings.Contains(name, "--") {
+		return fmt.Errorf("the name %q of %s is invalid, it can't contain consecutive hyphens.%s", name, resourceType, uniquenessHint)
+	}
+	return nil
+}
+
+var _ function.Function = &ValidateResourceNameFunction{}
@@ -0,0 +1,116 @@
+package functions_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/terraform-provider-azapi/internal/services/functions"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestValidateResourceNameFunction(t *testing.T) {
+	testCases := map[string]struct {
+		request  function.RunRequest
+		expected function.RunResponse
+	}{
+		"valid-storage-account-name": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue("Microsoft.Storage/storageAccounts@2023-01-01"),
+					types.StringValue("mystorage01"),
+				}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("mystorage01")),
+			},
+		},
+		"storage-account-name-with-uppercase": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue("Microsoft.Storage/storageAccounts"),
+					types.StringValue("MyStorage01"),
+				}),
+			},
+			expected: function.RunResponse{
+				Error:  function.NewArgumentFuncError(1, `the name "MyStorage01" of Microsoft.Storage/storageAccounts is invalid, it can contain only lowercase letters and numbers. Note: the name of Microsoft.Storage/storageAccounts must be globally unique across Azure.`),
+				Result: function.NewResultData(types.StringUnknown()),
+			},
+		},
+		"key-vault-name-too-long": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue("Microsoft.KeyVault/vaults"),
+					types.StringValue("my-key-vault-with-a-long-name"),
+				}),
+			},
+			expected: function.RunResponse{
+				Error:  function.NewArgumentFuncError(1, `the name "my-key-vault-with-a-long-name" of Microsoft.KeyVault/vaults is invalid, its length must be between 3 and 24 characters, but got 29. Note: the name of Microsoft.KeyVault/vaults must be globally unique across Azure.`),
+				Result: function.NewResultData(types.StringUnknown()),
+			},
+		},
+		"key-vault-name-with-consecutive-hyphens": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue("Microsoft.KeyVault/vaults"),
+					types.StringValue("my--vault"),
+				}),
+			},
+			expected: function.RunResponse{
+				Error:  function.NewArgumentFuncError(1, `the name "my--vault" of Microsoft.KeyVault/vaults is invalid, it can't contain consecutive hyphens. Note: the name of Microsoft.KeyVault/vaults must be globally unique across Azure.`),
+				Result: function.NewResultData(types.StringUnknown()),
+			},
+		},
+		"resource-group-name-ending-with-period": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue("Microsoft.Resources/resourceGroups"),
+					types.StringValue("my-rg."),
+				}),
+			},
+			expected: function.RunResponse{
+				Error:  function.NewArgumentFuncError(1, `the name "my-rg." of Microsoft.Resources/resourceGroups is invalid, it can contain only underscores, hyphens, periods, parentheses, letters and digits, and can't end with a period.`),
+				Result: function.NewResultData(types.StringUnknown()),
+			},
+		},
+		"valid-name-of-type-without-rule": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue("Microsoft.Automation/automationAccounts/runbooks"),
+					types.StringValue("my_runbook-01"),
+				}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("my_runbook-01")),
+			},
+		},
+		"invalid-name-of-type-without-rule": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue("Microsoft.Automation/automationAccounts/runbooks"),
+					types.StringValue("my/runbook"),
+				}),
+			},
+			expected: function.RunResponse{
+				Error:  function.NewArgumentFuncError(1, "the name \"my/runbook\" of Microsoft.Automation/automationAccounts/runbooks is invalid, it can't contain the characters `<>*%&:\\?/#` or control characters, and can't end with a period or space."),
+				Result: function.NewResultData(types.StringUnknown()),
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got := function.RunResponse{
+				Result: function.NewResultData(types.StringUnknown()),
+			}
+
+			validateResourceNameFunction := functions.ValidateResourceNameFunction{}
+			validateResourceNameFunction.Run(context.Background(), testCase.request, &got)
+			if diff := cmp.Diff(got, testCase.expected); diff != "" {
+				t.Errorf("unexpected difference: %s", diff)
+			}
+		})
+	}
+}
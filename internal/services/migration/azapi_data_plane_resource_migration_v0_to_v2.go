@@ -5,6 +5,7 @@ import (
 
 	"github.com/Azure/terraform-provider-azapi/internal/retry"
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -95,6 +96,8 @@ func AzapiDataPlaneResourceMigrationV0ToV2(ctx context.Context) resource.StateUp
 				ReplaceTriggersExternalValues types.Dynamic       `tfsdk:"replace_triggers_external_values"`
 				ReplaceTriggersRefs           types.List          `tfsdk:"replace_triggers_refs"`
 				ResponseExportValues          types.Dynamic       `tfsdk:"response_export_values"`
+				OutputSchema                  types.Map           `tfsdk:"output_schema"`
+				Authentication                types.Object        `tfsdk:"authentication"`
 				Retry                         retry.RetryValue    `tfsdk:"retry"`
 				Locks                         types.List          `tfsdk:"locks"`
 				Output                        types.Dynamic       `tfsdk:"output"`
@@ -143,6 +146,8 @@ func AzapiDataPlaneResourceMigrationV0ToV2(ctx context.Context) resource.StateUp
 				IgnoreCasing:                  oldState.IgnoreCasing,
 				IgnoreMissingProperty:         oldState.IgnoreMissingProperty,
 				ResponseExportValues:          responseExportValues,
+				OutputSchema:                  types.MapNull(types.StringType),
+				Authentication:                types.ObjectNull(map[string]attr.Type{"sas_token_env_var": types.StringType, "account_key_env_var": types.StringType, "use_msi": types.BoolType, "client_id": types.StringType}),
 				ReplaceTriggersExternalValues: types.DynamicNull(),
 				ReplaceTriggersRefs:           types.ListNull(types.StringType),
 				Retry:                         retry.NewRetryValueNull(),
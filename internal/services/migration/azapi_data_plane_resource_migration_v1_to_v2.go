@@ -5,6 +5,7 @@ import (
 
 	"github.com/Azure/terraform-provider-azapi/internal/retry"
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -93,6 +94,8 @@ func AzapiDataPlaneResourceMigrationV1ToV2(ctx context.Context) resource.StateUp
 				IgnoreCasing                  types.Bool          `tfsdk:"ignore_casing"`
 				IgnoreMissingProperty         types.Bool          `tfsdk:"ignore_missing_property"`
 				ResponseExportValues          types.Dynamic       `tfsdk:"response_export_values"`
+				OutputSchema                  types.Map           `tfsdk:"output_schema"`
+				Authentication                types.Object        `tfsdk:"authentication"`
 				ReplaceTriggersExternalValues types.Dynamic       `tfsdk:"replace_triggers_external_values"`
 				ReplaceTriggersRefs           types.List          `tfsdk:"replace_triggers_refs"`
 				Retry                         retry.RetryValue    `tfsdk:"retry"`
@@ -141,6 +144,8 @@ func AzapiDataPlaneResourceMigrationV1ToV2(ctx context.Context) resource.StateUp
 				IgnoreCasing:                  oldState.IgnoreCasing,
 				IgnoreMissingProperty:         oldState.IgnoreMissingProperty,
 				ResponseExportValues:          responseExportValues,
+				OutputSchema:                  types.MapNull(types.StringType),
+				Authentication:                types.ObjectNull(map[string]attr.Type{"sas_token_env_var": types.StringType, "account_key_env_var": types.StringType, "use_msi": types.BoolType, "client_id": types.StringType}),
 				ReplaceTriggersRefs:           types.ListNull(types.StringType),
 				ReplaceTriggersExternalValues: types.DynamicNull(),
 				Retry:                         retry.NewRetryValueNull(),
@@ -5,6 +5,7 @@ import (
 
 	"github.com/Azure/terraform-provider-azapi/internal/retry"
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -89,14 +90,23 @@ func AzapiResourceActionMigrationV0ToV2(ctx context.Context) resource.StateUpgra
 				Action               types.String        `tfsdk:"action"`
 				Method               types.String        `tfsdk:"method"`
 				Body                 types.Dynamic       `tfsdk:"body"`
+				PayloadFile          types.String        `tfsdk:"payload_file"`
+				PayloadFileHash      types.String        `tfsdk:"payload_file_hash"`
 				When                 types.String        `tfsdk:"when"`
+				CompletionCondition  types.Object        `tfsdk:"completion_condition"`
 				Locks                types.List          `tfsdk:"locks"`
 				ResponseExportValues types.Dynamic       `tfsdk:"response_export_values"`
+				OutputSchema         types.Map           `tfsdk:"output_schema"`
 				Output               types.Dynamic       `tfsdk:"output"`
 				Timeouts             timeouts.Value      `tfsdk:"timeouts"`
 				Retry                retry.RetryValue    `tfsdk:"retry"`
 				Headers              map[string]string   `tfsdk:"headers"`
 				QueryParameters      map[string][]string `tfsdk:"query_parameters"`
+				Triggers             types.Map           `tfsdk:"triggers"`
+				RerunInterval        types.String        `tfsdk:"rerun_interval"`
+				LastPerformedAt      types.String        `tfsdk:"last_performed_at"`
+				SelectSecret         types.String        `tfsdk:"select_secret"`
+				VersionTracking      types.String        `tfsdk:"version_tracking"`
 			}
 
 			var oldState OldModel
@@ -146,9 +156,18 @@ func AzapiResourceActionMigrationV0ToV2(ctx context.Context) resource.StateUpgra
 				When:                 when,
 				Locks:                oldState.Locks,
 				ResponseExportValues: responseExportValues,
+				OutputSchema:         types.MapNull(types.StringType),
 				Output:               outputVal,
 				Timeouts:             oldState.Timeouts,
 				Retry:                retry.NewRetryValueNull(),
+				PayloadFile:          types.StringNull(),
+				PayloadFileHash:      types.StringNull(),
+				Triggers:             types.MapNull(types.StringType),
+				RerunInterval:        types.StringNull(),
+				LastPerformedAt:      types.StringNull(),
+				SelectSecret:         types.StringNull(),
+				VersionTracking:      types.StringNull(),
+				CompletionCondition:  types.ObjectNull(map[string]attr.Type{"expression": types.StringType, "failure_expression": types.StringType, "url": types.StringType, "api_version": types.StringType, "interval_seconds": types.Int64Type}),
 			}
 
 			response.Diagnostics.Append(response.State.Set(ctx, newState)...)
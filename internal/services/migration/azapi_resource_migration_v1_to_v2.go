@@ -2,6 +2,7 @@ package migration
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/Azure/terraform-provider-azapi/internal/retry"
 	"github.com/Azure/terraform-provider-azapi/internal/services/dynamic"
@@ -145,18 +146,39 @@ func AzapiResourceMigrationV1ToV2(ctx context.Context) resource.StateUpgrader {
 				Name                          types.String        `tfsdk:"name"`
 				ParentID                      types.String        `tfsdk:"parent_id"`
 				Type                          types.String        `tfsdk:"type"`
+				UpdateMethod                  types.String        `tfsdk:"update_method"`
+				UseEtag                       types.Bool          `tfsdk:"use_etag"`
 				Location                      types.String        `tfsdk:"location"`
 				Identity                      types.List          `tfsdk:"identity"`
 				Body                          types.Dynamic       `tfsdk:"body"`
+				BodyFile                      types.String        `tfsdk:"body_file"`
+				BodyFileHash                  types.String        `tfsdk:"body_file_hash"`
+				BodyFragments                 types.Dynamic       `tfsdk:"body_fragments"`
+				BodyVars                      types.Map           `tfsdk:"body_vars"`
+				Endpoint                      types.String        `tfsdk:"endpoint"`
+				Etag                          types.String        `tfsdk:"etag"`
+				ExistenceCheckMethod          types.String        `tfsdk:"existence_check_method"`
+				FullName                      types.String        `tfsdk:"full_name"`
 				Locks                         types.List          `tfsdk:"locks"`
 				SchemaValidationEnabled       types.Bool          `tfsdk:"schema_validation_enabled"`
+				StripReadOnly                 types.Bool          `tfsdk:"strip_read_only"`
+				ApplyDefaultNaming            types.Bool          `tfsdk:"apply_default_naming"`
 				IgnoreCasing                  types.Bool          `tfsdk:"ignore_casing"`
 				IgnoreMissingProperty         types.Bool          `tfsdk:"ignore_missing_property"`
+				AuthoritativePaths            types.List          `tfsdk:"authoritative_paths"`
 				ReplaceTriggersExternalValues types.Dynamic       `tfsdk:"replace_triggers_external_values"`
 				ReplaceTriggersRefs           types.List          `tfsdk:"replace_triggers_refs"`
 				ResponseExportValues          types.Dynamic       `tfsdk:"response_export_values"`
+				OutputSchema                  types.Map           `tfsdk:"output_schema"`
+				OutputWaitFor                 types.List          `tfsdk:"output_wait_for"`
+				PollingInterval               types.String        `tfsdk:"polling_interval"`
+				PurgeOnDestroy                types.Bool          `tfsdk:"purge_on_destroy"`
+				PreviousBody                  types.Dynamic       `tfsdk:"previous_body"`
 				Retry                         retry.RetryValue    `tfsdk:"retry"`
 				Output                        types.Dynamic       `tfsdk:"output"`
+				OutputFqdn                    types.String        `tfsdk:"output_fqdn"`
+				OutputId                      types.String        `tfsdk:"output_id"`
+				OutputName                    types.String        `tfsdk:"output_name"`
 				Tags                          types.Map           `tfsdk:"tags"`
 				Timeouts                      timeouts.Value      `tfsdk:"timeouts"`
 				CreateHeaders                 map[string]string   `tfsdk:"create_headers"`
@@ -196,18 +218,39 @@ func AzapiResourceMigrationV1ToV2(ctx context.Context) resource.StateUpgrader {
 				Name:                          oldState.Name,
 				ParentID:                      oldState.ParentID,
 				Type:                          oldState.Type,
+				UpdateMethod:                  types.StringValue(http.MethodPut),
+				UseEtag:                       types.BoolValue(false),
 				Location:                      oldState.Location,
 				Identity:                      oldState.Identity,
 				Body:                          bodyVal,
+				BodyFile:                      types.StringNull(),
+				BodyFileHash:                  types.StringNull(),
+				BodyFragments:                 types.DynamicNull(),
+				BodyVars:                      types.MapNull(types.StringType),
+				Endpoint:                      types.StringNull(),
+				Etag:                          types.StringNull(),
+				ExistenceCheckMethod:          types.StringValue("GET"),
+				FullName:                      oldState.Name,
 				Locks:                         oldState.Locks,
 				SchemaValidationEnabled:       oldState.SchemaValidationEnabled,
+				StripReadOnly:                 types.BoolValue(false),
+				ApplyDefaultNaming:            types.BoolValue(true),
 				IgnoreCasing:                  oldState.IgnoreCasing,
 				IgnoreMissingProperty:         oldState.IgnoreMissingProperty,
+				AuthoritativePaths:            types.ListNull(types.StringType),
 				ReplaceTriggersExternalValues: types.DynamicNull(),
 				ReplaceTriggersRefs:           types.ListNull(types.StringType),
 				ResponseExportValues:          responseExportValues,
+				OutputSchema:                  types.MapNull(types.StringType),
+				OutputWaitFor:                 types.ListNull(types.StringType),
+				PollingInterval:               types.StringNull(),
+				PurgeOnDestroy:                types.BoolValue(false),
+				PreviousBody:                  types.DynamicNull(),
 				Retry:                         retry.NewRetryValueNull(),
 				Output:                        outputVal,
+				OutputFqdn:                    types.StringNull(),
+				OutputId:                      types.StringNull(),
+				OutputName:                    types.StringNull(),
 				Tags:                          oldState.Tags,
 				Timeouts:                      oldState.Timeouts,
 			}
@@ -108,10 +108,12 @@ func AzapiUpdateResourceMigrationV0ToV2(ctx context.Context) resource.StateUpgra
 				IgnoreCasing          types.Bool          `tfsdk:"ignore_casing"`
 				IgnoreMissingProperty types.Bool          `tfsdk:"ignore_missing_property"`
 				ResponseExportValues  types.Dynamic       `tfsdk:"response_export_values"`
+				OutputSchema          types.Map           `tfsdk:"output_schema"`
 				Locks                 types.List          `tfsdk:"locks"`
 				Output                types.Dynamic       `tfsdk:"output"`
 				Timeouts              timeouts.Value      `tfsdk:"timeouts"`
 				Retry                 retry.RetryValue    `tfsdk:"retry"`
+				RestoreOnDestroy      types.Bool          `tfsdk:"restore_on_destroy"`
 				UpdateHeaders         map[string]string   `tfsdk:"update_headers"`
 				UpdateQueryParameters map[string][]string `tfsdk:"update_query_parameters"`
 				ReadHeaders           map[string]string   `tfsdk:"read_headers"`
@@ -153,9 +155,11 @@ func AzapiUpdateResourceMigrationV0ToV2(ctx context.Context) resource.StateUpgra
 				IgnoreCasing:          oldState.IgnoreCasing,
 				IgnoreMissingProperty: oldState.IgnoreMissingProperty,
 				ResponseExportValues:  responseExportValues,
+				OutputSchema:          types.MapNull(types.StringType),
 				Output:                outputVal,
 				Timeouts:              oldState.Timeouts,
 				Retry:                 retry.NewRetryValueNull(),
+				RestoreOnDestroy:      types.BoolValue(false),
 			}
 
 			response.Diagnostics.Append(response.State.Set(ctx, newState)...)
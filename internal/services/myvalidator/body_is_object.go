@@ -0,0 +1,44 @@
+package myvalidator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type bodyIsObject struct{}
+
+func (v bodyIsObject) Description(ctx context.Context) string {
+	return "validate the body is an HCL object"
+}
+
+func (v bodyIsObject) MarkdownDescription(ctx context.Context) string {
+	return "validate the body is an HCL object"
+}
+
+// ValidateDynamic reports the JSON string bodies, which were accepted before v2.0, with a hint of the migration.
+func (_ bodyIsObject) ValidateDynamic(ctx context.Context, req validator.DynamicRequest, resp *validator.DynamicResponse) {
+	raw := req.ConfigValue
+
+	if raw.IsUnknown() || raw.IsNull() || raw.UnderlyingValue() == nil || raw.IsUnderlyingValueNull() || raw.IsUnderlyingValueUnknown() {
+		return
+	}
+
+	value, ok := raw.UnderlyingValue().(types.String)
+	if !ok {
+		return
+	}
+	detail := fmt.Sprintf("The `%s` must be an HCL object, e.g. `{ properties = { ... } }`.", req.Path)
+	var out interface{}
+	if err := json.Unmarshal([]byte(value.ValueString()), &out); err == nil {
+		detail += " It's a JSON string, please remove the `jsonencode` function, or decode the JSON string by the `jsondecode` function."
+	}
+	resp.Diagnostics.AddAttributeError(req.Path, "Invalid body", detail)
+}
+
+func BodyIsObject() validator.Dynamic {
+	return bodyIsObject{}
+}
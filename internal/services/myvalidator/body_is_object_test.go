@@ -0,0 +1,49 @@
+package myvalidator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+func TestBodyIsObject_ValidateDynamic(t *testing.T) {
+	v := bodyIsObject{}
+
+	t.Run("object", func(t *testing.T) {
+		req := validator.DynamicRequest{
+			ConfigValue: basetypes.NewDynamicValue(basetypes.NewObjectValueMust(map[string]attr.Type{}, map[string]attr.Value{})),
+			Path:        path.Root("body"),
+		}
+		resp := &validator.DynamicResponse{
+			Diagnostics: diag.Diagnostics{},
+		}
+
+		v.ValidateDynamic(context.Background(), req, resp)
+
+		if resp.Diagnostics.HasError() {
+			t.Errorf("Expected no errors, but got: %v", resp.Diagnostics)
+		}
+	})
+
+	t.Run("JSON string", func(t *testing.T) {
+		req := validator.DynamicRequest{
+			ConfigValue: basetypes.NewDynamicValue(basetypes.NewStringValue(`{"properties":{}}`)),
+			Path:        path.Root("body"),
+		}
+		resp := &validator.DynamicResponse{
+			Diagnostics: diag.Diagnostics{},
+		}
+
+		v.ValidateDynamic(context.Background(), req, resp)
+
+		if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "jsonencode") {
+			t.Errorf("Expected an error with the migration hint, but got: %v", resp.Diagnostics)
+		}
+	})
+}
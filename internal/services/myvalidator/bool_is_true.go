@@ -0,0 +1,37 @@
+package myvalidator
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+type boolIsTrue struct{}
+
+func (v boolIsTrue) Description(ctx context.Context) string {
+	return "validates that the value is true if it's specified"
+}
+
+func (v boolIsTrue) MarkdownDescription(ctx context.Context) string {
+	return "validates that the value is `true` if it's specified"
+}
+
+func (boolIsTrue) ValidateBool(ctx context.Context, req validator.BoolRequest, resp *validator.BoolResponse) {
+	value := req.ConfigValue
+
+	if value.IsUnknown() || value.IsNull() {
+		return
+	}
+
+	if !value.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid value",
+			"the value must be `true` if it's specified, remove the argument instead of setting it to `false`",
+		)
+	}
+}
+
+func BoolIsTrue() validator.Bool {
+	return boolIsTrue{}
+}
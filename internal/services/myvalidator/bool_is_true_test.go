@@ -0,0 +1,33 @@
+package myvalidator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestBoolIsTrue_ValidateBool(t *testing.T) {
+	testCases := []struct {
+		name      string
+		value     types.Bool
+		wantError bool
+	}{
+		{name: "true", value: types.BoolValue(true)},
+		{name: "false", value: types.BoolValue(false), wantError: true},
+		{name: "null", value: types.BoolNull()},
+		{name: "unknown", value: types.BoolUnknown()},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &validator.BoolResponse{}
+			BoolIsTrue().ValidateBool(context.Background(), validator.BoolRequest{ConfigValue: tc.value, Path: path.Empty()}, resp)
+			if resp.Diagnostics.HasError() != tc.wantError {
+				t.Errorf("Expected error %v, but got: %v", tc.wantError, resp.Diagnostics)
+			}
+		})
+	}
+}
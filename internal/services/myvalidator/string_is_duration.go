@@ -0,0 +1,73 @@
+package myvalidator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+type stringIsDuration struct{}
+
+func (v stringIsDuration) Description(ctx context.Context) string {
+	return "validates that the string is a valid duration, e.g. 30s, 10m, 2h"
+}
+
+func (v stringIsDuration) MarkdownDescription(ctx context.Context) string {
+	return "validates that the string is a valid duration, e.g. 30s, 10m, 2h"
+}
+
+func (stringIsDuration) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	str := req.ConfigValue
+
+	if str.IsUnknown() || str.IsNull() {
+		return
+	}
+
+	if d, err := time.ParseDuration(str.ValueString()); err != nil || d <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid duration",
+			`The value must be a positive duration which consists of a sequence of numbers with a unit suffix, e.g. "30s", "10m", "2h".`,
+		)
+	}
+}
+
+func StringIsDuration() validator.String {
+	return stringIsDuration{}
+}
+
+type stringIsDurationAtLeast struct {
+	min time.Duration
+}
+
+func (v stringIsDurationAtLeast) Description(ctx context.Context) string {
+	return fmt.Sprintf("validates that the string is a valid duration which is at least %s", v.min)
+}
+
+func (v stringIsDurationAtLeast) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("validates that the string is a valid duration which is at least `%s`", v.min)
+}
+
+func (v stringIsDurationAtLeast) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	str := req.ConfigValue
+
+	if str.IsUnknown() || str.IsNull() {
+		return
+	}
+
+	if d, err := time.ParseDuration(str.ValueString()); err != nil || d < v.min {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid duration",
+			fmt.Sprintf(`The value must be a duration of at least %s which consists of a sequence of numbers with a unit suffix, e.g. "30s", "10m", "2h".`, v.min),
+		)
+	}
+}
+
+// StringIsDurationAtLeast returns a validator which checks that the string is a duration which isn't shorter than the minimum,
+// e.g. the polling intervals which are too short flood the resource providers with requests.
+func StringIsDurationAtLeast(min time.Duration) validator.String {
+	return stringIsDurationAtLeast{min: min}
+}
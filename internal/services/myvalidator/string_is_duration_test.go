@@ -0,0 +1,40 @@
+package myvalidator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+func TestStringIsDurationAtLeast_ValidateString(t *testing.T) {
+	v := StringIsDurationAtLeast(time.Second)
+
+	testcases := map[string]bool{
+		"1s":    false,
+		"1m":    false,
+		"999ms": true,
+		"0s":    true,
+		"-5s":   true,
+		"10":    true,
+	}
+	for input, expectError := range testcases {
+		req := validator.StringRequest{
+			ConfigValue: basetypes.NewStringValue(input),
+			Path:        path.Empty(),
+		}
+		resp := &validator.StringResponse{
+			Diagnostics: diag.Diagnostics{},
+		}
+
+		v.ValidateString(context.Background(), req, resp)
+
+		if resp.Diagnostics.HasError() != expectError {
+			t.Errorf("Expected error %v for %q, but got: %v", expectError, input, resp.Diagnostics)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package myvalidator
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+type stringIsHttpsURL struct{}
+
+func (v stringIsHttpsURL) Description(ctx context.Context) string {
+	return "validates that the string is an HTTPS URL without path, query or fragment, e.g. https://westus.management.azure.com"
+}
+
+func (v stringIsHttpsURL) MarkdownDescription(ctx context.Context) string {
+	return "validates that the string is an HTTPS URL without path, query or fragment, e.g. https://westus.management.azure.com"
+}
+
+func (stringIsHttpsURL) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	str := req.ConfigValue
+
+	if str.IsUnknown() || str.IsNull() {
+		return
+	}
+
+	u, err := url.Parse(str.ValueString())
+	if err != nil || u.Scheme != "https" || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid URL",
+			`The value must be an HTTPS URL without path, query or fragment, e.g. "https://westus.management.azure.com".`,
+		)
+	}
+}
+
+func StringIsHttpsURL() validator.String {
+	return stringIsHttpsURL{}
+}
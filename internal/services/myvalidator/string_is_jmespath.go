@@ -0,0 +1,38 @@
+package myvalidator
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/jmespath/go-jmespath"
+)
+
+type stringIsJMESPath struct{}
+
+func (v stringIsJMESPath) Description(ctx context.Context) string {
+	return "validates that the string compiles as a valid JMESPath expression"
+}
+
+func (v stringIsJMESPath) MarkdownDescription(ctx context.Context) string {
+	return "validates that the string compiles as a valid JMESPath expression"
+}
+
+func (stringIsJMESPath) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	str := req.ConfigValue
+
+	if str.IsUnknown() || str.IsNull() {
+		return
+	}
+
+	if _, err := jmespath.Compile(str.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid JMESPath expression",
+			err.Error(),
+		)
+	}
+}
+
+func StringIsJMESPath() validator.String {
+	return stringIsJMESPath{}
+}
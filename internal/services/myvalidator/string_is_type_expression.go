@@ -0,0 +1,38 @@
+package myvalidator
+
+import (
+	"context"
+
+	"github.com/Azure/terraform-provider-azapi/internal/services/dynamic"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+type stringIsTypeExpression struct{}
+
+func (v stringIsTypeExpression) Description(ctx context.Context) string {
+	return "validates that the string is a valid type expression"
+}
+
+func (v stringIsTypeExpression) MarkdownDescription(ctx context.Context) string {
+	return "validates that the string is a valid type expression"
+}
+
+func (stringIsTypeExpression) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	str := req.ConfigValue
+
+	if str.IsUnknown() || str.IsNull() {
+		return
+	}
+
+	if _, err := dynamic.ParseTypeExpression(str.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid type expression",
+			err.Error(),
+		)
+	}
+}
+
+func StringIsTypeExpression() validator.String {
+	return stringIsTypeExpression{}
+}
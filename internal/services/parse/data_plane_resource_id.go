@@ -2,17 +2,34 @@ package parse
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/Azure/terraform-provider-azapi/utils"
 )
 
 type DataPlaneResourceId struct {
-	AzureResourceId   string
-	ApiVersion        string
-	AzureResourceType string
-	Name              string
-	ParentId          string
+	AzureResourceId     string
+	ApiVersion          string
+	AzureResourceType   string
+	Name                string
+	ParentId            string
+	ReadMethod          string
+	CreateUrl           string
+	CreateMethod        string
+	UpdateUrl           string
+	UpdateMethod        string
+	NameProperty        string
+	ContentType         string
+	AlwaysExists        bool
+	DeleteUrl           string
+	DeleteMethod        string
+	DeleteBody          string
+	WriteOnly           bool
+	GeneratedName       bool
+	GeneratedNameHeader string
+	NoUpdate            bool
+	NoDelete            bool
 }
 
 func NewDataPlaneResourceId(name, parentId, resourceType string) (DataPlaneResourceId, error) {
@@ -22,36 +39,112 @@ func NewDataPlaneResourceId(name, parentId, resourceType string) (DataPlaneResou
 	}
 
 	azureResourceId := ""
+	readMethod := ""
+	createUrl, createMethod := "", http.MethodPut
+	updateUrl, updateMethod := "", http.MethodPut
+	nameProperty := ""
+	contentType := ""
+	alwaysExists := false
+	deleteUrl, deleteMethod, deleteBody := "", http.MethodDelete, ""
+	writeOnly := false
+	generatedName := false
+	generatedNameHeader := ""
+	noUpdate, noDelete := false, false
 	if apiPath := findApiPathByResourceType(azureResourceType); apiPath != nil {
-		parts := strings.Split(apiPath.UrlFormat, "/")
-		for i, part := range parts {
-			switch {
-			case part == "{parentId}":
-				parts[i] = parentId
-			case part == "{name}":
-				parts[i] = name
-			case part == "{apiVersion}":
-				parts[i] = apiVersion
-			case strings.HasPrefix(part, "{name="):
-				defaultName := part[6 : len(part)-1]
-				if !strings.EqualFold(name, defaultName) {
-					return DataPlaneResourceId{}, fmt.Errorf("name %s is not equal to %s", name, defaultName)
-				}
-				parts[i] = defaultName
+		azureResourceId, err = buildDataPlaneUrl(apiPath.UrlFormat, name, parentId, apiVersion)
+		if err != nil {
+			return DataPlaneResourceId{}, err
+		}
+		readMethod = apiPath.ReadMethod
+
+		createUrl, updateUrl = azureResourceId, azureResourceId
+		if apiPath.CreateUrlFormat != "" {
+			if createUrl, err = buildDataPlaneUrl(apiPath.CreateUrlFormat, name, parentId, apiVersion); err != nil {
+				return DataPlaneResourceId{}, err
+			}
+		}
+		if apiPath.UpdateUrlFormat != "" {
+			if updateUrl, err = buildDataPlaneUrl(apiPath.UpdateUrlFormat, name, parentId, apiVersion); err != nil {
+				return DataPlaneResourceId{}, err
 			}
 		}
-		azureResourceId = strings.Join(parts, "/")
+		if apiPath.CreateMethod != "" {
+			createMethod = apiPath.CreateMethod
+		}
+		if apiPath.UpdateMethod != "" {
+			updateMethod = apiPath.UpdateMethod
+		}
+		nameProperty = apiPath.NameProperty
+		contentType = apiPath.ContentType
+		alwaysExists = apiPath.AlwaysExists
+
+		deleteUrl = azureResourceId
+		if apiPath.DeleteUrlFormat != "" {
+			if deleteUrl, err = buildDataPlaneUrl(apiPath.DeleteUrlFormat, name, parentId, apiVersion); err != nil {
+				return DataPlaneResourceId{}, err
+			}
+		}
+		if apiPath.DeleteMethod != "" {
+			deleteMethod = apiPath.DeleteMethod
+		}
+		deleteBody = apiPath.DeleteBody
+		writeOnly = apiPath.WriteOnly
+		generatedName, generatedNameHeader = apiPath.GeneratedName, apiPath.GeneratedNameHeader
+		if noUpdate = apiPath.NoUpdate; noUpdate {
+			updateUrl, updateMethod = "", ""
+		}
+		noDelete = apiPath.NoDelete
 	}
 
 	return DataPlaneResourceId{
-		AzureResourceId:   azureResourceId,
-		ApiVersion:        apiVersion,
-		AzureResourceType: azureResourceType,
-		Name:              name,
-		ParentId:          parentId,
+		AzureResourceId:     azureResourceId,
+		ApiVersion:          apiVersion,
+		AzureResourceType:   azureResourceType,
+		Name:                name,
+		ParentId:            parentId,
+		ReadMethod:          readMethod,
+		CreateUrl:           createUrl,
+		CreateMethod:        createMethod,
+		UpdateUrl:           updateUrl,
+		UpdateMethod:        updateMethod,
+		NameProperty:        nameProperty,
+		ContentType:         contentType,
+		AlwaysExists:        alwaysExists,
+		DeleteUrl:           deleteUrl,
+		DeleteMethod:        deleteMethod,
+		DeleteBody:          deleteBody,
+		WriteOnly:           writeOnly,
+		GeneratedName:       generatedName,
+		GeneratedNameHeader: generatedNameHeader,
+		NoUpdate:            noUpdate,
+		NoDelete:            noDelete,
 	}, nil
 }
 
+func buildDataPlaneUrl(urlFormat, name, parentId, apiVersion string) (string, error) {
+	parts := strings.Split(urlFormat, "/")
+	for i, part := range parts {
+		switch {
+		case part == "{parentId}":
+			parts[i] = parentId
+		case part == "{name}":
+			parts[i] = name
+		case part == "{apiVersion}":
+			parts[i] = apiVersion
+		case strings.HasPrefix(part, "{name="):
+			defaultName := part[6 : len(part)-1]
+			if !strings.EqualFold(name, defaultName) {
+				return "", fmt.Errorf("name %s is not equal to %s", name, defaultName)
+			}
+			parts[i] = defaultName
+		case strings.Contains(part, "{name}"):
+			// the name is embedded in the segment, e.g. certificates(thumbprintAlgorithm=sha1,thumbprint={name})
+			parts[i] = strings.ReplaceAll(part, "{name}", name)
+		}
+	}
+	return strings.Join(parts, "/"), nil
+}
+
 // DataPlaneResourceIDWithResourceType parses a Resource ID and resource type into an ResourceId struct
 func DataPlaneResourceIDWithResourceType(azureResourceId, resourceType string) (DataPlaneResourceId, error) {
 	azureResourceType, _, err := utils.GetAzureResourceTypeApiVersion(resourceType)
@@ -73,6 +166,10 @@ func DataPlaneResourceIDWithResourceType(azureResourceId, resourceType string) (
 			case strings.HasPrefix(urlFormatParts[i], "{name"):
 				name = azureResourceIdParts[j]
 				j--
+			case strings.Contains(urlFormatParts[i], "{name}"):
+				prefix, suffix, _ := strings.Cut(urlFormatParts[i], "{name}")
+				name = strings.TrimSuffix(strings.TrimPrefix(azureResourceIdParts[j], prefix), suffix)
+				j--
 			case urlFormatParts[i] == "{parentId}":
 				for j >= 0 {
 					if j > 0 && i > 0 && azureResourceIdParts[j-1] == urlFormatParts[i-1] {
@@ -58,6 +58,203 @@ func Test_NewDataPlaneResourceId(t *testing.T) {
 				AzureResourceType: "Microsoft.KeyVault/vaults/certificates/contacts",
 			},
 		},
+		{
+			Name:         "conn1",
+			ParentId:     "foo.webpubsub.azure.com/api/hubs/hub1/permissions/joinLeaveGroup",
+			ResourceType: "Microsoft.SignalRService/webPubSub/hubs/permissions/connections@2024-01-01",
+			Error:        false,
+			Expected: &parse.DataPlaneResourceId{
+				AzureResourceId:   "foo.webpubsub.azure.com/api/hubs/hub1/permissions/joinLeaveGroup/connections/conn1",
+				ApiVersion:        "2024-01-01",
+				AzureResourceType: "Microsoft.SignalRService/webPubSub/hubs/permissions/connections",
+				ReadMethod:        "HEAD",
+			},
+		},
+		{
+			Name:         "dashboard1",
+			ParentId:     "foo-abcd.eus.grafana.azure.com",
+			ResourceType: "Microsoft.Dashboard/grafana/dashboards@2023-09-01",
+			Error:        false,
+			Expected: &parse.DataPlaneResourceId{
+				AzureResourceId:   "foo-abcd.eus.grafana.azure.com/api/dashboards/uid/dashboard1",
+				ApiVersion:        "2023-09-01",
+				AzureResourceType: "Microsoft.Dashboard/grafana/dashboards",
+				CreateUrl:         "foo-abcd.eus.grafana.azure.com/api/dashboards/db",
+				CreateMethod:      "POST",
+				UpdateUrl:         "foo-abcd.eus.grafana.azure.com/api/dashboards/db",
+				UpdateMethod:      "POST",
+				NameProperty:      "dashboard.uid",
+			},
+		},
+		{
+			Name:         "datasource1",
+			ParentId:     "foo-abcd.eus.grafana.azure.com",
+			ResourceType: "Microsoft.Dashboard/grafana/datasources@2023-09-01",
+			Error:        false,
+			Expected: &parse.DataPlaneResourceId{
+				AzureResourceId:   "foo-abcd.eus.grafana.azure.com/api/datasources/uid/datasource1",
+				ApiVersion:        "2023-09-01",
+				AzureResourceType: "Microsoft.Dashboard/grafana/datasources",
+				CreateUrl:         "foo-abcd.eus.grafana.azure.com/api/datasources",
+				CreateMethod:      "POST",
+				UpdateUrl:         "foo-abcd.eus.grafana.azure.com/api/datasources/uid/datasource1",
+				UpdateMethod:      "PUT",
+				NameProperty:      "uid",
+			},
+		},
+		{
+			Name:         "0123456789abcdef",
+			ParentId:     "foo.eastus.batch.azure.com",
+			ResourceType: "Microsoft.Batch/batchAccounts/certificates@2024-02-01.19.0",
+			Error:        false,
+			Expected: &parse.DataPlaneResourceId{
+				AzureResourceId:   "foo.eastus.batch.azure.com/certificates(thumbprintAlgorithm=sha1,thumbprint=0123456789abcdef)",
+				ApiVersion:        "2024-02-01.19.0",
+				AzureResourceType: "Microsoft.Batch/batchAccounts/certificates",
+				CreateUrl:         "foo.eastus.batch.azure.com/certificates",
+				CreateMethod:      "POST",
+				NameProperty:      "thumbprint",
+				NoUpdate:          true,
+			},
+		},
+		{
+			Name:         "2.15",
+			ParentId:     "foo.confidential-ledger.azure.com",
+			ResourceType: "Microsoft.ConfidentialLedger/ledgers/transactions@2022-05-13",
+			Error:        false,
+			Expected: &parse.DataPlaneResourceId{
+				AzureResourceId:     "foo.confidential-ledger.azure.com/app/transactions/2.15",
+				ApiVersion:          "2022-05-13",
+				AzureResourceType:   "Microsoft.ConfidentialLedger/ledgers/transactions",
+				CreateUrl:           "foo.confidential-ledger.azure.com/app/transactions",
+				CreateMethod:        "POST",
+				GeneratedName:       true,
+				GeneratedNameHeader: "x-ms-ccf-transaction-id",
+				NoUpdate:            true,
+				NoDelete:            true,
+			},
+		},
+		{
+			Name:         "blocklist1",
+			ParentId:     "foo.cognitiveservices.azure.com",
+			ResourceType: "Microsoft.CognitiveServices/accounts/ContentSafety/blocklists@2023-10-01",
+			Error:        false,
+			Expected: &parse.DataPlaneResourceId{
+				AzureResourceId:   "foo.cognitiveservices.azure.com/contentsafety/text/blocklists/blocklist1",
+				ApiVersion:        "2023-10-01",
+				AzureResourceType: "Microsoft.CognitiveServices/accounts/ContentSafety/blocklists",
+				CreateUrl:         "foo.cognitiveservices.azure.com/contentsafety/text/blocklists/blocklist1",
+				CreateMethod:      "PATCH",
+				UpdateUrl:         "foo.cognitiveservices.azure.com/contentsafety/text/blocklists/blocklist1",
+				UpdateMethod:      "PATCH",
+				ContentType:       "application/merge-patch+json",
+			},
+		},
+		{
+			Name:         "assistant1",
+			ParentId:     "foo.openai.azure.com",
+			ResourceType: "Microsoft.CognitiveServices/accounts/OpenAI/assistants@2024-05-01-preview",
+			Error:        false,
+			Expected: &parse.DataPlaneResourceId{
+				AzureResourceId:   "foo.openai.azure.com/openai/assistants/assistant1",
+				ApiVersion:        "2024-05-01-preview",
+				AzureResourceType: "Microsoft.CognitiveServices/accounts/OpenAI/assistants",
+				CreateUrl:         "foo.openai.azure.com/openai/assistants",
+				CreateMethod:      "POST",
+				UpdateUrl:         "foo.openai.azure.com/openai/assistants/assistant1",
+				UpdateMethod:      "POST",
+				GeneratedName:     true,
+			},
+		},
+		{
+			Name:         "file1",
+			ParentId:     "foo.openai.azure.com",
+			ResourceType: "Microsoft.CognitiveServices/accounts/OpenAI/files@2024-10-21",
+			Error:        false,
+			Expected: &parse.DataPlaneResourceId{
+				AzureResourceId:   "foo.openai.azure.com/openai/files/file1",
+				ApiVersion:        "2024-10-21",
+				AzureResourceType: "Microsoft.CognitiveServices/accounts/OpenAI/files",
+				CreateUrl:         "foo.openai.azure.com/openai/files/import",
+				CreateMethod:      "POST",
+				UpdateUrl:         "foo.openai.azure.com/openai/files/file1",
+				UpdateMethod:      "PUT",
+				GeneratedName:     true,
+			},
+		},
+		{
+			Name:         "job1",
+			ParentId:     "foo.openai.azure.com",
+			ResourceType: "Microsoft.CognitiveServices/accounts/OpenAI/fineTuningJobs@2024-10-21",
+			Error:        false,
+			Expected: &parse.DataPlaneResourceId{
+				AzureResourceId:   "foo.openai.azure.com/openai/fine_tuning/jobs/job1",
+				ApiVersion:        "2024-10-21",
+				AzureResourceType: "Microsoft.CognitiveServices/accounts/OpenAI/fineTuningJobs",
+				CreateUrl:         "foo.openai.azure.com/openai/fine_tuning/jobs",
+				CreateMethod:      "POST",
+				UpdateUrl:         "foo.openai.azure.com/openai/fine_tuning/jobs/job1",
+				UpdateMethod:      "PUT",
+				GeneratedName:     true,
+			},
+		},
+		{
+			Name:         "SgxEnclave",
+			ParentId:     "foo.eus.attest.azure.net",
+			ResourceType: "Microsoft.Attestation/attestationProviders/policies@2022-08-01",
+			Error:        false,
+			Expected: &parse.DataPlaneResourceId{
+				AzureResourceId:   "foo.eus.attest.azure.net/policies/SgxEnclave",
+				ApiVersion:        "2022-08-01",
+				AzureResourceType: "Microsoft.Attestation/attestationProviders/policies",
+				ContentType:       "text/plain",
+				AlwaysExists:      true,
+				DeleteUrl:         "foo.eus.attest.azure.net/policies/SgxEnclave:reset",
+				DeleteMethod:      "POST",
+			},
+		},
+		{
+			Name:         "key1",
+			ParentId:     "foo.managedhsm.azure.net",
+			ResourceType: "Microsoft.KeyVault/managedHSMs/keys@7.4",
+			Error:        false,
+			Expected: &parse.DataPlaneResourceId{
+				AzureResourceId:   "foo.managedhsm.azure.net/keys/key1",
+				ApiVersion:        "7.4",
+				AzureResourceType: "Microsoft.KeyVault/managedHSMs/keys",
+				CreateUrl:         "foo.managedhsm.azure.net/keys/key1/create",
+				CreateMethod:      "POST",
+				UpdateUrl:         "foo.managedhsm.azure.net/keys/key1/create",
+				UpdateMethod:      "POST",
+			},
+		},
+		{
+			Name:         "00000000-0000-0000-0000-000000000000",
+			ParentId:     "foo.managedhsm.azure.net/keys",
+			ResourceType: "Microsoft.KeyVault/managedHSMs/roleAssignments@7.4",
+			Error:        false,
+			Expected: &parse.DataPlaneResourceId{
+				AzureResourceId:   "foo.managedhsm.azure.net/keys/providers/Microsoft.Authorization/roleAssignments/00000000-0000-0000-0000-000000000000",
+				ApiVersion:        "7.4",
+				AzureResourceType: "Microsoft.KeyVault/managedHSMs/roleAssignments",
+			},
+		},
+		{
+			Name:         "download",
+			ParentId:     "foo.managedhsm.azure.net",
+			ResourceType: "Microsoft.KeyVault/managedHSMs/securityDomain@7.4",
+			Error:        false,
+			Expected: &parse.DataPlaneResourceId{
+				AzureResourceId:   "foo.managedhsm.azure.net/securitydomain/download",
+				ApiVersion:        "7.4",
+				AzureResourceType: "Microsoft.KeyVault/managedHSMs/securityDomain",
+				CreateUrl:         "foo.managedhsm.azure.net/securitydomain/download",
+				CreateMethod:      "POST",
+				UpdateUrl:         "foo.managedhsm.azure.net/securitydomain/download",
+				UpdateMethod:      "POST",
+				WriteOnly:         true,
+			},
+		},
 	}
 
 	for _, v := range testData {
@@ -84,6 +281,44 @@ func Test_NewDataPlaneResourceId(t *testing.T) {
 		if actual.AzureResourceType != v.Expected.AzureResourceType {
 			t.Fatalf("Expected %q but got %q for AzureResourceType", v.Expected.AzureResourceType, actual.AzureResourceType)
 		}
+		if actual.ReadMethod != v.Expected.ReadMethod {
+			t.Fatalf("Expected %q but got %q for ReadMethod", v.Expected.ReadMethod, actual.ReadMethod)
+		}
+		if v.Expected.CreateUrl != "" {
+			if actual.CreateUrl != v.Expected.CreateUrl || actual.CreateMethod != v.Expected.CreateMethod {
+				t.Fatalf("Expected %s %q but got %s %q for create request", v.Expected.CreateMethod, v.Expected.CreateUrl, actual.CreateMethod, actual.CreateUrl)
+			}
+			if actual.UpdateUrl != v.Expected.UpdateUrl || actual.UpdateMethod != v.Expected.UpdateMethod {
+				t.Fatalf("Expected %s %q but got %s %q for update request", v.Expected.UpdateMethod, v.Expected.UpdateUrl, actual.UpdateMethod, actual.UpdateUrl)
+			}
+		}
+		if actual.NameProperty != v.Expected.NameProperty {
+			t.Fatalf("Expected %q but got %q for NameProperty", v.Expected.NameProperty, actual.NameProperty)
+		}
+		if actual.ContentType != v.Expected.ContentType {
+			t.Fatalf("Expected %q but got %q for ContentType", v.Expected.ContentType, actual.ContentType)
+		}
+		if actual.AlwaysExists != v.Expected.AlwaysExists {
+			t.Fatalf("Expected %v but got %v for AlwaysExists", v.Expected.AlwaysExists, actual.AlwaysExists)
+		}
+		if v.Expected.DeleteUrl != "" && (actual.DeleteUrl != v.Expected.DeleteUrl || actual.DeleteMethod != v.Expected.DeleteMethod) {
+			t.Fatalf("Expected %s %q but got %s %q for delete request", v.Expected.DeleteMethod, v.Expected.DeleteUrl, actual.DeleteMethod, actual.DeleteUrl)
+		}
+		if actual.WriteOnly != v.Expected.WriteOnly {
+			t.Fatalf("Expected %v but got %v for WriteOnly", v.Expected.WriteOnly, actual.WriteOnly)
+		}
+		if actual.GeneratedName != v.Expected.GeneratedName {
+			t.Fatalf("Expected %v but got %v for GeneratedName", v.Expected.GeneratedName, actual.GeneratedName)
+		}
+		if actual.GeneratedNameHeader != v.Expected.GeneratedNameHeader {
+			t.Fatalf("Expected %q but got %q for GeneratedNameHeader", v.Expected.GeneratedNameHeader, actual.GeneratedNameHeader)
+		}
+		if actual.NoUpdate != v.Expected.NoUpdate {
+			t.Fatalf("Expected %v but got %v for NoUpdate", v.Expected.NoUpdate, actual.NoUpdate)
+		}
+		if actual.NoDelete != v.Expected.NoDelete {
+			t.Fatalf("Expected %v but got %v for NoDelete", v.Expected.NoDelete, actual.NoDelete)
+		}
 	}
 }
 
@@ -106,6 +341,30 @@ func Test_DataPlaneResourceIDWithResourceType(t *testing.T) {
 				Name:              "test",
 			},
 		},
+		{
+			ResourceId:   "foo.openai.azure.com/openai/files/file-0123456789abcdef",
+			ResourceType: "Microsoft.CognitiveServices/accounts/OpenAI/files@2024-10-21",
+			Error:        false,
+			Expected: &parse.DataPlaneResourceId{
+				AzureResourceId:   "foo.openai.azure.com/openai/files/file-0123456789abcdef",
+				ApiVersion:        "2024-10-21",
+				AzureResourceType: "Microsoft.CognitiveServices/accounts/OpenAI/files",
+				ParentId:          "foo.openai.azure.com",
+				Name:              "file-0123456789abcdef",
+			},
+		},
+		{
+			ResourceId:   "foo.managedhsm.azure.net/keys/providers/Microsoft.Authorization/roleAssignments/test",
+			ResourceType: "Microsoft.KeyVault/managedHSMs/roleAssignments@7.4",
+			Error:        false,
+			Expected: &parse.DataPlaneResourceId{
+				AzureResourceId:   "foo.managedhsm.azure.net/keys/providers/Microsoft.Authorization/roleAssignments/test",
+				ApiVersion:        "7.4",
+				AzureResourceType: "Microsoft.KeyVault/managedHSMs/roleAssignments",
+				ParentId:          "foo.managedhsm.azure.net/keys",
+				Name:              "test",
+			},
+		},
 		{
 			ResourceId:   "xxx.xxx.xxx/v2/management/groups/test",
 			ResourceType: "Microsoft.DeviceUpdate/accounts/v2/groups@8.2",
@@ -142,6 +401,42 @@ func Test_DataPlaneResourceIDWithResourceType(t *testing.T) {
 				Name:              "",
 			},
 		},
+		{
+			ResourceId:   "foo.service.signalr.net/api/hubs/hub1/users/user1/groups/group1",
+			ResourceType: "Microsoft.SignalRService/signalR/hubs/users/groups@2022-11-01",
+			Error:        false,
+			Expected: &parse.DataPlaneResourceId{
+				AzureResourceId:   "foo.service.signalr.net/api/hubs/hub1/users/user1/groups/group1",
+				ApiVersion:        "2022-11-01",
+				AzureResourceType: "Microsoft.SignalRService/signalR/hubs/users/groups",
+				ParentId:          "foo.service.signalr.net/api/hubs/hub1/users/user1",
+				Name:              "group1",
+			},
+		},
+		{
+			ResourceId:   "foo.eastus.batch.azure.com/certificates(thumbprintAlgorithm=sha1,thumbprint=0123456789abcdef)",
+			ResourceType: "Microsoft.Batch/batchAccounts/certificates@2024-02-01.19.0",
+			Error:        false,
+			Expected: &parse.DataPlaneResourceId{
+				AzureResourceId:   "foo.eastus.batch.azure.com/certificates(thumbprintAlgorithm=sha1,thumbprint=0123456789abcdef)",
+				ApiVersion:        "2024-02-01.19.0",
+				AzureResourceType: "Microsoft.Batch/batchAccounts/certificates",
+				ParentId:          "foo.eastus.batch.azure.com",
+				Name:              "0123456789abcdef",
+			},
+		},
+		{
+			ResourceId:   "foo.eastus.batch.azure.com/jobschedules/schedule1",
+			ResourceType: "Microsoft.Batch/batchAccounts/jobschedules@2024-02-01.19.0",
+			Error:        false,
+			Expected: &parse.DataPlaneResourceId{
+				AzureResourceId:   "foo.eastus.batch.azure.com/jobschedules/schedule1",
+				ApiVersion:        "2024-02-01.19.0",
+				AzureResourceType: "Microsoft.Batch/batchAccounts/jobschedules",
+				ParentId:          "foo.eastus.batch.azure.com",
+				Name:              "schedule1",
+			},
+		},
 	}
 
 	for _, v := range testData {
@@ -171,5 +466,8 @@ func Test_DataPlaneResourceIDWithResourceType(t *testing.T) {
 		if actual.ParentId != v.Expected.ParentId {
 			t.Fatalf("Expected %q but got %q for ParentId", v.Expected.ParentId, actual.ParentId)
 		}
+		if actual.Name != v.Expected.Name {
+			t.Fatalf("Expected %q but got %q for Name", v.Expected.Name, actual.Name)
+		}
 	}
 }
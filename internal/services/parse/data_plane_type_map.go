@@ -10,6 +10,41 @@ type ApiPath struct {
 	ResourceType    string
 	URL             string
 	ParentIDExample string
+	// ReadMethod is the HTTP method used to read the resource, it defaults to GET.
+	// Some data plane APIs only support HEAD to check the existence of a resource.
+	ReadMethod string
+	// CreateUrlFormat and UpdateUrlFormat are the URL formats used to create and update the resource, they default to UrlFormat.
+	// Some data plane APIs create resources by sending a request to the collection instead of the resource itself.
+	CreateUrlFormat string
+	UpdateUrlFormat string
+	// CreateMethod and UpdateMethod are the HTTP methods used to create and update the resource, they default to PUT.
+	CreateMethod string
+	UpdateMethod string
+	// NameProperty is the path of the property in the request body which holds the resource name,
+	// it's only set when the name is not part of the request URL.
+	NameProperty string
+	// ContentType is the content type of the request body, it defaults to application/json.
+	// The body is sent as is when it's a string and the content type is not JSON.
+	ContentType string
+	// AlwaysExists indicates the resource can't be created or deleted, e.g. a policy which has a default value.
+	// Creating the resource updates it and deleting the resource resets it by sending DeleteBody with DeleteMethod to DeleteUrlFormat.
+	AlwaysExists    bool
+	DeleteUrlFormat string
+	DeleteMethod    string
+	DeleteBody      string
+	// WriteOnly indicates the resource can't be read back, e.g. the security domain of a managed HSM which is only returned by the download request.
+	// The output is built from the response of the create request, the refresh keeps the state, and deleting the resource only removes it from the state.
+	WriteOnly bool
+	// GeneratedName indicates the name is generated by the service and returned in the `id` property of the create response, e.g. the Azure OpenAI files.
+	// The resource is created by sending the request to CreateUrlFormat, and the resource ID is built from the generated name.
+	GeneratedName bool
+	// GeneratedNameHeader is the response header which holds the generated name, the `id` property of the response body is used if it's empty.
+	GeneratedNameHeader string
+	// NoUpdate indicates the resource can't be updated, e.g. the Batch certificates which are only created by sending a request to the collection.
+	// The changes of the body replace the resource, and the other changes are only saved to the state.
+	NoUpdate bool
+	// NoDelete indicates the resource can't be deleted, e.g. the confidential ledger entries which are immutable, deleting the resource only removes it from the state.
+	NoDelete bool
 }
 
 var apiPaths = make([]ApiPath, 0)
@@ -38,6 +73,150 @@ const raw = `
     "ParentIDExample": "{storeName}.azconfig.io",
     "Url": "/kv/{key}"
   },
+  {
+    "UrlFormat": "{parentId}/policies/{name}",
+    "ResourceType": "Microsoft.Attestation/attestationProviders/policies",
+    "ParentIDExample": "{providerName}.{region}.attest.azure.net",
+    "Url": "/policies/{attestationType}",
+    "ContentType": "text/plain",
+    "AlwaysExists": true,
+    "DeleteUrlFormat": "{parentId}/policies/{name}:reset",
+    "DeleteMethod": "POST",
+    "DeleteBody": "eyJhbGciOiJub25lIn0.."
+  },
+  {
+    "UrlFormat": "{parentId}/certificates(thumbprintAlgorithm=sha1,thumbprint={name})",
+    "ResourceType": "Microsoft.Batch/batchAccounts/certificates",
+    "ParentIDExample": "{accountName}.{region}.batch.azure.com",
+    "Url": "/certificates(thumbprintAlgorithm=sha1,thumbprint={thumbprint})",
+    "CreateUrlFormat": "{parentId}/certificates",
+    "CreateMethod": "POST",
+    "NameProperty": "thumbprint",
+    "NoUpdate": true
+  },
+  {
+    "UrlFormat": "{parentId}/jobs/{name}",
+    "ResourceType": "Microsoft.Batch/batchAccounts/jobs",
+    "ParentIDExample": "{accountName}.{region}.batch.azure.com",
+    "Url": "/jobs/{jobId}",
+    "CreateUrlFormat": "{parentId}/jobs",
+    "CreateMethod": "POST",
+    "NameProperty": "id"
+  },
+  {
+    "UrlFormat": "{parentId}/jobschedules/{name}",
+    "ResourceType": "Microsoft.Batch/batchAccounts/jobschedules",
+    "ParentIDExample": "{accountName}.{region}.batch.azure.com",
+    "Url": "/jobschedules/{jobScheduleId}",
+    "CreateUrlFormat": "{parentId}/jobschedules",
+    "CreateMethod": "POST",
+    "NameProperty": "id"
+  },
+  {
+    "UrlFormat": "{parentId}/contentsafety/text/blocklists/{name}",
+    "ResourceType": "Microsoft.CognitiveServices/accounts/ContentSafety/blocklists",
+    "ParentIDExample": "{accountName}.cognitiveservices.azure.com",
+    "Url": "/contentsafety/text/blocklists/{blocklistName}",
+    "CreateMethod": "PATCH",
+    "UpdateMethod": "PATCH",
+    "ContentType": "application/merge-patch+json"
+  },
+  {
+    "UrlFormat": "{parentId}/language/authoring/analyze-conversations/projects/{name}",
+    "ResourceType": "Microsoft.CognitiveServices/accounts/Language/analyzeConversationsProjects",
+    "ParentIDExample": "{accountName}.cognitiveservices.azure.com",
+    "Url": "/language/authoring/analyze-conversations/projects/{projectName}",
+    "CreateMethod": "PATCH",
+    "UpdateMethod": "PATCH",
+    "ContentType": "application/merge-patch+json"
+  },
+  {
+    "UrlFormat": "{parentId}/language/authoring/analyze-text/projects/{name}",
+    "ResourceType": "Microsoft.CognitiveServices/accounts/Language/analyzeTextProjects",
+    "ParentIDExample": "{accountName}.cognitiveservices.azure.com",
+    "Url": "/language/authoring/analyze-text/projects/{projectName}",
+    "CreateMethod": "PATCH",
+    "UpdateMethod": "PATCH",
+    "ContentType": "application/merge-patch+json"
+  },
+  {
+    "UrlFormat": "{parentId}/openai/assistants/{name}",
+    "ResourceType": "Microsoft.CognitiveServices/accounts/OpenAI/assistants",
+    "ParentIDExample": "{accountName}.openai.azure.com",
+    "Url": "/openai/assistants/{assistantId}",
+    "CreateUrlFormat": "{parentId}/openai/assistants",
+    "CreateMethod": "POST",
+    "UpdateMethod": "POST",
+    "GeneratedName": true
+  },
+  {
+    "UrlFormat": "{parentId}/openai/files/{name}",
+    "ResourceType": "Microsoft.CognitiveServices/accounts/OpenAI/files",
+    "ParentIDExample": "{accountName}.openai.azure.com",
+    "Url": "/openai/files/{fileId}",
+    "CreateUrlFormat": "{parentId}/openai/files/import",
+    "CreateMethod": "POST",
+    "GeneratedName": true
+  },
+  {
+    "UrlFormat": "{parentId}/openai/fine_tuning/jobs/{name}",
+    "ResourceType": "Microsoft.CognitiveServices/accounts/OpenAI/fineTuningJobs",
+    "ParentIDExample": "{accountName}.openai.azure.com",
+    "Url": "/openai/fine_tuning/jobs/{fineTuningJobId}",
+    "CreateUrlFormat": "{parentId}/openai/fine_tuning/jobs",
+    "CreateMethod": "POST",
+    "GeneratedName": true
+  },
+  {
+    "UrlFormat": "{parentId}/app/users/{name}",
+    "ResourceType": "Microsoft.ConfidentialLedger/ledgers/users",
+    "ParentIDExample": "{ledgerName}.confidential-ledger.azure.com",
+    "Url": "/app/users/{userId}",
+    "CreateMethod": "PATCH",
+    "UpdateMethod": "PATCH",
+    "ContentType": "application/merge-patch+json"
+  },
+  {
+    "UrlFormat": "{parentId}/app/transactions/{name}",
+    "ResourceType": "Microsoft.ConfidentialLedger/ledgers/transactions",
+    "ParentIDExample": "{ledgerName}.confidential-ledger.azure.com",
+    "Url": "/app/transactions/{transactionId}",
+    "CreateUrlFormat": "{parentId}/app/transactions",
+    "CreateMethod": "POST",
+    "GeneratedName": true,
+    "GeneratedNameHeader": "x-ms-ccf-transaction-id",
+    "NoUpdate": true,
+    "NoDelete": true
+  },
+  {
+    "UrlFormat": "{parentId}/api/dashboards/uid/{name}",
+    "ResourceType": "Microsoft.Dashboard/grafana/dashboards",
+    "ParentIDExample": "{grafanaName}-{hash}.{region}.grafana.azure.com",
+    "Url": "/api/dashboards/uid/{uid}",
+    "CreateUrlFormat": "{parentId}/api/dashboards/db",
+    "CreateMethod": "POST",
+    "UpdateUrlFormat": "{parentId}/api/dashboards/db",
+    "UpdateMethod": "POST",
+    "NameProperty": "dashboard.uid"
+  },
+  {
+    "UrlFormat": "{parentId}/api/datasources/uid/{name}",
+    "ResourceType": "Microsoft.Dashboard/grafana/datasources",
+    "ParentIDExample": "{grafanaName}-{hash}.{region}.grafana.azure.com",
+    "Url": "/api/datasources/uid/{uid}",
+    "CreateUrlFormat": "{parentId}/api/datasources",
+    "CreateMethod": "POST",
+    "NameProperty": "uid"
+  },
+  {
+    "UrlFormat": "{parentId}/api/folders/{name}",
+    "ResourceType": "Microsoft.Dashboard/grafana/folders",
+    "ParentIDExample": "{grafanaName}-{hash}.{region}.grafana.azure.com",
+    "Url": "/api/folders/{uid}",
+    "CreateUrlFormat": "{parentId}/api/folders",
+    "CreateMethod": "POST",
+    "NameProperty": "uid"
+  },
   {
     "UrlFormat": "{parentId}/management/groups/{name}",
     "ResourceType": "Microsoft.DeviceUpdate/accounts/groups",
@@ -182,6 +361,37 @@ const raw = `
     "ParentIDExample": "{appSubdomain}.azureiotcentral.com/enrollmentGroups/{enrollmentGroupId}",
     "Url": "/enrollmentGroups/{enrollmentGroupId}/certificates/{entry}"
   },
+  {
+    "UrlFormat": "{parentId}/keys/{name}",
+    "ResourceType": "Microsoft.KeyVault/managedHSMs/keys",
+    "ParentIDExample": "{hsmName}.managedhsm.azure.net",
+    "Url": "/keys/{key-name}",
+    "CreateUrlFormat": "{parentId}/keys/{name}/create",
+    "CreateMethod": "POST",
+    "UpdateUrlFormat": "{parentId}/keys/{name}/create",
+    "UpdateMethod": "POST"
+  },
+  {
+    "UrlFormat": "{parentId}/providers/Microsoft.Authorization/roleAssignments/{name}",
+    "ResourceType": "Microsoft.KeyVault/managedHSMs/roleAssignments",
+    "ParentIDExample": "{hsmName}.managedhsm.azure.net",
+    "Url": "/{scope}/providers/Microsoft.Authorization/roleAssignments/{roleAssignmentName}"
+  },
+  {
+    "UrlFormat": "{parentId}/providers/Microsoft.Authorization/roleDefinitions/{name}",
+    "ResourceType": "Microsoft.KeyVault/managedHSMs/roleDefinitions",
+    "ParentIDExample": "{hsmName}.managedhsm.azure.net",
+    "Url": "/{scope}/providers/Microsoft.Authorization/roleDefinitions/{roleDefinitionName}"
+  },
+  {
+    "UrlFormat": "{parentId}/securitydomain/{name=download}",
+    "ResourceType": "Microsoft.KeyVault/managedHSMs/securityDomain",
+    "ParentIDExample": "{hsmName}.managedhsm.azure.net",
+    "Url": "/securitydomain/download",
+    "CreateMethod": "POST",
+    "UpdateMethod": "POST",
+    "WriteOnly": true
+  },
   {
     "UrlFormat": "{parentId}/certificates/contacts",
     "ResourceType": "Microsoft.KeyVault/vaults/certificates/contacts",
@@ -278,6 +488,27 @@ const raw = `
     "ParentIDExample": "{accountName}.purview.azure.com",
     "Url": "/workflows/{workflowId}"
   },
+  {
+    "UrlFormat": "{parentId}/connections/{name}",
+    "ResourceType": "Microsoft.SignalRService/signalR/hubs/permissions/connections",
+    "ParentIDExample": "{signalRName}.service.signalr.net/api/hubs/{hub}/permissions/{permission}",
+    "Url": "/api/hubs/{hub}/permissions/{permission}/connections/{connectionId}",
+    "ReadMethod": "HEAD"
+  },
+  {
+    "UrlFormat": "{parentId}/groups/{name}",
+    "ResourceType": "Microsoft.SignalRService/signalR/hubs/users/groups",
+    "ParentIDExample": "{signalRName}.service.signalr.net/api/hubs/{hub}/users/{user}",
+    "Url": "/api/hubs/{hub}/users/{user}/groups/{group}",
+    "ReadMethod": "HEAD"
+  },
+  {
+    "UrlFormat": "{parentId}/connections/{name}",
+    "ResourceType": "Microsoft.SignalRService/webPubSub/hubs/permissions/connections",
+    "ParentIDExample": "{webPubSubName}.webpubsub.azure.com/api/hubs/{hub}/permissions/{permission}",
+    "Url": "/api/hubs/{hub}/permissions/{permission}/connections/{connectionId}",
+    "ReadMethod": "HEAD"
+  },
   {
     "UrlFormat": "{parentId}/databases/{name}",
     "ResourceType": "Microsoft.Synapse/workspaces/databases",
@@ -12,6 +12,12 @@ import (
 	"github.com/Azure/terraform-provider-azapi/utils"
 )
 
+// scopeTypesOverrides are the scopes of the resource types whose scopes are unknown in the embedded schema.
+// The deployments could be created at all the scopes, but they can't be created under other resources.
+var scopeTypesOverrides = map[string][]types.ScopeType{
+	"microsoft.resources/deployments": {types.Tenant, types.ManagementGroup, types.Subscription, types.ResourceGroup},
+}
+
 type ResourceId struct {
 	AzureResourceId   string
 	ApiVersion        string
@@ -99,7 +105,7 @@ func newResourceID(name, parentId, resourceType string, skipScopeValidation bool
 	case utils.IsTopLevelResourceType(azureResourceType):
 		// case 1: top level resource, verify parent_id providers correct scope
 		if !skipScopeValidation {
-			if err = validateParentIdScope(resourceDef, parentId); err != nil {
+			if err = validateParentIdScope(azureResourceType, resourceDef, parentId); err != nil {
 				return ResourceId{}, fmt.Errorf("`parent_id is invalid`: %+v", err)
 			}
 		}
@@ -217,7 +223,7 @@ func (id ResourceId) ID() string {
 	return id.AzureResourceId
 }
 
-func validateParentIdScope(resourceDef *types.ResourceType, parentId string) error {
+func validateParentIdScope(azureResourceType string, resourceDef *types.ResourceType, parentId string) error {
 	if resourceDef != nil {
 		scopeTypes := make([]types.ScopeType, 0)
 		for _, scope := range resourceDef.ScopeTypes {
@@ -225,6 +231,13 @@ func validateParentIdScope(resourceDef *types.ResourceType, parentId string) err
 				scopeTypes = append(scopeTypes, scope)
 			}
 		}
+		if overrides, ok := scopeTypesOverrides[strings.ToLower(azureResourceType)]; ok && len(scopeTypes) == 0 {
+			parentIdType := utils.GetResourceType(parentId)
+			if !isScopeResourceType(parentIdType) {
+				return fmt.Errorf("expect ID of the scope whose type is one of %v, but got %s", overrides, parentIdType)
+			}
+			scopeTypes = append(scopeTypes, overrides...)
+		}
 
 		parentIdScope := utils.GetScopeType(parentId)
 		// known scope, use `type` to verify `parent_id`
@@ -251,6 +264,16 @@ func validateParentIdScope(resourceDef *types.ResourceType, parentId string) err
 	return nil
 }
 
+// isScopeResourceType checks if the resource type is the type of a scope, i.e. tenant, management group, subscription or resource group.
+func isScopeResourceType(resourceType string) bool {
+	for _, scopeResourceType := range []string{arm.TenantResourceType.String(), "Microsoft.Management/managementGroups", arm.SubscriptionResourceType.String(), arm.ResourceGroupResourceType.String()} {
+		if strings.EqualFold(resourceType, scopeResourceType) {
+			return true
+		}
+	}
+	return false
+}
+
 func validateParentIdType(azureResourceType string, parentId string) error {
 	parentIdExpectedType := utils.GetParentType(azureResourceType)
 	parentIdType := utils.GetResourceType(parentId)
@@ -634,6 +634,54 @@ func Test_NewResourceID(t *testing.T) {
 			},
 		},
 
+		{
+			// management group scope
+			Name:             "myDeployment",
+			ParentId:         "/providers/Microsoft.Management/managementGroups/myGroup",
+			ResourceType:     "Microsoft.Resources/deployments@2021-04-01",
+			ResourceDefExist: true,
+			Expected: &ResourceId{
+				ApiVersion:        "2021-04-01",
+				AzureResourceType: "Microsoft.Resources/deployments",
+				AzureResourceId:   "/providers/Microsoft.Management/managementGroups/myGroup/providers/Microsoft.Resources/deployments/myDeployment",
+			},
+		},
+
+		{
+			// subscription scope
+			Name:             "myDeployment",
+			ParentId:         "/subscriptions/00000000-0000-0000-0000-000000000000",
+			ResourceType:     "Microsoft.Resources/deployments@2021-04-01",
+			ResourceDefExist: true,
+			Expected: &ResourceId{
+				ApiVersion:        "2021-04-01",
+				AzureResourceType: "Microsoft.Resources/deployments",
+				AzureResourceId:   "/subscriptions/00000000-0000-0000-0000-000000000000/providers/Microsoft.Resources/deployments/myDeployment",
+			},
+		},
+
+		{
+			// resource group scope
+			Name:             "myDeployment",
+			ParentId:         "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/myRg",
+			ResourceType:     "Microsoft.Resources/deployments@2021-04-01",
+			ResourceDefExist: true,
+			Expected: &ResourceId{
+				ApiVersion:        "2021-04-01",
+				AzureResourceType: "Microsoft.Resources/deployments",
+				AzureResourceId:   "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/myRg/providers/Microsoft.Resources/deployments/myDeployment",
+			},
+		},
+
+		{
+			// deployments can't be created under a resource
+			Name:             "myDeployment",
+			ParentId:         "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/myRg/providers/Microsoft.Network/virtualNetworks/myVnet",
+			ResourceType:     "Microsoft.Resources/deployments@2021-04-01",
+			ResourceDefExist: true,
+			Error:            true,
+		},
+
 		{
 			// tenant scope, but child resource
 			Name:             "default",
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/Azure/terraform-provider-azapi/internal/azure"
+	"github.com/Azure/terraform-provider-azapi/utils"
+)
+
+// The performance budget of planning a small resource, which is enforced by Test_PerformanceBudget when the performanceBudgetEnvVar is set.
+// A configuration with thousands of resources created by `for_each` runs these steps for every resource,
+// so the per-resource overhead must stay small and must not grow with the number of the resources in the plan.
+const (
+	// planResourceBudget is the maximum duration of the validation and the dependency hints of a small resource.
+	planResourceBudget = 2 * 1000 * 1000 // 2ms
+	// planDependencyHintsBudget is the maximum duration of recording a resource in the registry of the planned resources, which must not scan the registry.
+	planDependencyHintsBudget = 50 * 1000 // 50µs
+	// readResourceBudget is the maximum duration of merging a response body of a small resource into its configuration.
+	readResourceBudget = 100 * 1000 // 100µs
+	// plannedResourcesCount is the number of the resources which are planned before the budget is measured.
+	plannedResourcesCount = 5000
+	// performanceBudgetEnvVar enables Test_PerformanceBudget, the timings depend on the machine, so it's not run by default.
+	performanceBudgetEnvVar = "AZAPI_PERFORMANCE_BUDGET"
+)
+
+const benchmarkResourceType = "Microsoft.Network/virtualNetworks/subnets"
+const benchmarkApiVersion = "2023-04-01"
+
+func benchmarkResourceId(i int) string {
+	return fmt.Sprintf("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg%d/providers/Microsoft.Network/virtualNetworks/vnet%d/subnets/subnet%d", i%10, i%100, i)
+}
+
+func benchmarkBody(i int) interface{} {
+	var body interface{}
+	_ = json.Unmarshal([]byte(fmt.Sprintf(`{
+  "properties": {
+    "addressPrefix": "10.%d.%d.0/24",
+    "networkSecurityGroup": {
+      "id": "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg%d/providers/Microsoft.Network/networkSecurityGroups/nsg%d"
+    },
+    "serviceEndpoints": [
+      {
+        "service": "Microsoft.Storage"
+      }
+    ]
+  }
+}`, i/256%256, i%256, i%10, i%100)), &body)
+	return body
+}
+
+// planResource runs the per-resource steps of the plan which don't call the Azure APIs.
+func planResource(b *testing.B, registry *plannedResourceRegistry, i int) {
+	resourceDef, err := azure.GetResourceDefinition(benchmarkResourceType, benchmarkApiVersion)
+	if err != nil {
+		b.Fatal(err)
+	}
+	body := benchmarkBody(i)
+	if err := schemaValidation(benchmarkResourceType, benchmarkApiVersion, resourceDef, body); err != nil {
+		b.Fatal(err)
+	}
+	resourceId := benchmarkResourceId(i)
+	registry.planReferences(resourceId, embeddedResourceIds(body))
+	registry.planCreation(resourceId)
+}
+
+func BenchmarkPlanResource(b *testing.B) {
+	// the schema validation logs are discarded, the previous output is restored after the benchmark
+	previousOutput := log.Writer()
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() {
+		log.SetOutput(previousOutput)
+	})
+	registry := newPlannedResourceRegistry()
+	for i := 0; i < plannedResourcesCount; i++ {
+		planResource(b, registry, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		planResource(b, registry, plannedResourcesCount+i)
+	}
+}
+
+func BenchmarkPlanDependencyHints(b *testing.B) {
+	registry := newPlannedResourceRegistry()
+	referencedIds := make([][]string, 0, plannedResourcesCount+1)
+	for i := 0; i <= plannedResourcesCount; i++ {
+		referencedIds = append(referencedIds, embeddedResourceIds(benchmarkBody(i)))
+	}
+	for i := 0; i < plannedResourcesCount; i++ {
+		registry.planReferences(benchmarkResourceId(i), referencedIds[i])
+		registry.planCreation(benchmarkResourceId(i))
+	}
+	resourceId := benchmarkResourceId(plannedResourcesCount)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		registry.planReferences(resourceId, referencedIds[plannedResourcesCount])
+		registry.planCreation(resourceId)
+	}
+}
+
+func BenchmarkReadResource(b *testing.B) {
+	config := benchmarkBody(0)
+	var response interface{}
+	_ = json.Unmarshal([]byte(`{
+  "id": "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg0/providers/Microsoft.Network/virtualNetworks/vnet0/subnets/subnet0",
+  "name": "subnet0",
+  "etag": "W/\"00000000-0000-0000-0000-000000000000\"",
+  "properties": {
+    "provisioningState": "Succeeded",
+    "addressPrefix": "10.0.0.0/24",
+    "networkSecurityGroup": {
+      "id": "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg0/providers/Microsoft.Network/networkSecurityGroups/nsg0"
+    },
+    "serviceEndpoints": [
+      {
+        "service": "Microsoft.Storage",
+        "locations": ["westus", "eastus"],
+        "provisioningState": "Succeeded"
+      }
+    ],
+    "privateEndpointNetworkPolicies": "Disabled",
+    "privateLinkServiceNetworkPolicies": "Enabled"
+  },
+  "type": "Microsoft.Network/virtualNetworks/subnets"
+}`), &response)
+	option := utils.UpdateJsonOption{IgnoreCasing: true, IgnoreMissingProperty: true}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		utils.UpdateObject(config, response, option)
+	}
+}
+
+func BenchmarkGetResourceDefinition(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := azure.GetResourceDefinition(benchmarkResourceType, benchmarkApiVersion); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func Test_PerformanceBudget(t *testing.T) {
+	if os.Getenv(performanceBudgetEnvVar) == "" {
+		t.Skipf("skipping the performance budget, set %s to run it", performanceBudgetEnvVar)
+	}
+	testcases := []struct {
+		Name      string
+		Benchmark func(b *testing.B)
+		Budget    int64
+	}{
+		{
+			Name:      "plan resource",
+			Benchmark: BenchmarkPlanResource,
+			Budget:    planResourceBudget,
+		},
+		{
+			Name:      "plan dependency hints",
+			Benchmark: BenchmarkPlanDependencyHints,
+			Budget:    planDependencyHintsBudget,
+		},
+		{
+			Name:      "read resource",
+			Benchmark: BenchmarkReadResource,
+			Budget:    readResourceBudget,
+		},
+	}
+
+	for _, testcase := range testcases {
+		result := testing.Benchmark(testcase.Benchmark)
+		if result.N == 0 {
+			t.Fatalf("%s: the benchmark failed", testcase.Name)
+		}
+		if result.NsPerOp() > testcase.Budget {
+			t.Errorf("%s: expected at most %dns per resource but got %dns", testcase.Name, testcase.Budget, result.NsPerOp())
+		}
+		t.Logf("%s: %s", testcase.Name, result.String())
+	}
+}
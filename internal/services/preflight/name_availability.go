@@ -0,0 +1,143 @@
+package preflight
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/terraform-provider-azapi/internal/clients"
+	"github.com/Azure/terraform-provider-azapi/internal/services/dynamic"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// nameAvailabilityChecker describes the checkNameAvailability API of a resource type whose name must be globally unique
+type nameAvailabilityChecker struct {
+	Provider   string
+	Action     string
+	ApiVersion string
+	// NameField is the field of the request body which contains the name
+	NameField string
+	// NameFromBody returns the name to check from the resource body, the resource name is checked if it's nil
+	NameFromBody func(body map[string]interface{}) string
+	// AvailableField is the field of the response body which indicates whether the name is available
+	AvailableField string
+}
+
+var nameAvailabilityCheckers = map[string]nameAvailabilityChecker{
+	"microsoft.storage/storageaccounts": {
+		Provider:       "Microsoft.Storage",
+		Action:         "checkNameAvailability",
+		ApiVersion:     "2023-01-01",
+		NameField:      "name",
+		AvailableField: "nameAvailable",
+	},
+	"microsoft.keyvault/vaults": {
+		Provider:       "Microsoft.KeyVault",
+		Action:         "checkNameAvailability",
+		ApiVersion:     "2023-07-01",
+		NameField:      "name",
+		AvailableField: "nameAvailable",
+	},
+	"microsoft.containerregistry/registries": {
+		Provider:       "Microsoft.ContainerRegistry",
+		Action:         "checkNameAvailability",
+		ApiVersion:     "2023-07-01",
+		NameField:      "name",
+		AvailableField: "nameAvailable",
+	},
+	"microsoft.web/sites": {
+		Provider:       "Microsoft.Web",
+		Action:         "checknameavailability",
+		ApiVersion:     "2022-03-01",
+		NameField:      "name",
+		AvailableField: "nameAvailable",
+	},
+	// the name of the cognitive services account isn't globally unique, but its custom subdomain is
+	"microsoft.cognitiveservices/accounts": {
+		Provider:   "Microsoft.CognitiveServices",
+		Action:     "checkDomainAvailability",
+		ApiVersion: "2023-05-01",
+		NameField:  "subdomainName",
+		NameFromBody: func(body map[string]interface{}) string {
+			if properties, ok := body["properties"].(map[string]interface{}); ok {
+				if v, ok := properties["customSubDomainName"].(string); ok {
+					return v
+				}
+			}
+			return ""
+		},
+		AvailableField: "isSubdomainAvailable",
+	},
+}
+
+// IsNameAvailabilityCheckSupported checks if the resource type exposes a checkNameAvailability API
+func IsNameAvailabilityCheckSupported(azureResourceType string) bool {
+	_, ok := nameAvailabilityCheckers[strings.ToLower(azureResourceType)]
+	return ok
+}
+
+// CheckNameAvailability checks whether the globally unique name of the resource is available, and returns an error with the reason if it's taken.
+func CheckNameAvailability(ctx context.Context, client *clients.ResourceClient, subscriptionId string, azureResourceType string, name string, body types.Dynamic) error {
+	checker, ok := nameAvailabilityCheckers[strings.ToLower(azureResourceType)]
+	if !ok {
+		return nil
+	}
+
+	resourceBody := make(map[string]interface{})
+	if checker.NameFromBody != nil {
+		if !dynamic.IsFullyKnown(body) {
+			return nil
+		}
+		data, err := dynamic.ToJSON(body)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &resourceBody); err != nil {
+			return err
+		}
+	}
+	payload, checkedName := nameAvailabilityRequestBody(checker, azureResourceType, name, resourceBody)
+	if checkedName == "" {
+		return nil
+	}
+
+	responseBody, err := client.Action(ctx, fmt.Sprintf("/subscriptions/%s/providers/%s", subscriptionId, checker.Provider), checker.Action, checker.ApiVersion, "POST", payload, clients.DefaultRequestOptions())
+	if err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Skipping the name availability check for resource %s because the request failed: %v", azureResourceType, err))
+		return nil
+	}
+	return nameAvailabilityError(checker, checkedName, responseBody)
+}
+
+func nameAvailabilityRequestBody(checker nameAvailabilityChecker, azureResourceType string, name string, body map[string]interface{}) (map[string]interface{}, string) {
+	checkedName := name
+	if checker.NameFromBody != nil {
+		checkedName = checker.NameFromBody(body)
+	}
+	return map[string]interface{}{
+		checker.NameField: checkedName,
+		"type":            azureResourceType,
+	}, checkedName
+}
+
+func nameAvailabilityError(checker nameAvailabilityChecker, name string, responseBody interface{}) error {
+	responseMap, ok := responseBody.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if available, ok := responseMap[checker.AvailableField].(bool); !ok || available {
+		return nil
+	}
+	reason, _ := responseMap["reason"].(string)
+	message, _ := responseMap["message"].(string)
+	errorMessage := fmt.Sprintf("the name %q is not available", name)
+	if reason != "" {
+		errorMessage += fmt.Sprintf(", reason: %s", reason)
+	}
+	if message != "" {
+		errorMessage += fmt.Sprintf(", message: %s", message)
+	}
+	return fmt.Errorf("%s", errorMessage)
+}
@@ -0,0 +1,103 @@
+package preflight
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_NameAvailabilityRequestBody(t *testing.T) {
+	testcases := []struct {
+		ResourceType string
+		Name         string
+		Body         map[string]interface{}
+		ExpectedBody map[string]interface{}
+		ExpectedName string
+	}{
+		{
+			ResourceType: "Microsoft.Storage/storageAccounts",
+			Name:         "mystorage",
+			ExpectedBody: map[string]interface{}{
+				"name": "mystorage",
+				"type": "Microsoft.Storage/storageAccounts",
+			},
+			ExpectedName: "mystorage",
+		},
+		{
+			ResourceType: "Microsoft.CognitiveServices/accounts",
+			Name:         "myaccount",
+			Body: map[string]interface{}{
+				"properties": map[string]interface{}{
+					"customSubDomainName": "mysubdomain",
+				},
+			},
+			ExpectedBody: map[string]interface{}{
+				"subdomainName": "mysubdomain",
+				"type":          "Microsoft.CognitiveServices/accounts",
+			},
+			ExpectedName: "mysubdomain",
+		},
+		{
+			ResourceType: "Microsoft.CognitiveServices/accounts",
+			Name:         "myaccount",
+			Body:         map[string]interface{}{},
+			ExpectedBody: map[string]interface{}{
+				"subdomainName": "",
+				"type":          "Microsoft.CognitiveServices/accounts",
+			},
+			ExpectedName: "",
+		},
+	}
+
+	for _, testcase := range testcases {
+		if !IsNameAvailabilityCheckSupported(testcase.ResourceType) {
+			t.Fatalf("expect the name availability check to be supported for %s", testcase.ResourceType)
+		}
+		checker := nameAvailabilityCheckers[strings.ToLower(testcase.ResourceType)]
+		body, name := nameAvailabilityRequestBody(checker, testcase.ResourceType, testcase.Name, testcase.Body)
+		if !reflect.DeepEqual(body, testcase.ExpectedBody) {
+			t.Errorf("expect body %v, but got %v", testcase.ExpectedBody, body)
+		}
+		if name != testcase.ExpectedName {
+			t.Errorf("expect name %q, but got %q", testcase.ExpectedName, name)
+		}
+	}
+}
+
+func Test_NameAvailabilityError(t *testing.T) {
+	checker := nameAvailabilityCheckers["microsoft.storage/storageaccounts"]
+	testcases := []struct {
+		Response interface{}
+		Expected string
+	}{
+		{
+			Response: map[string]interface{}{
+				"nameAvailable": true,
+			},
+		},
+		{
+			Response: map[string]interface{}{
+				"nameAvailable": false,
+				"reason":        "AlreadyExists",
+				"message":       "The storage account named mystorage is already taken.",
+			},
+			Expected: `the name "mystorage" is not available, reason: AlreadyExists, message: The storage account named mystorage is already taken.`,
+		},
+		{
+			Response: "unexpected",
+		},
+	}
+
+	for _, testcase := range testcases {
+		err := nameAvailabilityError(checker, "mystorage", testcase.Response)
+		if testcase.Expected == "" {
+			if err != nil {
+				t.Errorf("expect no error, but got %v", err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != testcase.Expected {
+			t.Errorf("expect error %q, but got %v", testcase.Expected, err)
+		}
+	}
+}
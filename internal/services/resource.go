@@ -1,24 +1,45 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/terraform-provider-azapi/azerrors"
 	"github.com/Azure/terraform-provider-azapi/internal/azure"
 	aztypes "github.com/Azure/terraform-provider-azapi/internal/azure/types"
+	"github.com/Azure/terraform-provider-azapi/internal/clients"
+	"github.com/Azure/terraform-provider-azapi/internal/features"
+	"github.com/Azure/terraform-provider-azapi/internal/guardrail"
 	"github.com/Azure/terraform-provider-azapi/internal/services/dynamic"
+	"github.com/Azure/terraform-provider-azapi/internal/services/parse"
 	"github.com/Azure/terraform-provider-azapi/utils"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jmespath/go-jmespath"
 )
 
 func schemaValidation(azureResourceType, apiVersion string, resourceDef *aztypes.ResourceType, body interface{}) error {
-	log.Printf("[INFO] prepare validation for resource type: %s, api-version: %s", azureResourceType, apiVersion)
+	log.Printf("[DEBUG] prepare validation for resource type: %s, api-version: %s", azureResourceType, apiVersion)
 	versions := azure.GetApiVersions(azureResourceType)
 	if len(versions) == 0 {
 		return schemaValidationError(fmt.Sprintf("the argument \"type\" is invalid.\n resource type %s can't be found.\n", azureResourceType))
@@ -50,7 +71,7 @@ func schemaValidation(azureResourceType, apiVersion string, resourceDef *aztypes
 func schemaValidationError(detail string) error {
 	return fmt.Errorf("embedded schema validation failed: %s You can try to update `azapi` provider to "+
 		"the latest version or disable the validation using the feature flag `schema_validation_enabled = false` "+
-		"within the resource block", detail)
+		"within the resource block or the provider block", detail)
 }
 
 func canResourceHaveProperty(resourceDef *aztypes.ResourceType, property string) bool {
@@ -128,6 +149,51 @@ func flattenOutputJMES(responseBody interface{}, paths map[string]string) attr.V
 	return out
 }
 
+// versionTrackingKey is the private state key of the random HMAC key which is used to compute the version_tracking of the resource.
+const versionTrackingKey = "version_tracking_key"
+
+// secretVersionKey returns the HMAC key of the resource from the private state, a random key is generated and stored if there isn't one,
+// so the version_tracking of the different resources can't be correlated or brute-forced without the private state.
+func secretVersionKey(ctx context.Context, private privateState) ([]byte, diag.Diagnostics) {
+	var key []byte
+	if data, diags := private.GetKey(ctx, versionTrackingKey); diags.HasError() {
+		return nil, diags
+	} else if len(data) != 0 && json.Unmarshal(data, &key) == nil && len(key) != 0 {
+		return key, nil
+	}
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Failed to generate the version tracking key", err.Error())
+		return nil, diags
+	}
+	// the private state values must be valid JSON, the key is encoded as a base64 string
+	data, _ := json.Marshal(key)
+	return key, private.SetKey(ctx, versionTrackingKey, data)
+}
+
+// secretVersion returns the HMAC-SHA256 of the secret which is selected from the response body by the JMESPath expression,
+// so the rotation of the secret can be detected without storing it. It returns null if the expression is not specified.
+func secretVersion(responseBody interface{}, selectSecret types.String, key []byte) (types.String, error) {
+	if selectSecret.IsNull() || selectSecret.IsUnknown() {
+		return types.StringNull(), nil
+	}
+	secret, err := jmespath.Search(selectSecret.ValueString(), responseBody)
+	if err != nil {
+		return types.StringNull(), fmt.Errorf("evaluating the `select_secret` expression %q: %+v", selectSecret.ValueString(), err)
+	}
+	if secret == nil {
+		return types.StringNull(), fmt.Errorf("the `select_secret` expression %q doesn't select any value from the response", selectSecret.ValueString())
+	}
+	data, err := json.Marshal(secret)
+	if err != nil {
+		return types.StringNull(), err
+	}
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return types.StringValue(hex.EncodeToString(h.Sum(nil))), nil
+}
+
 func AsStringList(input types.List) []string {
 	var result []string
 	diags := input.ElementsAs(context.Background(), &result, false)
@@ -137,6 +203,47 @@ func AsStringList(input types.List) []string {
 	return result
 }
 
+func AsMapOfString(input types.Map) map[string]string {
+	var result map[string]string
+	diags := input.ElementsAs(context.Background(), &result, false)
+	if diags.HasError() {
+		tflog.Warn(context.Background(), fmt.Sprintf("failed to convert map to string map: %s", diags))
+	}
+	return result
+}
+
+// maxRequestBodySize is the maximum size of the request body accepted by Azure Resource Manager, which is 4MB.
+const maxRequestBodySize = 4 * 1024 * 1024
+
+// validateRequestBodySize returns an error if the request body exceeds the maximum request body size.
+// The body is not validated if it contains unknown values.
+func validateRequestBodySize(body types.Dynamic) error {
+	if body.IsNull() || !dynamic.IsFullyKnown(body) {
+		return nil
+	}
+	data, err := dynamic.ToJSON(body)
+	if err != nil {
+		return nil
+	}
+	return requestBodySizeError(len(data))
+}
+
+// validateMergedRequestBodySize returns an error if the request body, which is merged from the body, the body file and the body fragments, exceeds the maximum request body size.
+func validateMergedRequestBodySize(body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil
+	}
+	return requestBodySizeError(len(data))
+}
+
+func requestBodySizeError(size int) error {
+	if size > maxRequestBodySize {
+		return fmt.Errorf("the request body is %d bytes, which exceeds the maximum request body size of %d bytes (4MB) accepted by Azure Resource Manager", size, maxRequestBodySize)
+	}
+	return nil
+}
+
 func unmarshalBody(input types.Dynamic, out interface{}) error {
 	if input.IsNull() || input.IsUnknown() || input.IsUnderlyingValueUnknown() {
 		return nil
@@ -150,3 +257,688 @@ func unmarshalBody(input types.Dynamic, out interface{}) error {
 	}
 	return nil
 }
+
+// setBodyProperty sets the value of the property specified by a dot-separated path in the body, the intermediate objects are created if missing.
+func setBodyProperty(body map[string]interface{}, path string, value interface{}) {
+	keys := strings.Split(path, ".")
+	current := body
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[key] = next
+		}
+		current = next
+	}
+	current[keys[len(keys)-1]] = value
+}
+
+// mergeBodyFragments deep-merges the body fragments into the body in order, the values in the later fragments override the earlier ones.
+func mergeBodyFragments(body map[string]interface{}, fragments types.Dynamic) (map[string]interface{}, error) {
+	fragmentList, err := bodyFragmentList(fragments)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{} = body
+	for _, fragment := range fragmentList {
+		out = utils.MergeObject(out, fragment)
+	}
+	return out.(map[string]interface{}), nil
+}
+
+func bodyFragmentList(fragments types.Dynamic) ([]interface{}, error) {
+	if fragments.IsNull() || fragments.IsUnknown() || fragments.IsUnderlyingValueUnknown() {
+		return nil, nil
+	}
+	var fragmentList []interface{}
+	if err := unmarshalBody(fragments, &fragmentList); err != nil {
+		return nil, fmt.Errorf("expect a list of objects: %+v", err)
+	}
+	for i, fragment := range fragmentList {
+		if _, ok := fragment.(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("expect the fragment at index %d to be an object, but got %T", i, fragment)
+		}
+	}
+	return fragmentList, nil
+}
+
+// overriddenProperties returns the properties in the input which are also defined in the override, the values are taken from the input.
+func overriddenProperties(input interface{}, override interface{}) interface{} {
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return input
+	}
+	overrideMap, ok := override.(map[string]interface{})
+	if !ok {
+		return input
+	}
+	out := make(map[string]interface{})
+	for key, value := range overrideMap {
+		if inputValue, ok := inputMap[key]; ok {
+			out[key] = overriddenProperties(inputValue, value)
+		}
+	}
+	return out
+}
+
+// flattenBodyFragments builds the body fragments from the body, each fragment only contains the properties which are defined in the original fragment.
+// The properties which are overridden by the later fragments keep their configured values, because they're not reflected in the body.
+func flattenBodyFragments(data []byte, fragments types.Dynamic) (types.Dynamic, error) {
+	var elements []attr.Value
+	switch v := fragments.UnderlyingValue().(type) {
+	case types.Tuple:
+		elements = v.Elements()
+	case types.List:
+		elements = v.Elements()
+	default:
+		return fragments, nil
+	}
+
+	fragmentList, err := bodyFragmentList(fragments)
+	if err != nil {
+		return types.DynamicNull(), err
+	}
+	var body interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return types.DynamicNull(), err
+	}
+
+	elemTypes := make([]attr.Type, 0, len(elements))
+	elemValues := make([]attr.Value, 0, len(elements))
+	for i, element := range elements {
+		var laterFragments interface{} = make(map[string]interface{})
+		for _, fragment := range fragmentList[i+1:] {
+			laterFragments = utils.MergeObject(laterFragments, fragment)
+		}
+		fragmentData, err := json.Marshal(utils.MergeObject(body, overriddenProperties(fragmentList[i], laterFragments)))
+		if err != nil {
+			return types.DynamicNull(), err
+		}
+
+		payload, err := dynamic.FromJSON(fragmentData, element.Type(context.Background()))
+		if err != nil {
+			tflog.Warn(context.Background(), fmt.Sprintf("Failed to parse payload: %s", err.Error()))
+			return fragments, nil
+		}
+		elemTypes = append(elemTypes, payload.UnderlyingValue().Type(context.Background()))
+		elemValues = append(elemValues, payload.UnderlyingValue())
+	}
+	tuple, diags := types.TupleValue(elemTypes, elemValues)
+	if diags.HasError() {
+		return types.DynamicNull(), fmt.Errorf("%s: %s", diags.Errors()[0].Summary(), diags.Errors()[0].Detail())
+	}
+	return types.DynamicValue(tuple), nil
+}
+
+// readBodyFile reads the request body from a JSON file, the ${name} placeholders in the content are replaced by the values in the vars.
+// It returns the body and the SHA256 hash of the rendered content.
+func readBodyFile(filename string, vars map[string]string) (map[string]interface{}, string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err = renderBodyTemplate(data, vars)
+	if err != nil {
+		return nil, "", fmt.Errorf("rendering the content of %q: %+v", filename, err)
+	}
+	body := make(map[string]interface{})
+	if err = json.Unmarshal(data, &body); err != nil {
+		return nil, "", fmt.Errorf("unmarshaling the content of %q: %+v", filename, err)
+	}
+	hash := sha256.Sum256(data)
+	return body, hex.EncodeToString(hash[:]), nil
+}
+
+// locationRequiredScopes are the scopes where the location is required though it's optional in the schema,
+// e.g. the deployments store the deployment data in the location when they're created above the resource group scope.
+var locationRequiredScopes = map[string][]aztypes.ScopeType{
+	"microsoft.resources/deployments": {aztypes.Tenant, aztypes.ManagementGroup, aztypes.Subscription},
+}
+
+// validateLocationForScope checks if the location is specified when it's required at the scope of the parent_id.
+func validateLocationForScope(azureResourceType string, parentId string, location string) error {
+	if location != "" {
+		return nil
+	}
+	parentIdScope := utils.GetScopeType(parentId)
+	for _, scope := range locationRequiredScopes[strings.ToLower(azureResourceType)] {
+		if scope == parentIdScope {
+			return fmt.Errorf("the location is required for %s at the %v scope, please specify the `location`", azureResourceType, scope)
+		}
+	}
+	return nil
+}
+
+// readPayloadFile reads the request payload from a file which is sent as is, e.g. a certificate or an archive.
+// It returns the content and the SHA256 hash of the content.
+func readPayloadFile(filename string) ([]byte, string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, "", err
+	}
+	hash := sha256.Sum256(data)
+	return data, hex.EncodeToString(hash[:]), nil
+}
+
+var bodyTemplatePlaceholderRegex = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// renderBodyTemplate replaces the ${name} placeholders in the content with the values in the vars, $${name} is escaped to a literal ${name}.
+// The values are JSON-escaped, so the quotes, backslashes and newlines in them don't break the JSON document.
+func renderBodyTemplate(content []byte, vars map[string]string) ([]byte, error) {
+	var err error
+	out := bodyTemplatePlaceholderRegex.ReplaceAllFunc(content, func(placeholder []byte) []byte {
+		if bytes.HasPrefix(placeholder, []byte("$$")) {
+			return placeholder[1:]
+		}
+		name := string(bodyTemplatePlaceholderRegex.FindSubmatch(placeholder)[1])
+		value, ok := vars[name]
+		if !ok {
+			if err == nil {
+				err = fmt.Errorf(`the variable %q is not defined in the "body_vars"`, name)
+			}
+			return placeholder
+		}
+		escaped, marshalErr := json.Marshal(value)
+		if marshalErr != nil {
+			if err == nil {
+				err = marshalErr
+			}
+			return placeholder
+		}
+		return escaped[1 : len(escaped)-1]
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// bodyWithBodyFile returns the body read from the body file if it's specified, otherwise it returns the input body.
+func bodyWithBodyFile(body map[string]interface{}, bodyFile types.String, bodyVars types.Map) (map[string]interface{}, error) {
+	if bodyFile.IsNull() || bodyFile.IsUnknown() {
+		return body, nil
+	}
+	fileBody, _, err := readBodyFile(bodyFile.ValueString(), AsMapOfString(bodyVars))
+	if err != nil {
+		return nil, err
+	}
+	return fileBody, nil
+}
+
+// interruptedOperationKey is the private state key of the long-running operation which was cancelled before it completed,
+// it's recorded when the `cancellation_behavior` of the provider is `record` or `cancel`, and removed after the next successful apply.
+const interruptedOperationKey = "interrupted_operation"
+
+type interruptedOperation struct {
+	OperationURL  string `json:"operationUrl"`
+	InterruptedAt string `json:"interruptedAt"`
+}
+
+// recordInterruptedOperation records the operation URL in the private state if the error is caused by an interrupted long-running operation.
+func recordInterruptedOperation(ctx context.Context, private privateState, err error) diag.Diagnostics {
+	var interruptedErr *clients.InterruptedOperationError
+	if !errors.As(err, &interruptedErr) {
+		return nil
+	}
+	data, marshalErr := json.Marshal(interruptedOperation{
+		OperationURL:  interruptedErr.OperationURL,
+		InterruptedAt: time.Now().UTC().Format(time.RFC3339),
+	})
+	if marshalErr != nil {
+		return nil
+	}
+	return private.SetKey(ctx, interruptedOperationKey, data)
+}
+
+// interruptedOperationWarning returns a warning if an interrupted long-running operation is recorded in the private state,
+// because the operation might still be running in Azure and change the resource after the refresh.
+func interruptedOperationWarning(ctx context.Context, private privateState, resourceId string) diag.Diagnostics {
+	data, diags := private.GetKey(ctx, interruptedOperationKey)
+	if diags.HasError() || len(data) == 0 {
+		return diags
+	}
+	var operation interruptedOperation
+	if err := json.Unmarshal(data, &operation); err != nil {
+		return diags
+	}
+	diags.AddWarning("Interrupted long-running operation",
+		fmt.Sprintf("The long-running operation of %s was cancelled at %s before it completed, it might still be running in Azure, the operation can be tracked at %s. The warning is removed after the resource is applied successfully.", resourceId, operation.InterruptedAt, operation.OperationURL))
+	return diags
+}
+
+// provisioningStateFailure returns an error which contains the failure details if the provisioning state of the resource is Failed.
+func provisioningStateFailure(responseBody interface{}) error {
+	bodyMap, ok := responseBody.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	properties, ok := bodyMap["properties"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if provisioningState, ok := properties["provisioningState"].(string); !ok || !strings.EqualFold(provisioningState, "Failed") {
+		return nil
+	}
+
+	details := make(map[string]interface{})
+	for _, key := range []string{"error", "statuses"} {
+		if v := properties[key]; v != nil {
+			details[key] = v
+		}
+	}
+	if v := bodyMap["error"]; v != nil && details["error"] == nil {
+		details["error"] = v
+	}
+	if len(details) == 0 {
+		return fmt.Errorf("the provisioning state of the resource is Failed")
+	}
+	detailsJson, _ := json.MarshalIndent(details, "", "  ")
+	return fmt.Errorf("the provisioning state of the resource is Failed, details: %s", string(detailsJson))
+}
+
+// ignoredProperties returns the paths of the properties in the request body which are dropped or rewritten in the response body.
+// The masked values, e.g. `******` and `<redacted>`, are not considered as rewritten.
+func ignoredProperties(requestBody interface{}, responseBody interface{}, path string, ignoreCasing bool) (dropped []string, rewritten []string) {
+	switch requestValue := requestBody.(type) {
+	case map[string]interface{}:
+		responseMap, ok := responseBody.(map[string]interface{})
+		if !ok {
+			return nil, []string{path}
+		}
+		keys := make([]string, 0, len(requestValue))
+		for key := range requestValue {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if requestValue[key] == nil {
+				continue
+			}
+			propertyPath := key
+			if path != "" {
+				propertyPath = path + "." + key
+			}
+			if responseMap[key] == nil {
+				dropped = append(dropped, propertyPath)
+				continue
+			}
+			d, r := ignoredProperties(requestValue[key], responseMap[key], propertyPath, ignoreCasing)
+			dropped = append(dropped, d...)
+			rewritten = append(rewritten, r...)
+		}
+	case []interface{}:
+		responseArray, ok := responseBody.([]interface{})
+		if !ok || len(requestValue) != len(responseArray) {
+			return nil, []string{path}
+		}
+		for i := range requestValue {
+			d, r := ignoredProperties(requestValue[i], responseArray[i], fmt.Sprintf("%s[%d]", path, i), ignoreCasing)
+			dropped = append(dropped, d...)
+			rewritten = append(rewritten, r...)
+		}
+	case string:
+		responseValue, ok := responseBody.(string)
+		masked := regexp.MustCompile(`^\*+$`).MatchString(responseValue) || responseValue == "<redacted>" || responseValue == ""
+		if !ok || requestValue != responseValue && !(ignoreCasing && strings.EqualFold(requestValue, responseValue)) && !masked {
+			rewritten = append(rewritten, path)
+		}
+	default:
+		if !reflect.DeepEqual(requestBody, responseBody) {
+			rewritten = append(rewritten, path)
+		}
+	}
+	return dropped, rewritten
+}
+
+// requiredTagsDiagnostics returns an error if the tags lack any of the required tag names, which are compared case-insensitively like Azure does.
+func requiredTagsDiagnostics(requiredTags []string, resourceTags types.Map) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if resourceTags.IsUnknown() {
+		return diags
+	}
+	missing := make([]string, 0)
+	for _, requiredTag := range requiredTags {
+		found := false
+		for name := range resourceTags.Elements() {
+			if strings.EqualFold(name, requiredTag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, requiredTag)
+		}
+	}
+	if len(missing) != 0 {
+		diags.AddAttributeError(path.Root("tags"), "Missing required tags",
+			fmt.Sprintf("The tags %s are required by the `required_tags` in the provider, but they're missing in the `tags` of the resource and the `default_tags` of the provider.", strings.Join(missing, ", ")))
+	}
+	return diags
+}
+
+// subscriptionMismatchWarning returns a warning if the resource ID belongs to a subscription which is different from the one configured in the provider.
+// It's usually caused by using a wrong provider alias, which results in confusing errors like 404 when the resource is managed.
+// The check is skipped unless the `warn_on_subscription_mismatch` of the provider is enabled.
+func subscriptionMismatchWarning(attribute string, resourceId types.String, providerData *clients.Client) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if !providerData.Features.WarnOnSubscriptionMismatch || resourceId.IsNull() || resourceId.IsUnknown() {
+		return diags
+	}
+	matches := subscriptionIdRegex.FindStringSubmatch(resourceId.ValueString())
+	if len(matches) != 2 {
+		return diags
+	}
+	if subscriptionId := providerData.Account.GetSubscriptionId(); subscriptionId != "" && !strings.EqualFold(matches[1], subscriptionId) {
+		diags.AddAttributeWarning(path.Root(attribute), "Subscription mismatch",
+			fmt.Sprintf("The subscription %q of the `%s` doesn't match the subscription %q which is configured in the provider, please check whether the correct provider alias is used.", matches[1], attribute, subscriptionId))
+	}
+	return diags
+}
+
+var subscriptionIdRegex = regexp.MustCompile(`(?i)^/subscriptions/([^/]+)`)
+
+// childResourceIds returns the IDs of the existing child resources, which are deleted with the resource by ARM.
+// The child resources of a resource group are listed by the resources API, the others are listed by the child resource types
+// which are defined in the schema, the child resource types which can't be listed are skipped.
+// The child resources which are created by the resource providers, e.g. the master database of a SQL server, are skipped.
+func childResourceIds(ctx context.Context, client *clients.ResourceClient, id parse.ResourceId) []string {
+	listUrls := make(map[string]string)
+	if strings.EqualFold(id.AzureResourceType, arm.ResourceGroupResourceType.String()) {
+		listUrls[id.AzureResourceId+"/resources"] = "2021-04-01"
+	} else {
+		for childType, apiVersion := range azure.GetChildResourceTypes(id.AzureResourceType) {
+			listUrls[id.AzureResourceId+"/"+childType[strings.LastIndex(childType, "/")+1:]] = apiVersion
+		}
+	}
+
+	ids := make([]string, 0)
+	for listUrl, apiVersion := range listUrls {
+		responseBody, err := client.List(ctx, listUrl, apiVersion, clients.DefaultRequestOptions())
+		if err != nil {
+			tflog.Debug(ctx, fmt.Sprintf("skipping the child resources of %s: %+v", listUrl, err))
+			continue
+		}
+		responseMap, ok := responseBody.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		values, ok := responseMap["value"].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, value := range values {
+			if valueMap, ok := value.(map[string]interface{}); ok {
+				if childId, ok := valueMap["id"].(string); ok && childId != "" && !isSystemChildResource(childId) {
+					ids = append(ids, childId)
+				}
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// systemChildResourceNames are the names of the child resources which are created by the resource providers, mapped by the lower-cased resource types.
+var systemChildResourceNames = map[string][]string{
+	"microsoft.sql/servers/databases": {"master"},
+}
+
+// isSystemChildResource returns true if the child resource is created by the resource provider and can't be created by users.
+func isSystemChildResource(childId string) bool {
+	resourceId, err := arm.ParseResourceID(childId)
+	if err != nil {
+		return false
+	}
+	for _, name := range systemChildResourceNames[strings.ToLower(resourceId.ResourceType.String())] {
+		if strings.EqualFold(resourceId.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// outputFqdnPaths are the paths of the response body where the resource providers commonly export the fully qualified domain name
+var outputFqdnPaths = [][]string{
+	{"properties", "fqdn"},
+	{"properties", "fullyQualifiedDomainName"},
+	{"properties", "dnsSettings", "fqdn"},
+	{"properties", "defaultHostName"},
+}
+
+// flattenOutputShortcuts returns the id, name and fully qualified domain name exported by the response body, they're null if they're not present.
+func flattenOutputShortcuts(responseBody interface{}) (id types.String, name types.String, fqdn types.String) {
+	id, name, fqdn = types.StringNull(), types.StringNull(), types.StringNull()
+	bodyMap, ok := responseBody.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if v, ok := bodyMap["id"].(string); ok {
+		id = types.StringValue(v)
+	}
+	if v, ok := bodyMap["name"].(string); ok {
+		name = types.StringValue(v)
+	}
+	for _, fqdnPath := range outputFqdnPaths {
+		var current interface{} = bodyMap
+		for _, key := range fqdnPath {
+			currentMap, ok := current.(map[string]interface{})
+			if !ok {
+				current = nil
+				break
+			}
+			current = currentMap[key]
+		}
+		if v, ok := current.(string); ok && v != "" {
+			fqdn = types.StringValue(v)
+			break
+		}
+	}
+	return
+}
+
+// stripReadOnlyProperties removes the read-only properties of the resource definition from the body.
+func stripReadOnlyProperties(resourceDef *aztypes.ResourceType, body map[string]interface{}) map[string]interface{} {
+	if v, ok := azure.StripReadOnlyProperties(resourceDef, body).(map[string]interface{}); ok {
+		return v
+	}
+	return body
+}
+
+// strippedProperties returns the properties in the input which are removed from the stripped body, e.g. the read-only properties in the configuration.
+// The array items are compared by their indexes, the arrays whose items have no stripped properties are skipped.
+func strippedProperties(input interface{}, stripped interface{}) interface{} {
+	switch inputValue := input.(type) {
+	case map[string]interface{}:
+		strippedMap, ok := stripped.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		out := make(map[string]interface{})
+		for key, value := range inputValue {
+			strippedValue, ok := strippedMap[key]
+			if !ok {
+				out[key] = value
+				continue
+			}
+			if v := strippedProperties(value, strippedValue); v != nil {
+				out[key] = v
+			}
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		return out
+	case []interface{}:
+		strippedArray, ok := stripped.([]interface{})
+		if !ok || len(strippedArray) != len(inputValue) {
+			return nil
+		}
+		out := make([]interface{}, len(inputValue))
+		found := false
+		for i := range inputValue {
+			if out[i] = strippedProperties(inputValue[i], strippedArray[i]); out[i] != nil {
+				found = true
+			}
+		}
+		if !found {
+			return nil
+		}
+		return out
+	}
+	return nil
+}
+
+// restoreStrippedProperties adds the stripped properties back to the body, so the read-only properties in the configuration keep their configured values
+// instead of being compared with the remote values. The arrays whose lengths are changed remotely are kept as they are.
+func restoreStrippedProperties(body interface{}, stripped interface{}) interface{} {
+	switch strippedValue := stripped.(type) {
+	case map[string]interface{}:
+		bodyMap, ok := body.(map[string]interface{})
+		if !ok {
+			return body
+		}
+		out := make(map[string]interface{}, len(bodyMap)+len(strippedValue))
+		for key, value := range bodyMap {
+			out[key] = value
+		}
+		for key, value := range strippedValue {
+			if bodyValue, ok := out[key]; ok {
+				out[key] = restoreStrippedProperties(bodyValue, value)
+			} else {
+				out[key] = value
+			}
+		}
+		return out
+	case []interface{}:
+		bodyArray, ok := body.([]interface{})
+		if !ok || len(bodyArray) != len(strippedValue) {
+			return body
+		}
+		out := make([]interface{}, len(bodyArray))
+		for i := range bodyArray {
+			out[i] = bodyArray[i]
+			if strippedValue[i] != nil {
+				out[i] = restoreStrippedProperties(bodyArray[i], strippedValue[i])
+			}
+		}
+		return out
+	}
+	return body
+}
+
+// flattenEtag returns the etag of the response body, it falls back to the `ETag` response header and it's null if neither is present.
+func flattenEtag(responseBody interface{}, etagHeader string) types.String {
+	if bodyMap, ok := responseBody.(map[string]interface{}); ok {
+		if v, ok := bodyMap["etag"].(string); ok && v != "" {
+			return types.StringValue(v)
+		}
+	}
+	if etagHeader != "" {
+		return types.StringValue(etagHeader)
+	}
+	return types.StringNull()
+}
+
+// etagConflictDetail returns the diagnostic detail of the request which failed because the `If-Match` header doesn't match the current etag of the resource.
+func etagConflictDetail(id string, etag string, err error) string {
+	return fmt.Sprintf("The ETag %q of %s doesn't match the current ETag of the resource, which means the resource has been changed since it was last read. Please refresh the state and apply again.\n\n%+v", etag, id, err)
+}
+
+// operationErrorSummary returns the diagnostic summary of a failed request, the long-running operations which are canceled
+// in Azure are reported distinctly, because they're neither retried nor caused by the configuration, and so are the requests denied by the Azure Policy.
+func operationErrorSummary(err error, summary string) string {
+	var canceledErr *clients.OperationCanceledError
+	if errors.As(err, &canceledErr) {
+		return "Operation canceled"
+	}
+	var policyDeniedErr *azerrors.PolicyDeniedError
+	if errors.As(err, &policyDeniedErr) {
+		return "Request disallowed by policy"
+	}
+	return summary
+}
+
+// guardrailDiagnostics evaluates the policy bundle against the planned body, the violations of the deny policies are reported as errors and the others as warnings.
+func guardrailDiagnostics(bundle *guardrail.Bundle, input guardrail.Input) diag.Diagnostics {
+	var diags diag.Diagnostics
+	violations, err := bundle.Evaluate(input)
+	if err != nil {
+		diags.AddError("Failed to evaluate the policy bundle", err.Error())
+		return diags
+	}
+	for _, violation := range violations {
+		summary := fmt.Sprintf("Policy violation: %s", violation.Policy)
+		detail := fmt.Sprintf("The planned body of %s %q violates the policy %q: %s", input.Type, input.Name, violation.Policy, violation.Message)
+		if violation.Effect == guardrail.EffectWarn {
+			diags.AddAttributeWarning(path.Root("body"), summary, detail)
+		} else {
+			diags.AddAttributeError(path.Root("body"), summary, detail)
+		}
+	}
+	return diags
+}
+
+// importIdWithApiVersion appends the latest api-version of the resource type to the import ID if it's not specified.
+func importIdWithApiVersion(input string) (string, error) {
+	idUrl, err := url.Parse(input)
+	if err != nil {
+		return "", err
+	}
+	if idUrl.Query().Get("api-version") == "" {
+		resourceType := utils.GetResourceType(input)
+		apiVersions := azure.GetApiVersions(resourceType)
+		if len(apiVersions) != 0 {
+			input = fmt.Sprintf("%s?api-version=%s", input, apiVersions[len(apiVersions)-1])
+		}
+	}
+	return input, nil
+}
+
+// readOnlyModeDiagnostics returns an error if the provider runs in the read-only mode, which rejects all the mutating operations before any request is sent.
+func readOnlyModeDiagnostics(userFeatures features.UserFeatures, operation string, resourceId string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if userFeatures.ReadOnly {
+		diags.AddError("The provider is in the read-only mode",
+			fmt.Sprintf("%s %q is rejected because the `read_only` is enabled in the provider, no request is sent to Azure. Disable the `read_only` to apply the changes.", operation, resourceId))
+	}
+	return diags
+}
+
+// appendDeprecationWarnings appends the deprecation notices which are collected from the responses as warnings.
+func appendDeprecationWarnings(diagnostics *diag.Diagnostics, notices *clients.DeprecationNotices) {
+	for _, notice := range notices.Messages() {
+		diagnostics.AddWarning("Deprecated API", fmt.Sprintf("The API is going to be deprecated or removed, please check the response header and migrate to a supported version: %s", notice))
+	}
+}
+
+// waitForOutputPaths reads the resource with the exponential backoff until all the paths in the response body are non-null, or the context is done.
+// It returns the last response body and the paths which are still null.
+func waitForOutputPaths(ctx context.Context, client clients.Requester, id parse.ResourceId, paths []string, responseBody interface{}, options clients.RequestOptions) (interface{}, []string) {
+	interval := time.Second
+	for {
+		missing := make([]string, 0)
+		for _, path := range paths {
+			if value, err := jmespath.Search(path, responseBody); err != nil || value == nil {
+				missing = append(missing, path)
+			}
+		}
+		if len(missing) == 0 {
+			return responseBody, nil
+		}
+
+		tflog.Debug(ctx, fmt.Sprintf("the paths %s of %s are null, reading it again in %s", strings.Join(missing, ", "), id.ID(), interval))
+		select {
+		case <-ctx.Done():
+			return responseBody, missing
+		case <-time.After(interval):
+		}
+		interval = min(interval*2, 30*time.Second)
+
+		body, err := client.Get(ctx, id.AzureResourceId, id.ApiVersion, options)
+		if err != nil {
+			tflog.Debug(ctx, fmt.Sprintf("reading %s: %+v", id.ID(), err))
+			continue
+		}
+		responseBody = body
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Azure/terraform-provider-azapi/internal/azure"
+	"github.com/Azure/terraform-provider-azapi/internal/clients"
+	"github.com/Azure/terraform-provider-azapi/version"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// resourceMetadataKey is the private state key of the metadata of the last apply.
+const resourceMetadataKey = "metadata"
+
+// resourceMetadata is the machine-readable metadata of the last apply, it's stored in the private state,
+// so the future versions of the provider can migrate the resources automatically, e.g. when the api-version is deprecated.
+// The fields are only added, never renamed or removed, so the metadata written by the older versions can always be read.
+type resourceMetadata struct {
+	// ApiVersion is the api-version of the requests
+	ApiVersion string `json:"api_version"`
+	// SchemaHash is the hash of the embedded type definitions of the resource type and api-version, it's empty if the definitions are not found
+	SchemaHash string `json:"schema_hash,omitempty"`
+	// ProviderVersion is the version of the provider which applied the resource
+	ProviderVersion string `json:"provider_version"`
+	// CorrelationId is the `x-ms-correlation-request-id` header of the requests, it's empty if the header is disabled
+	CorrelationId string `json:"correlation_id,omitempty"`
+	// AppliedAt is the time of the apply in RFC3339 format
+	AppliedAt string `json:"applied_at"`
+}
+
+// newResourceMetadata returns the metadata of the apply of the resource type and api-version.
+func newResourceMetadata(client *clients.Client, resourceType string, apiVersion string) resourceMetadata {
+	metadata := resourceMetadata{
+		ApiVersion:      apiVersion,
+		ProviderVersion: version.ProviderVersion,
+		AppliedAt:       time.Now().UTC().Format(time.RFC3339),
+	}
+	if hash, err := azure.GetResourceDefinitionHash(resourceType, apiVersion); err == nil {
+		metadata.SchemaHash = hash
+	}
+	if client != nil {
+		metadata.CorrelationId = client.CorrelationRequestID
+	}
+	return metadata
+}
+
+// writeResourceMetadata stores the metadata of the apply in the private state.
+func writeResourceMetadata(ctx context.Context, private privateState, metadata resourceMetadata) diag.Diagnostics {
+	var diags diag.Diagnostics
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		diags.AddError("Failed to marshal the resource metadata", err.Error())
+		return diags
+	}
+	return private.SetKey(ctx, resourceMetadataKey, data)
+}
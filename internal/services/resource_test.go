@@ -1,12 +1,41 @@
 package services
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/terraform-provider-azapi/internal/azure"
+	"github.com/Azure/terraform-provider-azapi/internal/azure/identity"
+	"github.com/Azure/terraform-provider-azapi/internal/azure/tags"
+	aztypes "github.com/Azure/terraform-provider-azapi/internal/azure/types"
+	"github.com/Azure/terraform-provider-azapi/internal/clients"
+	"github.com/Azure/terraform-provider-azapi/internal/features"
 	"github.com/Azure/terraform-provider-azapi/internal/services/dynamic"
+	"github.com/Azure/terraform-provider-azapi/internal/services/parse"
+	"github.com/Azure/terraform-provider-azapi/utils"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 func Test_FlattenOutputJMES(t *testing.T) {
@@ -107,6 +136,27 @@ func Test_FlattenOutputJMES(t *testing.T) {
 {
   "names": ["test1", "test2"]
 }
+`,
+		},
+		{
+			ResponseBody: `
+{
+  "properties": {
+    "outboundIpAddresses": ["10.0.0.1", "20.0.0.1", "10.0.0.2"]
+  }
+}
+`,
+			Paths: map[string]string{
+				"private_ips": "properties.outboundIpAddresses[?starts_with(@, '10.')]",
+				"first_ip":    "properties.outboundIpAddresses[0]",
+				"last_ips":    "properties.outboundIpAddresses[-2:]",
+			},
+			ExpectJson: `
+{
+  "private_ips": ["10.0.0.1", "10.0.0.2"],
+  "first_ip": "10.0.0.1",
+  "last_ips": ["20.0.0.1", "10.0.0.2"]
+}
 `,
 		},
 	}
@@ -129,3 +179,2037 @@ func Test_FlattenOutputJMES(t *testing.T) {
 		}
 	}
 }
+
+func Test_SetBodyProperty(t *testing.T) {
+	testcases := []struct {
+		Body       string
+		Path       string
+		Value      interface{}
+		ExpectJson string
+	}{
+		{
+			Body:       `{"name": "test"}`,
+			Path:       "uid",
+			Value:      "foo",
+			ExpectJson: `{"name": "test", "uid": "foo"}`,
+		},
+		{
+			Body:       `{"dashboard": {"title": "test"}, "overwrite": true}`,
+			Path:       "dashboard.uid",
+			Value:      "foo",
+			ExpectJson: `{"dashboard": {"title": "test", "uid": "foo"}, "overwrite": true}`,
+		},
+		{
+			Body:       `{}`,
+			Path:       "dashboard.uid",
+			Value:      "foo",
+			ExpectJson: `{"dashboard": {"uid": "foo"}}`,
+		},
+	}
+
+	for _, testcase := range testcases {
+		var body map[string]interface{}
+		var expected interface{}
+		_ = json.Unmarshal([]byte(testcase.Body), &body)
+		_ = json.Unmarshal([]byte(testcase.ExpectJson), &expected)
+
+		setBodyProperty(body, testcase.Path, testcase.Value)
+
+		if !reflect.DeepEqual(interface{}(body), expected) {
+			resultJson, _ := json.Marshal(body)
+			t.Fatalf("Expected %s but got %s", testcase.ExpectJson, string(resultJson))
+		}
+	}
+}
+
+func Test_ValidateRequestBodySize(t *testing.T) {
+	testcases := []struct {
+		Body        types.Dynamic
+		ExpectError bool
+	}{
+		{
+			Body:        types.DynamicNull(),
+			ExpectError: false,
+		},
+		{
+			Body:        types.DynamicUnknown(),
+			ExpectError: false,
+		},
+		{
+			Body:        types.DynamicValue(types.StringValue(strings.Repeat("a", 1024))),
+			ExpectError: false,
+		},
+		{
+			Body:        types.DynamicValue(types.StringValue(strings.Repeat("a", maxRequestBodySize))),
+			ExpectError: true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		err := validateRequestBodySize(testcase.Body)
+		if testcase.ExpectError != (err != nil) {
+			t.Fatalf("Expected error %v but got %v", testcase.ExpectError, err)
+		}
+	}
+
+	// the body fragments are merged into the body before the size is validated
+	body, err := mergeBodyFragments(map[string]interface{}{"location": "westus"}, types.DynamicValue(types.TupleValueMust(
+		[]attr.Type{types.ObjectType{AttrTypes: map[string]attr.Type{"properties": types.StringType}}},
+		[]attr.Value{types.ObjectValueMust(map[string]attr.Type{"properties": types.StringType}, map[string]attr.Value{"properties": types.StringValue(strings.Repeat("a", maxRequestBodySize))})},
+	)))
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	if err := validateMergedRequestBodySize(body); err == nil {
+		t.Fatalf("Expected an error for the merged body")
+	}
+	if err := validateMergedRequestBodySize(map[string]interface{}{"location": "westus"}); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+}
+
+func Test_BuildOutputFromBodyWithOutputSchema(t *testing.T) {
+	responseBody := map[string]interface{}{
+		"properties": map[string]interface{}{
+			"fqdn": "example.azure.com",
+			"port": 443,
+			"subnets": []interface{}{
+				map[string]interface{}{"id": "subnet1"},
+				map[string]interface{}{"id": "subnet2"},
+			},
+		},
+	}
+	responseExportValues := types.DynamicValue(types.MapValueMust(types.StringType, map[string]attr.Value{
+		"fqdn":       types.StringValue("properties.fqdn"),
+		"port":       types.StringValue("properties.port"),
+		"subnet_ids": types.StringValue("properties.subnets[*].id"),
+	}))
+
+	testcases := []struct {
+		OutputSchema map[string]string
+		ExpectType   attr.Type
+		ExpectError  bool
+	}{
+		{
+			OutputSchema: nil,
+			ExpectType: types.ObjectType{AttrTypes: map[string]attr.Type{
+				"fqdn":       types.StringType,
+				"port":       types.NumberType,
+				"subnet_ids": types.TupleType{ElemTypes: []attr.Type{types.StringType, types.StringType}},
+			}},
+		},
+		{
+			OutputSchema: map[string]string{
+				"fqdn":       "string",
+				"subnet_ids": "list(string)",
+			},
+			ExpectType: types.ObjectType{AttrTypes: map[string]attr.Type{
+				"fqdn":       types.StringType,
+				"port":       types.NumberType,
+				"subnet_ids": types.ListType{ElemType: types.StringType},
+			}},
+		},
+		{
+			OutputSchema: map[string]string{
+				"port": "string",
+			},
+			ExpectError: true,
+		},
+		{
+			OutputSchema: map[string]string{
+				"location": "string",
+			},
+			ExpectError: true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		outputSchema := types.MapNull(types.StringType)
+		if testcase.OutputSchema != nil {
+			elements := make(map[string]attr.Value)
+			for k, v := range testcase.OutputSchema {
+				elements[k] = types.StringValue(v)
+			}
+			outputSchema = types.MapValueMust(types.StringType, elements)
+		}
+
+		output, err := buildOutputFromBody(responseBody, responseExportValues, outputSchema)
+		if testcase.ExpectError {
+			if err == nil {
+				t.Fatalf("Expected error but got nil")
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Expected no error but got %v", err)
+		}
+		actual := output.UnderlyingValue()
+		if v, ok := actual.(types.Dynamic); ok {
+			actual = v.UnderlyingValue()
+		}
+		if actualType := actual.Type(context.Background()); !actualType.Equal(testcase.ExpectType) {
+			t.Fatalf("Expected type %s but got %s", testcase.ExpectType, actualType)
+		}
+	}
+}
+
+func Test_ValidateOutputSchema(t *testing.T) {
+	outputSchema := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"fqdn":       types.StringValue("string"),
+		"properties": types.StringValue("any"),
+	})
+	paths := func(values ...string) types.Dynamic {
+		elements := make([]attr.Value, 0, len(values))
+		elementTypes := make([]attr.Type, 0, len(values))
+		for _, v := range values {
+			elements = append(elements, types.StringValue(v))
+			elementTypes = append(elementTypes, types.StringType)
+		}
+		return types.DynamicValue(types.TupleValueMust(elementTypes, elements))
+	}
+
+	testcases := []struct {
+		ResponseExportValues types.Dynamic
+		ExpectErrors         int
+	}{
+		{
+			ResponseExportValues: types.DynamicValue(types.MapValueMust(types.StringType, map[string]attr.Value{
+				"fqdn":       types.StringValue("properties.fqdn"),
+				"properties": types.StringValue("properties"),
+			})),
+		},
+		{
+			ResponseExportValues: types.DynamicValue(types.MapValueMust(types.StringType, map[string]attr.Value{
+				"fqdn": types.StringValue("properties.fqdn"),
+			})),
+			ExpectErrors: 1,
+		},
+		{
+			// the list of the paths exports the top level properties
+			ResponseExportValues: paths("properties.fqdn"),
+			ExpectErrors:         1,
+		},
+		{
+			ResponseExportValues: paths("*"),
+		},
+		{
+			ResponseExportValues: types.DynamicNull(),
+			ExpectErrors:         2,
+		},
+		{
+			ResponseExportValues: types.DynamicUnknown(),
+		},
+	}
+
+	for _, testcase := range testcases {
+		if diags := validateOutputSchema(testcase.ResponseExportValues, outputSchema); diags.ErrorsCount() != testcase.ExpectErrors {
+			t.Fatalf("Expected %d errors but got %v", testcase.ExpectErrors, diags)
+		}
+	}
+	if diags := validateOutputSchema(paths("properties.fqdn"), types.MapNull(types.StringType)); diags.HasError() {
+		t.Fatalf("Expected no errors without the output schema but got %v", diags)
+	}
+}
+
+func Test_MergeBodyFragments(t *testing.T) {
+	testcases := []struct {
+		Body        string
+		Fragments   string
+		ExpectJson  string
+		ExpectError bool
+	}{
+		{
+			Body:       `{"properties":{"sku":"Basic"}}`,
+			Fragments:  `null`,
+			ExpectJson: `{"properties":{"sku":"Basic"}}`,
+		},
+		{
+			Body:       `{"properties":{"sku":"Basic","enabled":false}}`,
+			Fragments:  `[{"properties":{"sku":"Standard"}},{"properties":{"enabled":true},"tags":{"env":"prod"}}]`,
+			ExpectJson: `{"properties":{"sku":"Standard","enabled":true},"tags":{"env":"prod"}}`,
+		},
+		{
+			Body:       `{}`,
+			Fragments:  `[{"properties":{"sku":"Basic"}},{"properties":{"sku":"Premium"}}]`,
+			ExpectJson: `{"properties":{"sku":"Premium"}}`,
+		},
+		{
+			Body:        `{}`,
+			Fragments:   `["invalid"]`,
+			ExpectError: true,
+		},
+		{
+			Body:        `{}`,
+			Fragments:   `{"properties":{}}`,
+			ExpectError: true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		var body map[string]interface{}
+		_ = json.Unmarshal([]byte(testcase.Body), &body)
+		fragments := types.DynamicNull()
+		if testcase.Fragments != "null" {
+			var err error
+			fragments, err = dynamic.FromJSONImplied([]byte(testcase.Fragments))
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		result, err := mergeBodyFragments(body, fragments)
+		if testcase.ExpectError {
+			if err == nil {
+				t.Fatalf("Expected error but got nil")
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Expected no error but got %v", err)
+		}
+
+		var expected interface{}
+		_ = json.Unmarshal([]byte(testcase.ExpectJson), &expected)
+		if !reflect.DeepEqual(interface{}(result), expected) {
+			resultJson, _ := json.Marshal(result)
+			t.Fatalf("Expected %s but got %s", testcase.ExpectJson, string(resultJson))
+		}
+	}
+}
+
+func Test_FlattenBodyFragments(t *testing.T) {
+	testcases := []struct {
+		Body       string
+		Fragments  string
+		ExpectJson string
+	}{
+		{
+			Body:       `{"properties":{"sku":"Premium","enabled":true},"tags":{"env":"dev"}}`,
+			Fragments:  `[{"properties":{"sku":"Standard"}},{"tags":{"env":"prod"}}]`,
+			ExpectJson: `[{"properties":{"sku":"Premium"}},{"tags":{"env":"dev"}}]`,
+		},
+		{
+			// the sku in the first fragment is overridden by the second fragment, it keeps the configured value
+			Body:       `{"properties":{"sku":"Premium","enabled":false}}`,
+			Fragments:  `[{"properties":{"sku":"Standard","enabled":true}},{"properties":{"sku":"Basic"}}]`,
+			ExpectJson: `[{"properties":{"enabled":false,"sku":"Standard"}},{"properties":{"sku":"Premium"}}]`,
+		},
+	}
+
+	for _, testcase := range testcases {
+		fragments, err := dynamic.FromJSONImplied([]byte(testcase.Fragments))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := flattenBodyFragments([]byte(testcase.Body), fragments)
+		if err != nil {
+			t.Fatalf("Expected no error but got %v", err)
+		}
+
+		resultJson, err := dynamic.ToJSON(result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(resultJson) != testcase.ExpectJson {
+			t.Fatalf("Expected %s but got %s", testcase.ExpectJson, string(resultJson))
+		}
+	}
+}
+
+func Test_OverriddenProperties(t *testing.T) {
+	var input, override interface{}
+	_ = json.Unmarshal([]byte(`{"properties":{"sku":"Basic","enabled":false,"rules":{"a":1}},"tags":{"env":"dev"}}`), &input)
+	_ = json.Unmarshal([]byte(`{"properties":{"sku":"Standard","rules":"all"},"location":"westus"}`), &override)
+
+	var expected interface{}
+	_ = json.Unmarshal([]byte(`{"properties":{"sku":"Basic","rules":{"a":1}}}`), &expected)
+	if result := overriddenProperties(input, override); !reflect.DeepEqual(result, expected) {
+		resultJson, _ := json.Marshal(result)
+		t.Fatalf("Expected %v but got %s", expected, string(resultJson))
+	}
+}
+
+func Test_ReadBodyFile(t *testing.T) {
+	dir := t.TempDir()
+	testcases := []struct {
+		Content     string
+		ExpectJson  string
+		ExpectError bool
+	}{
+		{
+			Content:    `{"properties":{"sku":"Basic"}}`,
+			ExpectJson: `{"properties":{"sku":"Basic"}}`,
+		},
+		{
+			Content:     `{"properties":`,
+			ExpectError: true,
+		},
+		{
+			Content:     `["properties"]`,
+			ExpectError: true,
+		},
+	}
+
+	for i, testcase := range testcases {
+		filename := filepath.Join(dir, fmt.Sprintf("body%d.json", i))
+		if err := os.WriteFile(filename, []byte(testcase.Content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		body, hash, err := readBodyFile(filename, nil)
+		if testcase.ExpectError {
+			if err == nil {
+				t.Fatalf("Expected error but got nil")
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Expected no error but got %v", err)
+		}
+
+		var expected interface{}
+		_ = json.Unmarshal([]byte(testcase.ExpectJson), &expected)
+		if !reflect.DeepEqual(interface{}(body), expected) {
+			resultJson, _ := json.Marshal(body)
+			t.Fatalf("Expected %s but got %s", testcase.ExpectJson, string(resultJson))
+		}
+		if expectHash := fmt.Sprintf("%x", sha256.Sum256([]byte(testcase.Content))); hash != expectHash {
+			t.Fatalf("Expected hash %s but got %s", expectHash, hash)
+		}
+	}
+
+	if _, _, err := readBodyFile(filepath.Join(dir, "not_exist.json"), nil); err == nil {
+		t.Fatalf("Expected error but got nil")
+	}
+}
+
+func Test_ValidateLocationForScope(t *testing.T) {
+	testcases := []struct {
+		ResourceType string
+		ParentId     string
+		Location     string
+		ExpectError  bool
+	}{
+		{
+			ResourceType: "Microsoft.Resources/deployments",
+			ParentId:     "/",
+			ExpectError:  true,
+		},
+		{
+			ResourceType: "Microsoft.Resources/deployments",
+			ParentId:     "/providers/Microsoft.Management/managementGroups/myGroup",
+			ExpectError:  true,
+		},
+		{
+			ResourceType: "Microsoft.Resources/deployments",
+			ParentId:     "/providers/Microsoft.Management/managementGroups/myGroup",
+			Location:     "westus",
+		},
+		{
+			ResourceType: "Microsoft.Resources/deployments",
+			ParentId:     "/subscriptions/00000000-0000-0000-0000-000000000000",
+			ExpectError:  true,
+		},
+		{
+			ResourceType: "Microsoft.Resources/deployments",
+			ParentId:     "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/myRg",
+		},
+		{
+			ResourceType: "Microsoft.Network/virtualNetworks",
+			ParentId:     "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/myRg",
+		},
+	}
+
+	for _, testcase := range testcases {
+		err := validateLocationForScope(testcase.ResourceType, testcase.ParentId, testcase.Location)
+		if testcase.ExpectError != (err != nil) {
+			t.Fatalf("Expected error %v but got %v for %s under %s", testcase.ExpectError, err, testcase.ResourceType, testcase.ParentId)
+		}
+	}
+}
+
+func Test_ReadOnlyModeDiagnostics(t *testing.T) {
+	userFeatures := features.Default()
+	if diags := readOnlyModeDiagnostics(userFeatures, "Deleting", "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1"); diags.HasError() {
+		t.Fatalf("Expected no error but got %v", diags)
+	}
+
+	userFeatures.ReadOnly = true
+	diags := readOnlyModeDiagnostics(userFeatures, "Deleting", "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1")
+	if !diags.HasError() {
+		t.Fatalf("Expected an error but got nil")
+	}
+	if detail := diags.Errors()[0].Detail(); !strings.Contains(detail, `Deleting "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1" is rejected`) {
+		t.Fatalf("Unexpected error detail: %s", detail)
+	}
+}
+
+func Test_ReadPayloadFile(t *testing.T) {
+	content := []byte{0x30, 0x82, 0x01, 0x0a, 0x00, 0xff}
+	filename := filepath.Join(t.TempDir(), "payload.pfx")
+	if err := os.WriteFile(filename, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	payload, hash, err := readPayloadFile(filename)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	if !bytes.Equal(payload, content) {
+		t.Fatalf("Expected %x but got %x", content, payload)
+	}
+	if expectHash := fmt.Sprintf("%x", sha256.Sum256(content)); hash != expectHash {
+		t.Fatalf("Expected hash %s but got %s", expectHash, hash)
+	}
+
+	if _, _, err := readPayloadFile(filepath.Join(t.TempDir(), "not_exist.pfx")); err == nil {
+		t.Fatalf("Expected error but got nil")
+	}
+}
+
+func Test_RenderBodyTemplate(t *testing.T) {
+	vars := map[string]string{
+		"location":    "westus",
+		"count":       "3",
+		"description": "a \"quoted\" value\\with\nlines",
+	}
+	testcases := []struct {
+		Content     string
+		Expect      string
+		ExpectError bool
+	}{
+		{
+			Content: `{"location":"${location}","properties":{"count":${count}}}`,
+			Expect:  `{"location":"westus","properties":{"count":3}}`,
+		},
+		{
+			Content: `{"expression":"$${location}","location":"${location}"}`,
+			Expect:  `{"expression":"${location}","location":"westus"}`,
+		},
+		{
+			Content: `{"properties":{}}`,
+			Expect:  `{"properties":{}}`,
+		},
+		{
+			// the values are JSON-escaped
+			Content: `{"properties":{"description":"${description}"}}`,
+			Expect:  `{"properties":{"description":"a \"quoted\" value\\with\nlines"}}`,
+		},
+		{
+			Content:     `{"name":"${name}"}`,
+			ExpectError: true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		result, err := renderBodyTemplate([]byte(testcase.Content), vars)
+		if testcase.ExpectError {
+			if err == nil {
+				t.Fatalf("Expected error but got nil")
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Expected no error but got %v", err)
+		}
+		if string(result) != testcase.Expect {
+			t.Fatalf("Expected %s but got %s", testcase.Expect, string(result))
+		}
+	}
+}
+
+func Test_ProvisioningStateFailure(t *testing.T) {
+	testcases := []struct {
+		Body        string
+		ExpectError string
+	}{
+		{
+			Body: `{"properties":{"provisioningState":"Succeeded"}}`,
+		},
+		{
+			Body: `{"name":"test"}`,
+		},
+		{
+			Body:        `{"properties":{"provisioningState":"Failed"}}`,
+			ExpectError: "the provisioning state of the resource is Failed",
+		},
+		{
+			Body:        `{"properties":{"provisioningState":"failed","statuses":[{"code":"ProvisioningState/failed","message":"disk is full"}]}}`,
+			ExpectError: "disk is full",
+		},
+		{
+			Body:        `{"properties":{"provisioningState":"Failed"},"error":{"code":"InternalError","message":"something went wrong"}}`,
+			ExpectError: "something went wrong",
+		},
+	}
+
+	for _, testcase := range testcases {
+		var body interface{}
+		_ = json.Unmarshal([]byte(testcase.Body), &body)
+		err := provisioningStateFailure(body)
+		if testcase.ExpectError == "" {
+			if err != nil {
+				t.Fatalf("Expected no error but got %v", err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), testcase.ExpectError) {
+			t.Fatalf("Expected error containing %q but got %v", testcase.ExpectError, err)
+		}
+	}
+}
+
+func Test_IgnoredProperties(t *testing.T) {
+	var requestBody, responseBody interface{}
+	_ = json.Unmarshal([]byte(`{"properties":{"sku":"Basic","skuName":"Basic","publicNetworkAccess":"enabled","password":"secret","rules":[{"name":"a"}],"count":1},"tags":{"env":"dev"}}`), &requestBody)
+	_ = json.Unmarshal([]byte(`{"properties":{"sku":"Basic","publicNetworkAccess":"Enabled","password":"******","rules":[{"name":"a"},{"name":"b"}],"count":1,"provisioningState":"Succeeded"},"tags":{"env":"dev"}}`), &responseBody)
+
+	testcases := []struct {
+		IgnoreCasing    bool
+		ExpectDropped   []string
+		ExpectRewritten []string
+	}{
+		{
+			IgnoreCasing:    false,
+			ExpectDropped:   []string{"properties.skuName"},
+			ExpectRewritten: []string{"properties.publicNetworkAccess", "properties.rules"},
+		},
+		{
+			IgnoreCasing:    true,
+			ExpectDropped:   []string{"properties.skuName"},
+			ExpectRewritten: []string{"properties.rules"},
+		},
+	}
+
+	for _, testcase := range testcases {
+		dropped, rewritten := ignoredProperties(requestBody, responseBody, "", testcase.IgnoreCasing)
+		if !reflect.DeepEqual(dropped, testcase.ExpectDropped) {
+			t.Fatalf("Expected dropped properties %v but got %v", testcase.ExpectDropped, dropped)
+		}
+		if !reflect.DeepEqual(rewritten, testcase.ExpectRewritten) {
+			t.Fatalf("Expected rewritten properties %v but got %v", testcase.ExpectRewritten, rewritten)
+		}
+	}
+}
+
+func Test_SubscriptionMismatchWarning(t *testing.T) {
+	providerData := &clients.Client{
+		Features: features.Default(),
+		Account:  clients.NewResourceManagerAccount("", "00000000-0000-0000-0000-000000000000"),
+	}
+	providerData.Features.WarnOnSubscriptionMismatch = true
+	testcases := []struct {
+		ResourceId    types.String
+		ExpectWarning bool
+	}{
+		{
+			ResourceId:    types.StringValue("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1"),
+			ExpectWarning: false,
+		},
+		{
+			ResourceId:    types.StringValue("/SUBSCRIPTIONS/00000000-0000-0000-0000-000000000000"),
+			ExpectWarning: false,
+		},
+		{
+			ResourceId:    types.StringValue("/providers/Microsoft.Management/managementGroups/mg1"),
+			ExpectWarning: false,
+		},
+		{
+			ResourceId:    types.StringUnknown(),
+			ExpectWarning: false,
+		},
+		{
+			ResourceId:    types.StringValue("/subscriptions/11111111-1111-1111-1111-111111111111/resourceGroups/rg1"),
+			ExpectWarning: true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		diags := subscriptionMismatchWarning("parent_id", testcase.ResourceId, providerData)
+		if diags.HasError() {
+			t.Fatalf("Expected no error but got %v", diags)
+		}
+		if hasWarning := diags.WarningsCount() != 0; hasWarning != testcase.ExpectWarning {
+			t.Fatalf("Expected warning %v for %v but got %v", testcase.ExpectWarning, testcase.ResourceId, diags)
+		}
+	}
+
+	// the warning is disabled by default
+	providerData.Features.WarnOnSubscriptionMismatch = false
+	if diags := subscriptionMismatchWarning("parent_id", types.StringValue("/subscriptions/11111111-1111-1111-1111-111111111111/resourceGroups/rg1"), providerData); diags.WarningsCount() != 0 {
+		t.Fatalf("Expected no warning but got %v", diags)
+	}
+}
+
+func Test_FlattenOutputShortcuts(t *testing.T) {
+	testcases := []struct {
+		Body       string
+		ExpectId   types.String
+		ExpectName types.String
+		ExpectFqdn types.String
+	}{
+		{
+			Body:       `{"id":"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1","name":"rg1"}`,
+			ExpectId:   types.StringValue("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1"),
+			ExpectName: types.StringValue("rg1"),
+			ExpectFqdn: types.StringNull(),
+		},
+		{
+			Body:       `{"name":"server1","properties":{"fullyQualifiedDomainName":"server1.database.windows.net"}}`,
+			ExpectId:   types.StringNull(),
+			ExpectName: types.StringValue("server1"),
+			ExpectFqdn: types.StringValue("server1.database.windows.net"),
+		},
+		{
+			Body:       `{"properties":{"dnsSettings":{"fqdn":"pip1.westeurope.cloudapp.azure.com"}}}`,
+			ExpectId:   types.StringNull(),
+			ExpectName: types.StringNull(),
+			ExpectFqdn: types.StringValue("pip1.westeurope.cloudapp.azure.com"),
+		},
+		{
+			Body:       `"text"`,
+			ExpectId:   types.StringNull(),
+			ExpectName: types.StringNull(),
+			ExpectFqdn: types.StringNull(),
+		},
+	}
+
+	for _, testcase := range testcases {
+		var body interface{}
+		_ = json.Unmarshal([]byte(testcase.Body), &body)
+		id, name, fqdn := flattenOutputShortcuts(body)
+		if !id.Equal(testcase.ExpectId) || !name.Equal(testcase.ExpectName) || !fqdn.Equal(testcase.ExpectFqdn) {
+			t.Fatalf("Expected %v, %v, %v but got %v, %v, %v", testcase.ExpectId, testcase.ExpectName, testcase.ExpectFqdn, id, name, fqdn)
+		}
+	}
+}
+
+func Test_FlattenEtag(t *testing.T) {
+	testcases := []struct {
+		Body   string
+		Header string
+		Expect types.String
+	}{
+		{
+			Body:   `{"name":"zone1","etag":"00000000-0000-0000-0000-000000000000"}`,
+			Expect: types.StringValue("00000000-0000-0000-0000-000000000000"),
+		},
+		{
+			Body:   `{"name":"vnet1","etag":""}`,
+			Expect: types.StringNull(),
+		},
+		{
+			Body:   `{"name":"rg1"}`,
+			Expect: types.StringNull(),
+		},
+		{
+			Body:   `"text"`,
+			Expect: types.StringNull(),
+		},
+		{
+			Body:   `{"name":"site1"}`,
+			Header: `W/"datetime'2024-01-01T00%3A00%3A00.000Z'"`,
+			Expect: types.StringValue(`W/"datetime'2024-01-01T00%3A00%3A00.000Z'"`),
+		},
+		{
+			Body:   `{"name":"zone1","etag":"body-etag"}`,
+			Header: `"header-etag"`,
+			Expect: types.StringValue("body-etag"),
+		},
+	}
+
+	for _, testcase := range testcases {
+		var body interface{}
+		_ = json.Unmarshal([]byte(testcase.Body), &body)
+		if etag := flattenEtag(body, testcase.Header); !etag.Equal(testcase.Expect) {
+			t.Fatalf("Expected %v but got %v", testcase.Expect, etag)
+		}
+	}
+}
+
+func Test_PlannedResourceRegistry(t *testing.T) {
+	vnetId := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1"
+	nicId := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Network/networkInterfaces/nic1"
+	vmId := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Compute/virtualMachines/vm1"
+
+	var body interface{}
+	_ = json.Unmarshal([]byte(`{"properties":{"ipConfigurations":[{"properties":{"subnet":{"id":"`+strings.ToUpper(vnetId)+`/subnets/default"}}}],"location":"westus","tags":{"note":"/not/an/id"}}}`), &body)
+	ids := embeddedResourceIds(body)
+	if !reflect.DeepEqual(ids, []string{strings.ToLower(vnetId) + "/subnets/default"}) {
+		t.Fatalf("Expected the subnet ID but got %v", ids)
+	}
+
+	// the referenced resource is planned first
+	registry := newPlannedResourceRegistry()
+	if out := registry.planCreation(vnetId); len(out) != 0 {
+		t.Fatalf("Expected no referrers but got %v", out)
+	}
+	if out := registry.planReferences(nicId, ids); !reflect.DeepEqual(out, []string{vnetId}) {
+		t.Fatalf("Expected %v but got %v", []string{vnetId}, out)
+	}
+	if out := registry.planReferences(vmId, []string{strings.ToLower(nicId) + "2"}); len(out) != 0 {
+		t.Fatalf("Expected no planned creations but got %v", out)
+	}
+
+	// the referrer is planned first
+	registry = newPlannedResourceRegistry()
+	if out := registry.planReferences(nicId, ids); len(out) != 0 {
+		t.Fatalf("Expected no planned creations but got %v", out)
+	}
+	if out := registry.planCreation(vnetId); !reflect.DeepEqual(out, []string{nicId}) {
+		t.Fatalf("Expected %v but got %v", []string{nicId}, out)
+	}
+
+	// at apply time, the referenced resource is created before the referrer is planned again
+	registry.markCreated(vnetId)
+	if out := registry.planReferences(nicId, ids); len(out) != 0 {
+		t.Fatalf("Expected no planned creations after the creation but got %v", out)
+	}
+	registry.markCreated(nicId)
+	if out := registry.planCreation(vnetId); len(out) != 0 {
+		t.Fatalf("Expected no referrers after the creation but got %v", out)
+	}
+}
+
+func Test_ImportListItems(t *testing.T) {
+	var responseBody interface{}
+	_ = json.Unmarshal([]byte(`{"value":[
+		{"id":"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/my-vnet","type":"Microsoft.Network/virtualNetworks","name":"my-vnet"},
+		{"id":"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/My_Vnet","type":"Microsoft.Network/virtualNetworks","name":"My_Vnet"},
+		{"id":"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Unknown/fooBars/1st","type":"Microsoft.Unknown/fooBars","name":"1st"}
+	]}`), &responseBody)
+
+	items := importListItems(responseBody)
+	expected := []string{"azapi_resource.virtual_networks_my_vnet", "azapi_resource.virtual_networks_my_vnet_2", "azapi_resource.foo_bars_1st"}
+	if len(items) != len(expected) {
+		t.Fatalf("Expected %d items but got %d", len(expected), len(items))
+	}
+	for i, item := range items {
+		if item.SuggestedAddress.ValueString() != expected[i] {
+			t.Fatalf("Expected %q but got %q", expected[i], item.SuggestedAddress.ValueString())
+		}
+	}
+	if items[0].ApiVersion.IsNull() || strings.Contains(items[0].ApiVersion.ValueString(), "preview") {
+		t.Fatalf("Expected a stable api-version but got %v", items[0].ApiVersion)
+	}
+	if !items[2].ApiVersion.IsNull() {
+		t.Fatalf("Expected a null api-version but got %v", items[2].ApiVersion)
+	}
+
+	if filter := resourceTypesFilter([]string{"Microsoft.Network/virtualNetworks", "Microsoft.Storage/storageAccounts"}); filter != "resourceType eq 'Microsoft.Network/virtualNetworks' or resourceType eq 'Microsoft.Storage/storageAccounts'" {
+		t.Fatalf("Unexpected filter %q", filter)
+	}
+}
+
+// statusRequester returns the status bodies in order for the Get requests, the last one is repeated.
+type statusRequester struct {
+	clients.Requester
+	statuses []string
+	urls     []string
+}
+
+func (r *statusRequester) Get(ctx context.Context, resourceID string, apiVersion string, options clients.RequestOptions) (interface{}, error) {
+	r.urls = append(r.urls, resourceID+"?api-version="+apiVersion)
+	status := r.statuses[0]
+	if len(r.statuses) > 1 {
+		r.statuses = r.statuses[1:]
+	}
+	var responseBody interface{}
+	err := json.Unmarshal([]byte(status), &responseBody)
+	return responseBody, err
+}
+
+func Test_WaitForCompletionCondition(t *testing.T) {
+	condition := completionConditionModel{
+		Expression:        types.StringValue("properties.status == 'Completed'"),
+		FailureExpression: types.StringValue("properties.status == 'Failed'"),
+		Url:               types.StringNull(),
+		ApiVersion:        types.StringNull(),
+		IntervalSeconds:   types.Int64Value(1),
+	}
+
+	client := &statusRequester{statuses: []string{`{"properties":{"status":"Running"}}`, `{"properties":{"status":"Completed"}}`}}
+	if err := waitForCompletionCondition(context.Background(), client, condition, "/resource1", "2024-01-01"); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	if !reflect.DeepEqual(client.urls, []string{"/resource1?api-version=2024-01-01", "/resource1?api-version=2024-01-01"}) {
+		t.Fatalf("Unexpected polling requests %v", client.urls)
+	}
+
+	condition.Url = types.StringValue("/operations/op1")
+	condition.ApiVersion = types.StringValue("2023-01-01")
+	client = &statusRequester{statuses: []string{`{"properties":{"status":"Failed"}}`}}
+	if err := waitForCompletionCondition(context.Background(), client, condition, "/resource1", "2024-01-01"); err == nil || !strings.Contains(err.Error(), "failure expression") {
+		t.Fatalf("Expected the failure expression error but got %v", err)
+	}
+	if !reflect.DeepEqual(client.urls, []string{"/operations/op1?api-version=2023-01-01"}) {
+		t.Fatalf("Unexpected polling requests %v", client.urls)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	client = &statusRequester{statuses: []string{`{"properties":{"status":"Running"}}`}}
+	if err := waitForCompletionCondition(ctx, client, condition, "/resource1", "2024-01-01"); err == nil || !strings.Contains(err.Error(), "deadline exceeded") {
+		t.Fatalf("Expected the deadline exceeded error but got %v", err)
+	}
+}
+
+func Test_WaitForOutputPaths(t *testing.T) {
+	id := parse.ResourceId{AzureResourceId: "/resource1", ApiVersion: "2024-01-01"}
+	var responseBody interface{}
+	_ = json.Unmarshal([]byte(`{"properties":{"fqdn":null}}`), &responseBody)
+
+	client := &statusRequester{statuses: []string{`{"properties":{"fqdn":"example.com"}}`}}
+	body, missing := waitForOutputPaths(context.Background(), client, id, []string{"properties.fqdn"}, responseBody, clients.DefaultRequestOptions())
+	if len(missing) != 0 || !reflect.DeepEqual(body, map[string]interface{}{"properties": map[string]interface{}{"fqdn": "example.com"}}) {
+		t.Fatalf("Expected the populated body but got %v, missing %v", body, missing)
+	}
+	if len(client.urls) != 1 {
+		t.Fatalf("Expected 1 request but got %v", client.urls)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	client = &statusRequester{statuses: []string{`{"properties":{}}`}}
+	_, missing = waitForOutputPaths(ctx, client, id, []string{"properties.fqdn", "properties"}, responseBody, clients.DefaultRequestOptions())
+	if !reflect.DeepEqual(missing, []string{"properties.fqdn"}) {
+		t.Fatalf("Expected the missing paths but got %v", missing)
+	}
+}
+
+func Test_ExportTemplateWarnings(t *testing.T) {
+	var exportError interface{}
+	_ = json.Unmarshal([]byte(`{"code":"ExportTemplateCompletedWithErrors","message":"Export template operation completed with errors.","details":[
+		{"code":"ExportTemplateProviderError","target":"Microsoft.Web/sites/site1","message":"Could not get resources of the type 'Microsoft.Web/sites/config'."}
+	]}`), &exportError)
+	diags := exportTemplateWarnings(exportError)
+	if len(diags) != 1 || diags.HasError() {
+		t.Fatalf("Expected 1 warning but got %v", diags)
+	}
+	if summary := diags[0].Summary(); summary != `The resource "Microsoft.Web/sites/site1" couldn't be exported` {
+		t.Fatalf("Unexpected summary %q", summary)
+	}
+
+	if diags := exportTemplateWarnings(nil); len(diags) != 0 {
+		t.Fatalf("Expected no warnings but got %v", diags)
+	}
+}
+
+// softDeleteRequester finds the soft-deleted resources and records the requests which recover or purge them.
+// The soft-deleted resources are not found for the first notFoundCount requests.
+type softDeleteRequester struct {
+	clients.Requester
+	notFoundCount int
+	requests      []string
+	bodies        []interface{}
+}
+
+func (r *softDeleteRequester) Get(ctx context.Context, resourceID string, apiVersion string, options clients.RequestOptions) (interface{}, error) {
+	r.requests = append(r.requests, "GET "+resourceID)
+	if r.notFoundCount > 0 {
+		r.notFoundCount--
+		return nil, &azcore.ResponseError{StatusCode: http.StatusNotFound}
+	}
+	return map[string]interface{}{}, nil
+}
+
+func (r *softDeleteRequester) Delete(ctx context.Context, resourceID string, apiVersion string, options clients.RequestOptions) (interface{}, error) {
+	r.requests = append(r.requests, "DELETE "+resourceID)
+	return nil, nil
+}
+
+func (r *softDeleteRequester) CreateOrUpdate(ctx context.Context, resourceID string, apiVersion string, body interface{}, options clients.RequestOptions) (interface{}, error) {
+	r.requests = append(r.requests, "PUT "+resourceID)
+	r.bodies = append(r.bodies, body)
+	return nil, nil
+}
+
+func (r *softDeleteRequester) Action(ctx context.Context, resourceID string, action string, apiVersion string, method string, body interface{}, options clients.RequestOptions) (interface{}, error) {
+	r.requests = append(r.requests, method+" "+resourceID+"/"+action)
+	return nil, nil
+}
+
+func Test_HandleSoftDeletedResource(t *testing.T) {
+	id, err := parse.ResourceIDWithResourceType("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.KeyVault/vaults/vault1", "Microsoft.KeyVault/vaults@2023-07-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deletedId := "/subscriptions/00000000-0000-0000-0000-000000000000/providers/Microsoft.KeyVault/locations/westeurope/deletedVaults/vault1"
+	newBody := func() map[string]interface{} {
+		return map[string]interface{}{"location": "West Europe", "properties": map[string]interface{}{"tenantId": "tenant1"}}
+	}
+
+	client := &softDeleteRequester{}
+	if diags := handleSoftDeletedResource(context.Background(), client, id, newBody(), features.SoftDeletedResourcesOnCreateFail, clients.DefaultRequestOptions()); !diags.HasError() || !strings.Contains(diags[0].Detail(), deletedId) {
+		t.Fatalf("Expected the conflict error but got %v", diags)
+	}
+
+	body := newBody()
+	client = &softDeleteRequester{}
+	if diags := handleSoftDeletedResource(context.Background(), client, id, body, features.SoftDeletedResourcesOnCreateRecover, clients.DefaultRequestOptions()); diags.HasError() {
+		t.Fatalf("Expected no error but got %v", diags)
+	}
+	if !reflect.DeepEqual(client.requests, []string{"GET " + deletedId, "PUT " + id.AzureResourceId}) {
+		t.Fatalf("Unexpected requests %v", client.requests)
+	}
+	if !reflect.DeepEqual(client.bodies[0], map[string]interface{}{"location": "West Europe", "properties": map[string]interface{}{"tenantId": "tenant1", "createMode": "recover"}}) {
+		t.Fatalf("Unexpected recover body %v", client.bodies[0])
+	}
+	if !reflect.DeepEqual(body, newBody()) {
+		t.Fatalf("Expected the body is not modified but got %v", body)
+	}
+
+	client = &softDeleteRequester{}
+	if diags := handleSoftDeletedResource(context.Background(), client, id, newBody(), features.SoftDeletedResourcesOnCreatePurge, clients.DefaultRequestOptions()); diags.HasError() {
+		t.Fatalf("Expected no error but got %v", diags)
+	}
+	if !reflect.DeepEqual(client.requests, []string{"GET " + deletedId, "POST " + deletedId + "/purge"}) {
+		t.Fatalf("Unexpected requests %v", client.requests)
+	}
+}
+
+func Test_PurgeOnDestroy(t *testing.T) {
+	softDeletedResourcePollInterval = time.Millisecond
+	defer func() {
+		softDeletedResourcePollInterval = 5 * time.Second
+	}()
+
+	id, err := parse.ResourceIDWithResourceType("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.CognitiveServices/accounts/account1", "Microsoft.CognitiveServices/accounts@2023-05-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deletedId := "/subscriptions/00000000-0000-0000-0000-000000000000/providers/Microsoft.CognitiveServices/locations/eastus/resourceGroups/rg1/deletedAccounts/account1"
+
+	// the deleted resource is soft-deleted after a while
+	client := &softDeleteRequester{notFoundCount: 2}
+	if diags := purgeOnDestroy(context.Background(), client, id, "eastus"); len(diags) != 0 {
+		t.Fatalf("Expected no diagnostics but got %v", diags)
+	}
+	if !reflect.DeepEqual(client.requests, []string{"GET " + deletedId, "GET " + deletedId, "GET " + deletedId, "DELETE " + deletedId}) {
+		t.Fatalf("Unexpected requests %v", client.requests)
+	}
+
+	// the deleted resource is never soft-deleted
+	client = &softDeleteRequester{notFoundCount: softDeletedResourcePollCount}
+	if diags := purgeOnDestroy(context.Background(), client, id, "eastus"); len(diags) != 1 || diags.HasError() {
+		t.Fatalf("Expected a warning but got %v", diags)
+	}
+
+	// the resource type doesn't support soft delete
+	id, err = parse.ResourceIDWithResourceType("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1", "Microsoft.Network/virtualNetworks@2023-04-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diags := purgeOnDestroy(context.Background(), &softDeleteRequester{}, id, "eastus"); len(diags) != 1 || !strings.Contains(diags[0].Detail(), "`Microsoft.KeyVault/vaults`") {
+		t.Fatalf("Expected a warning but got %v", diags)
+	}
+}
+
+func Test_OriginalValues(t *testing.T) {
+	var body, existing interface{}
+	_ = json.Unmarshal([]byte(`{"tags":{"env":"test"},"properties":{"enabled":true,"rules":[1],"network":{"public":false}}}`), &body)
+	_ = json.Unmarshal([]byte(`{"name":"example","tags":{"owner":"me"},"properties":{"enabled":false,"rules":[2,3]}}`), &existing)
+
+	original := originalValues(body, existing)
+	expected := map[string]interface{}{
+		"tags": map[string]interface{}{"env": nil},
+		"properties": map[string]interface{}{
+			"enabled": false,
+			"rules":   []interface{}{float64(2), float64(3)},
+			"network": map[string]interface{}{"public": nil},
+		},
+	}
+	if !reflect.DeepEqual(original, expected) {
+		t.Fatalf("Expected %v but got %v", expected, original)
+	}
+
+	// the recorded values are kept, the added properties are recorded
+	var updated interface{}
+	_ = json.Unmarshal([]byte(`{"tags":{"env":"test","team":"a"},"properties":{"enabled":true}}`), &updated)
+	merged := mergeOriginalValues(original, originalValues(updated, map[string]interface{}{"tags": map[string]interface{}{"env": "test"}, "properties": map[string]interface{}{"enabled": true}}))
+	expected["tags"] = map[string]interface{}{"env": nil, "team": nil}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Fatalf("Expected %v but got %v", expected, merged)
+	}
+}
+
+func Test_CloudEndpointDiagnostics(t *testing.T) {
+	var body interface{}
+	_ = json.Unmarshal([]byte(`{"properties":{"endpoint":"https://example.blob.core.windows.net/container","rules":["https://example.vault.azure.cn/"],"name":"core.windows.net.example","zone":"privatelink.vault.azure.cn"}}`), &body)
+
+	// only the hosts of the URLs are checked
+	diags := cloudEndpointDiagnostics("usgovernment", "body", body)
+	if diags.HasError() || diags.WarningsCount() != 2 {
+		t.Fatalf("Expected 2 warnings but got %v", diags)
+	}
+	if detail := diags[0].Detail(); !strings.Contains(detail, `"example.blob.core.windows.net" of the public cloud`) {
+		t.Fatalf("Expected the public endpoint first but got %s", detail)
+	}
+
+	if diags := cloudEndpointDiagnostics("china", "parent_id", "https://example.blob.core.usgovcloudapi.net"); diags.HasError() || diags.WarningsCount() != 1 {
+		t.Fatalf("Expected 1 warning but got %v", diags)
+	}
+	if diags := cloudEndpointDiagnostics("china", "parent_id", "/subscriptions/000/resourceGroups/rg1/providers/Microsoft.Network/privateDnsZones/privatelink.blob.core.windows.net"); len(diags) != 0 {
+		t.Fatalf("Expected no diagnostics for the resource ID but got %v", diags)
+	}
+	if diags := cloudEndpointDiagnostics("public", "body", body); diags.WarningsCount() != 1 {
+		t.Fatalf("Expected 1 warning but got %v", diags)
+	}
+	if diags := cloudEndpointDiagnostics("", "body", body); len(diags) != 0 {
+		t.Fatalf("Expected no diagnostics for the unknown environment but got %v", diags)
+	}
+}
+
+func Test_RequiredTagsDiagnostics(t *testing.T) {
+	resourceTags := tags.FlattenTags(map[string]interface{}{"CostCenter": "1234", "env": "test"})
+	if diags := requiredTagsDiagnostics([]string{"costCenter", "env"}, resourceTags); diags.HasError() {
+		t.Fatalf("Expected no errors but got %v", diags)
+	}
+
+	diags := requiredTagsDiagnostics([]string{"costCenter", "owner", "team"}, resourceTags)
+	if diags.ErrorsCount() != 1 || !strings.Contains(diags[0].Detail(), "owner, team") {
+		t.Fatalf("Expected the missing tags but got %v", diags)
+	}
+
+	if diags := requiredTagsDiagnostics([]string{"owner"}, tags.FlattenTags(nil)); diags.ErrorsCount() != 1 {
+		t.Fatalf("Expected an error for the null tags but got %v", diags)
+	}
+	if diags := requiredTagsDiagnostics([]string{"owner"}, basetypes.NewMapUnknown(types.StringType)); len(diags) != 0 {
+		t.Fatalf("Expected no diagnostics for the unknown tags but got %v", diags)
+	}
+}
+
+func Test_IdentityExpandFlatten(t *testing.T) {
+	id1 := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.ManagedIdentity/userAssignedIdentities/id1"
+	id2 := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.ManagedIdentity/userAssignedIdentities/id2"
+	model := identity.Model{
+		Type:        types.StringValue(string(identity.UserAssigned)),
+		IdentityIDs: types.ListValueMust(types.StringType, []attr.Value{types.StringValue(id2), types.StringValue(strings.Replace(id2, "rg1", "RG1", 1)), types.StringValue(id1), types.StringUnknown()}),
+	}
+	out, err := identity.ExpandIdentity(model)
+	if err != nil {
+		t.Fatalf("Expected no error but got %+v", err)
+	}
+	if userAssignedIdentities := out.(map[string]interface{})["userAssignedIdentities"].(map[string]interface{}); len(userAssignedIdentities) != 2 {
+		t.Fatalf("Expected the deduplicated identities but got %v", userAssignedIdentities)
+	}
+
+	// the IDs are parsed case-insensitively
+	model.IdentityIDs = types.ListValueMust(types.StringType, []attr.Value{types.StringValue(strings.Replace(strings.Replace(id1, "resourceGroups", "resourcegroups", 1), "userAssignedIdentities", "UserAssignedIdentities", 1))})
+	if _, err := identity.ExpandIdentity(model); err != nil {
+		t.Fatalf("Expected no error for the identity ID in a different casing but got %+v", err)
+	}
+
+	for _, invalidId := range []string{"id1", "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1"} {
+		model.IdentityIDs = types.ListValueMust(types.StringType, []attr.Value{types.StringValue(invalidId)})
+		if _, err := identity.ExpandIdentity(model); err == nil {
+			t.Fatalf("Expected an error for the invalid identity ID %q", invalidId)
+		}
+	}
+
+	flattened := identity.FlattenIdentity(map[string]interface{}{
+		"type": "SystemAssigned,UserAssigned,Delegated",
+		"userAssignedIdentities": map[string]interface{}{
+			id2: map[string]interface{}{},
+			id1: map[string]interface{}{},
+		},
+	})
+	if flattened.Type.ValueString() != string(identity.SystemAssignedUserAssigned) {
+		t.Fatalf("Expected %q but got %q", identity.SystemAssignedUserAssigned, flattened.Type.ValueString())
+	}
+	if expected := types.ListValueMust(types.StringType, []attr.Value{types.StringValue(id1), types.StringValue(id2)}); !flattened.IdentityIDs.Equal(expected) {
+		t.Fatalf("Expected the sorted identity IDs %v but got %v", expected, flattened.IdentityIDs)
+	}
+	if !identity.SameIdentityIds(flattened.IdentityIDs, types.ListValueMust(types.StringType, []attr.Value{types.StringValue(strings.Replace(id2, "rg1", "RG1", 1)), types.StringValue(id1)})) {
+		t.Fatalf("Expected the identity IDs are the same")
+	}
+
+	if v := identity.FlattenIdentity(map[string]interface{}{"type": "ManagedServiceIdentity"}); v.Type.ValueString() != "ManagedServiceIdentity" {
+		t.Fatalf("Expected the unknown identity type is preserved but got %q", v.Type.ValueString())
+	}
+	if v := identity.FlattenIdentity(map[string]interface{}{}); v.Type.ValueString() != string(identity.None) {
+		t.Fatalf("Expected %q but got %q", identity.None, v.Type.ValueString())
+	}
+}
+
+func Test_ActionTriggers(t *testing.T) {
+	triggers := func(rotation string) types.Map {
+		return types.MapValueMust(types.StringType, map[string]attr.Value{"rotation": types.StringValue(rotation)})
+	}
+	state := &ActionResourceModel{Triggers: triggers("2024-01")}
+
+	if !actionTriggersUnchanged(&ActionResourceModel{Triggers: triggers("2024-01")}, state) {
+		t.Fatalf("Expected the action not to be performed again when the triggers are not changed")
+	}
+	if actionTriggersUnchanged(&ActionResourceModel{Triggers: triggers("2024-02")}, state) {
+		t.Fatalf("Expected the action to be performed again when the triggers are changed")
+	}
+	if actionTriggersUnchanged(&ActionResourceModel{Triggers: types.MapNull(types.StringType)}, &ActionResourceModel{Triggers: types.MapNull(types.StringType)}) {
+		t.Fatalf("Expected the action to be performed again when the triggers are not specified")
+	}
+	if actionTriggersUnchanged(&ActionResourceModel{Triggers: triggers("2024-01")}, nil) {
+		t.Fatalf("Expected the action to be performed when it's created")
+	}
+
+	model := ActionResourceModel{
+		Type:        types.StringValue("Microsoft.Automation/automationAccounts@2021-06-22"),
+		ResourceId:  types.StringValue("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Automation/automationAccounts/account1"),
+		Action:      types.StringValue("agentRegistrationInformation/regenerateKey"),
+		Method:      types.StringValue("POST"),
+		Body:        types.DynamicValue(types.ObjectValueMust(map[string]attr.Type{"keyName": types.StringType}, map[string]attr.Value{"keyName": types.StringValue("primary")})),
+		PayloadFile: types.StringNull(),
+	}
+	fingerprint, ok := plannedActionFingerprint(model)
+	if !ok {
+		t.Fatalf("Expected the fingerprint of the known inputs")
+	}
+	if expected := actionFingerprint(model, model.ResourceId.ValueString()+"/agentRegistrationInformation/regenerateKey", map[string]interface{}{"keyName": "primary"}); fingerprint != expected {
+		t.Fatalf("Expected the fingerprint %s but got %s", expected, fingerprint)
+	}
+
+	model.Body = types.DynamicValue(types.ObjectValueMust(map[string]attr.Type{"keyName": types.StringType}, map[string]attr.Value{"keyName": types.StringValue("secondary")}))
+	if changed, _ := plannedActionFingerprint(model); changed == fingerprint {
+		t.Fatalf("Expected the fingerprint to be changed with the body")
+	}
+
+	model.Body = types.DynamicValue(types.ObjectValueMust(map[string]attr.Type{"keyName": types.StringType}, map[string]attr.Value{"keyName": types.StringUnknown()}))
+	if _, ok := plannedActionFingerprint(model); ok {
+		t.Fatalf("Expected no fingerprint of the unknown inputs")
+	}
+}
+
+func Test_ActionRerunDue(t *testing.T) {
+	plan := ActionResourceModel{
+		When:          types.StringValue("apply"),
+		RerunInterval: types.StringValue("1h"),
+	}
+	performedAt := func(d time.Duration) *ActionResourceModel {
+		return &ActionResourceModel{LastPerformedAt: types.StringValue(time.Now().Add(-d).UTC().Format(time.RFC3339))}
+	}
+
+	if actionRerunDue(plan, nil) {
+		t.Fatalf("Expected the action not to be due when it's created")
+	}
+	if actionRerunDue(plan, performedAt(time.Minute)) {
+		t.Fatalf("Expected the action not to be due before the interval")
+	}
+	if !actionRerunDue(plan, performedAt(2*time.Hour)) {
+		t.Fatalf("Expected the action to be due after the interval")
+	}
+	if !actionRerunDue(plan, &ActionResourceModel{LastPerformedAt: types.StringNull()}) {
+		t.Fatalf("Expected the action to be due when the last run isn't recorded")
+	}
+
+	plan.RerunInterval = types.StringNull()
+	if actionRerunDue(plan, performedAt(2*time.Hour)) {
+		t.Fatalf("Expected the action not to be due without the interval")
+	}
+	plan.RerunInterval = types.StringValue("1h")
+	plan.When = types.StringValue("destroy")
+	if actionRerunDue(plan, performedAt(2*time.Hour)) {
+		t.Fatalf("Expected the action not to be due when it's performed on destroy")
+	}
+}
+
+func Test_ImportIdWithApiVersion(t *testing.T) {
+	input := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Automation/automationAccounts/account1"
+	if output, err := importIdWithApiVersion(input + "?api-version=2021-06-22"); err != nil || output != input+"?api-version=2021-06-22" {
+		t.Fatalf("Expected the specified api-version to be kept but got %s, %v", output, err)
+	}
+	output, err := importIdWithApiVersion(input)
+	if err != nil || !strings.HasPrefix(output, input+"?api-version=") {
+		t.Fatalf("Expected the latest api-version to be appended but got %s, %v", output, err)
+	}
+}
+
+type testPrivateState map[string][]byte
+
+func (p testPrivateState) GetKey(_ context.Context, key string) ([]byte, diag.Diagnostics) {
+	return p[key], nil
+}
+
+func (p testPrivateState) SetKey(_ context.Context, key string, value []byte) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if len(value) != 0 && !json.Valid(value) {
+		diags.AddError("Invalid private state value", string(value))
+		return diags
+	}
+	p[key] = value
+	return diags
+}
+
+func Test_ResourceMetadata(t *testing.T) {
+	private := testPrivateState{}
+	client := &clients.Client{CorrelationRequestID: "00000000-0000-0000-0000-000000000001"}
+	if diags := writeResourceMetadata(context.Background(), private, newResourceMetadata(client, "Microsoft.Resources/resourceGroups", "2021-04-01")); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(private[resourceMetadataKey], &metadata); err != nil {
+		t.Fatalf("failed to unmarshal the metadata: %v", err)
+	}
+	if metadata["api_version"] != "2021-04-01" {
+		t.Errorf("expect api_version 2021-04-01, got %v", metadata["api_version"])
+	}
+	if metadata["correlation_id"] != client.CorrelationRequestID {
+		t.Errorf("expect correlation_id %s, got %v", client.CorrelationRequestID, metadata["correlation_id"])
+	}
+	if hash, _ := metadata["schema_hash"].(string); len(hash) != 64 {
+		t.Errorf("expect a SHA-256 schema_hash, got %v", metadata["schema_hash"])
+	}
+	for _, key := range []string{"provider_version", "applied_at"} {
+		if v, _ := metadata[key].(string); v == "" {
+			t.Errorf("expect %s to be set", key)
+		}
+	}
+
+	// the unknown resource types don't have the schema hash
+	if metadata := newResourceMetadata(nil, "Microsoft.Foo/bars", "2021-04-01"); metadata.SchemaHash != "" || metadata.CorrelationId != "" {
+		t.Errorf("expect no schema_hash and correlation_id, got %+v", metadata)
+	}
+}
+
+type testBundleRequester struct {
+	clients.Requester
+	existing map[string]bool
+	failOn   string
+	calls    []string
+}
+
+func (r *testBundleRequester) Get(_ context.Context, resourceID string, _ string, _ clients.RequestOptions) (interface{}, error) {
+	if !r.existing[resourceID] {
+		return nil, &azcore.ResponseError{StatusCode: http.StatusNotFound}
+	}
+	return map[string]interface{}{}, nil
+}
+
+func (r *testBundleRequester) CreateOrUpdate(_ context.Context, resourceID string, _ string, _ interface{}, _ clients.RequestOptions) (interface{}, error) {
+	r.calls = append(r.calls, "PUT "+resourceID)
+	if resourceID == r.failOn {
+		return nil, &azcore.ResponseError{StatusCode: http.StatusBadRequest, ErrorCode: "InvalidRequest"}
+	}
+	return map[string]interface{}{}, nil
+}
+
+func (r *testBundleRequester) Delete(_ context.Context, resourceID string, _ string, _ clients.RequestOptions) (interface{}, error) {
+	r.calls = append(r.calls, "DELETE "+resourceID)
+	return nil, nil
+}
+
+func Test_CreateBundle(t *testing.T) {
+	const rg = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1"
+	memberTypes := map[string]attr.Type{"type": types.StringType, "name": types.StringType, "parent_id": types.StringType}
+	member := func(resourceType, name, parentId string) attr.Value {
+		return types.ObjectValueMust(memberTypes, map[string]attr.Value{"type": types.StringValue(resourceType), "name": types.StringValue(name), "parent_id": types.StringValue(parentId)})
+	}
+	memberType := types.ObjectType{AttrTypes: memberTypes}
+	members, ids, err := expandBundleMembers(types.DynamicValue(types.TupleValueMust(
+		[]attr.Type{memberType, memberType, memberType},
+		[]attr.Value{
+			member("Microsoft.Network/privateDnsZones@2020-06-01", "zone", rg),
+			member("Microsoft.Network/privateEndpoints@2023-04-01", "pe", rg),
+			member("Microsoft.Network/privateEndpoints/privateDnsZoneGroups@2023-04-01", "default", rg+"/providers/Microsoft.Network/privateEndpoints/pe"),
+		},
+	)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	zoneId, peId, groupId := ids[0].AzureResourceId, ids[1].AzureResourceId, ids[2].AzureResourceId
+
+	requester := &testBundleRequester{}
+	if err := createBundle(context.Background(), requester, members, ids); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"PUT " + zoneId, "PUT " + peId, "PUT " + groupId}; !reflect.DeepEqual(requester.calls, expected) {
+		t.Fatalf("expect %v, got %v", expected, requester.calls)
+	}
+
+	// the created members are deleted in the reverse order
+	requester = &testBundleRequester{failOn: groupId}
+	if err := createBundle(context.Background(), requester, members, ids); err == nil {
+		t.Fatalf("expect an error")
+	}
+	if expected := []string{"PUT " + zoneId, "PUT " + peId, "PUT " + groupId, "DELETE " + groupId, "DELETE " + peId, "DELETE " + zoneId}; !reflect.DeepEqual(requester.calls, expected) {
+		t.Fatalf("expect %v, got %v", expected, requester.calls)
+	}
+
+	// nothing is created if any member already exists
+	requester = &testBundleRequester{existing: map[string]bool{peId: true}}
+	if err := createBundle(context.Background(), requester, members, ids); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expect an error for the existing member, got %v", err)
+	}
+	if len(requester.calls) != 0 {
+		t.Fatalf("expect no requests, got %v", requester.calls)
+	}
+
+	if _, _, err := expandBundleMembers(types.DynamicValue(types.TupleValueMust(
+		[]attr.Type{memberType, memberType},
+		[]attr.Value{member("Microsoft.Network/privateDnsZones@2020-06-01", "zone", rg), member("Microsoft.Network/privateDnsZones@2020-06-01", "zone", rg)},
+	))); err == nil {
+		t.Fatalf("expect an error for the duplicated members")
+	}
+}
+
+func Test_FlattenBundleResources(t *testing.T) {
+	var resources interface{}
+	_ = json.Unmarshal([]byte(`[
+  {"type": "Microsoft.Network/privateDnsZones@2020-06-01", "name": "zone", "parent_id": "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1", "body": {"tags": {"env": "test"}}},
+  {"type": "Microsoft.Network/privateEndpoints@2023-04-01", "name": "pe", "parent_id": "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1"}
+]`), &resources)
+	data, _ := json.Marshal(resources)
+	input, err := dynamic.FromJSONImplied(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output, err := flattenBundleResources(context.Background(), input, []interface{}{
+		map[string]interface{}{"name": "zone", "tags": map[string]interface{}{"env": "prod"}, "properties": map[string]interface{}{"numberOfRecordSets": 1}},
+		map[string]interface{}{"name": "pe"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err = dynamic.ToJSON(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var actual []map[string]interface{}
+	_ = json.Unmarshal(data, &actual)
+	// only the specified properties are updated, so the drift of the tags is detected
+	if expected := map[string]interface{}{"tags": map[string]interface{}{"env": "prod"}}; !reflect.DeepEqual(actual[0]["body"], expected) {
+		t.Fatalf("expect %v, got %v", expected, actual[0]["body"])
+	}
+	if _, ok := actual[1]["body"]; ok {
+		t.Fatalf("expect no body for the second member, got %v", actual[1]["body"])
+	}
+}
+
+func Test_SecretVersion(t *testing.T) {
+	responseBody := map[string]interface{}{
+		"keys": []interface{}{
+			map[string]interface{}{"keyName": "key1", "value": "secret1"},
+			map[string]interface{}{"keyName": "key2", "value": "secret2"},
+		},
+	}
+	selectSecret := types.StringValue("keys[?keyName=='key1'].value | [0]")
+	private := testPrivateState{}
+	key, diags := secretVersionKey(context.Background(), private)
+	if diags.HasError() || len(key) != 32 {
+		t.Fatalf("expect a random key, got %v, %v", key, diags)
+	}
+	if again, _ := secretVersionKey(context.Background(), private); !bytes.Equal(again, key) {
+		t.Fatalf("expect the key in the private state, got %v", again)
+	}
+
+	version, err := secretVersion(responseBody, selectSecret, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if version.ValueString() == "" || strings.Contains(version.ValueString(), "secret1") {
+		t.Fatalf("expect the hash of the secret, got %q", version.ValueString())
+	}
+	if again, _ := secretVersion(responseBody, selectSecret, key); !again.Equal(version) {
+		t.Fatalf("expect the same version %q, got %q", version.ValueString(), again.ValueString())
+	}
+
+	// the versions of the different resources aren't the plain hashes of the secret and can't be correlated
+	if plain := sha256.Sum256([]byte(`"secret1"`)); version.ValueString() == fmt.Sprintf("%x", plain) {
+		t.Fatalf("expect a keyed hash of the secret, got the plain hash %q", version.ValueString())
+	}
+	otherKey, _ := secretVersionKey(context.Background(), testPrivateState{})
+	if other, _ := secretVersion(responseBody, selectSecret, otherKey); other.Equal(version) {
+		t.Fatalf("expect a different version with another key, got %q", other.ValueString())
+	}
+
+	// the version changes when the secret is rotated
+	responseBody["keys"].([]interface{})[0].(map[string]interface{})["value"] = "rotated"
+	if rotated, _ := secretVersion(responseBody, selectSecret, key); rotated.Equal(version) {
+		t.Fatalf("expect a new version after the rotation, got %q", rotated.ValueString())
+	}
+
+	if version, err := secretVersion(responseBody, types.StringNull(), key); err != nil || !version.IsNull() {
+		t.Fatalf("expect a null version, got %q, %v", version.ValueString(), err)
+	}
+	if _, err := secretVersion(responseBody, types.StringValue("keys[?keyName=='key3'].value | [0]"), key); err == nil {
+		t.Fatalf("expect an error when the expression doesn't select any value")
+	}
+}
+
+func Test_ActionSelectSecretChanged(t *testing.T) {
+	state := &ActionResourceModel{Triggers: types.MapValueMust(types.StringType, map[string]attr.Value{"version": types.StringValue("v1")}), SelectSecret: types.StringValue("keys[0].value")}
+	plan := *state
+	if actionSelectSecretChanged(&plan, state) {
+		t.Fatalf("expect the secret selection to be unchanged")
+	}
+	// the action is performed again even if the triggers are unchanged, so the version_tracking of the new secret is computed
+	plan.SelectSecret = types.StringValue("keys[1].value")
+	if !actionTriggersUnchanged(&plan, state) || !actionSelectSecretChanged(&plan, state) {
+		t.Fatalf("expect the secret selection to be changed with the unchanged triggers")
+	}
+	plan.SelectSecret = types.StringNull()
+	if actionSelectSecretChanged(&plan, state) {
+		t.Fatalf("expect no new secret when the selection is removed")
+	}
+	if actionSelectSecretChanged(&plan, nil) {
+		t.Fatalf("expect no change for the new resource")
+	}
+}
+
+func Test_FullNameWithDefaultNaming(t *testing.T) {
+	r := &AzapiResource{
+		ProviderData: &clients.Client{
+			Features: features.UserFeatures{
+				DefaultNaming:       "default",
+				DefaultNamingPrefix: "contoso-",
+				DefaultNamingSuffix: "-prod",
+			},
+		},
+	}
+	vnetDef, _ := azure.GetResourceDefinition("Microsoft.Network/virtualNetworks", "2023-09-01")
+	blobServicesDef, _ := azure.GetResourceDefinition("Microsoft.Storage/storageAccounts/blobServices", "2023-01-01")
+	testcases := []struct {
+		Config             types.String
+		ApplyDefaultNaming bool
+		ResourceDef        *aztypes.ResourceType
+		Expect             types.String
+	}{
+		{
+			Config:             types.StringValue("vnet1"),
+			ApplyDefaultNaming: true,
+			ResourceDef:        vnetDef,
+			Expect:             types.StringValue("contoso-vnet1-prod"),
+		},
+		{
+			Config:             types.StringValue("vnet1"),
+			ApplyDefaultNaming: false,
+			ResourceDef:        vnetDef,
+			Expect:             types.StringValue("vnet1"),
+		},
+		{
+			// the name is fixed by the resource type
+			Config:             types.StringValue("default"),
+			ApplyDefaultNaming: true,
+			ResourceDef:        blobServicesDef,
+			Expect:             types.StringValue("default"),
+		},
+		{
+			// the name is a GUID, e.g. the name of a role assignment
+			Config:             types.StringValue("6faae21a-0cd6-4536-8c23-a278823d12ed"),
+			ApplyDefaultNaming: true,
+			Expect:             types.StringValue("6faae21a-0cd6-4536-8c23-a278823d12ed"),
+		},
+		{
+			// the default name isn't prefixed or suffixed
+			Config:             types.StringNull(),
+			ApplyDefaultNaming: true,
+			Expect:             types.StringValue("default"),
+		},
+		{
+			Config:             types.StringUnknown(),
+			ApplyDefaultNaming: true,
+			Expect:             types.StringUnknown(),
+		},
+	}
+	for _, testcase := range testcases {
+		name, diags := r.fullNameWithDefaultNaming(testcase.Config, testcase.ApplyDefaultNaming, testcase.ResourceDef)
+		if diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		if !name.Equal(testcase.Expect) {
+			t.Fatalf("Expected %v but got %v", testcase.Expect, name)
+		}
+	}
+}
+
+func Test_IsSystemChildResource(t *testing.T) {
+	testcases := map[string]bool{
+		"/subscriptions/000/resourceGroups/rg1/providers/Microsoft.Sql/servers/server1/databases/master": true,
+		"/subscriptions/000/resourceGroups/rg1/providers/Microsoft.Sql/servers/server1/databases/MASTER": true,
+		"/subscriptions/000/resourceGroups/rg1/providers/Microsoft.Sql/servers/server1/databases/db1":    false,
+		"/subscriptions/000/resourceGroups/rg1/providers/Microsoft.Sql/servers/master":                   false,
+	}
+	for input, expect := range testcases {
+		if actual := isSystemChildResource(input); actual != expect {
+			t.Fatalf("Expected %v but got %v for %s", expect, actual, input)
+		}
+	}
+}
+
+func Test_StripReadOnlyRoundTrip(t *testing.T) {
+	resourceDef, err := azure.GetResourceDefinition("Microsoft.Storage/storageAccounts", "2023-01-01")
+	if err != nil {
+		t.Fatalf("Expected no error but got %+v", err)
+	}
+	var config, response map[string]interface{}
+	_ = json.Unmarshal([]byte(`{
+  "id": "/subscriptions/000/resourceGroups/rg1/providers/Microsoft.Storage/storageAccounts/account1",
+  "kind": "StorageV2",
+  "properties": {
+    "accessTier": "Hot",
+    "provisioningState": "Succeeded",
+    "networkAcls": {
+      "defaultAction": "Deny",
+      "ipRules": [{"value": "1.2.3.4"}]
+    }
+  }
+}`), &config)
+	_ = json.Unmarshal([]byte(`{
+  "id": "/subscriptions/000/resourceGroups/rg1/providers/Microsoft.Storage/storageAccounts/account1",
+  "kind": "StorageV2",
+  "properties": {
+    "accessTier": "Cool",
+    "provisioningState": "ResolvingDNS",
+    "networkAcls": {
+      "defaultAction": "Deny",
+      "ipRules": [{"value": "1.2.3.4", "action": "Allow"}]
+    }
+  }
+}`), &response)
+
+	template := stripReadOnlyProperties(resourceDef, config)
+	if _, ok := template["properties"].(map[string]interface{})["provisioningState"]; ok {
+		t.Fatalf("Expected the read-only properties to be stripped but got %v", template)
+	}
+	readOnlyProperties := strippedProperties(config, template)
+	body := restoreStrippedProperties(utils.UpdateObject(template, response, utils.UpdateJsonOption{IgnoreMissingProperty: true}), readOnlyProperties)
+
+	// the read-only properties keep their configured values, and the drift of the other properties is detected
+	var expected interface{}
+	_ = json.Unmarshal([]byte(`{
+  "id": "/subscriptions/000/resourceGroups/rg1/providers/Microsoft.Storage/storageAccounts/account1",
+  "kind": "StorageV2",
+  "properties": {
+    "accessTier": "Cool",
+    "provisioningState": "Succeeded",
+    "networkAcls": {
+      "defaultAction": "Deny",
+      "ipRules": [{"value": "1.2.3.4"}]
+    }
+  }
+}`), &expected)
+	if !reflect.DeepEqual(body, expected) {
+		t.Fatalf("Expected %v but got %v", expected, body)
+	}
+
+	if v := strippedProperties(template, template); v != nil {
+		t.Fatalf("Expected no stripped properties but got %v", v)
+	}
+}
+
+func Test_TagsWithMergedDefaultTags(t *testing.T) {
+	r := &AzapiResource{
+		ProviderData: &clients.Client{
+			Features: features.UserFeatures{
+				DefaultTags:      map[string]string{"key": "default", "costCenter": "1234"},
+				MergeDefaultTags: true,
+			},
+		},
+	}
+	resourceDef, _ := azure.GetResourceDefinition("Microsoft.Automation/automationAccounts", "2023-11-01")
+
+	// the tags in the body are merged with the default tags in the plan, the tags of the resource win
+	planned := r.tagsWithDefaultTags(types.MapNull(types.StringType), map[string]interface{}{"tags": map[string]interface{}{"key": "override", "owner": "team1"}}, nil, resourceDef)
+	expected := tags.FlattenTags(map[string]string{"key": "override", "owner": "team1", "costCenter": "1234"})
+	if !planned.Equal(expected) {
+		t.Fatalf("Expected %v but got %v", expected, planned)
+	}
+
+	// the tags argument is planned as configured, the default tags are merged in the request body
+	configTags := tags.FlattenTags(map[string]string{"key": "override", "owner": "team1"})
+	if planned := r.tagsWithDefaultTags(configTags, map[string]interface{}{}, nil, resourceDef); !planned.Equal(configTags) {
+		t.Fatalf("Expected %v but got %v", configTags, planned)
+	}
+	if merged := mergeDefaultTags(r.ProviderData.Features.DefaultTags, tags.ExpandTags(configTags)); !reflect.DeepEqual(merged, map[string]string{"key": "override", "owner": "team1", "costCenter": "1234"}) {
+		t.Fatalf("Expected the merged tags but got %v", merged)
+	}
+
+	// the default tags aren't read back into the tags argument, unless they're changed remotely
+	remoteTags := tags.FlattenTags(map[string]string{"key": "override", "owner": "team1", "costCenter": "1234"})
+	if v := withoutMergedDefaultTags(remoteTags, configTags, r.ProviderData.Features.DefaultTags); !v.Equal(configTags) {
+		t.Fatalf("Expected %v but got %v", configTags, v)
+	}
+	remoteTags = tags.FlattenTags(map[string]string{"key": "override", "owner": "team1", "costCenter": "5678"})
+	if v := withoutMergedDefaultTags(remoteTags, configTags, r.ProviderData.Features.DefaultTags); !v.Equal(remoteTags) {
+		t.Fatalf("Expected %v but got %v", remoteTags, v)
+	}
+
+	// without the merge, the tags in the body replace the default tags
+	r.ProviderData.Features.MergeDefaultTags = false
+	expected = tags.FlattenTags(map[string]interface{}{"key": "override", "owner": "team1"})
+	if planned := r.tagsWithDefaultTags(types.MapNull(types.StringType), map[string]interface{}{"tags": map[string]interface{}{"key": "override", "owner": "team1"}}, nil, resourceDef); !planned.Equal(expected) {
+		t.Fatalf("Expected %v but got %v", expected, planned)
+	}
+}
+
+func Test_ImportedUpdateBody(t *testing.T) {
+	resourceDef, err := azure.GetResourceDefinition("Microsoft.Storage/storageAccounts", "2023-01-01")
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	responseBody := map[string]interface{}{
+		"id":       "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/acc",
+		"name":     "acc",
+		"type":     "Microsoft.Storage/storageAccounts",
+		"location": "westeurope",
+		"properties": map[string]interface{}{
+			"minimumTlsVersion": "TLS1_2",
+			"provisioningState": "Succeeded",
+		},
+	}
+
+	body, err := importedUpdateBody(resourceDef, responseBody)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	data, err := dynamic.ToJSON(body)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	var actual map[string]interface{}
+	if err := json.Unmarshal(data, &actual); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	expected := map[string]interface{}{
+		"location": "westeurope",
+		"properties": map[string]interface{}{
+			"minimumTlsVersion": "TLS1_2",
+		},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected %v but got %v", expected, actual)
+	}
+
+	// without a resource definition, the whole response except the identifying fields is kept
+	body, err = importedUpdateBody(nil, map[string]interface{}{"name": "acc", "properties": map[string]interface{}{"foo": "bar"}})
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	if data, _ = dynamic.ToJSON(body); string(data) != `{"properties":{"foo":"bar"}}` {
+		t.Fatalf("Expected the properties but got %s", data)
+	}
+}
+
+func Test_RecordInterruptedOperation(t *testing.T) {
+	private := testPrivateState{}
+	if diags := recordInterruptedOperation(context.Background(), private, fmt.Errorf("polling failed: %w", context.DeadlineExceeded)); diags.HasError() || len(private[interruptedOperationKey]) != 0 {
+		t.Fatalf("Expected no operation to be recorded but got %v, %s", diags, private[interruptedOperationKey])
+	}
+
+	err := fmt.Errorf("creating: %w", &clients.InterruptedOperationError{OperationURL: "https://management.azure.com/operations/op1", Err: context.DeadlineExceeded})
+	if diags := recordInterruptedOperation(context.Background(), private, err); diags.HasError() {
+		t.Fatalf("Expected no error but got %v", diags)
+	}
+	diags := interruptedOperationWarning(context.Background(), private, "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1")
+	if diags.WarningsCount() != 1 || !strings.Contains(diags[0].Detail(), "https://management.azure.com/operations/op1") {
+		t.Fatalf("Expected a warning with the operation URL but got %v", diags)
+	}
+
+	// the recorded operation is removed after a successful apply
+	private.SetKey(context.Background(), interruptedOperationKey, nil)
+	if diags := interruptedOperationWarning(context.Background(), private, "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1"); len(diags) != 0 {
+		t.Fatalf("Expected no warning but got %v", diags)
+	}
+}
+
+func Test_GeneratedDataPlaneResourceId(t *testing.T) {
+	id, err := parse.NewDataPlaneResourceId("assistant1", "foo.openai.azure.com", "Microsoft.CognitiveServices/accounts/OpenAI/assistants@2024-05-01-preview")
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	generated, err := generatedDataPlaneResourceId(id, map[string]interface{}{"id": "asst_abc123", "object": "assistant"}, "")
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	if expected := "foo.openai.azure.com/openai/assistants/asst_abc123"; generated.ID() != expected {
+		t.Fatalf("Expected %q but got %q", expected, generated.ID())
+	}
+	if generated.Name != "asst_abc123" || generated.ApiVersion != "2024-05-01-preview" || !generated.GeneratedName {
+		t.Fatalf("Expected the generated ID but got %+v", generated)
+	}
+
+	if _, err := generatedDataPlaneResourceId(id, map[string]interface{}{"object": "assistant"}, ""); err == nil {
+		t.Fatalf("Expected an error when the response doesn't contain the id")
+	}
+
+	// the transaction ID of the confidential ledger entries is returned in the header
+	id, err = parse.NewDataPlaneResourceId("entry1", "foo.confidential-ledger.azure.com", "Microsoft.ConfidentialLedger/ledgers/transactions@2022-05-13")
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	generated, err = generatedDataPlaneResourceId(id, map[string]interface{}{"collectionId": "subledger:0"}, "2.15")
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	if expected := "foo.confidential-ledger.azure.com/app/transactions/2.15"; generated.ID() != expected {
+		t.Fatalf("Expected %q but got %q", expected, generated.ID())
+	}
+	if _, err := generatedDataPlaneResourceId(id, map[string]interface{}{"collectionId": "subledger:0"}, ""); err == nil {
+		t.Fatalf("Expected an error when the response doesn't contain the header")
+	}
+}
+
+func Test_ExpandDataPlaneAuthentication(t *testing.T) {
+	attrTypes := map[string]attr.Type{"sas_token_env_var": types.StringType, "account_key_env_var": types.StringType, "use_msi": types.BoolType, "client_id": types.StringType}
+	authentication := func(sasTokenEnvVar string) types.Object {
+		return types.ObjectValueMust(attrTypes, map[string]attr.Value{
+			"sas_token_env_var":   types.StringValue(sasTokenEnvVar),
+			"account_key_env_var": types.StringNull(),
+			"use_msi":             types.BoolNull(),
+			"client_id":           types.StringNull(),
+		})
+	}
+	t.Setenv("TEST_AZAPI_SAS_TOKEN", "sv=2022-11-02&sig=signature")
+
+	var diags diag.Diagnostics
+	auth := expandDataPlaneAuthentication(context.Background(), authentication("TEST_AZAPI_SAS_TOKEN"), &diags)
+	if auth == nil || auth.SasToken != "sv=2022-11-02&sig=signature" || diags.WarningsCount() != 0 {
+		t.Fatalf("Expected the SAS token from the environment variable but got %+v, %v", auth, diags)
+	}
+
+	// the credentials of the provider are used with a warning if the environment variable isn't set
+	auth = expandDataPlaneAuthentication(context.Background(), authentication("TEST_AZAPI_SAS_TOKEN_UNSET"), &diags)
+	if auth != nil || diags.WarningsCount() != 1 {
+		t.Fatalf("Expected no authentication with a warning but got %+v, %v", auth, diags)
+	}
+
+	if auth := expandDataPlaneAuthentication(context.Background(), types.ObjectNull(attrTypes), &diags); auth != nil {
+		t.Fatalf("Expected no authentication but got %+v", auth)
+	}
+}
+
+type testTokenCredential struct{}
+
+func (testTokenCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func Test_EtagConflict(t *testing.T) {
+	const resourceId = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1"
+	var ifMatch []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifMatch = append(ifMatch, r.Method+" "+r.Header.Get("If-Match"))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusPreconditionFailed)
+		_, _ = w.Write([]byte(`{"error":{"code":"PreconditionFailed","message":"The specified precondition is not met."}}`))
+	}))
+	defer server.Close()
+
+	resourceClient, err := clients.NewResourceClient(testTokenCredential{}, &arm.ClientOptions{
+		ClientOptions: policy.ClientOptions{
+			Cloud: cloud.Configuration{
+				Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+					cloud.ResourceManager: {Endpoint: server.URL, Audience: "https://management.azure.com"},
+				},
+			},
+			Transport: server.Client(),
+			Retry: policy.RetryOptions{
+				MaxRetries: -1,
+			},
+		},
+		DisableRPRegistration: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := &AzapiResource{ProviderData: &clients.Client{ResourceClient: resourceClient, Features: features.Default()}}
+
+	ctx := context.Background()
+	schemaResponse := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResponse)
+	newState := func() tfsdk.State {
+		state := tfsdk.State{Schema: schemaResponse.Schema, Raw: tftypes.NewValue(schemaResponse.Schema.Type().TerraformType(ctx), nil)}
+		values := map[string]attr.Value{
+			"id":        types.StringValue(resourceId),
+			"name":      types.StringValue("rg1"),
+			"parent_id": types.StringValue("/subscriptions/00000000-0000-0000-0000-000000000000"),
+			"type":      types.StringValue("Microsoft.Resources/resourceGroups@2021-04-01"),
+			"location":  types.StringValue("westus"),
+			"body":      types.DynamicValue(types.ObjectValueMust(map[string]attr.Type{}, map[string]attr.Value{})),
+			"use_etag":  types.BoolValue(true),
+			"etag":      types.StringValue(`"etag1"`),
+		}
+		for name, value := range values {
+			if diags := state.SetAttribute(ctx, path.Root(name), value); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+		}
+		return state
+	}
+	expectConflict := func(diagnostics diag.Diagnostics) {
+		if !diagnostics.HasError() || diagnostics.Errors()[0].Summary() != "Resource has been changed outside of Terraform" {
+			t.Fatalf("expect the etag conflict error, got %v", diagnostics)
+		}
+		if detail := diagnostics.Errors()[0].Detail(); !strings.Contains(detail, `The ETag "\"etag1\""`) {
+			t.Fatalf("expect the etag in the detail, got %q", detail)
+		}
+	}
+
+	// the update fails instead of overwriting the changes which are made outside of Terraform
+	state := newState()
+	var diagnostics diag.Diagnostics
+	r.CreateUpdate(ctx, tfsdk.Plan{Schema: state.Schema, Raw: state.Raw}, &state, testPrivateState{}, &diagnostics)
+	expectConflict(diagnostics)
+
+	deleteResponse := &resource.DeleteResponse{}
+	r.Delete(ctx, resource.DeleteRequest{State: newState()}, deleteResponse)
+	expectConflict(deleteResponse.Diagnostics)
+
+	if expected := []string{`PUT "etag1"`, `DELETE "etag1"`}; !reflect.DeepEqual(ifMatch, expected) {
+		t.Fatalf("expect the If-Match headers %v, got %v", expected, ifMatch)
+	}
+}
+
+func Test_ModifyPlanWithDefaultNaming(t *testing.T) {
+	userFeatures := features.Default()
+	userFeatures.DefaultNamingPrefix = "contoso-"
+	userFeatures.DefaultNamingSuffix = "-prod"
+	r := &AzapiResource{ProviderData: &clients.Client{Features: userFeatures}}
+
+	ctx := context.Background()
+	schemaResponse := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResponse)
+	objectType := schemaResponse.Schema.Type().TerraformType(ctx)
+	newValue := func(values map[string]attr.Value) tftypes.Value {
+		state := tfsdk.State{Schema: schemaResponse.Schema, Raw: tftypes.NewValue(objectType, nil)}
+		for name, value := range values {
+			if diags := state.SetAttribute(ctx, path.Root(name), value); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+		}
+		return state.Raw
+	}
+	configValues := map[string]attr.Value{
+		"name":                      types.StringValue("vnet1"),
+		"parent_id":                 types.StringValue("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1"),
+		"type":                      types.StringValue("Microsoft.Network/virtualNetworks@2023-09-01"),
+		"location":                  types.StringValue("westus"),
+		"body":                      types.DynamicValue(types.ObjectValueMust(map[string]attr.Type{}, map[string]attr.Value{})),
+		"schema_validation_enabled": types.BoolValue(false),
+	}
+	planValues := map[string]attr.Value{"apply_default_naming": types.BoolValue(true)}
+	for name, value := range configValues {
+		planValues[name] = value
+	}
+	modifyPlan := func(state tftypes.Value) (*AzapiResourceModel, *resource.ModifyPlanResponse) {
+		request := resource.ModifyPlanRequest{
+			Config: tfsdk.Config{Schema: schemaResponse.Schema, Raw: newValue(configValues)},
+			Plan:   tfsdk.Plan{Schema: schemaResponse.Schema, Raw: newValue(planValues)},
+			State:  tfsdk.State{Schema: schemaResponse.Schema, Raw: state},
+		}
+		response := &resource.ModifyPlanResponse{Plan: request.Plan}
+		r.ModifyPlan(ctx, request, response)
+		if response.Diagnostics.HasError() {
+			t.Fatalf("unexpected error: %v", response.Diagnostics)
+		}
+		var plan *AzapiResourceModel
+		if diags := response.Plan.Get(ctx, &plan); diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		return plan, response
+	}
+
+	// the planned name must be the configured name, otherwise Terraform rejects the plan
+	plan, _ := modifyPlan(tftypes.NewValue(objectType, nil))
+	if plan.Name.ValueString() != "vnet1" {
+		t.Fatalf("expect the planned name to be the configured name, got %q", plan.Name.ValueString())
+	}
+	if plan.FullName.ValueString() != "contoso-vnet1-prod" {
+		t.Fatalf("expect the full name with the prefix and suffix, got %q", plan.FullName.ValueString())
+	}
+
+	// the resource is replaced if the full name is changed, e.g. the prefix is changed
+	stateValues := map[string]attr.Value{"full_name": types.StringValue("fabrikam-vnet1-prod"), "id": types.StringValue("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/fabrikam-vnet1-prod")}
+	for name, value := range planValues {
+		stateValues[name] = value
+	}
+	_, response := modifyPlan(newValue(stateValues))
+	if !reflect.DeepEqual(response.RequiresReplace, path.Paths{path.Root("full_name")}) {
+		t.Fatalf("expect the full name to require the replacement, got %v", response.RequiresReplace)
+	}
+
+	stateValues["full_name"] = types.StringValue("contoso-vnet1-prod")
+	if _, response := modifyPlan(newValue(stateValues)); len(response.RequiresReplace) != 0 {
+		t.Fatalf("expect no replacement, got %v", response.RequiresReplace)
+	}
+}
+
+func Test_DataPlaneResourceWithoutUpdate(t *testing.T) {
+	methods := make([]string, 0)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"thumbprint":"0123456789abcdef","state":"active"}`))
+	}))
+	defer server.Close()
+
+	dataPlaneClient, err := clients.NewDataPlaneClient(testTokenCredential{}, &arm.ClientOptions{
+		ClientOptions: policy.ClientOptions{
+			Transport: server.Client(),
+			Retry:     policy.RetryOptions{MaxRetries: -1},
+		},
+		DisableRPRegistration: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := &DataPlaneResource{ProviderData: &clients.Client{DataPlaneClient: dataPlaneClient, Features: features.Default()}}
+
+	ctx := context.Background()
+	schemaResponse := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResponse)
+	objectType := schemaResponse.Schema.Type().TerraformType(ctx)
+	newValue := func(body string, values map[string]attr.Value) tftypes.Value {
+		state := tfsdk.State{Schema: schemaResponse.Schema, Raw: tftypes.NewValue(objectType, nil)}
+		values["name"] = types.StringValue("0123456789abcdef")
+		values["parent_id"] = types.StringValue(strings.TrimPrefix(server.URL, "https://"))
+		values["type"] = types.StringValue("Microsoft.Batch/batchAccounts/certificates@2024-02-01.19.0")
+		values["body"] = types.DynamicValue(types.ObjectValueMust(map[string]attr.Type{"password": types.StringType}, map[string]attr.Value{"password": types.StringValue(body)}))
+		for name, value := range values {
+			if diags := state.SetAttribute(ctx, path.Root(name), value); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+		}
+		return state.Raw
+	}
+	stateValues := func() map[string]attr.Value {
+		return map[string]attr.Value{
+			"id":     types.StringValue(strings.TrimPrefix(server.URL, "https://") + "/certificates(thumbprintAlgorithm=sha1,thumbprint=0123456789abcdef)"),
+			"output": types.DynamicValue(types.ObjectValueMust(map[string]attr.Type{}, map[string]attr.Value{})),
+		}
+	}
+	state := tfsdk.State{Schema: schemaResponse.Schema, Raw: newValue("p1", stateValues())}
+
+	// the changes of the body replace the certificate, because it can't be updated
+	config := tfsdk.Config{Schema: schemaResponse.Schema, Raw: newValue("p2", map[string]attr.Value{})}
+	plan := tfsdk.Plan{Schema: schemaResponse.Schema, Raw: newValue("p2", stateValues())}
+	modifyPlanResponse := &resource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(ctx, resource.ModifyPlanRequest{Config: config, Plan: plan, State: state}, modifyPlanResponse)
+	if modifyPlanResponse.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", modifyPlanResponse.Diagnostics)
+	}
+	if !reflect.DeepEqual(modifyPlanResponse.RequiresReplace, path.Paths{path.Root("body")}) {
+		t.Fatalf("expect the body to require the replacement, got %v", modifyPlanResponse.RequiresReplace)
+	}
+
+	// the other changes are only saved to the state without sending the body
+	values := stateValues()
+	values["update_headers"] = types.MapValueMust(types.StringType, map[string]attr.Value{"x-ms-client-request-id": types.StringValue("1")})
+	config = tfsdk.Config{Schema: schemaResponse.Schema, Raw: newValue("p1", map[string]attr.Value{"update_headers": values["update_headers"]})}
+	plan = tfsdk.Plan{Schema: schemaResponse.Schema, Raw: newValue("p1", values)}
+	modifyPlanResponse = &resource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(ctx, resource.ModifyPlanRequest{Config: config, Plan: plan, State: state}, modifyPlanResponse)
+	if len(modifyPlanResponse.RequiresReplace) != 0 {
+		t.Fatalf("expect no replacement, got %v", modifyPlanResponse.RequiresReplace)
+	}
+	updateResponse := &resource.UpdateResponse{State: tfsdk.State{Schema: schemaResponse.Schema, Raw: state.Raw}}
+	r.Update(ctx, resource.UpdateRequest{Config: config, Plan: plan, State: state}, updateResponse)
+	if updateResponse.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", updateResponse.Diagnostics)
+	}
+	if !reflect.DeepEqual(methods, []string{http.MethodGet}) {
+		t.Fatalf("expect the certificate to be only read, got the requests %v", methods)
+	}
+}
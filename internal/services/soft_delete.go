@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/terraform-provider-azapi/internal/azure"
+	"github.com/Azure/terraform-provider-azapi/internal/azure/location"
+	"github.com/Azure/terraform-provider-azapi/internal/clients"
+	"github.com/Azure/terraform-provider-azapi/internal/features"
+	"github.com/Azure/terraform-provider-azapi/internal/services/parse"
+	"github.com/Azure/terraform-provider-azapi/utils"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// softDeletableResourceType describes how a soft-deleted resource of the type is found, recovered and purged.
+type softDeletableResourceType struct {
+	// deletedResourceId returns the ID of the soft-deleted resource, which is located by the location of the resource instead of its resource group
+	deletedResourceId func(id *arm.ResourceID, location string) string
+	apiVersion        string
+	// purgeAction is the action which purges the soft-deleted resource, the soft-deleted resource is deleted if it's empty
+	purgeAction string
+	// recover sets the property in the copy of the body which recovers the soft-deleted resource
+	recover func(body map[string]interface{})
+}
+
+// softDeletableResourceTypes maps the lower-cased resource types to how their soft-deleted resources are handled.
+var softDeletableResourceTypes = map[string]softDeletableResourceType{
+	"microsoft.keyvault/vaults": {
+		deletedResourceId: func(id *arm.ResourceID, location string) string {
+			return fmt.Sprintf("/subscriptions/%s/providers/Microsoft.KeyVault/locations/%s/deletedVaults/%s", id.SubscriptionID, location, id.Name)
+		},
+		apiVersion:  "2023-07-01",
+		purgeAction: "purge",
+		recover: func(body map[string]interface{}) {
+			setBodyProperty(body, "properties.createMode", "recover")
+		},
+	},
+	"microsoft.cognitiveservices/accounts": {
+		deletedResourceId: func(id *arm.ResourceID, location string) string {
+			return fmt.Sprintf("/subscriptions/%s/providers/Microsoft.CognitiveServices/locations/%s/resourceGroups/%s/deletedAccounts/%s", id.SubscriptionID, location, id.ResourceGroupName, id.Name)
+		},
+		apiVersion: "2023-05-01",
+		recover: func(body map[string]interface{}) {
+			setBodyProperty(body, "properties.restore", true)
+		},
+	},
+	"microsoft.apimanagement/service": {
+		deletedResourceId: func(id *arm.ResourceID, location string) string {
+			return fmt.Sprintf("/subscriptions/%s/providers/Microsoft.ApiManagement/locations/%s/deletedservices/%s", id.SubscriptionID, location, id.Name)
+		},
+		apiVersion: "2022-08-01",
+		recover: func(body map[string]interface{}) {
+			setBodyProperty(body, "properties.restore", true)
+		},
+	},
+}
+
+// softDeletedResourceId returns the ID of the soft-deleted resource which has the same name as the resource, and the API version to manage it.
+// It returns false if the resource type doesn't support soft delete or the location is unknown.
+func softDeletedResourceId(id parse.ResourceId, resourceLocation string) (string, string, bool) {
+	resourceType, ok := softDeletableResourceTypes[strings.ToLower(id.AzureResourceType)]
+	if !ok || resourceLocation == "" {
+		return "", "", false
+	}
+	armId, err := arm.ParseResourceID(id.AzureResourceId)
+	if err != nil {
+		return "", "", false
+	}
+	return resourceType.deletedResourceId(armId, location.Normalize(resourceLocation)), resourceType.apiVersion, true
+}
+
+// purgeSoftDeletedResource permanently deletes the soft-deleted resource.
+func purgeSoftDeletedResource(ctx context.Context, client clients.Requester, id parse.ResourceId, deletedId string, apiVersion string) error {
+	resourceType := softDeletableResourceTypes[strings.ToLower(id.AzureResourceType)]
+	if resourceType.purgeAction != "" {
+		_, err := client.Action(ctx, deletedId, resourceType.purgeAction, apiVersion, http.MethodPost, nil, clients.DefaultRequestOptions())
+		return err
+	}
+	_, err := client.Delete(ctx, deletedId, apiVersion, clients.DefaultRequestOptions())
+	return err
+}
+
+// handleSoftDeletedResource checks whether a soft-deleted resource has the same name as the resource which will be created,
+// because creating the resource fails with a conflict error until the soft-deleted resource is recovered or purged.
+// The soft-deleted resource is recovered or purged according to the behavior, otherwise an error which explains the conflict is returned.
+func handleSoftDeletedResource(ctx context.Context, client clients.Requester, id parse.ResourceId, body map[string]interface{}, behavior features.SoftDeletedResourcesOnCreate, options clients.RequestOptions) diag.Diagnostics {
+	var diags diag.Diagnostics
+	resourceLocation, _ := body["location"].(string)
+	deletedId, apiVersion, ok := softDeletedResourceId(id, resourceLocation)
+	if !ok {
+		return diags
+	}
+
+	if _, err := client.Get(ctx, deletedId, apiVersion, clients.DefaultRequestOptions()); err != nil {
+		if !utils.ResponseErrorWasNotFound(err) {
+			// the principal may not have the permission to read the soft-deleted resources, the creation reports the conflict if there's any
+			tflog.Warn(ctx, fmt.Sprintf("Failed to check the soft-deleted resource %s: %+v", deletedId, err))
+		}
+		return diags
+	}
+
+	switch behavior {
+	case features.SoftDeletedResourcesOnCreateRecover:
+		tflog.Info(ctx, fmt.Sprintf("Recovering the soft-deleted resource %s", deletedId))
+		recoverBody := make(map[string]interface{}, len(body))
+		for key, value := range body {
+			recoverBody[key] = value
+		}
+		if properties, ok := body["properties"].(map[string]interface{}); ok {
+			recoverProperties := make(map[string]interface{}, len(properties))
+			for key, value := range properties {
+				recoverProperties[key] = value
+			}
+			recoverBody["properties"] = recoverProperties
+		}
+		softDeletableResourceTypes[strings.ToLower(id.AzureResourceType)].recover(recoverBody)
+		if _, err := client.CreateOrUpdate(ctx, id.AzureResourceId, id.ApiVersion, recoverBody, options); err != nil {
+			diags.AddError("Failed to recover the soft-deleted resource", fmt.Errorf("recovering %s: %+v", deletedId, err).Error())
+		}
+	case features.SoftDeletedResourcesOnCreatePurge:
+		tflog.Info(ctx, fmt.Sprintf("Purging the soft-deleted resource %s", deletedId))
+		if err := purgeSoftDeletedResource(ctx, client, id, deletedId, apiVersion); err != nil {
+			diags.AddError("Failed to purge the soft-deleted resource", fmt.Errorf("purging %s: %+v", deletedId, err).Error())
+		}
+	default:
+		diags.AddError("Soft-deleted resource exists", fmt.Sprintf("The resource %s can't be created, because the soft-deleted resource %s has the same name. Please recover or purge it, or set the `soft_deleted_resources_on_create` in the provider to `recover` or `purge` to handle it automatically.", id, deletedId))
+	}
+	return diags
+}
+
+// softDeletedResourcePollInterval is the interval of checking whether the deleted resource is soft-deleted, it's a variable so that the tests can shorten it.
+var softDeletedResourcePollInterval = 5 * time.Second
+
+// softDeletedResourcePollCount is the maximum number of the checks, the soft-deleted resources usually appear shortly after the deletions complete.
+const softDeletedResourcePollCount = 12
+
+// purgeOnDestroy purges the soft-deleted resource after the resource is deleted, it waits until the deleted resource is soft-deleted,
+// and the purge waits until the long-running operation completes.
+func purgeOnDestroy(ctx context.Context, client clients.Requester, id parse.ResourceId, resourceLocation string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	deletedId, apiVersion, ok := softDeletedResourceId(id, resourceLocation)
+	if !ok {
+		diags.AddWarning("Soft-deleted resource is not purged", fmt.Sprintf("The `purge_on_destroy` is only supported by the %s resource types with a known location, the resource %s is not purged.", strings.Join(softDeletableResourceTypeNames(), ", "), id))
+		return diags
+	}
+
+	for i := 0; ; i++ {
+		_, err := client.Get(ctx, deletedId, apiVersion, clients.DefaultRequestOptions())
+		if err == nil {
+			break
+		}
+		if !utils.ResponseErrorWasNotFound(err) {
+			diags.AddError("Failed to purge the soft-deleted resource", fmt.Errorf("reading %s: %+v", deletedId, err).Error())
+			return diags
+		}
+		if i == softDeletedResourcePollCount-1 {
+			diags.AddWarning("Soft-deleted resource is not purged", fmt.Sprintf("The soft-deleted resource %s is not found after the resource %s is deleted, it's not purged.", deletedId, id))
+			return diags
+		}
+		select {
+		case <-ctx.Done():
+			diags.AddError("Failed to purge the soft-deleted resource", fmt.Errorf("waiting for %s: %+v", deletedId, ctx.Err()).Error())
+			return diags
+		case <-time.After(softDeletedResourcePollInterval):
+		}
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Purging the soft-deleted resource %s", deletedId))
+	if err := purgeSoftDeletedResource(ctx, client, id, deletedId, apiVersion); err != nil && !utils.ResponseErrorWasNotFound(err) {
+		diags.AddError("Failed to purge the soft-deleted resource", fmt.Errorf("purging %s: %+v", deletedId, err).Error())
+	}
+	return diags
+}
+
+// softDeletableResourceTypeNames returns the sorted resource types which support soft delete, e.g. `Microsoft.KeyVault/vaults`.
+func softDeletableResourceTypeNames() []string {
+	out := make([]string, 0, len(softDeletableResourceTypes))
+	for resourceType := range softDeletableResourceTypes {
+		if v, ok := azure.GetCanonicalResourceType(resourceType); ok {
+			resourceType = v
+		}
+		out = append(out, "`"+resourceType+"`")
+	}
+	sort.Strings(out)
+	return out
+}
@@ -64,10 +64,20 @@ func MergeObject(old interface{}, new interface{}) interface{} {
 type UpdateJsonOption struct {
 	IgnoreCasing          bool
 	IgnoreMissingProperty bool
+	// ReplacePaths is a set of dot-separated paths, e.g. `properties.appSettings`, whose values are replaced by the new values instead of being merged.
+	// The paths apply to the items of the arrays too, e.g. `properties.subnets.properties.routeTable` matches the route table of every subnet.
+	ReplacePaths map[string]bool
 }
 
+// maskedValueRegex matches the values which are masked by the service, e.g. `****`.
+var maskedValueRegex = regexp.MustCompile(`^\*+$`)
+
 // UpdateObject is used to get an updated object which has same schema as old, but with new value
 func UpdateObject(old interface{}, new interface{}, option UpdateJsonOption) interface{} {
+	return updateObject(old, new, option, "")
+}
+
+func updateObject(old interface{}, new interface{}, option UpdateJsonOption, path string) interface{} {
 	if reflect.DeepEqual(old, new) {
 		return old
 	}
@@ -76,9 +86,15 @@ func UpdateObject(old interface{}, new interface{}, option UpdateJsonOption) int
 		if newMap, ok := new.(map[string]interface{}); ok {
 			res := make(map[string]interface{})
 			for key, value := range oldValue {
+				nestedPath := strings.TrimPrefix(path+"."+key, ".")
 				switch {
+				case option.ReplacePaths[nestedPath]:
+					// the property is removed remotely, so it's removed from the result too
+					if newValue, ok := newMap[key]; ok {
+						res[key] = newValue
+					}
 				case newMap[key] != nil:
-					res[key] = UpdateObject(value, newMap[key], option)
+					res[key] = updateObject(value, newMap[key], option, nestedPath)
 				case option.IgnoreMissingProperty || isZeroValue(value):
 					res[key] = value
 				}
@@ -98,7 +114,7 @@ func UpdateObject(old interface{}, new interface{}, option UpdateJsonOption) int
 				}
 				res := make([]interface{}, 0)
 				for index := range oldValue {
-					res = append(res, UpdateObject(oldValue[index], newArr[index], option))
+					res = append(res, updateObject(oldValue[index], newArr[index], option, path))
 				}
 				return res
 			}
@@ -110,7 +126,7 @@ func UpdateObject(old interface{}, new interface{}, option UpdateJsonOption) int
 				found := false
 				for index, newItem := range newArr {
 					if reflect.DeepEqual(oldItem, newItem) && !used[index] {
-						res = append(res, UpdateObject(oldItem, newItem, option))
+						res = append(res, updateObject(oldItem, newItem, option, path))
 						used[index] = true
 						found = true
 						break
@@ -121,7 +137,7 @@ func UpdateObject(old interface{}, new interface{}, option UpdateJsonOption) int
 				}
 				for index, newItem := range newArr {
 					if areSameArrayItems(oldItem, newItem) && !used[index] {
-						res = append(res, UpdateObject(oldItem, newItem, option))
+						res = append(res, updateObject(oldItem, newItem, option, path))
 						used[index] = true
 						break
 					}
@@ -140,7 +156,7 @@ func UpdateObject(old interface{}, new interface{}, option UpdateJsonOption) int
 			if option.IgnoreCasing && strings.EqualFold(oldValue, newStr) {
 				return oldValue
 			}
-			if option.IgnoreMissingProperty && (regexp.MustCompile(`^\*+$`).MatchString(newStr) || "<redacted>" == newStr || "" == newStr) {
+			if option.IgnoreMissingProperty && (maskedValueRegex.MatchString(newStr) || "<redacted>" == newStr || "" == newStr) {
 				return oldValue
 			}
 		}
@@ -214,6 +230,24 @@ func ExtractObjectJMES(old interface{}, pathKey, path string) interface{} {
 	return result
 }
 
+// IsJMESPathTruthy follows the JMESPath definition of false values: false, null, empty string, empty list and empty object.
+func IsJMESPathTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) != 0
+	case map[string]interface{}:
+		return len(v) != 0
+	default:
+		return true
+	}
+}
+
 // OverrideWithPaths is used to override old object with new object for specific paths
 func OverrideWithPaths(old interface{}, new interface{}, path string, pathSet map[string]bool) (interface{}, error) {
 	if len(pathSet) == 0 || old == nil {
@@ -1018,3 +1018,87 @@ func Test_UpdateObjectDuplicateIdentifiersWithInconsistentOrdering(t *testing.T)
 		t.Fatalf("Expected:\n%s\n\n but got\n%s", expectedJson, gotJson)
 	}
 }
+
+func Test_UpdateObjectWithReplacePaths(t *testing.T) {
+	OldJson := `
+{
+	"properties": {
+		"appSettings": {
+			"A": "1",
+			"B": "2"
+		},
+		"sites": [
+			{
+				"name": "site1",
+				"settings": {
+					"C": "3"
+				}
+			}
+		],
+		"tags": {
+			"D": "4"
+		}
+	}
+}
+`
+	NewJson := `
+{
+	"properties": {
+		"appSettings": {
+			"A": "1",
+			"E": "5"
+		},
+		"sites": [
+			{
+				"name": "site1",
+				"settings": {
+					"F": "6"
+				}
+			}
+		],
+		"tags": {
+			"G": "7"
+		}
+	}
+}
+`
+	ExpectedJson := `
+{
+	"properties": {
+		"appSettings": {
+			"A": "1",
+			"E": "5"
+		},
+		"sites": [
+			{
+				"name": "site1",
+				"settings": {
+					"F": "6"
+				}
+			}
+		],
+		"tags": {
+			"D": "4"
+		}
+	}
+}
+`
+	var old, new, expected any
+	_ = json.Unmarshal([]byte(OldJson), &old)
+	_ = json.Unmarshal([]byte(NewJson), &new)
+	_ = json.Unmarshal([]byte(ExpectedJson), &expected)
+
+	got := utils.UpdateObject(old, new, utils.UpdateJsonOption{
+		IgnoreCasing:          false,
+		IgnoreMissingProperty: true,
+		ReplacePaths: map[string]bool{
+			"properties.appSettings":    true,
+			"properties.sites.settings": true,
+		},
+	})
+	if !reflect.DeepEqual(got, expected) {
+		expectedJson, _ := json.MarshalIndent(expected, "", "  ")
+		gotJson, _ := json.MarshalIndent(got, "", "  ")
+		t.Fatalf("Expected:\n%s\n\n but got\n%s", expectedJson, gotJson)
+	}
+}
@@ -19,3 +19,7 @@ func ResponseErrorWasStatusCode(err error, statusCode int) bool {
 	var responseErr *azcore.ResponseError
 	return errors.As(err, &responseErr) && responseErr.StatusCode == statusCode
 }
+
+func ResponseErrorWasThrottled(err error) bool {
+	return ResponseErrorWasStatusCode(err, http.StatusTooManyRequests)
+}